@@ -0,0 +1,1295 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"maps"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/andriawan24/link-short/internal/config"
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/models/responses"
+	"github.com/andriawan24/link-short/internal/repository/memory"
+	"github.com/andriawan24/link-short/internal/resilience"
+	"github.com/andriawan24/link-short/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	desktopUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	mobileUserAgent  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"
+)
+
+// testApp is the full router backed by the in-memory store and miniredis.
+type testApp struct {
+	t      *testing.T
+	cfg    config.Config
+	router http.Handler
+	store  *memory.Store
+	redis  *miniredis.Miniredis
+	rdb    *redis.Client
+}
+
+// newTestApp builds the app from the default configuration. configure may
+// adjust it before the router is set up.
+func newTestApp(t *testing.T, configure ...func(*config.Config)) *testApp {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Default()
+	cfg.Auth.TokenSecret = "test-token-secret"
+	cfg.Auth.RefreshTokenSecret = "test-refresh-token-secret"
+	cfg.Mail.Driver = "memory"
+	cfg.Spool.Dir = t.TempDir()
+	cfg.Storage.LocalDir = t.TempDir()
+	for _, fn := range configure {
+		fn(&cfg)
+	}
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr(), Protocol: 2})
+	t.Cleanup(func() { rdb.Close() })
+
+	dbBreaker := resilience.NewBreaker("postgres", cfg.Breaker.FailureThreshold, cfg.Breaker.OpenTimeout)
+	redisBreaker := resilience.NewBreaker("redis", cfg.Breaker.FailureThreshold, cfg.Breaker.OpenTimeout)
+
+	store := memory.New()
+	router := setupRouter(t.Context(), cfg, store, store, rdb, fixedLocator{testLocation}, dbBreaker, redisBreaker)
+
+	return &testApp{t: t, cfg: cfg, router: router, store: store, redis: mr, rdb: rdb}
+}
+
+// testLocation is where every test request comes from.
+var testLocation = services.GeoLocation{Country: "ID", Region: "West Java", City: "Bandung", ASN: 7713}
+
+// fixedLocator places every IP address at the same location.
+type fixedLocator struct {
+	location services.GeoLocation
+}
+
+func (l fixedLocator) Locate(string) services.GeoLocation { return l.location }
+
+func (fixedLocator) Run(context.Context) {}
+
+type testRequest struct {
+	method string
+	path   string
+	// body is encoded as JSON unless it is an io.Reader, which is sent as is
+	// with contentType.
+	body        any
+	contentType string
+	token       string
+	userAgent   string
+}
+
+func (a *testApp) do(req testRequest) *httptest.ResponseRecorder {
+	a.t.Helper()
+
+	var body io.Reader
+	contentType := "application/json"
+	switch b := req.body.(type) {
+	case nil:
+		body = http.NoBody
+	case io.Reader:
+		body = b
+		contentType = req.contentType
+	default:
+		var encoded bytes.Buffer
+		if err := json.NewEncoder(&encoded).Encode(b); err != nil {
+			a.t.Fatalf("encode request body: %v", err)
+		}
+		body = &encoded
+	}
+
+	httpReq := httptest.NewRequest(req.method, req.path, body)
+	httpReq.Header.Set("Content-Type", contentType)
+	if req.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+req.token)
+	}
+	if req.userAgent != "" {
+		httpReq.Header.Set("User-Agent", req.userAgent)
+	}
+
+	rec := httptest.NewRecorder()
+	a.router.ServeHTTP(rec, httpReq)
+	return rec
+}
+
+// expect performs the request, checks the status and decodes the data field
+// of the response into T.
+func expect[T any](a *testApp, req testRequest, status int) T {
+	a.t.Helper()
+
+	rec := a.do(req)
+	if rec.Code != status {
+		a.t.Fatalf("%s %s: status = %d, want %d; body: %s", req.method, req.path, rec.Code, status, rec.Body.String())
+	}
+
+	var response struct {
+		Data T `json:"data"`
+	}
+	if rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			a.t.Fatalf("%s %s: decode response: %v; body: %s", req.method, req.path, err, rec.Body.String())
+		}
+	}
+	return response.Data
+}
+
+func (a *testApp) register(name string, email string) responses.LoginResponse {
+	a.t.Helper()
+
+	return expect[responses.LoginResponse](a, testRequest{
+		method: http.MethodPost,
+		path:   "/auth/register",
+		body:   gin.H{"name": name, "email": email, "password": "correct horse battery"},
+	}, http.StatusOK)
+}
+
+func (a *testApp) createLink(token string, body gin.H) responses.LinkResponse {
+	a.t.Helper()
+
+	return expect[responses.LinkResponse](a, testRequest{
+		method: http.MethodPost,
+		path:   "/links/create",
+		body:   body,
+		token:  token,
+	}, http.StatusCreated)
+}
+
+func TestAuthFlow(t *testing.T) {
+	app := newTestApp(t)
+
+	registered := app.register("Ada", "ada@example.com")
+	if registered.Token == "" || registered.RefreshToken == "" {
+		t.Fatalf("register returned no tokens: %+v", registered)
+	}
+	if registered.User.Email != "ada@example.com" || registered.User.Role != "user" || registered.User.IsVerified {
+		t.Fatalf("unexpected registered user: %+v", registered.User)
+	}
+
+	expect[any](app, testRequest{
+		method: http.MethodPost,
+		path:   "/auth/register",
+		body:   gin.H{"name": "Ada", "email": "ada@example.com", "password": "another password"},
+	}, http.StatusConflict)
+
+	expect[any](app, testRequest{
+		method: http.MethodPost,
+		path:   "/auth/login",
+		body:   gin.H{"email": "ada@example.com", "password": "wrong password"},
+	}, http.StatusUnauthorized)
+
+	login := expect[responses.LoginResponse](app, testRequest{
+		method: http.MethodPost,
+		path:   "/auth/login",
+		body:   gin.H{"email": "ada@example.com", "password": "correct horse battery"},
+	}, http.StatusOK)
+
+	profile := expect[responses.UserResponse](app, testRequest{method: http.MethodGet, path: "/auth/me", token: login.Token}, http.StatusOK)
+	if profile.ID != registered.User.ID {
+		t.Fatalf("profile id = %s, want %s", profile.ID, registered.User.ID)
+	}
+
+	expect[any](app, testRequest{method: http.MethodGet, path: "/auth/me"}, http.StatusUnauthorized)
+	expect[any](app, testRequest{method: http.MethodGet, path: "/auth/me", token: login.RefreshToken}, http.StatusUnauthorized)
+
+	refreshed := expect[responses.LoginResponse](app, testRequest{
+		method: http.MethodPost,
+		path:   "/auth/refresh",
+		body:   gin.H{"refresh_token": login.RefreshToken},
+	}, http.StatusOK)
+	expect[responses.UserResponse](app, testRequest{method: http.MethodGet, path: "/auth/me", token: refreshed.Token}, http.StatusOK)
+
+	expect[any](app, testRequest{
+		method: http.MethodPost,
+		path:   "/auth/refresh",
+		body:   gin.H{"refresh_token": login.Token},
+	}, http.StatusUnauthorized)
+}
+
+func TestPasswordResetSignsOut(t *testing.T) {
+	mailDir := t.TempDir()
+	app := newTestApp(t, func(cfg *config.Config) {
+		cfg.Mail.Driver = "file"
+		cfg.Mail.FileDir = mailDir
+	})
+	stolen := app.register("Ada", "ada@example.com")
+
+	// Token issue times have second precision; start the reset in a later
+	// second than the tokens above.
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+
+	expect[any](app, testRequest{method: http.MethodPost, path: "/auth/forgot-password", body: gin.H{"email": "ada@example.com"}}, http.StatusOK)
+	resetToken := app.mailedToken(mailDir, "/reset-password?token=")
+	expect[any](app, testRequest{method: http.MethodPost, path: "/auth/reset-password", body: gin.H{"token": resetToken, "password": "a brand new password"}}, http.StatusOK)
+
+	expect[any](app, testRequest{method: http.MethodGet, path: "/auth/me", token: stolen.Token}, http.StatusUnauthorized)
+	expect[any](app, testRequest{method: http.MethodPost, path: "/auth/refresh", body: gin.H{"refresh_token": stolen.RefreshToken}}, http.StatusUnauthorized)
+
+	login := expect[responses.LoginResponse](app, testRequest{
+		method: http.MethodPost,
+		path:   "/auth/login",
+		body:   gin.H{"email": "ada@example.com", "password": "a brand new password"},
+	}, http.StatusOK)
+	expect[responses.UserResponse](app, testRequest{method: http.MethodGet, path: "/auth/me", token: login.Token}, http.StatusOK)
+	expect[responses.LoginResponse](app, testRequest{method: http.MethodPost, path: "/auth/refresh", body: gin.H{"refresh_token": login.RefreshToken}}, http.StatusOK)
+}
+
+func TestSuspendedUser(t *testing.T) {
+	app := newTestApp(t)
+	adminToken := app.register("Admin", "admin@example.com").Token
+	if err := app.store.PromoteUsersToAdmin(t.Context(), []string{"admin@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	user := app.register("Mallory", "mallory@example.com")
+
+	expect[any](app, testRequest{method: http.MethodGet, path: "/links/all", token: user.Token}, http.StatusOK)
+	expect[any](app, testRequest{method: http.MethodPost, path: "/admin/users/" + user.User.ID.String() + "/suspend", token: user.Token}, http.StatusForbidden)
+	expect[any](app, testRequest{method: http.MethodPost, path: "/admin/users/" + user.User.ID.String() + "/suspend", token: adminToken}, http.StatusOK)
+
+	// The access token issued before the suspension stops working at once.
+	expect[any](app, testRequest{method: http.MethodGet, path: "/links/all", token: user.Token}, http.StatusForbidden)
+	expect[any](app, testRequest{method: http.MethodPost, path: "/links/create", body: gin.H{"original_url": "https://example.test/spam"}, token: user.Token}, http.StatusForbidden)
+
+	expect[any](app, testRequest{method: http.MethodPost, path: "/admin/users/" + user.User.ID.String() + "/reactivate", token: adminToken}, http.StatusOK)
+	expect[any](app, testRequest{method: http.MethodGet, path: "/links/all", token: user.Token}, http.StatusOK)
+}
+
+func TestTwoFactorDisableThrottled(t *testing.T) {
+	app := newTestApp(t)
+	token := app.register("Ada", "ada@example.com").Token
+
+	// Guessing the password of a stolen session is throttled like sign-ins.
+	for range 3 {
+		expect[any](app, testRequest{method: http.MethodPost, path: "/auth/2fa/disable", body: gin.H{"password": "wrong", "code": "000000"}, token: token}, http.StatusUnauthorized)
+	}
+	expect[any](app, testRequest{method: http.MethodPost, path: "/auth/2fa/disable", body: gin.H{"password": "correct horse battery", "code": "000000"}, token: token}, http.StatusTooManyRequests)
+	expect[any](app, testRequest{method: http.MethodPost, path: "/auth/login", body: gin.H{"email": "ada@example.com", "password": "correct horse battery"}}, http.StatusTooManyRequests)
+}
+
+func TestLinkCRUD(t *testing.T) {
+	app := newTestApp(t)
+	owner := app.register("Owner", "owner@example.com").Token
+	other := app.register("Other", "other@example.com").Token
+
+	generated := app.createLink(owner, gin.H{"original_url": "https://example.test/generated"})
+	if generated.ShortCode == "" || generated.CustomShortCode != nil {
+		t.Fatalf("unexpected generated link: %+v", generated)
+	}
+
+	custom := app.createLink(owner, gin.H{"original_url": "https://example.test/custom", "custom_short_code": "my-link"})
+	if custom.CustomShortCode == nil || *custom.CustomShortCode != "my-link" {
+		t.Fatalf("custom short code = %v, want my-link", custom.CustomShortCode)
+	}
+
+	expect[any](app, testRequest{
+		method: http.MethodPost,
+		path:   "/links/create",
+		body:   gin.H{"original_url": "https://example.test/taken", "custom_short_code": "my-link"},
+		token:  other,
+	}, http.StatusConflict)
+
+	links := expect[[]responses.LinkResponse](app, testRequest{method: http.MethodGet, path: "/links/all", token: owner}, http.StatusOK)
+	if len(links) != 2 || links[0].ID != custom.ID {
+		t.Fatalf("links = %+v, want the custom link first of two", links)
+	}
+
+	otherLinks := expect[[]responses.LinkResponse](app, testRequest{method: http.MethodGet, path: "/links/all", token: other}, http.StatusOK)
+	if len(otherLinks) != 0 {
+		t.Fatalf("other user sees %d links, want 0", len(otherLinks))
+	}
+
+	path := "/links/" + custom.ID.String()
+	expect[any](app, testRequest{method: http.MethodGet, path: path, token: other}, http.StatusNotFound)
+
+	updated := expect[responses.LinkResponse](app, testRequest{
+		method: http.MethodPut,
+		path:   path,
+		body:   gin.H{"original_url": "https://example.test/updated", "custom_short_code": "renamed"},
+		token:  owner,
+	}, http.StatusOK)
+	if updated.OriginalURL != "https://example.test/updated" || *updated.CustomShortCode != "renamed" {
+		t.Fatalf("unexpected updated link: %+v", updated)
+	}
+
+	fetched := expect[responses.LinkResponse](app, testRequest{method: http.MethodGet, path: path, token: owner}, http.StatusOK)
+	if fetched.OriginalURL != updated.OriginalURL {
+		t.Fatalf("fetched url = %s, want %s", fetched.OriginalURL, updated.OriginalURL)
+	}
+
+	if rec := app.do(testRequest{method: http.MethodDelete, path: path, token: owner}); rec.Code != http.StatusNoContent {
+		t.Fatalf("delete status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	expect[any](app, testRequest{method: http.MethodGet, path: path, token: owner}, http.StatusNotFound)
+
+	expect[any](app, testRequest{method: http.MethodGet, path: "/links/all"}, http.StatusUnauthorized)
+}
+
+func TestRedirect(t *testing.T) {
+	app := newTestApp(t)
+	token := app.register("Owner", "owner@example.com").Token
+
+	link := app.createLink(token, gin.H{"original_url": "https://example.test/destination", "custom_short_code": "go-here"})
+
+	for _, code := range []string{link.ShortCode, "go-here"} {
+		rec := app.do(testRequest{method: http.MethodGet, path: "/" + code, userAgent: desktopUserAgent})
+		if rec.Code != http.StatusFound {
+			t.Fatalf("redirect %s: status = %d, want %d", code, rec.Code, http.StatusFound)
+		}
+		if location := rec.Header().Get("Location"); location != "https://example.test/destination" {
+			t.Fatalf("redirect %s: location = %q", code, location)
+		}
+	}
+
+	// Redirect targets are written to Redis in the background.
+	waitFor(t, func() bool { return app.redis.Exists("url:go-here") })
+
+	if rec := app.do(testRequest{method: http.MethodGet, path: "/go-here"}); rec.Code != http.StatusFound {
+		t.Fatalf("cached redirect: status = %d, want %d", rec.Code, http.StatusFound)
+	}
+
+	expect[any](app, testRequest{method: http.MethodGet, path: "/missing"}, http.StatusNotFound)
+	if value, _ := app.redis.Get("url:missing"); value != "!" {
+		t.Fatalf("unknown code cached as %q, want the not found marker", value)
+	}
+
+	if rec := app.do(testRequest{method: http.MethodDelete, path: "/links/" + link.ID.String(), token: token}); rec.Code != http.StatusNoContent {
+		t.Fatalf("delete status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	expect[any](app, testRequest{method: http.MethodGet, path: "/go-here"}, http.StatusNotFound)
+}
+
+func TestScheduledLink(t *testing.T) {
+	app := newTestApp(t)
+	token := app.register("Owner", "owner@example.com").Token
+
+	launch := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	past := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+	expect[any](app, testRequest{
+		method: http.MethodPost,
+		path:   "/links/create",
+		body:   gin.H{"original_url": "https://example.test/bad", "active_from": launch, "expired_at": past},
+		token:  token,
+	}, http.StatusBadRequest)
+
+	scheduled := app.createLink(token, gin.H{"original_url": "https://example.test/launch", "custom_short_code": "soon", "active_from": launch})
+	if scheduled.State != "scheduled" || scheduled.ActiveFrom == nil || !scheduled.ActiveFrom.Equal(launch) {
+		t.Fatalf("unexpected scheduled link: %+v", scheduled)
+	}
+	app.createLink(token, gin.H{
+		"original_url":      "https://example.test/launch",
+		"custom_short_code": "teaser",
+		"active_from":       launch,
+		"prelaunch_url":     "https://example.test/coming-soon",
+	})
+	app.createLink(token, gin.H{"original_url": "https://example.test/live", "custom_short_code": "live"})
+	app.createLink(token, gin.H{"original_url": "https://example.test/over", "custom_short_code": "over", "expired_at": past})
+
+	rec := app.do(testRequest{method: http.MethodGet, path: "/soon"})
+	var pending struct {
+		Error struct {
+			ActiveFrom time.Time `json:"active_from"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &pending); err != nil || rec.Code != http.StatusNotFound || !pending.Error.ActiveFrom.Equal(launch) {
+		t.Fatalf("not yet available: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	rec = app.do(testRequest{method: http.MethodGet, path: "/teaser"})
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != "https://example.test/coming-soon" {
+		t.Fatalf("pre-launch redirect: status = %d, location = %q", rec.Code, rec.Header().Get("Location"))
+	}
+
+	expect[any](app, testRequest{method: http.MethodGet, path: "/over"}, http.StatusGone)
+
+	if rec := app.do(testRequest{method: http.MethodGet, path: "/live"}); rec.Code != http.StatusFound {
+		t.Fatalf("active link: status = %d, want %d", rec.Code, http.StatusFound)
+	}
+	waitFor(t, func() bool { return app.redis.Exists("url:live") })
+	for _, code := range []string{"soon", "teaser", "over"} {
+		if app.redis.Exists("url:" + code) {
+			t.Fatalf("link %s outside its activation window was cached", code)
+		}
+	}
+
+	for state, want := range map[string][]string{
+		"scheduled": {"teaser", "soon"},
+		"active":    {"live"},
+		"expired":   {"over"},
+	} {
+		links := expect[[]responses.LinkResponse](app, testRequest{method: http.MethodGet, path: "/links/all?state=" + state, token: token}, http.StatusOK)
+		var codes []string
+		for _, link := range links {
+			codes = append(codes, *link.CustomShortCode)
+		}
+		if !slices.Equal(codes, want) {
+			t.Fatalf("links in state %s = %v, want %v", state, codes, want)
+		}
+	}
+	expect[any](app, testRequest{method: http.MethodGet, path: "/links/all?state=paused", token: token}, http.StatusBadRequest)
+
+	updated := expect[responses.LinkResponse](app, testRequest{
+		method: http.MethodPut,
+		path:   "/links/" + scheduled.ID.String(),
+		body:   gin.H{"original_url": "https://example.test/launch", "custom_short_code": "soon", "active_from": past},
+		token:  token,
+	}, http.StatusOK)
+	if updated.State != "active" {
+		t.Fatalf("state after moving active_from into the past = %q, want active", updated.State)
+	}
+	if rec := app.do(testRequest{method: http.MethodGet, path: "/soon"}); rec.Code != http.StatusFound {
+		t.Fatalf("launched link: status = %d, want %d", rec.Code, http.StatusFound)
+	}
+}
+
+func TestDeepLinks(t *testing.T) {
+	const (
+		androidUserAgent   = "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36"
+		instagramUserAgent = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 Instagram 309.0.0.0 (iPhone15,2; iOS 17_0; en_US)"
+		fingerprint        = "14:6D:E9:83:C5:73:06:50:D8:EE:B9:95:2F:34:FC:64:16:A0:83:42:E6:1D:BE:A8:8A:04:96:B2:3F:CF:44:E5"
+	)
+
+	app := newTestApp(t, func(cfg *config.Config) {
+		cfg.AppLinks.IOSAppIDs = []string{"ABCDE12345.test.shop"}
+		cfg.AppLinks.AndroidPackages = []string{"test.shop"}
+		cfg.AppLinks.AndroidCertFingerprints = []string{fingerprint}
+	})
+	owner := app.register("Owner", "owner@example.com")
+	token := owner.Token
+
+	rec := app.do(testRequest{method: http.MethodGet, path: "/.well-known/apple-app-site-association"})
+	var aasa responses.AppleAppSiteAssociation
+	if err := json.Unmarshal(rec.Body.Bytes(), &aasa); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("apple-app-site-association: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if len(aasa.AppLinks.Details) != 1 || aasa.AppLinks.Details[0].AppIDs[0] != "ABCDE12345.test.shop" || aasa.AppLinks.Details[0].Components[0]["/"] != "*" {
+		t.Fatalf("unexpected apple-app-site-association: %+v", aasa)
+	}
+	rec = app.do(testRequest{method: http.MethodGet, path: "/.well-known/assetlinks.json"})
+	var statements []responses.AssetLinkStatement
+	if err := json.Unmarshal(rec.Body.Bytes(), &statements); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("assetlinks.json: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if len(statements) != 1 || statements[0].Target.PackageName != "test.shop" || statements[0].Target.SHA256CertFingerprints[0] != fingerprint {
+		t.Fatalf("unexpected assetlinks.json: %+v", statements)
+	}
+
+	expect[any](app, testRequest{
+		method: http.MethodPost,
+		path:   "/links/create",
+		body:   gin.H{"original_url": "https://example.test/shoes", "ios_deep_link": "javascript:alert(1)"},
+		token:  token,
+	}, http.StatusBadRequest)
+
+	link := app.createLink(token, gin.H{
+		"original_url":      "https://example.test/shoes",
+		"custom_short_code": "shoes",
+		"ios_deep_link":     "shop://product/42",
+		"ios_store_url":     "https://apps.apple.test/app/shop",
+		"android_deep_link": "shop://product/42",
+	})
+	if link.IOSDeepLink == nil || *link.IOSDeepLink != "shop://product/42" || link.AndroidStoreURL != nil {
+		t.Fatalf("unexpected deep links: %+v", link)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		userAgent string
+		location  string
+	}{
+		{"iOS goes to the store", mobileUserAgent, "https://apps.apple.test/app/shop"},
+		{"Android without a store falls back to the original URL", androidUserAgent, "https://example.test/shoes"},
+		{"desktop", desktopUserAgent, "https://example.test/shoes"},
+	} {
+		rec := app.do(testRequest{method: http.MethodGet, path: "/shoes", userAgent: tc.userAgent})
+		if rec.Code != http.StatusFound || rec.Header().Get("Location") != tc.location {
+			t.Fatalf("%s: status = %d, location = %q", tc.name, rec.Code, rec.Header().Get("Location"))
+		}
+		if rec.Header().Get("Vary") != "User-Agent" {
+			t.Fatalf("%s: Vary = %q, want User-Agent", tc.name, rec.Header().Get("Vary"))
+		}
+	}
+
+	rec = app.do(testRequest{method: http.MethodGet, path: "/shoes", userAgent: instagramUserAgent})
+	body := rec.Body.String()
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("in-app browser: status = %d, content type = %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(body, `href="shop://product/42"`) || !strings.Contains(body, `location.replace("https://apps.apple.test/app/shop")`) || !strings.Contains(body, " 1500 ") {
+		t.Fatalf("in-app browser page does not open the app: %s", body)
+	}
+
+	if app.redis.Exists("url:shoes") {
+		t.Fatal("link with deep links was cached")
+	}
+	stored := expect[responses.LinkResponse](app, testRequest{method: http.MethodGet, path: "/links/" + link.ID.String(), token: token}, http.StatusOK)
+	if stored.ClickCount != 4 {
+		t.Fatalf("click count = %d, want 4", stored.ClickCount)
+	}
+
+	// The fallback of the in-app browser page must never run script.
+	const script = "javascript:alert(document.domain)"
+	expect[any](app, testRequest{
+		method: http.MethodPost,
+		path:   "/links/create",
+		body:   gin.H{"original_url": script, "ios_deep_link": "shop://product/42"},
+		token:  token,
+	}, http.StatusBadRequest)
+	expect[any](app, testRequest{
+		method: http.MethodPut,
+		path:   "/links/" + link.ID.String(),
+		body:   gin.H{"original_url": script, "custom_short_code": "shoes", "ios_deep_link": "shop://product/42"},
+		token:  token,
+	}, http.StatusBadRequest)
+
+	// Links saved before original_url had to be http(s) are escaped.
+	legacy := app.createLink(token, gin.H{"original_url": "https://example.test/legacy", "custom_short_code": "legacy", "ios_deep_link": "shop://product/7"})
+	_, err := app.store.UpdateLink(t.Context(), database.UpdateLinkParams{
+		ID:              legacy.ID,
+		UserID:          owner.User.ID,
+		OriginalUrl:     script,
+		CustomShortCode: sql.NullString{String: "legacy", Valid: true},
+		IosDeepLink:     sql.NullString{String: "shop://product/7", Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("store legacy link: %v", err)
+	}
+	rec = app.do(testRequest{method: http.MethodGet, path: "/legacy", userAgent: instagramUserAgent})
+	if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), "javascript:") {
+		t.Fatalf("in-app browser page runs the stored destination: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestLinkPreview(t *testing.T) {
+	app := newTestApp(t)
+	token := app.register("Owner", "owner@example.com").Token
+
+	docs := app.createLink(token, gin.H{"original_url": "https://example.test/docs", "custom_short_code": "guide"})
+	err := app.store.UpdateLinkMetadata(t.Context(), database.UpdateLinkMetadataParams{
+		ID:          docs.ID,
+		OriginalUrl: docs.OriginalURL,
+		MetaTitle:   sql.NullString{String: "Docs <script>", Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("update metadata: %v", err)
+	}
+
+	for _, path := range []string{"/guide+", "/guide/preview", "/" + docs.ShortCode + "+"} {
+		rec := app.do(testRequest{method: http.MethodGet, path: path})
+		body := rec.Body.String()
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Header().Get("Content-Type"), "text/html") {
+			t.Fatalf("%s: status = %d, content type = %q", path, rec.Code, rec.Header().Get("Content-Type"))
+		}
+		if !strings.Contains(body, "https://example.test/docs") || !strings.Contains(body, "Docs &lt;script&gt;") || !strings.Contains(body, "not been checked") {
+			t.Fatalf("%s: unexpected preview page: %s", path, body)
+		}
+		if !strings.Contains(body, `href="https://example.test/docs"`) {
+			t.Fatalf("%s: preview page has no continue link", path)
+		}
+	}
+	expect[any](app, testRequest{method: http.MethodGet, path: "/missing+"}, http.StatusNotFound)
+
+	takenDown := app.createLink(token, gin.H{"original_url": "https://example.test/scam", "custom_short_code": "scam"})
+	if _, err := app.store.TakeDownLink(t.Context(), database.TakeDownLinkParams{ID: takenDown.ID}); err != nil {
+		t.Fatalf("take down: %v", err)
+	}
+	rec := app.do(testRequest{method: http.MethodGet, path: "/scam+"})
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Blocked") || strings.Contains(rec.Body.String(), `href="https://example.test/scam"`) {
+		t.Fatalf("taken down preview: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	forced := app.createLink(token, gin.H{"original_url": "http://example.test/plain", "custom_short_code": "plain", "force_preview": true})
+	if !forced.ForcePreview {
+		t.Fatalf("force_preview not stored: %+v", forced)
+	}
+	for range 2 {
+		rec := app.do(testRequest{method: http.MethodGet, path: "/plain"})
+		body := rec.Body.String()
+		if rec.Code != http.StatusOK || !strings.Contains(body, "asks visitors") || !strings.Contains(body, "does not use HTTPS") {
+			t.Fatalf("forced preview: status = %d, body = %s", rec.Code, body)
+		}
+	}
+	if app.redis.Exists("url:plain") {
+		t.Fatal("link with a forced preview was cached")
+	}
+
+	for _, tc := range []struct {
+		link   responses.LinkResponse
+		clicks int64
+	}{{docs, 0}, {forced, 2}} {
+		link := expect[responses.LinkResponse](app, testRequest{method: http.MethodGet, path: "/links/" + tc.link.ID.String(), token: token}, http.StatusOK)
+		if link.ClickCount != tc.clicks {
+			t.Fatalf("link %s click count = %d, want %d", link.ShortCode, link.ClickCount, tc.clicks)
+		}
+	}
+}
+
+func TestPublicStats(t *testing.T) {
+	app := newTestApp(t)
+	token := app.register("Owner", "owner@example.com").Token
+	otherToken := app.register("Other", "other@example.com").Token
+
+	link := app.createLink(token, gin.H{"original_url": "https://example.test/partner", "custom_short_code": "partner"})
+	if link.PublicStats || link.StatsShareToken != nil {
+		t.Fatalf("new link has public stats: %+v", link)
+	}
+	settingsPath := "/links/" + link.ID.String() + "/public-stats"
+
+	expect[any](app, testRequest{method: http.MethodPut, path: settingsPath, body: gin.H{}, token: token}, http.StatusBadRequest)
+	expect[any](app, testRequest{method: http.MethodPut, path: settingsPath, body: gin.H{"enabled": true}, token: otherToken}, http.StatusNotFound)
+
+	settings := expect[responses.PublicStatsSettingsResponse](app, testRequest{method: http.MethodPut, path: settingsPath, body: gin.H{"enabled": true}, token: token}, http.StatusOK)
+	if !settings.Enabled || settings.ShareToken == nil || len(*settings.ShareToken) < 20 {
+		t.Fatalf("unexpected settings: %+v", settings)
+	}
+	shareToken := *settings.ShareToken
+
+	for _, userAgent := range []string{desktopUserAgent, desktopUserAgent, mobileUserAgent} {
+		app.do(testRequest{method: http.MethodGet, path: "/partner", userAgent: userAgent})
+	}
+
+	rec := app.do(testRequest{method: http.MethodGet, path: "/stats/" + shareToken})
+	if strings.Contains(rec.Body.String(), "192.0.2.1") || strings.Contains(rec.Body.String(), "Mozilla") {
+		t.Fatalf("public stats leak personal data: %s", rec.Body.String())
+	}
+	stats := expect[responses.PublicLinkStatsResponse](app, testRequest{method: http.MethodGet, path: "/stats/" + shareToken}, http.StatusOK)
+	if stats.ShortCode != link.ShortCode || stats.TotalClicks != 3 || stats.TimeRange != "all" {
+		t.Fatalf("unexpected public stats: %+v", stats)
+	}
+	if got := typeValues(stats.DeviceBreakdowns); got["desktop"] != 2 || got["mobile"] != 1 {
+		t.Fatalf("device breakdown = %v, want 2 desktop and 1 mobile", got)
+	}
+	if len(stats.Overviews) != 1 || stats.Overviews[0].Value != 3 {
+		t.Fatalf("overviews = %+v, want 3 clicks today", stats.Overviews)
+	}
+
+	// Served from the cache until it expires.
+	app.do(testRequest{method: http.MethodGet, path: "/partner"})
+	if stats := expect[responses.PublicLinkStatsResponse](app, testRequest{method: http.MethodGet, path: "/stats/" + shareToken}, http.StatusOK); stats.TotalClicks != 3 {
+		t.Fatalf("cached total clicks = %d, want 3", stats.TotalClicks)
+	}
+	if !app.redis.Exists("public_stats:" + shareToken + ":all") {
+		t.Fatal("public stats were not cached")
+	}
+
+	settings = expect[responses.PublicStatsSettingsResponse](app, testRequest{method: http.MethodPut, path: settingsPath, body: gin.H{"enabled": false}, token: token}, http.StatusOK)
+	if settings.Enabled || settings.ShareToken == nil || *settings.ShareToken != shareToken {
+		t.Fatalf("disabling changed the share token: %+v", settings)
+	}
+	expect[any](app, testRequest{method: http.MethodGet, path: "/stats/" + shareToken}, http.StatusNotFound)
+
+	expect[responses.PublicStatsSettingsResponse](app, testRequest{method: http.MethodPut, path: settingsPath, body: gin.H{"enabled": true}, token: token}, http.StatusOK)
+	if stats := expect[responses.PublicLinkStatsResponse](app, testRequest{method: http.MethodGet, path: "/stats/" + shareToken}, http.StatusOK); stats.TotalClicks != 4 {
+		t.Fatalf("total clicks after re-enabling = %d, want 4", stats.TotalClicks)
+	}
+
+	rotated := expect[responses.PublicStatsSettingsResponse](app, testRequest{method: http.MethodPost, path: settingsPath + "/rotate", token: token}, http.StatusOK)
+	if rotated.ShareToken == nil || *rotated.ShareToken == shareToken {
+		t.Fatalf("share token was not rotated: %+v", rotated)
+	}
+	expect[any](app, testRequest{method: http.MethodGet, path: "/stats/" + shareToken}, http.StatusNotFound)
+	expect[responses.PublicLinkStatsResponse](app, testRequest{method: http.MethodGet, path: "/stats/" + *rotated.ShareToken}, http.StatusOK)
+
+	owned := expect[responses.LinkResponse](app, testRequest{method: http.MethodGet, path: "/links/" + link.ID.String(), token: token}, http.StatusOK)
+	if !owned.PublicStats || owned.StatsShareToken == nil || *owned.StatsShareToken != *rotated.ShareToken {
+		t.Fatalf("link does not report its public stats: %+v", owned)
+	}
+
+	// Cached statistics disappear together with a taken down or deleted link.
+	adminToken := app.register("Admin", "admin@example.com").Token
+	if err := app.store.PromoteUsersToAdmin(t.Context(), []string{"admin@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	expect[any](app, testRequest{method: http.MethodPost, path: "/admin/links/" + link.ID.String() + "/takedown", body: gin.H{"reason": "phishing"}, token: adminToken}, http.StatusOK)
+	expect[any](app, testRequest{method: http.MethodGet, path: "/stats/" + *rotated.ShareToken}, http.StatusNotFound)
+
+	deleted := app.createLink(token, gin.H{"original_url": "https://example.test/short-lived"})
+	settings = expect[responses.PublicStatsSettingsResponse](app, testRequest{method: http.MethodPut, path: "/links/" + deleted.ID.String() + "/public-stats", body: gin.H{"enabled": true}, token: token}, http.StatusOK)
+	expect[responses.PublicLinkStatsResponse](app, testRequest{method: http.MethodGet, path: "/stats/" + *settings.ShareToken}, http.StatusOK)
+	expect[any](app, testRequest{method: http.MethodDelete, path: "/links/" + deleted.ID.String(), token: token}, http.StatusNoContent)
+	expect[any](app, testRequest{method: http.MethodGet, path: "/stats/" + *settings.ShareToken}, http.StatusNotFound)
+}
+
+func TestAnalytics(t *testing.T) {
+	app := newTestApp(t)
+	token := app.register("Owner", "owner@example.com").Token
+	otherToken := app.register("Other", "other@example.com").Token
+
+	popular := app.createLink(token, gin.H{"original_url": "https://example.test/popular"})
+	quiet := app.createLink(token, gin.H{"original_url": "https://example.test/quiet"})
+	app.createLink(otherToken, gin.H{"original_url": "https://example.test/elsewhere", "custom_short_code": "elsewhere"})
+
+	visits := []struct {
+		code      string
+		userAgent string
+	}{
+		{popular.ShortCode, desktopUserAgent},
+		{popular.ShortCode, desktopUserAgent},
+		{popular.ShortCode, mobileUserAgent},
+		{quiet.ShortCode, mobileUserAgent},
+		{"elsewhere", desktopUserAgent},
+	}
+	for _, visit := range visits {
+		if rec := app.do(testRequest{method: http.MethodGet, path: "/" + visit.code, userAgent: visit.userAgent}); rec.Code != http.StatusFound {
+			t.Fatalf("redirect %s: status = %d", visit.code, rec.Code)
+		}
+	}
+
+	analytics := expect[responses.AnalyticsResponse](app, testRequest{method: http.MethodGet, path: "/analytics/?range=7d", token: token}, http.StatusOK)
+	if analytics.TotalClicks != 4 || analytics.TotalActiveLinks != 2 {
+		t.Fatalf("total clicks = %d, active links = %d; want 4 and 2", analytics.TotalClicks, analytics.TotalActiveLinks)
+	}
+	if analytics.TopLink == nil || analytics.TopLink.Link.ID != popular.ID || analytics.TopLink.TotalClicks != 3 {
+		t.Fatalf("top link = %+v, want the popular link with 3 clicks", analytics.TopLink)
+	}
+	if got := typeValues(analytics.DeviceBreakdowns); got["desktop"] != 2 || got["mobile"] != 2 {
+		t.Fatalf("device breakdown = %v, want 2 desktop and 2 mobile", got)
+	}
+	if got := typeValues(analytics.TrafficSources); got["direct"] != 4 {
+		t.Fatalf("traffic sources = %v, want 4 direct", got)
+	}
+	if got := typeValues(analytics.TopRegions); len(got) != 1 || got["west java, id"] != 4 {
+		t.Fatalf("top regions = %v, want 4 from West Java, ID", got)
+	}
+	if got := typeValues(analytics.TopCities); len(got) != 1 || got["bandung, west java, id"] != 4 {
+		t.Fatalf("top cities = %v, want 4 from Bandung, West Java, ID", got)
+	}
+
+	link := expect[responses.LinkResponse](app, testRequest{method: http.MethodGet, path: "/links/" + popular.ID.String(), token: token}, http.StatusOK)
+	if link.ClickCount != 3 {
+		t.Fatalf("link click count = %d, want 3", link.ClickCount)
+	}
+	if got := typeValues(link.DeviceBreakdowns); got["desktop"] != 2 || got["mobile"] != 1 {
+		t.Fatalf("link device breakdown = %v, want 2 desktop and 1 mobile", got)
+	}
+
+	stats := expect[responses.LandingStatsResponse](app, testRequest{method: http.MethodGet, path: "/dashboard/stats"}, http.StatusOK)
+	if stats.TotalLinks != 3 || stats.TotalActiveUsers != 2 || stats.TotalClicks != 5 {
+		t.Fatalf("landing stats = %+v, want 3 links, 2 users and 5 clicks", stats)
+	}
+}
+
+func TestClickStream(t *testing.T) {
+	app := newTestApp(t)
+	owner := app.register("Owner", "owner@example.com")
+	other := app.register("Other", "other@example.com")
+
+	link := app.createLink(owner.Token, gin.H{"original_url": "https://example.test/streamed"})
+	otherLink := app.createLink(other.Token, gin.H{"original_url": "https://example.test/elsewhere"})
+
+	server := httptest.NewServer(app.router)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/analytics/stream", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+owner.Token)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK || !strings.HasPrefix(res.Header.Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("stream status = %d, content type = %q", res.StatusCode, res.Header.Get("Content-Type"))
+	}
+
+	channel := "clicks:" + owner.User.ID.String()
+	waitFor(t, func() bool { return app.redis.PubSubNumSub(channel)[channel] == 1 })
+
+	// Only the owner's own click shows up on the stream.
+	for _, code := range []string{otherLink.ShortCode, link.ShortCode} {
+		if rec := app.do(testRequest{method: http.MethodGet, path: "/" + code, userAgent: mobileUserAgent}); rec.Code != http.StatusFound {
+			t.Fatalf("redirect %s: status = %d", code, rec.Code)
+		}
+	}
+
+	var event responses.ClickEventResponse
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		if scanner.Text() != "event:click" {
+			continue
+		}
+		if !scanner.Scan() {
+			break
+		}
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			t.Fatalf("click event without data: %q", scanner.Text())
+		}
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			t.Fatalf("decode click event: %v", err)
+		}
+		break
+	}
+	if event.LinkID != link.ID || event.Code != link.ShortCode || event.DeviceType != "mobile" || event.Country != "ID" {
+		t.Fatalf("click event = %+v, want the owner's mobile click; stream error: %v", event, scanner.Err())
+	}
+}
+
+func TestCampaigns(t *testing.T) {
+	app := newTestApp(t)
+	token := app.register("Owner", "owner@example.com").Token
+	otherToken := app.register("Other", "other@example.com").Token
+
+	expect[any](app, testRequest{
+		method: http.MethodPost,
+		path:   "/campaigns",
+		body:   gin.H{"name": "Backwards", "starts_at": time.Now(), "ends_at": time.Now().Add(-time.Hour)},
+		token:  token,
+	}, http.StatusBadRequest)
+
+	campaign := expect[responses.CampaignResponse](app, testRequest{
+		method: http.MethodPost,
+		path:   "/campaigns",
+		body:   gin.H{"name": "Spring sale", "starts_at": time.Now().Add(-24 * time.Hour)},
+		token:  token,
+	}, http.StatusCreated)
+	path := "/campaigns/" + campaign.ID.String()
+
+	email := app.createLink(token, gin.H{"original_url": "https://example.test/sale", "custom_short_code": "sale-email"})
+	social := app.createLink(token, gin.H{"original_url": "https://example.test/sale", "custom_short_code": "sale-social"})
+	app.createLink(token, gin.H{"original_url": "https://example.test/other", "custom_short_code": "unrelated"})
+	foreign := app.createLink(otherToken, gin.H{"original_url": "https://example.test/foreign"})
+
+	expect[any](app, testRequest{
+		method: http.MethodPost,
+		path:   path + "/links",
+		body:   gin.H{"link_ids": []string{email.ID.String(), foreign.ID.String()}},
+		token:  token,
+	}, http.StatusNotFound)
+	expect[any](app, testRequest{method: http.MethodGet, path: path, token: otherToken}, http.StatusNotFound)
+
+	updated := expect[responses.CampaignResponse](app, testRequest{
+		method: http.MethodPost,
+		path:   path + "/links",
+		body:   gin.H{"link_ids": []string{email.ID.String(), social.ID.String()}},
+		token:  token,
+	}, http.StatusOK)
+	if updated.LinkCount != 2 {
+		t.Fatalf("link count = %d, want 2", updated.LinkCount)
+	}
+
+	for _, code := range []string{"sale-social", "sale-social", "sale-email", "unrelated"} {
+		if rec := app.do(testRequest{method: http.MethodGet, path: "/" + code, userAgent: mobileUserAgent}); rec.Code != http.StatusFound {
+			t.Fatalf("redirect %s: status = %d", code, rec.Code)
+		}
+	}
+
+	analytics := expect[responses.CampaignAnalyticsResponse](app, testRequest{method: http.MethodGet, path: path + "/analytics", token: token}, http.StatusOK)
+	if analytics.TotalClicks != 3 || analytics.TimeRange != "campaign" {
+		t.Fatalf("total clicks = %d in range %q, want 3 over the campaign", analytics.TotalClicks, analytics.TimeRange)
+	}
+	if got := typeValues(analytics.DeviceBreakdowns); got["mobile"] != 3 {
+		t.Fatalf("device breakdown = %v, want 3 mobile", got)
+	}
+	if len(analytics.Leaderboard) != 2 || analytics.Leaderboard[0].LinkID != social.ID || analytics.Leaderboard[0].TotalClicks != 2 {
+		t.Fatalf("leaderboard = %+v, want the social link first with 2 clicks", analytics.Leaderboard)
+	}
+
+	if rec := app.do(testRequest{method: http.MethodDelete, path: path + "/links/" + email.ID.String(), token: token}); rec.Code != http.StatusNoContent {
+		t.Fatalf("remove link: status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	expect[any](app, testRequest{method: http.MethodDelete, path: path + "/links/" + email.ID.String(), token: token}, http.StatusNotFound)
+
+	ended := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	expect[responses.CampaignResponse](app, testRequest{
+		method: http.MethodPut,
+		path:   path,
+		body:   gin.H{"name": "Spring sale", "ends_at": ended},
+		token:  token,
+	}, http.StatusOK)
+
+	cacheService := services.NewCacheService(app.rdb, services.CacheOptions{TTL: time.Minute, NegativeTTL: time.Minute})
+	services.NewCampaignService(app.store, app.store, cacheService).ExpireEnded(t.Context())
+
+	link := expect[responses.LinkResponse](app, testRequest{method: http.MethodGet, path: "/links/" + social.ID.String(), token: token}, http.StatusOK)
+	if link.ExpiredAt == nil || !link.ExpiredAt.Equal(ended) || link.State != "expired" {
+		t.Fatalf("campaign link after the end = %+v, want it expired at %s", link, ended)
+	}
+	if link := expect[responses.LinkResponse](app, testRequest{method: http.MethodGet, path: "/links/" + email.ID.String(), token: token}, http.StatusOK); link.ExpiredAt != nil {
+		t.Fatalf("link removed from the campaign expired at %s", link.ExpiredAt)
+	}
+	waitFor(t, func() bool {
+		return app.do(testRequest{method: http.MethodGet, path: "/sale-social"}).Code == http.StatusGone
+	})
+
+	campaigns := expect[[]responses.CampaignResponse](app, testRequest{method: http.MethodGet, path: "/campaigns", token: token}, http.StatusOK)
+	if len(campaigns) != 1 || campaigns[0].LinksExpiredAt == nil || campaigns[0].LinkCount != 1 {
+		t.Fatalf("campaigns = %+v, want one ended campaign with 1 link", campaigns)
+	}
+	expect[any](app, testRequest{method: http.MethodGet, path: "/campaigns?page=0", token: token}, http.StatusBadRequest)
+
+	if rec := app.do(testRequest{method: http.MethodDelete, path: path, token: token}); rec.Code != http.StatusNoContent {
+		t.Fatalf("delete campaign: status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if link := expect[responses.LinkResponse](app, testRequest{method: http.MethodGet, path: "/links/" + social.ID.String(), token: token}, http.StatusOK); link.CampaignID != nil {
+		t.Fatalf("link still belongs to deleted campaign %s", link.CampaignID)
+	}
+}
+
+func TestWebhooks(t *testing.T) {
+	app := newTestApp(t)
+	token := app.register("Owner", "owner@example.com").Token
+	events := []string{"link.created"}
+
+	for _, target := range []string{
+		"http://localhost:8080/hook",
+		"http://127.0.0.1/hook",
+		"http://10.0.0.5/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"http://[::ffff:192.168.1.1]/hook",
+		"ftp://hooks.example.test/hook",
+	} {
+		rec := app.do(testRequest{method: http.MethodPost, path: "/webhooks", body: gin.H{"url": target, "events": events}, token: token})
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("register %s: status = %d, want %d", target, rec.Code, http.StatusBadRequest)
+		}
+	}
+
+	webhook := expect[responses.WebhookResponse](app, testRequest{
+		method: http.MethodPost,
+		path:   "/webhooks",
+		body:   gin.H{"url": "https://hooks.example.test/pendekin", "events": events},
+		token:  token,
+	}, http.StatusCreated)
+	if webhook.Secret == "" || !webhook.IsActive {
+		t.Fatalf("unexpected webhook: %+v", webhook)
+	}
+
+	enabled := expect[responses.WebhookResponse](app, testRequest{method: http.MethodPost, path: "/webhooks/" + webhook.ID.String() + "/enable", token: token}, http.StatusOK)
+	if !enabled.IsActive || enabled.Secret != "" {
+		t.Fatalf("enabled webhook: %+v", enabled)
+	}
+
+	for i := range 3 {
+		app.createLink(token, gin.H{"original_url": "https://example.test/hooked/" + strconv.Itoa(i)})
+	}
+	deliveries := "/webhooks/" + webhook.ID.String() + "/deliveries"
+	for _, query := range []string{"?page=0", "?page=-1", "?page=x"} {
+		expect[any](app, testRequest{method: http.MethodGet, path: deliveries + query, token: token}, http.StatusBadRequest)
+	}
+	if got := expect[[]responses.WebhookDeliveryResponse](app, testRequest{method: http.MethodGet, path: deliveries + "?limit=100000", token: token}, http.StatusOK); len(got) != 3 {
+		t.Fatalf("deliveries = %d, want 3", len(got))
+	}
+	if got := expect[[]responses.WebhookDeliveryResponse](app, testRequest{method: http.MethodGet, path: deliveries + "?page=2&limit=2", token: token}, http.StatusOK); len(got) != 1 {
+		t.Fatalf("second page of deliveries = %d, want 1", len(got))
+	}
+}
+
+func TestHealth(t *testing.T) {
+	app := newTestApp(t)
+
+	expect[responses.HealthResponse](app, testRequest{method: http.MethodGet, path: "/healthz"}, http.StatusOK)
+
+	ready := expect[responses.HealthResponse](app, testRequest{method: http.MethodGet, path: "/readyz"}, http.StatusOK)
+	if ready.Status != "ok" {
+		t.Fatalf("readiness = %+v, want ok", ready)
+	}
+
+	app.redis.Close()
+
+	degraded := expect[responses.HealthResponse](app, testRequest{method: http.MethodGet, path: "/readyz"}, http.StatusOK)
+	if degraded.Status != "degraded" || degraded.Dependencies["redis"].Status != "down" {
+		t.Fatalf("readiness without redis = %+v, want degraded", degraded)
+	}
+}
+
+func TestProfileImage(t *testing.T) {
+	app := newTestApp(t)
+	token := app.register("Ada", "ada@example.com").Token
+
+	upload := func(data []byte, status int) responses.UserResponse {
+		t.Helper()
+
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, _ := form.CreateFormFile("profile_image", "avatar.gif")
+		part.Write(data)
+		form.Close()
+
+		return expect[responses.UserResponse](app, testRequest{
+			method:      http.MethodPut,
+			path:        "/auth/update-profile",
+			body:        &body,
+			contentType: form.FormDataContentType(),
+			token:       token,
+		}, status)
+	}
+
+	// A wide PNG with a file name claiming otherwise: the content decides.
+	var source bytes.Buffer
+	png.Encode(&source, image.NewRGBA(image.Rect(0, 0, 400, 300)))
+	first := upload(source.Bytes(), http.StatusOK)
+
+	if !strings.HasPrefix(first.ProfileImageUrl, "/uploads/profiles/"+first.ID.String()+"/") || !strings.HasSuffix(first.ProfileImageUrl, "/256.png") {
+		t.Fatalf("profile image url = %q", first.ProfileImageUrl)
+	}
+	for _, size := range []int{64, 128, 256} {
+		url := strings.TrimSuffix(first.ProfileImageUrl, "256.png") + strconv.Itoa(size) + ".png"
+		rec := app.do(testRequest{method: http.MethodGet, path: url})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: status = %d", url, rec.Code)
+		}
+		resized, _, err := image.Decode(rec.Body)
+		if err != nil || resized.Bounds().Dx() != size || resized.Bounds().Dy() != size {
+			t.Fatalf("GET %s: want a %dx%d image, got error %v", url, size, size, err)
+		}
+	}
+
+	source.Reset()
+	opaque := image.NewRGBA(image.Rect(0, 0, 50, 80))
+	draw.Draw(opaque, opaque.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	jpeg.Encode(&source, opaque, nil)
+	second := upload(source.Bytes(), http.StatusOK)
+
+	if !strings.HasSuffix(second.ProfileImageUrl, "/256.jpg") {
+		t.Fatalf("opaque images should be stored as JPEG, got %q", second.ProfileImageUrl)
+	}
+	if rec := app.do(testRequest{method: http.MethodGet, path: first.ProfileImageUrl}); rec.Code != http.StatusNotFound {
+		t.Fatalf("replaced image is still served: status %d", rec.Code)
+	}
+
+	upload([]byte("GIF89a but not really an image"), http.StatusBadRequest)
+	upload([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"), http.StatusBadRequest)
+	upload(append(source.Bytes(), make([]byte, 5<<20)...), http.StatusBadRequest)
+
+	me := expect[responses.UserResponse](app, testRequest{method: http.MethodGet, path: "/auth/me", token: token}, http.StatusOK)
+	if me.ProfileImageUrl != second.ProfileImageUrl {
+		t.Fatalf("rejected uploads changed the profile image to %q", me.ProfileImageUrl)
+	}
+}
+
+func typeValues(values []responses.TypeValue) map[string]int64 {
+	result := make(map[string]int64, len(values))
+	for _, value := range values {
+		result[strings.ToLower(value.Type)] = value.Value
+	}
+	return result
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDataExport(t *testing.T) {
+	app := newTestApp(t)
+	token := app.register("Ada", "ada@example.com").Token
+	app.createLink(token, gin.H{"original_url": "https://example.test/exported", "custom_short_code": "exported"})
+	app.do(testRequest{method: http.MethodGet, path: "/exported", userAgent: desktopUserAgent})
+
+	var export responses.DataExportResponse
+	waitFor(t, func() bool {
+		export = expect[responses.DataExportResponse](app, testRequest{method: http.MethodGet, path: "/account/export", token: token}, app.exportStatus(token))
+		return export.Status == "ready"
+	})
+	if export.DownloadURL == "" || export.SizeBytes == nil {
+		t.Fatalf("ready export without download details: %+v", export)
+	}
+
+	other := app.register("Other", "other@example.com").Token
+	expect[any](app, testRequest{method: http.MethodGet, path: export.DownloadURL, token: other}, http.StatusNotFound)
+
+	rec := app.do(testRequest{method: http.MethodGet, path: export.DownloadURL, token: token})
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/zip" {
+		t.Fatalf("download: status = %d, content type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	if err != nil {
+		t.Fatalf("open export: %v", err)
+	}
+	files := map[string]string{}
+	for _, file := range archive.File {
+		r, _ := file.Open()
+		content, _ := io.ReadAll(r)
+		r.Close()
+		files[file.Name] = string(content)
+	}
+	for _, name := range []string{"profile.json", "links.json", "links.csv", "click_logs.csv"} {
+		if _, ok := files[name]; !ok {
+			t.Fatalf("export is missing %s; has %v", name, slices.Collect(maps.Keys(files)))
+		}
+	}
+	if !strings.Contains(files["profile.json"], "ada@example.com") || !strings.Contains(files["links.json"], "https://example.test/exported") {
+		t.Fatalf("export content is incomplete: %v", files)
+	}
+	if lines := strings.Count(strings.TrimSpace(files["click_logs.csv"]), "\n"); lines != 1 {
+		t.Fatalf("click_logs.csv has %d rows, want 1:\n%s", lines, files["click_logs.csv"])
+	}
+	if !strings.Contains(files["click_logs.csv"], ",West Java,Bandung,7713") {
+		t.Fatalf("click_logs.csv is missing the click location:\n%s", files["click_logs.csv"])
+	}
+
+	// Exports are only handed out through the authenticated route.
+	if rec := app.do(testRequest{method: http.MethodGet, path: "/uploads/exports/"}); rec.Code != http.StatusNotFound {
+		t.Fatalf("exports are served statically: status %d", rec.Code)
+	}
+}
+
+// exportStatus is the status GET /account/export answers with for the
+// latest export of the user.
+func (a *testApp) exportStatus(token string) int {
+	rec := a.do(testRequest{method: http.MethodGet, path: "/account/export", token: token})
+	if rec.Code == http.StatusOK {
+		return http.StatusOK
+	}
+	return http.StatusAccepted
+}
+
+func TestAccountDeletion(t *testing.T) {
+	mailDir := t.TempDir()
+	app := newTestApp(t, func(cfg *config.Config) {
+		cfg.Mail.Driver = "file"
+		cfg.Mail.FileDir = mailDir
+	})
+	token := app.register("Ada", "ada@example.com").Token
+	link := app.createLink(token, gin.H{"original_url": "https://example.test/doomed"})
+	credentials := gin.H{"email": "ada@example.com", "password": "correct horse battery"}
+
+	expect[any](app, testRequest{method: http.MethodDelete, path: "/account", body: gin.H{"password": "wrong"}, token: token}, http.StatusUnauthorized)
+	activity := expect[[]responses.LoginAttemptResponse](app, testRequest{method: http.MethodGet, path: "/auth/activity", token: token}, http.StatusOK)
+	if len(activity) == 0 || activity[0].Success || activity[0].Reason != services.LoginReasonInvalidPassword {
+		t.Fatalf("activity after a wrong password = %+v, want the failure recorded", activity)
+	}
+
+	// Guessing the password of a stolen session is throttled like sign-ins.
+	other := app.register("Bob", "bob@example.com").Token
+	for range 3 {
+		expect[any](app, testRequest{method: http.MethodDelete, path: "/account", body: gin.H{"password": "wrong"}, token: other}, http.StatusUnauthorized)
+	}
+	expect[any](app, testRequest{method: http.MethodDelete, path: "/account", body: gin.H{"password": "correct horse battery"}, token: other}, http.StatusTooManyRequests)
+
+	deletion := expect[responses.AccountDeletionResponse](app, testRequest{
+		method: http.MethodDelete,
+		path:   "/account",
+		body:   gin.H{"password": "correct horse battery"},
+		token:  token,
+	}, http.StatusAccepted)
+	if time.Until(deletion.PurgeAfter) < 29*24*time.Hour {
+		t.Fatalf("purge_after = %s, want the 30 day grace period", deletion.PurgeAfter)
+	}
+
+	// The account is locked out right away, but its links keep redirecting
+	// until the purge so that restoring it loses nothing.
+	expect[any](app, testRequest{method: http.MethodPost, path: "/auth/login", body: credentials}, http.StatusUnauthorized)
+	expect[any](app, testRequest{method: http.MethodGet, path: "/links/all", token: token}, http.StatusUnauthorized)
+	if rec := app.do(testRequest{method: http.MethodGet, path: "/" + link.ShortCode}); rec.Code != http.StatusFound {
+		t.Fatalf("redirect during the grace period: status = %d, want 302", rec.Code)
+	}
+	restoreToken := app.mailedToken(mailDir, "/restore-account?token=")
+	expect[any](app, testRequest{method: http.MethodPost, path: "/account/restore", body: gin.H{"token": "bogus"}}, http.StatusBadRequest)
+	restored := expect[responses.UserResponse](app, testRequest{method: http.MethodPost, path: "/account/restore", body: gin.H{"token": restoreToken}}, http.StatusOK)
+	if restored.Email != "ada@example.com" {
+		t.Fatalf("restored user = %+v", restored)
+	}
+	expect[any](app, testRequest{method: http.MethodPost, path: "/account/restore", body: gin.H{"token": restoreToken}}, http.StatusBadRequest)
+	token = expect[responses.LoginResponse](app, testRequest{method: http.MethodPost, path: "/auth/login", body: credentials}, http.StatusOK).Token
+
+	// Clicks recorded under a custom code that was renamed since are purged
+	// as well, while other users keep theirs.
+	renamed := app.createLink(token, gin.H{"original_url": "https://example.test/launch", "custom_short_code": "ada-launch"})
+	bobLink := app.createLink(other, gin.H{"original_url": "https://example.test/bob"})
+	for _, code := range []string{"ada-launch", bobLink.ShortCode} {
+		if rec := app.do(testRequest{method: http.MethodGet, path: "/" + code}); rec.Code != http.StatusFound {
+			t.Fatalf("redirect of %s: status = %d, want 302", code, rec.Code)
+		}
+	}
+	expect[any](app, testRequest{
+		method: http.MethodPut,
+		path:   "/links/" + renamed.ID.String(),
+		body:   gin.H{"original_url": "https://example.test/launch", "custom_short_code": "ada-relaunch"},
+		token:  token,
+	}, http.StatusOK)
+
+	expect[any](app, testRequest{method: http.MethodDelete, path: "/account", body: gin.H{"password": "correct horse battery"}, token: token}, http.StatusAccepted)
+
+	blobStore := newBlobStore(app.cfg.Storage)
+	cacheService := services.NewCacheService(app.rdb, services.CacheOptions{TTL: time.Minute, NegativeTTL: time.Minute})
+	worker := services.NewAccountPurgeWorker(app.store, services.NewProfileImageService(blobStore, 1<<20), blobStore, cacheService, app.cfg.Privacy.DeletionGracePeriod)
+
+	due, err := app.store.GetDueAccountDeletions(t.Context(), database.GetDueAccountDeletionsParams{DeletedBefore: time.Now(), BatchSize: 10})
+	if err != nil || len(due) != 1 {
+		t.Fatalf("due deletions = %v, %v; want the deleted account", due, err)
+	}
+	if err := worker.Purge(t.Context(), due[0]); err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+
+	if codes, _ := app.store.GetUserLinkCodes(t.Context(), restored.ID); len(codes) != 0 {
+		t.Fatalf("links left after purge: %v", codes)
+	}
+	if _, err := app.store.GetUserByEmail(t.Context(), "ada@example.com"); err == nil {
+		t.Fatal("user still exists after purge")
+	}
+	if clicks, _ := app.store.GetGlobalTotalClicks(t.Context()); clicks != 1 {
+		t.Fatalf("clicks left after purge = %d, want only Bob's", clicks)
+	}
+	if rec := app.do(testRequest{method: http.MethodGet, path: "/" + link.ShortCode}); rec.Code != http.StatusNotFound {
+		t.Fatalf("redirect after purge: status = %d, want 404", rec.Code)
+	}
+	app.register("Ada", "ada@example.com")
+}
+
+// mailedToken returns the token of the most recent link with the given
+// prefix written by the file mailer.
+func (a *testApp) mailedToken(dir string, prefix string) string {
+	a.t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) == 0 {
+		a.t.Fatalf("no mail in %s: %v", dir, err)
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		content, err := os.ReadFile(filepath.Join(dir, entries[i].Name()))
+		if err != nil {
+			a.t.Fatalf("read mail: %v", err)
+		}
+		if _, rest, ok := strings.Cut(string(content), prefix); ok {
+			token, _, _ := strings.Cut(rest, "\n")
+			token, err = url.QueryUnescape(strings.TrimSpace(token))
+			if err != nil {
+				a.t.Fatalf("unescape token: %v", err)
+			}
+			return token
+		}
+	}
+	a.t.Fatalf("no mail contains %q", prefix)
+	return ""
+}
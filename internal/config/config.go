@@ -0,0 +1,324 @@
+package config
+
+import (
+	"time"
+
+	"github.com/andriawan24/link-short/internal/utils"
+)
+
+// Config holds every setting of the server. Each field can be set from a
+// config file using its dotted `config` path, from the environment variable
+// in its `env` tag, or from a flag named after the dotted path, with later
+// sources overriding earlier ones in that order.
+type Config struct {
+	App       AppConfig       `config:"app"`
+	HTTP      HTTPConfig      `config:"http"`
+	CORS      CORSConfig      `config:"cors"`
+	Database  DatabaseConfig  `config:"database"`
+	Redis     RedisConfig     `config:"redis"`
+	Cache     CacheConfig     `config:"cache"`
+	Breaker   BreakerConfig   `config:"circuit_breaker"`
+	Spool     SpoolConfig     `config:"click_spool"`
+	Auth      AuthConfig      `config:"auth"`
+	Google    GoogleConfig    `config:"google"`
+	Mail      MailConfig      `config:"mail"`
+	Storage   StorageConfig   `config:"storage"`
+	Privacy   PrivacyConfig   `config:"privacy"`
+	Retention RetentionConfig `config:"retention"`
+	ShortCode ShortCodeConfig `config:"short_code"`
+	AppLinks  AppLinksConfig  `config:"app_links"`
+	GeoIP     GeoIPConfig     `config:"geoip"`
+	Log       LogConfig       `config:"log"`
+	Tracing   TracingConfig   `config:"tracing"`
+}
+
+type AppConfig struct {
+	// BaseURL is the frontend origin used in emailed links.
+	BaseURL                  string   `config:"base_url" env:"APP_BASE_URL"`
+	RequireEmailVerification bool     `config:"require_email_verification" env:"REQUIRE_EMAIL_VERIFICATION"`
+	AdminEmails              []string `config:"admin_emails" env:"ADMIN_EMAILS"`
+}
+
+type HTTPConfig struct {
+	Port              int           `config:"port" env:"HTTP_PORT"`
+	ReadHeaderTimeout time.Duration `config:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	ReadTimeout       time.Duration `config:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	WriteTimeout      time.Duration `config:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout       time.Duration `config:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	ShutdownTimeout   time.Duration `config:"shutdown_timeout" env:"HTTP_SHUTDOWN_TIMEOUT"`
+}
+
+type CORSConfig struct {
+	AllowOrigins     []string      `config:"allow_origins" env:"CORS_ALLOW_ORIGINS"`
+	AllowCredentials bool          `config:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS"`
+	MaxAge           time.Duration `config:"max_age" env:"CORS_MAX_AGE"`
+}
+
+type DatabaseConfig struct {
+	Host            string        `config:"host" env:"DB_HOST"`
+	Port            int           `config:"port" env:"DB_PORT"`
+	User            string        `config:"user" env:"DB_USER"`
+	Password        string        `config:"password" env:"DB_PASSWORD"`
+	Name            string        `config:"name" env:"DB_NAME"`
+	SSLMode         string        `config:"sslmode" env:"DB_SSLMODE"`
+	MaxOpenConns    int           `config:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `config:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `config:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	ConnectTimeout  time.Duration `config:"connect_timeout" env:"DB_CONNECT_TIMEOUT"`
+}
+
+type RedisConfig struct {
+	Addr     string `config:"addr" env:"REDIS_ADDR"`
+	Password string `config:"password" env:"REDIS_PASSWORD"`
+	DB       int    `config:"db" env:"REDIS_DB"`
+	// PoolSize of 0 lets go-redis pick 10 connections per CPU.
+	PoolSize int `config:"pool_size" env:"REDIS_POOL_SIZE"`
+}
+
+type CacheConfig struct {
+	RedirectTTL time.Duration `config:"redirect_ttl" env:"CACHE_REDIRECT_TTL"`
+	// NegativeTTL is how long unknown codes are remembered as not found.
+	NegativeTTL time.Duration `config:"negative_ttl" env:"CACHE_NEGATIVE_TTL"`
+	LocalSize   int           `config:"local_size" env:"CACHE_LOCAL_SIZE"`
+	LocalTTL    time.Duration `config:"local_ttl" env:"CACHE_LOCAL_TTL"`
+	// PublicStatsTTL is how long public link statistics are served from
+	// Redis before they are computed again.
+	PublicStatsTTL time.Duration `config:"public_stats_ttl" env:"CACHE_PUBLIC_STATS_TTL"`
+}
+
+// BreakerConfig applies to the circuit breakers around Postgres and Redis.
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens a
+	// circuit. OpenTimeout is how long it stays open before a trial call.
+	FailureThreshold int           `config:"failure_threshold" env:"CIRCUIT_BREAKER_FAILURE_THRESHOLD"`
+	OpenTimeout      time.Duration `config:"open_timeout" env:"CIRCUIT_BREAKER_OPEN_TIMEOUT"`
+}
+
+// SpoolConfig controls where clicks are buffered while Postgres is down.
+type SpoolConfig struct {
+	Dir            string        `config:"dir" env:"CLICK_SPOOL_DIR"`
+	MaxBytes       int           `config:"max_bytes" env:"CLICK_SPOOL_MAX_BYTES"`
+	ReplayInterval time.Duration `config:"replay_interval" env:"CLICK_SPOOL_REPLAY_INTERVAL"`
+}
+
+type AuthConfig struct {
+	TokenSecret           string        `config:"token_secret" env:"TOKEN_SECRET"`
+	RefreshTokenSecret    string        `config:"refresh_token_secret" env:"REFRESH_TOKEN_SECRET"`
+	AccessTokenTTL        time.Duration `config:"access_token_ttl" env:"ACCESS_TOKEN_TTL"`
+	RefreshTokenTTL       time.Duration `config:"refresh_token_ttl" env:"REFRESH_TOKEN_TTL"`
+	TwoFactorChallengeTTL time.Duration `config:"two_factor_challenge_ttl" env:"TWO_FACTOR_CHALLENGE_TTL"`
+}
+
+type GoogleConfig struct {
+	ClientID     string `config:"client_id" env:"GOOGLE_CLIENT_ID"`
+	ClientSecret string `config:"client_secret" env:"GOOGLE_CLIENT_SECRET"`
+	CallbackURL  string `config:"callback_url" env:"GOOGLE_CALLBACK_URL"`
+}
+
+// Enabled reports whether Google sign-in is configured.
+func (c GoogleConfig) Enabled() bool {
+	return c.ClientID != "" || c.ClientSecret != "" || c.CallbackURL != ""
+}
+
+type MailConfig struct {
+	// Driver is one of smtp, file or memory.
+	Driver       string `config:"driver" env:"MAIL_DRIVER"`
+	From         string `config:"from" env:"MAIL_FROM"`
+	FileDir      string `config:"file_dir" env:"MAIL_FILE_DIR"`
+	SMTPHost     string `config:"smtp_host" env:"SMTP_HOST"`
+	SMTPPort     int    `config:"smtp_port" env:"SMTP_PORT"`
+	SMTPUsername string `config:"smtp_username" env:"SMTP_USERNAME"`
+	SMTPPassword string `config:"smtp_password" env:"SMTP_PASSWORD"`
+}
+
+// StorageConfig selects where uploaded profile images are kept.
+type StorageConfig struct {
+	// Driver is one of local or s3. Local files are served by this server
+	// under /uploads.
+	Driver   string `config:"driver" env:"STORAGE_DRIVER"`
+	LocalDir string `config:"local_dir" env:"STORAGE_LOCAL_DIR"`
+	// MaxUploadBytes caps the size of an uploaded image before resizing.
+	MaxUploadBytes    int    `config:"max_upload_bytes" env:"STORAGE_MAX_UPLOAD_BYTES"`
+	S3Endpoint        string `config:"s3_endpoint" env:"S3_ENDPOINT"`
+	S3Region          string `config:"s3_region" env:"S3_REGION"`
+	S3Bucket          string `config:"s3_bucket" env:"S3_BUCKET"`
+	S3AccessKeyID     string `config:"s3_access_key_id" env:"S3_ACCESS_KEY_ID"`
+	S3SecretAccessKey string `config:"s3_secret_access_key" env:"S3_SECRET_ACCESS_KEY"`
+	// S3PathStyle is needed by most self-hosted S3-compatible servers.
+	S3PathStyle bool `config:"s3_path_style" env:"S3_PATH_STYLE"`
+	// S3PublicURL is where stored objects are read from, e.g. a CDN.
+	// Defaults to the bucket URL.
+	S3PublicURL string `config:"s3_public_url" env:"S3_PUBLIC_URL"`
+}
+
+// PrivacyConfig controls personal data exports and account deletion.
+type PrivacyConfig struct {
+	// ExportTTL is how long a finished data export can be downloaded.
+	ExportTTL time.Duration `config:"export_ttl" env:"DATA_EXPORT_TTL"`
+	// DeletionGracePeriod is how long a deleted account can be restored
+	// before it is purged.
+	DeletionGracePeriod time.Duration `config:"deletion_grace_period" env:"ACCOUNT_DELETION_GRACE_PERIOD"`
+}
+
+// RetentionConfig limits how long raw click logs are kept.
+type RetentionConfig struct {
+	// ClickLogMonths is how many calendar months of click logs are kept,
+	// counting the current one. Older months are removed; 0 keeps them all.
+	ClickLogMonths int `config:"click_log_months" env:"CLICK_LOG_RETENTION_MONTHS"`
+	// ClickLogMode is drop, or archive to write each month to the blob
+	// storage as a gzipped CSV before it is removed.
+	ClickLogMode string `config:"click_log_mode" env:"CLICK_LOG_RETENTION_MODE"`
+	// IPAnonymizeAfter is how old a click gets before its IP address is
+	// truncated to the network it belongs to. 0 keeps full addresses.
+	IPAnonymizeAfter time.Duration `config:"ip_anonymize_after" env:"CLICK_LOG_IP_ANONYMIZE_AFTER"`
+}
+
+type ShortCodeConfig struct {
+	Length   int    `config:"length" env:"SHORT_CODE_LENGTH"`
+	Alphabet string `config:"alphabet" env:"SHORT_CODE_ALPHABET"`
+	// ReservedWords and BlockedWords extend the built-in lists.
+	ReservedWords []string `config:"reserved_words" env:"SHORT_CODE_RESERVED_WORDS"`
+	BlockedWords  []string `config:"blocked_words" env:"SHORT_CODE_BLOCKED_WORDS"`
+}
+
+// AppLinksConfig lists the mobile apps allowed to open short links directly,
+// published in /.well-known/apple-app-site-association and
+// /.well-known/assetlinks.json.
+type AppLinksConfig struct {
+	// IOSAppIDs are "<team ID>.<bundle ID>" pairs.
+	IOSAppIDs []string `config:"ios_app_ids" env:"APP_LINKS_IOS_APP_IDS"`
+	// IOSPaths limits the short link paths the iOS apps open.
+	IOSPaths        []string `config:"ios_paths" env:"APP_LINKS_IOS_PATHS"`
+	AndroidPackages []string `config:"android_packages" env:"APP_LINKS_ANDROID_PACKAGES"`
+	// AndroidCertFingerprints are the SHA-256 fingerprints of the signing
+	// certificates of the Android apps, as colon separated hex.
+	AndroidCertFingerprints []string `config:"android_cert_fingerprints" env:"APP_LINKS_ANDROID_CERT_FINGERPRINTS"`
+	// FallbackDelay is how long the in-app browser page waits for the app to
+	// open before it falls back to the store or the original URL.
+	FallbackDelay time.Duration `config:"fallback_delay" env:"APP_LINKS_FALLBACK_DELAY"`
+}
+
+type GeoIPConfig struct {
+	// CountryDatabasePath is the DB-IP country CSV, used when no MaxMind DB
+	// is configured. It only resolves countries.
+	CountryDatabasePath string `config:"country_database_path" env:"GEOIP_COUNTRY_DATABASE_PATH"`
+	// MMDBPath is a MaxMind DB with cities, such as GeoLite2-City. It takes
+	// precedence over the country CSV.
+	MMDBPath string `config:"mmdb_path" env:"GEOIP_MMDB_PATH"`
+	// ASNMMDBPath is an optional MaxMind DB with autonomous systems, such as
+	// GeoLite2-ASN.
+	ASNMMDBPath string `config:"asn_mmdb_path" env:"GEOIP_ASN_MMDB_PATH"`
+	// ReloadInterval is how often the MaxMind DB files are checked for
+	// updates.
+	ReloadInterval time.Duration `config:"reload_interval" env:"GEOIP_RELOAD_INTERVAL"`
+}
+
+type LogConfig struct {
+	// Level is one of debug, info, warn or error.
+	Level string `config:"level" env:"LOG_LEVEL"`
+}
+
+type TracingConfig struct {
+	// Exporter is one of none, otlp or stdout.
+	Exporter    string  `config:"exporter" env:"OTEL_TRACES_EXPORTER"`
+	ServiceName string  `config:"service_name" env:"OTEL_SERVICE_NAME"`
+	SampleRatio float64 `config:"sample_ratio" env:"OTEL_TRACES_SAMPLER_ARG"`
+}
+
+func Default() Config {
+	return Config{
+		App: AppConfig{
+			BaseURL: "http://localhost:3000",
+		},
+		HTTP: HTTPConfig{
+			Port:              8080,
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       10 * time.Second,
+			WriteTimeout:      15 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			ShutdownTimeout:   10 * time.Second,
+		},
+		CORS: CORSConfig{
+			AllowOrigins:     []string{"*"},
+			AllowCredentials: true,
+			MaxAge:           12 * time.Hour,
+		},
+		Database: DatabaseConfig{
+			Host:            "localhost",
+			Port:            5432,
+			User:            "postgres",
+			Password:        "postgres",
+			Name:            "link-short",
+			SSLMode:         "disable",
+			MaxOpenConns:    25,
+			MaxIdleConns:    25,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnectTimeout:  5 * time.Second,
+		},
+		Redis: RedisConfig{
+			Addr: "localhost:6379",
+		},
+		Cache: CacheConfig{
+			RedirectTTL:    24 * time.Hour,
+			NegativeTTL:    5 * time.Minute,
+			LocalSize:      10000,
+			LocalTTL:       time.Minute,
+			PublicStatsTTL: 5 * time.Minute,
+		},
+		Breaker: BreakerConfig{
+			FailureThreshold: 5,
+			OpenTimeout:      30 * time.Second,
+		},
+		Spool: SpoolConfig{
+			Dir:            "tmp/click-spool",
+			MaxBytes:       100 << 20,
+			ReplayInterval: 15 * time.Second,
+		},
+		Auth: AuthConfig{
+			AccessTokenTTL:        24 * time.Hour,
+			RefreshTokenTTL:       30 * 24 * time.Hour,
+			TwoFactorChallengeTTL: 5 * time.Minute,
+		},
+		Mail: MailConfig{
+			Driver:   "file",
+			From:     "Pendek.in <no-reply@pendek.in>",
+			FileDir:  "tmp/mails",
+			SMTPHost: "localhost",
+			SMTPPort: 587,
+		},
+		Storage: StorageConfig{
+			Driver:         "local",
+			LocalDir:       "uploads",
+			MaxUploadBytes: 5 << 20,
+			S3Region:       "us-east-1",
+		},
+		Privacy: PrivacyConfig{
+			ExportTTL:           7 * 24 * time.Hour,
+			DeletionGracePeriod: 30 * 24 * time.Hour,
+		},
+		Retention: RetentionConfig{
+			ClickLogMode:     "archive",
+			IPAnonymizeAfter: 90 * 24 * time.Hour,
+		},
+		ShortCode: ShortCodeConfig{
+			Length:   utils.DefaultShortCodeLength,
+			Alphabet: utils.DefaultShortCodeAlphabet,
+		},
+		AppLinks: AppLinksConfig{
+			IOSPaths:      []string{"*"},
+			FallbackDelay: 1500 * time.Millisecond,
+		},
+		GeoIP: GeoIPConfig{
+			CountryDatabasePath: "internal/sources/dbip-country.csv",
+			ReloadInterval:      time.Minute,
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			ServiceName: "pendek-in",
+			SampleRatio: 1,
+		},
+	}
+}
@@ -0,0 +1,240 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"go.yaml.in/yaml/v3"
+)
+
+// field is a single setting reachable through Config.
+type field struct {
+	path  string
+	env   string
+	value reflect.Value
+}
+
+// Load builds the configuration from the defaults, the config file named by
+// the -config flag or CONFIG_FILE, the environment and finally the remaining
+// flags in args, then validates it. Empty environment variables are treated
+// as unset so a blank .env entry keeps the default.
+func Load(args []string) (Config, error) {
+	config := Default()
+	fields := collectFields(reflect.ValueOf(&config).Elem(), "")
+
+	flagSet := flag.NewFlagSet("link-short", flag.ContinueOnError)
+	configFile := flagSet.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML or TOML config file")
+
+	// Flags are parsed up front to find the config file but applied last.
+	flagValues := make(map[string]string)
+	for _, f := range fields {
+		flagSet.Func(f.path, fmt.Sprintf("%s (env %s)", f.path, f.env), func(value string) error {
+			flagValues[f.path] = value
+			return nil
+		})
+	}
+
+	if err := flagSet.Parse(args); err != nil {
+		return config, err
+	}
+
+	var errs []error
+
+	if *configFile != "" {
+		fileValues, err := readFile(*configFile)
+		if err != nil {
+			return config, err
+		}
+
+		known := make(map[string]bool, len(fields))
+		for _, f := range fields {
+			known[f.path] = true
+		}
+		for path := range fileValues {
+			if !known[path] {
+				errs = append(errs, fmt.Errorf("%s: unknown setting %q", *configFile, path))
+			}
+		}
+
+		errs = append(errs, apply(fields, func(f field) (string, bool) {
+			value, ok := fileValues[f.path]
+			return value, ok
+		}, *configFile)...)
+	}
+
+	errs = append(errs, apply(fields, func(f field) (string, bool) {
+		value := os.Getenv(f.env)
+		return value, value != ""
+	}, "environment")...)
+
+	errs = append(errs, apply(fields, func(f field) (string, bool) {
+		value, ok := flagValues[f.path]
+		return value, ok
+	}, "flag")...)
+
+	if len(errs) > 0 {
+		return config, errors.Join(errs...)
+	}
+
+	return config, config.Validate()
+}
+
+func apply(fields []field, lookup func(field) (string, bool), source string) []error {
+	var errs []error
+	for _, f := range fields {
+		raw, ok := lookup(f)
+		if !ok {
+			continue
+		}
+
+		if err := setValue(f.value, raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid %s (%s) %q: %w", source, f.path, f.env, raw, err))
+		}
+	}
+	return errs
+}
+
+func collectFields(value reflect.Value, prefix string) []field {
+	var fields []field
+
+	for i := range value.NumField() {
+		structField := value.Type().Field(i)
+		path := structField.Tag.Get("config")
+		if path == "" {
+			continue
+		}
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+
+		if structField.Type.Kind() == reflect.Struct && structField.Type != reflect.TypeFor[time.Duration]() {
+			fields = append(fields, collectFields(value.Field(i), path)...)
+			continue
+		}
+
+		fields = append(fields, field{
+			path:  path,
+			env:   structField.Tag.Get("env"),
+			value: value.Field(i),
+		})
+	}
+
+	return fields
+}
+
+func setValue(value reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	if value.Type() == reflect.TypeFor[time.Duration]() {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(duration))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errors.New("must be true or false")
+		}
+		value.SetBool(parsed)
+	case reflect.Int:
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return errors.New("must be a whole number")
+		}
+		value.SetInt(int64(parsed))
+	case reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		value.SetFloat(parsed)
+	case reflect.Slice:
+		value.Set(reflect.ValueOf(splitList(raw)))
+	default:
+		return fmt.Errorf("unsupported setting type %s", value.Type())
+	}
+
+	return nil
+}
+
+// readFile flattens a YAML or TOML file into dotted paths mapped to the
+// same string form used by environment variables.
+func readFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	document := make(map[string]any)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &document)
+	case ".toml":
+		err = toml.Unmarshal(content, &document)
+	default:
+		return nil, fmt.Errorf("config file %s must end in .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	flatten(document, "", values)
+	return values, nil
+}
+
+func flatten(document map[string]any, prefix string, values map[string]string) {
+	keys := make([]string, 0, len(document))
+	for key := range document {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		switch value := document[key].(type) {
+		case map[string]any:
+			flatten(value, path, values)
+		case []any:
+			items := make([]string, 0, len(value))
+			for _, item := range value {
+				items = append(items, fmt.Sprint(item))
+			}
+			values[path] = strings.Join(items, ",")
+		case nil:
+			values[path] = ""
+		default:
+			values[path] = fmt.Sprint(value)
+		}
+	}
+}
+
+func splitList(value string) []string {
+	items := []string{}
+	for item := range strings.SplitSeq(value, ",") {
+		if trimmed := strings.TrimSpace(item); trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
@@ -0,0 +1,240 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setRequiredEnv provides the settings without a default so Load validates.
+func setRequiredEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("TOKEN_SECRET", "access secret")
+	t.Setenv("REFRESH_TOKEN_SECRET", "refresh secret")
+}
+
+// writeConfigFile writes content to a file with the given name in a
+// temporary directory and returns its path.
+func writeConfigFile(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := "http:\n  port: 8081\nlog:\n  level: warn\n"
+
+	for _, tc := range []struct {
+		name string
+		file bool
+		env  string
+		args []string
+		want int
+	}{
+		{name: "default", want: 8080},
+		{name: "file over default", file: true, want: 8081},
+		{name: "env over file", file: true, env: "8082", want: 8082},
+		{name: "empty env keeps file", file: true, env: "", want: 8081},
+		{name: "flag over env", file: true, env: "8082", args: []string{"-http.port", "8083"}, want: 8083},
+		{name: "flag without file", args: []string{"-http.port=8084"}, want: 8084},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("HTTP_PORT", tc.env)
+			t.Setenv("LOG_LEVEL", "")
+
+			args := tc.args
+			if tc.file {
+				args = append([]string{"-config", writeConfigFile(t, "config.yaml", file)}, args...)
+			}
+
+			config, err := Load(args)
+			if err != nil {
+				t.Fatalf("Load(%q): %v", args, err)
+			}
+			if config.HTTP.Port != tc.want {
+				t.Fatalf("http.port = %d, want %d", config.HTTP.Port, tc.want)
+			}
+			if wantLevel := map[bool]string{false: "info", true: "warn"}[tc.file]; config.Log.Level != wantLevel {
+				t.Fatalf("log.level = %q, want %q", config.Log.Level, wantLevel)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFileFromEnv(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("CONFIG_FILE", writeConfigFile(t, "config.toml", "[http]\nport = 9090\n"))
+
+	config, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if config.HTTP.Port != 9090 {
+		t.Fatalf("http.port = %d, want 9090", config.HTTP.Port)
+	}
+}
+
+func TestLoadParsesValues(t *testing.T) {
+	yamlFile := `
+http:
+  read_timeout: 1m30s
+cors:
+  allow_origins:
+    - https://a.example
+    - https://b.example
+app:
+  require_email_verification: true
+tracing:
+  sample_ratio: 0.25
+`
+	tomlFile := `
+[http]
+read_timeout = "1m30s"
+
+[cors]
+allow_origins = ["https://a.example", "https://b.example"]
+
+[app]
+require_email_verification = true
+
+[tracing]
+sample_ratio = 0.25
+`
+	env := map[string]string{
+		"HTTP_READ_TIMEOUT":          "1m30s",
+		"CORS_ALLOW_ORIGINS":         " https://a.example ,,https://b.example, ",
+		"REQUIRE_EMAIL_VERIFICATION": "true",
+		"OTEL_TRACES_SAMPLER_ARG":    "0.25",
+	}
+
+	for _, tc := range []struct {
+		name string
+		args func(t *testing.T) []string
+		env  map[string]string
+	}{
+		{name: "yaml", args: func(t *testing.T) []string { return []string{"-config", writeConfigFile(t, "config.yml", yamlFile)} }},
+		{name: "toml", args: func(t *testing.T) []string { return []string{"-config", writeConfigFile(t, "config.toml", tomlFile)} }},
+		{name: "env", args: func(t *testing.T) []string { return nil }, env: env},
+		{name: "flags", args: func(t *testing.T) []string {
+			return []string{
+				"-http.read_timeout", "1m30s",
+				"-cors.allow_origins", "https://a.example,https://b.example",
+				"-app.require_email_verification", "true",
+				"-tracing.sample_ratio", "0.25",
+			}
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			for key := range env {
+				t.Setenv(key, tc.env[key])
+			}
+
+			config, err := Load(tc.args(t))
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if config.HTTP.ReadTimeout != 90*time.Second {
+				t.Errorf("http.read_timeout = %s, want 1m30s", config.HTTP.ReadTimeout)
+			}
+			if want := []string{"https://a.example", "https://b.example"}; !reflect.DeepEqual(config.CORS.AllowOrigins, want) {
+				t.Errorf("cors.allow_origins = %q, want %q", config.CORS.AllowOrigins, want)
+			}
+			if !config.App.RequireEmailVerification {
+				t.Error("app.require_email_verification = false, want true")
+			}
+			if config.Tracing.SampleRatio != 0.25 {
+				t.Errorf("tracing.sample_ratio = %v, want 0.25", config.Tracing.SampleRatio)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsBadInput(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		fileName string
+		file     string
+		env      map[string]string
+		args     []string
+		want     []string
+	}{
+		{
+			name: "unknown file key",
+			file: "http:\n  prot: 8081\nredis:\n  addr: localhost:6380\n",
+			want: []string{`unknown setting "http.prot"`},
+		},
+		{
+			name:     "unknown section",
+			fileName: "config.toml",
+			file:     "[storag]\ndriver = \"s3\"\n",
+			want:     []string{`unknown setting "storag.driver"`},
+		},
+		{
+			name: "unknown flag",
+			args: []string{"-http.prot", "8081"},
+			want: []string{"http.prot"},
+		},
+		{
+			name: "bad duration",
+			env:  map[string]string{"HTTP_READ_TIMEOUT": "10"},
+			want: []string{"environment: invalid http.read_timeout (HTTP_READ_TIMEOUT)"},
+		},
+		{
+			name: "bad values from every source",
+			file: "http:\n  port: eighty\n",
+			env:  map[string]string{"CACHE_LOCAL_TTL": "soon"},
+			args: []string{"-app.require_email_verification", "maybe"},
+			want: []string{
+				"invalid http.port (HTTP_PORT) \"eighty\": must be a whole number",
+				"environment: invalid cache.local_ttl (CACHE_LOCAL_TTL)",
+				"flag: invalid app.require_email_verification (REQUIRE_EMAIL_VERIFICATION) \"maybe\": must be true or false",
+			},
+		},
+		{
+			name:     "unsupported file type",
+			fileName: "config.json",
+			file:     "{}",
+			want:     []string{"must end in .yaml, .yml or .toml"},
+		},
+		{
+			name: "fails validation",
+			env:  map[string]string{"HTTP_PORT": "0"},
+			want: []string{"http.port (HTTP_PORT) must be between 1 and 65535"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			args := tc.args
+			if tc.file != "" {
+				name := tc.fileName
+				if name == "" {
+					name = "config.yaml"
+				}
+				args = append([]string{"-config", writeConfigFile(t, name, tc.file)}, args...)
+			}
+
+			_, err := Load(args)
+			if err == nil {
+				t.Fatalf("Load(%q) succeeded, want an error", args)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Validate reports every problem at once so a misconfigured deployment can
+// be fixed in a single pass.
+func (c Config) Validate() error {
+	var problems []string
+	problem := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.Auth.TokenSecret == "" {
+		problem("auth.token_secret (TOKEN_SECRET) is required")
+	}
+	if c.Auth.RefreshTokenSecret == "" {
+		problem("auth.refresh_token_secret (REFRESH_TOKEN_SECRET) is required")
+	}
+	if c.Auth.TokenSecret != "" && c.Auth.TokenSecret == c.Auth.RefreshTokenSecret {
+		problem("auth.refresh_token_secret (REFRESH_TOKEN_SECRET) must differ from auth.token_secret (TOKEN_SECRET)")
+	}
+	if c.Auth.AccessTokenTTL <= 0 || c.Auth.RefreshTokenTTL <= 0 || c.Auth.TwoFactorChallengeTTL <= 0 {
+		problem("auth token TTLs must be positive")
+	}
+
+	if c.Google.Enabled() && (c.Google.ClientID == "" || c.Google.ClientSecret == "" || c.Google.CallbackURL == "") {
+		problem("google.client_id, google.client_secret and google.callback_url (GOOGLE_CLIENT_ID, GOOGLE_CLIENT_SECRET, GOOGLE_CALLBACK_URL) must be set together")
+	}
+
+	if c.HTTP.Port < 1 || c.HTTP.Port > 65535 {
+		problem("http.port (HTTP_PORT) must be between 1 and 65535")
+	}
+
+	if c.Database.MaxOpenConns < 1 {
+		problem("database.max_open_conns (DB_MAX_OPEN_CONNS) must be at least 1")
+	}
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		problem("database.max_idle_conns (DB_MAX_IDLE_CONNS) must be between 0 and database.max_open_conns")
+	}
+	if c.Redis.PoolSize < 0 {
+		problem("redis.pool_size (REDIS_POOL_SIZE) must not be negative")
+	}
+	if c.Cache.RedirectTTL <= 0 || c.Cache.NegativeTTL <= 0 || c.Cache.LocalTTL <= 0 || c.Cache.PublicStatsTTL <= 0 {
+		problem("cache.redirect_ttl, cache.negative_ttl, cache.local_ttl and cache.public_stats_ttl must be positive")
+	}
+	if c.Cache.LocalSize < 1 {
+		problem("cache.local_size (CACHE_LOCAL_SIZE) must be at least 1")
+	}
+
+	if c.Breaker.FailureThreshold < 1 {
+		problem("circuit_breaker.failure_threshold (CIRCUIT_BREAKER_FAILURE_THRESHOLD) must be at least 1")
+	}
+	if c.Breaker.OpenTimeout <= 0 {
+		problem("circuit_breaker.open_timeout (CIRCUIT_BREAKER_OPEN_TIMEOUT) must be positive")
+	}
+	if c.Spool.Dir == "" {
+		problem("click_spool.dir (CLICK_SPOOL_DIR) is required")
+	}
+	if c.Spool.MaxBytes < 1 {
+		problem("click_spool.max_bytes (CLICK_SPOOL_MAX_BYTES) must be at least 1")
+	}
+	if c.Spool.ReplayInterval <= 0 {
+		problem("click_spool.replay_interval (CLICK_SPOOL_REPLAY_INTERVAL) must be positive")
+	}
+
+	if !slices.Contains([]string{"smtp", "file", "memory"}, c.Mail.Driver) {
+		problem("mail.driver (MAIL_DRIVER) must be one of smtp, file or memory")
+	}
+
+	switch c.Storage.Driver {
+	case "local":
+		if c.Storage.LocalDir == "" {
+			problem("storage.local_dir (STORAGE_LOCAL_DIR) is required when storage.driver is local")
+		}
+	case "s3":
+		if c.Storage.S3Endpoint == "" || c.Storage.S3Bucket == "" || c.Storage.S3Region == "" {
+			problem("storage.s3_endpoint, storage.s3_bucket and storage.s3_region (S3_ENDPOINT, S3_BUCKET, S3_REGION) are required when storage.driver is s3")
+		}
+		if endpoint, err := url.Parse(c.Storage.S3Endpoint); c.Storage.S3Endpoint != "" && (err != nil || endpoint.Scheme == "" || endpoint.Host == "") {
+			problem("storage.s3_endpoint (S3_ENDPOINT) must be an absolute URL")
+		}
+		if c.Storage.S3AccessKeyID == "" || c.Storage.S3SecretAccessKey == "" {
+			problem("storage.s3_access_key_id and storage.s3_secret_access_key (S3_ACCESS_KEY_ID, S3_SECRET_ACCESS_KEY) are required when storage.driver is s3")
+		}
+	default:
+		problem("storage.driver (STORAGE_DRIVER) must be one of local or s3")
+	}
+	if c.Storage.MaxUploadBytes < 1 {
+		problem("storage.max_upload_bytes (STORAGE_MAX_UPLOAD_BYTES) must be at least 1")
+	}
+
+	if c.Privacy.ExportTTL <= 0 {
+		problem("privacy.export_ttl (DATA_EXPORT_TTL) must be positive")
+	}
+	if c.Privacy.DeletionGracePeriod <= 0 {
+		problem("privacy.deletion_grace_period (ACCOUNT_DELETION_GRACE_PERIOD) must be positive")
+	}
+
+	if c.Retention.ClickLogMonths < 0 {
+		problem("retention.click_log_months (CLICK_LOG_RETENTION_MONTHS) must not be negative")
+	}
+	if !slices.Contains([]string{"drop", "archive"}, c.Retention.ClickLogMode) {
+		problem("retention.click_log_mode (CLICK_LOG_RETENTION_MODE) must be one of drop or archive")
+	}
+	if c.Retention.IPAnonymizeAfter < 0 {
+		problem("retention.ip_anonymize_after (CLICK_LOG_IP_ANONYMIZE_AFTER) must not be negative")
+	}
+
+	if c.ShortCode.Length < 4 {
+		problem("short_code.length (SHORT_CODE_LENGTH) must be at least 4")
+	}
+	if len(c.ShortCode.Alphabet) < 2 {
+		problem("short_code.alphabet (SHORT_CODE_ALPHABET) must have at least 2 characters")
+	}
+
+	for _, appID := range c.AppLinks.IOSAppIDs {
+		if team, bundle, ok := strings.Cut(appID, "."); !ok || team == "" || bundle == "" {
+			problem("app_links.ios_app_ids (APP_LINKS_IOS_APP_IDS) entry %q must look like <team ID>.<bundle ID>", appID)
+		}
+	}
+	if len(c.AppLinks.IOSAppIDs) > 0 && len(c.AppLinks.IOSPaths) == 0 {
+		problem("app_links.ios_paths (APP_LINKS_IOS_PATHS) must not be empty when iOS apps are configured")
+	}
+	if len(c.AppLinks.AndroidPackages) > 0 && len(c.AppLinks.AndroidCertFingerprints) == 0 {
+		problem("app_links.android_cert_fingerprints (APP_LINKS_ANDROID_CERT_FINGERPRINTS) is required when Android packages are configured")
+	}
+	for _, fingerprint := range c.AppLinks.AndroidCertFingerprints {
+		if !validCertFingerprint(fingerprint) {
+			problem("app_links.android_cert_fingerprints (APP_LINKS_ANDROID_CERT_FINGERPRINTS) entry %q must be 32 colon separated hex bytes", fingerprint)
+		}
+	}
+	if c.AppLinks.FallbackDelay <= 0 {
+		problem("app_links.fallback_delay (APP_LINKS_FALLBACK_DELAY) must be positive")
+	}
+
+	if c.GeoIP.CountryDatabasePath == "" && c.GeoIP.MMDBPath == "" {
+		problem("geoip.country_database_path (GEOIP_COUNTRY_DATABASE_PATH) is required unless geoip.mmdb_path (GEOIP_MMDB_PATH) is set")
+	}
+	if c.GeoIP.ASNMMDBPath != "" && c.GeoIP.MMDBPath == "" {
+		problem("geoip.asn_mmdb_path (GEOIP_ASN_MMDB_PATH) requires geoip.mmdb_path (GEOIP_MMDB_PATH)")
+	}
+	if c.GeoIP.ReloadInterval <= 0 {
+		problem("geoip.reload_interval (GEOIP_RELOAD_INTERVAL) must be positive")
+	}
+
+	if !slices.Contains([]string{"debug", "info", "warn", "error"}, strings.ToLower(c.Log.Level)) {
+		problem("log.level (LOG_LEVEL) must be one of debug, info, warn or error")
+	}
+
+	if !slices.Contains([]string{"none", "otlp", "stdout"}, strings.ToLower(c.Tracing.Exporter)) {
+		problem("tracing.exporter (OTEL_TRACES_EXPORTER) must be one of none, otlp or stdout")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		problem("tracing.sample_ratio (OTEL_TRACES_SAMPLER_ARG) must be between 0 and 1")
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return errors.New("invalid configuration:\n  - " + strings.Join(problems, "\n  - "))
+}
+
+// validCertFingerprint reports whether s is a SHA-256 fingerprint written as
+// 32 colon separated hex bytes, the form assetlinks.json expects.
+func validCertFingerprint(s string) bool {
+	parts := strings.Split(s, ":")
+	if len(parts) != 32 {
+		return false
+	}
+	for _, part := range parts {
+		if _, err := hex.DecodeString(part); err != nil || len(part) != 2 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func validConfig() Config {
+	config := Default()
+	config.Auth.TokenSecret = "access secret"
+	config.Auth.RefreshTokenSecret = "refresh secret"
+	return config
+}
+
+func TestValidate(t *testing.T) {
+	fingerprint := strings.TrimSuffix(strings.Repeat("AB:", 32), ":")
+
+	for _, tc := range []struct {
+		name   string
+		change func(c *Config)
+		want   string
+	}{
+		{"missing token secret", func(c *Config) { c.Auth.TokenSecret = "" }, "auth.token_secret (TOKEN_SECRET) is required"},
+		{"missing refresh secret", func(c *Config) { c.Auth.RefreshTokenSecret = "" }, "auth.refresh_token_secret (REFRESH_TOKEN_SECRET) is required"},
+		{"shared secret", func(c *Config) { c.Auth.RefreshTokenSecret = c.Auth.TokenSecret }, "must differ from auth.token_secret"},
+		{"token ttl", func(c *Config) { c.Auth.TwoFactorChallengeTTL = 0 }, "auth token TTLs must be positive"},
+		{"partial google", func(c *Config) { c.Google.ClientID = "id" }, "google.client_id, google.client_secret and google.callback_url"},
+		{"port too low", func(c *Config) { c.HTTP.Port = 0 }, "http.port (HTTP_PORT) must be between 1 and 65535"},
+		{"port too high", func(c *Config) { c.HTTP.Port = 65536 }, "http.port (HTTP_PORT) must be between 1 and 65535"},
+		{"max open conns", func(c *Config) { c.Database.MaxOpenConns = 0 }, "database.max_open_conns (DB_MAX_OPEN_CONNS) must be at least 1"},
+		{"max idle conns", func(c *Config) { c.Database.MaxIdleConns = c.Database.MaxOpenConns + 1 }, "database.max_idle_conns (DB_MAX_IDLE_CONNS)"},
+		{"negative idle conns", func(c *Config) { c.Database.MaxIdleConns = -1 }, "database.max_idle_conns (DB_MAX_IDLE_CONNS)"},
+		{"redis pool size", func(c *Config) { c.Redis.PoolSize = -1 }, "redis.pool_size (REDIS_POOL_SIZE) must not be negative"},
+		{"cache ttl", func(c *Config) { c.Cache.NegativeTTL = 0 }, "cache.redirect_ttl, cache.negative_ttl, cache.local_ttl and cache.public_stats_ttl must be positive"},
+		{"public stats ttl", func(c *Config) { c.Cache.PublicStatsTTL = -time.Second }, "cache.public_stats_ttl must be positive"},
+		{"local cache size", func(c *Config) { c.Cache.LocalSize = 0 }, "cache.local_size (CACHE_LOCAL_SIZE) must be at least 1"},
+		{"breaker threshold", func(c *Config) { c.Breaker.FailureThreshold = 0 }, "circuit_breaker.failure_threshold (CIRCUIT_BREAKER_FAILURE_THRESHOLD) must be at least 1"},
+		{"breaker timeout", func(c *Config) { c.Breaker.OpenTimeout = 0 }, "circuit_breaker.open_timeout (CIRCUIT_BREAKER_OPEN_TIMEOUT) must be positive"},
+		{"spool dir", func(c *Config) { c.Spool.Dir = "" }, "click_spool.dir (CLICK_SPOOL_DIR) is required"},
+		{"spool size", func(c *Config) { c.Spool.MaxBytes = 0 }, "click_spool.max_bytes (CLICK_SPOOL_MAX_BYTES) must be at least 1"},
+		{"spool interval", func(c *Config) { c.Spool.ReplayInterval = 0 }, "click_spool.replay_interval (CLICK_SPOOL_REPLAY_INTERVAL) must be positive"},
+		{"mail driver", func(c *Config) { c.Mail.Driver = "sendmail" }, "mail.driver (MAIL_DRIVER) must be one of smtp, file or memory"},
+		{"local storage dir", func(c *Config) { c.Storage.LocalDir = "" }, "storage.local_dir (STORAGE_LOCAL_DIR) is required"},
+		{"storage driver", func(c *Config) { c.Storage.Driver = "gcs" }, "storage.driver (STORAGE_DRIVER) must be one of local or s3"},
+		{"s3 bucket", func(c *Config) {
+			c.Storage.Driver = "s3"
+			c.Storage.S3Endpoint = "https://s3.example"
+			c.Storage.S3AccessKeyID = "key"
+			c.Storage.S3SecretAccessKey = "secret"
+		}, "storage.s3_endpoint, storage.s3_bucket and storage.s3_region"},
+		{"s3 endpoint", func(c *Config) {
+			c.Storage.Driver = "s3"
+			c.Storage.S3Endpoint = "s3.example"
+			c.Storage.S3Bucket = "uploads"
+			c.Storage.S3AccessKeyID = "key"
+			c.Storage.S3SecretAccessKey = "secret"
+		}, "storage.s3_endpoint (S3_ENDPOINT) must be an absolute URL"},
+		{"s3 credentials", func(c *Config) {
+			c.Storage.Driver = "s3"
+			c.Storage.S3Endpoint = "https://s3.example"
+			c.Storage.S3Bucket = "uploads"
+		}, "storage.s3_access_key_id and storage.s3_secret_access_key"},
+		{"upload size", func(c *Config) { c.Storage.MaxUploadBytes = 0 }, "storage.max_upload_bytes (STORAGE_MAX_UPLOAD_BYTES) must be at least 1"},
+		{"export ttl", func(c *Config) { c.Privacy.ExportTTL = 0 }, "privacy.export_ttl (DATA_EXPORT_TTL) must be positive"},
+		{"deletion grace period", func(c *Config) { c.Privacy.DeletionGracePeriod = 0 }, "privacy.deletion_grace_period (ACCOUNT_DELETION_GRACE_PERIOD) must be positive"},
+		{"retention months", func(c *Config) { c.Retention.ClickLogMonths = -1 }, "retention.click_log_months (CLICK_LOG_RETENTION_MONTHS) must not be negative"},
+		{"retention mode", func(c *Config) { c.Retention.ClickLogMode = "keep" }, "retention.click_log_mode (CLICK_LOG_RETENTION_MODE) must be one of drop or archive"},
+		{"ip anonymization", func(c *Config) { c.Retention.IPAnonymizeAfter = -time.Hour }, "retention.ip_anonymize_after (CLICK_LOG_IP_ANONYMIZE_AFTER) must not be negative"},
+		{"short code length", func(c *Config) { c.ShortCode.Length = 3 }, "short_code.length (SHORT_CODE_LENGTH) must be at least 4"},
+		{"short code alphabet", func(c *Config) { c.ShortCode.Alphabet = "a" }, "short_code.alphabet (SHORT_CODE_ALPHABET) must have at least 2 characters"},
+		{"ios app id", func(c *Config) { c.AppLinks.IOSAppIDs = []string{"ABCDE12345"} }, `entry "ABCDE12345" must look like <team ID>.<bundle ID>`},
+		{"ios app id without team", func(c *Config) { c.AppLinks.IOSAppIDs = []string{".com.example.app"} }, "must look like <team ID>.<bundle ID>"},
+		{"ios paths", func(c *Config) {
+			c.AppLinks.IOSAppIDs = []string{"ABCDE12345.com.example.app"}
+			c.AppLinks.IOSPaths = nil
+		}, "app_links.ios_paths (APP_LINKS_IOS_PATHS) must not be empty"},
+		{"android fingerprints", func(c *Config) { c.AppLinks.AndroidPackages = []string{"com.example.app"} }, "app_links.android_cert_fingerprints (APP_LINKS_ANDROID_CERT_FINGERPRINTS) is required"},
+		{"short fingerprint", func(c *Config) { c.AppLinks.AndroidCertFingerprints = []string{"AB:CD"} }, `entry "AB:CD" must be 32 colon separated hex bytes`},
+		{"non hex fingerprint", func(c *Config) {
+			c.AppLinks.AndroidCertFingerprints = []string{strings.Replace(fingerprint, "AB", "ZZ", 1)}
+		}, "must be 32 colon separated hex bytes"},
+		{"fallback delay", func(c *Config) { c.AppLinks.FallbackDelay = 0 }, "app_links.fallback_delay (APP_LINKS_FALLBACK_DELAY) must be positive"},
+		{"geoip database", func(c *Config) { c.GeoIP.CountryDatabasePath = "" }, "geoip.country_database_path (GEOIP_COUNTRY_DATABASE_PATH) is required"},
+		{"asn without city database", func(c *Config) { c.GeoIP.ASNMMDBPath = "asn.mmdb" }, "geoip.asn_mmdb_path (GEOIP_ASN_MMDB_PATH) requires geoip.mmdb_path"},
+		{"geoip reload interval", func(c *Config) { c.GeoIP.ReloadInterval = 0 }, "geoip.reload_interval (GEOIP_RELOAD_INTERVAL) must be positive"},
+		{"log level", func(c *Config) { c.Log.Level = "trace" }, "log.level (LOG_LEVEL) must be one of debug, info, warn or error"},
+		{"tracing exporter", func(c *Config) { c.Tracing.Exporter = "jaeger" }, "tracing.exporter (OTEL_TRACES_EXPORTER) must be one of none, otlp or stdout"},
+		{"sample ratio", func(c *Config) { c.Tracing.SampleRatio = 1.5 }, "tracing.sample_ratio (OTEL_TRACES_SAMPLER_ARG) must be between 0 and 1"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config := validConfig()
+			tc.change(&config)
+
+			err := config.Validate()
+			if err == nil {
+				t.Fatal("Validate succeeded, want an error")
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("error %q does not mention %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateAccepts(t *testing.T) {
+	fingerprint := strings.TrimSuffix(strings.Repeat("ab:", 32), ":")
+
+	for _, tc := range []struct {
+		name   string
+		change func(c *Config)
+	}{
+		{"defaults", func(c *Config) {}},
+		{"google", func(c *Config) {
+			c.Google = GoogleConfig{ClientID: "id", ClientSecret: "secret", CallbackURL: "https://example.test/callback"}
+		}},
+		{"s3", func(c *Config) {
+			c.Storage.Driver = "s3"
+			c.Storage.S3Endpoint = "https://s3.example"
+			c.Storage.S3Bucket = "uploads"
+			c.Storage.S3AccessKeyID = "key"
+			c.Storage.S3SecretAccessKey = "secret"
+		}},
+		{"app links", func(c *Config) {
+			c.AppLinks.IOSAppIDs = []string{"ABCDE12345.com.example.app"}
+			c.AppLinks.AndroidPackages = []string{"com.example.app"}
+			c.AppLinks.AndroidCertFingerprints = []string{fingerprint}
+		}},
+		{"mmdb only", func(c *Config) {
+			c.GeoIP.CountryDatabasePath = ""
+			c.GeoIP.MMDBPath = "city.mmdb"
+			c.GeoIP.ASNMMDBPath = "asn.mmdb"
+		}},
+		{"upper case levels", func(c *Config) {
+			c.Log.Level = "DEBUG"
+			c.Tracing.Exporter = "OTLP"
+		}},
+		{"zero retention", func(c *Config) {
+			c.Retention.ClickLogMonths = 0
+			c.Retention.IPAnonymizeAfter = 0
+			c.Tracing.SampleRatio = 0
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config := validConfig()
+			tc.change(&config)
+
+			if err := config.Validate(); err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	config := Default()
+	config.HTTP.Port = 0
+
+	err := config.Validate()
+	if err == nil {
+		t.Fatal("Validate succeeded, want an error")
+	}
+	for _, want := range []string{"auth.token_secret", "auth.refresh_token_secret", "http.port"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: account_deletion.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const deleteUserClickLogs = `-- name: DeleteUserClickLogs :execrows
+DELETE FROM click_logs
+WHERE link_id IN (SELECT l.id FROM links l WHERE l.user_id = $1)
+`
+
+func (q *Queries) DeleteUserClickLogs(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUserClickLogs, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getDueAccountDeletions = `-- name: GetDueAccountDeletions :many
+SELECT id, name, email, password_hash, is_active, is_verified, created_at, updated_at, deleted_at, google_id, profile_image_url, totp_secret, totp_enabled, role, password_changed_at, totp_last_step FROM users
+WHERE deleted_at IS NOT NULL AND deleted_at <= $1::timestamptz
+ORDER BY deleted_at
+LIMIT $2
+`
+
+type GetDueAccountDeletionsParams struct {
+	DeletedBefore time.Time
+	BatchSize     int32
+}
+
+func (q *Queries) GetDueAccountDeletions(ctx context.Context, arg GetDueAccountDeletionsParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getDueAccountDeletions, arg.DeletedBefore, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.PasswordHash,
+			&i.IsActive,
+			&i.IsVerified,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.GoogleID,
+			&i.ProfileImageUrl,
+			&i.TotpSecret,
+			&i.TotpEnabled,
+			&i.Role,
+			&i.PasswordChangedAt,
+			&i.TotpLastStep,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserLinkCodes = `-- name: GetUserLinkCodes :many
+SELECT short_code, custom_short_code FROM links
+WHERE user_id = $1
+`
+
+type GetUserLinkCodesRow struct {
+	ShortCode       string
+	CustomShortCode sql.NullString
+}
+
+func (q *Queries) GetUserLinkCodes(ctx context.Context, userID uuid.UUID) ([]GetUserLinkCodesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserLinkCodes, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserLinkCodesRow
+	for rows.Next() {
+		var i GetUserLinkCodesRow
+		if err := rows.Scan(&i.ShortCode, &i.CustomShortCode); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeAuditEvents = `-- name: PurgeAuditEvents :one
+SELECT purge_audit_events($1::uuid)::bigint AS purged
+`
+
+func (q *Queries) PurgeAuditEvents(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, purgeAuditEvents, ownerID)
+	var purged int64
+	err := row.Scan(&purged)
+	return purged, err
+}
+
+const purgeUser = `-- name: PurgeUser :execrows
+DELETE FROM users
+WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) PurgeUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
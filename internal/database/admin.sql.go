@@ -0,0 +1,517 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: admin.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const adminGetLink = `-- name: AdminGetLink :one
+SELECT l.id, l.original_url, l.short_code, l.custom_short_code, l.user_id, l.expired_at, l.created_at, l.updated_at, l.deleted_at, l.expiry_notified_at, l.meta_title, l.meta_description, l.meta_image_url, l.meta_favicon_url, l.meta_fetched_at, l.health_status, l.consecutive_failures, l.last_checked_at, l.taken_down_at, l.takedown_reason, l.taken_down_by, l.active_from, l.prelaunch_url, l.campaign_id, l.ios_deep_link, l.ios_store_url, l.android_deep_link, l.android_store_url, l.force_preview, l.public_stats, l.stats_share_token, COUNT(cl.id) as counts FROM links l
+LEFT JOIN click_logs cl ON cl.code = l.short_code OR cl.code = l.custom_short_code
+WHERE l.id = $1 AND l.deleted_at IS NULL
+GROUP BY l.id
+LIMIT 1
+`
+
+type AdminGetLinkRow struct {
+	ID                  uuid.UUID
+	OriginalUrl         string
+	ShortCode           string
+	CustomShortCode     sql.NullString
+	UserID              uuid.UUID
+	ExpiredAt           sql.NullTime
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	DeletedAt           sql.NullTime
+	ExpiryNotifiedAt    sql.NullTime
+	MetaTitle           sql.NullString
+	MetaDescription     sql.NullString
+	MetaImageUrl        sql.NullString
+	MetaFaviconUrl      sql.NullString
+	MetaFetchedAt       sql.NullTime
+	HealthStatus        string
+	ConsecutiveFailures int32
+	LastCheckedAt       sql.NullTime
+	TakenDownAt         sql.NullTime
+	TakedownReason      sql.NullString
+	TakenDownBy         uuid.NullUUID
+	ActiveFrom          sql.NullTime
+	PrelaunchUrl        sql.NullString
+	CampaignID          uuid.NullUUID
+	IosDeepLink         sql.NullString
+	IosStoreUrl         sql.NullString
+	AndroidDeepLink     sql.NullString
+	AndroidStoreUrl     sql.NullString
+	ForcePreview        bool
+	PublicStats         bool
+	StatsShareToken     sql.NullString
+	Counts              int64
+}
+
+func (q *Queries) AdminGetLink(ctx context.Context, id uuid.UUID) (AdminGetLinkRow, error) {
+	row := q.db.QueryRowContext(ctx, adminGetLink, id)
+	var i AdminGetLinkRow
+	err := row.Scan(
+		&i.ID,
+		&i.OriginalUrl,
+		&i.ShortCode,
+		&i.CustomShortCode,
+		&i.UserID,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ExpiryNotifiedAt,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaImageUrl,
+		&i.MetaFaviconUrl,
+		&i.MetaFetchedAt,
+		&i.HealthStatus,
+		&i.ConsecutiveFailures,
+		&i.LastCheckedAt,
+		&i.TakenDownAt,
+		&i.TakedownReason,
+		&i.TakenDownBy,
+		&i.ActiveFrom,
+		&i.PrelaunchUrl,
+		&i.CampaignID,
+		&i.IosDeepLink,
+		&i.IosStoreUrl,
+		&i.AndroidDeepLink,
+		&i.AndroidStoreUrl,
+		&i.ForcePreview,
+		&i.PublicStats,
+		&i.StatsShareToken,
+		&i.Counts,
+	)
+	return i, err
+}
+
+const adminGetLinks = `-- name: AdminGetLinks :many
+SELECT l.id, l.original_url, l.short_code, l.custom_short_code, l.user_id, l.expired_at, l.created_at, l.updated_at, l.deleted_at, l.expiry_notified_at, l.meta_title, l.meta_description, l.meta_image_url, l.meta_favicon_url, l.meta_fetched_at, l.health_status, l.consecutive_failures, l.last_checked_at, l.taken_down_at, l.takedown_reason, l.taken_down_by, l.active_from, l.prelaunch_url, l.campaign_id, l.ios_deep_link, l.ios_store_url, l.android_deep_link, l.android_store_url, l.force_preview, l.public_stats, l.stats_share_token, u.email AS owner_email
+FROM links l
+JOIN users u ON u.id = l.user_id
+WHERE l.deleted_at IS NULL
+  AND ($3::text = '' OR l.short_code = $3::text OR l.custom_short_code = $3::text OR l.original_url ILIKE '%' || $3::text || '%')
+  AND (NOT $4::boolean OR l.taken_down_at IS NOT NULL)
+ORDER BY l.created_at DESC
+LIMIT $1
+OFFSET $2
+`
+
+type AdminGetLinksParams struct {
+	Limit         int32
+	Offset        int32
+	Search        string
+	TakenDownOnly bool
+}
+
+type AdminGetLinksRow struct {
+	ID                  uuid.UUID
+	OriginalUrl         string
+	ShortCode           string
+	CustomShortCode     sql.NullString
+	UserID              uuid.UUID
+	ExpiredAt           sql.NullTime
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	DeletedAt           sql.NullTime
+	ExpiryNotifiedAt    sql.NullTime
+	MetaTitle           sql.NullString
+	MetaDescription     sql.NullString
+	MetaImageUrl        sql.NullString
+	MetaFaviconUrl      sql.NullString
+	MetaFetchedAt       sql.NullTime
+	HealthStatus        string
+	ConsecutiveFailures int32
+	LastCheckedAt       sql.NullTime
+	TakenDownAt         sql.NullTime
+	TakedownReason      sql.NullString
+	TakenDownBy         uuid.NullUUID
+	ActiveFrom          sql.NullTime
+	PrelaunchUrl        sql.NullString
+	CampaignID          uuid.NullUUID
+	IosDeepLink         sql.NullString
+	IosStoreUrl         sql.NullString
+	AndroidDeepLink     sql.NullString
+	AndroidStoreUrl     sql.NullString
+	ForcePreview        bool
+	PublicStats         bool
+	StatsShareToken     sql.NullString
+	OwnerEmail          string
+}
+
+func (q *Queries) AdminGetLinks(ctx context.Context, arg AdminGetLinksParams) ([]AdminGetLinksRow, error) {
+	rows, err := q.db.QueryContext(ctx, adminGetLinks,
+		arg.Limit,
+		arg.Offset,
+		arg.Search,
+		arg.TakenDownOnly,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AdminGetLinksRow
+	for rows.Next() {
+		var i AdminGetLinksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OriginalUrl,
+			&i.ShortCode,
+			&i.CustomShortCode,
+			&i.UserID,
+			&i.ExpiredAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ExpiryNotifiedAt,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.MetaImageUrl,
+			&i.MetaFaviconUrl,
+			&i.MetaFetchedAt,
+			&i.HealthStatus,
+			&i.ConsecutiveFailures,
+			&i.LastCheckedAt,
+			&i.TakenDownAt,
+			&i.TakedownReason,
+			&i.TakenDownBy,
+			&i.ActiveFrom,
+			&i.PrelaunchUrl,
+			&i.CampaignID,
+			&i.IosDeepLink,
+			&i.IosStoreUrl,
+			&i.AndroidDeepLink,
+			&i.AndroidStoreUrl,
+			&i.ForcePreview,
+			&i.PublicStats,
+			&i.StatsShareToken,
+			&i.OwnerEmail,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const adminGetUsers = `-- name: AdminGetUsers :many
+SELECT
+    u.id,
+    u.name,
+    u.email,
+    u.role,
+    u.is_active,
+    u.is_verified,
+    u.created_at,
+    (SELECT COUNT(*) FROM links l WHERE l.user_id = u.id AND l.deleted_at IS NULL) AS link_count
+FROM users u
+WHERE u.deleted_at IS NULL
+  AND ($3::text = '' OR u.email ILIKE '%' || $3::text || '%' OR u.name ILIKE '%' || $3::text || '%')
+  AND ($4::boolean IS NULL OR u.is_active = $4::boolean)
+ORDER BY u.created_at DESC
+LIMIT $1
+OFFSET $2
+`
+
+type AdminGetUsersParams struct {
+	Limit    int32
+	Offset   int32
+	Search   string
+	IsActive sql.NullBool
+}
+
+type AdminGetUsersRow struct {
+	ID         uuid.UUID
+	Name       string
+	Email      string
+	Role       string
+	IsActive   bool
+	IsVerified bool
+	CreatedAt  time.Time
+	LinkCount  int64
+}
+
+func (q *Queries) AdminGetUsers(ctx context.Context, arg AdminGetUsersParams) ([]AdminGetUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, adminGetUsers,
+		arg.Limit,
+		arg.Offset,
+		arg.Search,
+		arg.IsActive,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AdminGetUsersRow
+	for rows.Next() {
+		var i AdminGetUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.Role,
+			&i.IsActive,
+			&i.IsVerified,
+			&i.CreatedAt,
+			&i.LinkCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPlatformGrowth = `-- name: GetPlatformGrowth :many
+SELECT
+    d.day::timestamptz AS day,
+    (SELECT COUNT(*) FROM users u WHERE u.created_at >= d.day AND u.created_at < d.day + INTERVAL '1 day') AS new_users,
+    (SELECT COUNT(*) FROM links l WHERE l.created_at >= d.day AND l.created_at < d.day + INTERVAL '1 day') AS new_links,
+    (SELECT COUNT(*) FROM click_logs cl WHERE cl.clicked_at >= d.day AND cl.clicked_at < d.day + INTERVAL '1 day') AS clicks
+FROM generate_series(
+    DATE_TRUNC('day', $1::timestamptz),
+    DATE_TRUNC('day', $2::timestamptz),
+    INTERVAL '1 day'
+) AS d(day)
+ORDER BY d.day ASC
+`
+
+type GetPlatformGrowthParams struct {
+	FromDate time.Time
+	ToDate   time.Time
+}
+
+type GetPlatformGrowthRow struct {
+	Day      time.Time
+	NewUsers int64
+	NewLinks int64
+	Clicks   int64
+}
+
+func (q *Queries) GetPlatformGrowth(ctx context.Context, arg GetPlatformGrowthParams) ([]GetPlatformGrowthRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPlatformGrowth, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPlatformGrowthRow
+	for rows.Next() {
+		var i GetPlatformGrowthRow
+		if err := rows.Scan(
+			&i.Day,
+			&i.NewUsers,
+			&i.NewLinks,
+			&i.Clicks,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const promoteUsersToAdmin = `-- name: PromoteUsersToAdmin :exec
+UPDATE users SET role = 'admin', updated_at = NOW()
+WHERE LOWER(email) = ANY($1::text[]) AND role <> 'admin' AND deleted_at IS NULL
+`
+
+func (q *Queries) PromoteUsersToAdmin(ctx context.Context, emails []string) error {
+	_, err := q.db.ExecContext(ctx, promoteUsersToAdmin, pq.Array(emails))
+	return err
+}
+
+const restoreLink = `-- name: RestoreLink :one
+UPDATE links SET taken_down_at = NULL, takedown_reason = NULL, taken_down_by = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, original_url, short_code, custom_short_code, user_id, expired_at, created_at, updated_at, deleted_at, expiry_notified_at, meta_title, meta_description, meta_image_url, meta_favicon_url, meta_fetched_at, health_status, consecutive_failures, last_checked_at, taken_down_at, takedown_reason, taken_down_by, active_from, prelaunch_url, campaign_id, ios_deep_link, ios_store_url, android_deep_link, android_store_url, force_preview, public_stats, stats_share_token
+`
+
+func (q *Queries) RestoreLink(ctx context.Context, id uuid.UUID) (Link, error) {
+	row := q.db.QueryRowContext(ctx, restoreLink, id)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.OriginalUrl,
+		&i.ShortCode,
+		&i.CustomShortCode,
+		&i.UserID,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ExpiryNotifiedAt,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaImageUrl,
+		&i.MetaFaviconUrl,
+		&i.MetaFetchedAt,
+		&i.HealthStatus,
+		&i.ConsecutiveFailures,
+		&i.LastCheckedAt,
+		&i.TakenDownAt,
+		&i.TakedownReason,
+		&i.TakenDownBy,
+		&i.ActiveFrom,
+		&i.PrelaunchUrl,
+		&i.CampaignID,
+		&i.IosDeepLink,
+		&i.IosStoreUrl,
+		&i.AndroidDeepLink,
+		&i.AndroidStoreUrl,
+		&i.ForcePreview,
+		&i.PublicStats,
+		&i.StatsShareToken,
+	)
+	return i, err
+}
+
+const setUserActive = `-- name: SetUserActive :one
+UPDATE users SET is_active = $1, updated_at = NOW()
+WHERE id = $2 AND deleted_at IS NULL
+RETURNING id, name, email, password_hash, is_active, is_verified, created_at, updated_at, deleted_at, google_id, profile_image_url, totp_secret, totp_enabled, role, password_changed_at, totp_last_step
+`
+
+type SetUserActiveParams struct {
+	IsActive bool
+	ID       uuid.UUID
+}
+
+func (q *Queries) SetUserActive(ctx context.Context, arg SetUserActiveParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, setUserActive, arg.IsActive, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.PasswordHash,
+		&i.IsActive,
+		&i.IsVerified,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.GoogleID,
+		&i.ProfileImageUrl,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.Role,
+		&i.PasswordChangedAt,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const setUserRole = `-- name: SetUserRole :one
+UPDATE users SET role = $1, updated_at = NOW()
+WHERE id = $2 AND deleted_at IS NULL
+RETURNING id, name, email, password_hash, is_active, is_verified, created_at, updated_at, deleted_at, google_id, profile_image_url, totp_secret, totp_enabled, role, password_changed_at, totp_last_step
+`
+
+type SetUserRoleParams struct {
+	Role string
+	ID   uuid.UUID
+}
+
+func (q *Queries) SetUserRole(ctx context.Context, arg SetUserRoleParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, setUserRole, arg.Role, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.PasswordHash,
+		&i.IsActive,
+		&i.IsVerified,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.GoogleID,
+		&i.ProfileImageUrl,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.Role,
+		&i.PasswordChangedAt,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const takeDownLink = `-- name: TakeDownLink :one
+UPDATE links SET taken_down_at = NOW(), takedown_reason = $1, taken_down_by = $2, updated_at = NOW()
+WHERE id = $3 AND deleted_at IS NULL
+RETURNING id, original_url, short_code, custom_short_code, user_id, expired_at, created_at, updated_at, deleted_at, expiry_notified_at, meta_title, meta_description, meta_image_url, meta_favicon_url, meta_fetched_at, health_status, consecutive_failures, last_checked_at, taken_down_at, takedown_reason, taken_down_by, active_from, prelaunch_url, campaign_id, ios_deep_link, ios_store_url, android_deep_link, android_store_url, force_preview, public_stats, stats_share_token
+`
+
+type TakeDownLinkParams struct {
+	TakedownReason sql.NullString
+	TakenDownBy    uuid.NullUUID
+	ID             uuid.UUID
+}
+
+func (q *Queries) TakeDownLink(ctx context.Context, arg TakeDownLinkParams) (Link, error) {
+	row := q.db.QueryRowContext(ctx, takeDownLink, arg.TakedownReason, arg.TakenDownBy, arg.ID)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.OriginalUrl,
+		&i.ShortCode,
+		&i.CustomShortCode,
+		&i.UserID,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ExpiryNotifiedAt,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaImageUrl,
+		&i.MetaFaviconUrl,
+		&i.MetaFetchedAt,
+		&i.HealthStatus,
+		&i.ConsecutiveFailures,
+		&i.LastCheckedAt,
+		&i.TakenDownAt,
+		&i.TakedownReason,
+		&i.TakenDownBy,
+		&i.ActiveFrom,
+		&i.PrelaunchUrl,
+		&i.CampaignID,
+		&i.IosDeepLink,
+		&i.IosStoreUrl,
+		&i.AndroidDeepLink,
+		&i.AndroidStoreUrl,
+		&i.ForcePreview,
+		&i.PublicStats,
+		&i.StatsShareToken,
+	)
+	return i, err
+}
@@ -0,0 +1,122 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: audit_events.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+const getAuditEvents = `-- name: GetAuditEvents :many
+SELECT id, owner_id, actor_id, action, target_type, target_id, changes, ip_address, user_agent, created_at FROM audit_events
+WHERE owner_id = $1
+  AND ($4::text IS NULL OR target_type = $4::text)
+  AND ($5::uuid IS NULL OR target_id = $5::uuid)
+  AND ($6::text IS NULL OR action = $6::text)
+ORDER BY created_at DESC
+LIMIT $2
+OFFSET $3
+`
+
+type GetAuditEventsParams struct {
+	OwnerID    uuid.UUID
+	Limit      int32
+	Offset     int32
+	TargetType sql.NullString
+	TargetID   uuid.NullUUID
+	Action     sql.NullString
+}
+
+func (q *Queries) GetAuditEvents(ctx context.Context, arg GetAuditEventsParams) ([]AuditEvent, error) {
+	rows, err := q.db.QueryContext(ctx, getAuditEvents,
+		arg.OwnerID,
+		arg.Limit,
+		arg.Offset,
+		arg.TargetType,
+		arg.TargetID,
+		arg.Action,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditEvent
+	for rows.Next() {
+		var i AuditEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.OwnerID,
+			&i.ActorID,
+			&i.Action,
+			&i.TargetType,
+			&i.TargetID,
+			&i.Changes,
+			&i.IpAddress,
+			&i.UserAgent,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertAuditEvent = `-- name: InsertAuditEvent :exec
+INSERT INTO audit_events(
+    owner_id,
+    actor_id,
+    action,
+    target_type,
+    target_id,
+    changes,
+    ip_address,
+    user_agent
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+)
+`
+
+type InsertAuditEventParams struct {
+	OwnerID    uuid.UUID
+	ActorID    uuid.NullUUID
+	Action     string
+	TargetType string
+	TargetID   uuid.NullUUID
+	Changes    json.RawMessage
+	IpAddress  sql.NullString
+	UserAgent  sql.NullString
+}
+
+func (q *Queries) InsertAuditEvent(ctx context.Context, arg InsertAuditEventParams) error {
+	_, err := q.db.ExecContext(ctx, insertAuditEvent,
+		arg.OwnerID,
+		arg.ActorID,
+		arg.Action,
+		arg.TargetType,
+		arg.TargetID,
+		arg.Changes,
+		arg.IpAddress,
+		arg.UserAgent,
+	)
+	return err
+}
@@ -0,0 +1,449 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: campaigns.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const addLinksToCampaign = `-- name: AddLinksToCampaign :many
+UPDATE links SET campaign_id = $1::uuid, updated_at = NOW()
+WHERE user_id = $2 AND id = ANY($3::uuid[]) AND deleted_at IS NULL
+RETURNING id
+`
+
+type AddLinksToCampaignParams struct {
+	CampaignID uuid.UUID
+	UserID     uuid.UUID
+	LinkIds    []uuid.UUID
+}
+
+func (q *Queries) AddLinksToCampaign(ctx context.Context, arg AddLinksToCampaignParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, addLinksToCampaign, arg.CampaignID, arg.UserID, pq.Array(arg.LinkIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteCampaign = `-- name: DeleteCampaign :exec
+DELETE FROM campaigns WHERE id = $1 AND user_id = $2
+`
+
+type DeleteCampaignParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) DeleteCampaign(ctx context.Context, arg DeleteCampaignParams) error {
+	_, err := q.db.ExecContext(ctx, deleteCampaign, arg.ID, arg.UserID)
+	return err
+}
+
+const expireCampaignLinks = `-- name: ExpireCampaignLinks :many
+UPDATE links SET expired_at = $1::timestamptz, expiry_notified_at = NULL, updated_at = NOW()
+WHERE campaign_id = $2::uuid
+  AND deleted_at IS NULL
+  AND (expired_at IS NULL OR expired_at > $1::timestamptz)
+  AND (active_from IS NULL OR active_from < $1::timestamptz)
+RETURNING short_code, custom_short_code
+`
+
+type ExpireCampaignLinksParams struct {
+	EndsAt     time.Time
+	CampaignID uuid.UUID
+}
+
+type ExpireCampaignLinksRow struct {
+	ShortCode       string
+	CustomShortCode sql.NullString
+}
+
+// Links that expire before the campaign ends keep their own date. Links that
+// are only scheduled to start after it ended are left alone, since they
+// cannot expire before they start.
+func (q *Queries) ExpireCampaignLinks(ctx context.Context, arg ExpireCampaignLinksParams) ([]ExpireCampaignLinksRow, error) {
+	rows, err := q.db.QueryContext(ctx, expireCampaignLinks, arg.EndsAt, arg.CampaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExpireCampaignLinksRow
+	for rows.Next() {
+		var i ExpireCampaignLinksRow
+		if err := rows.Scan(&i.ShortCode, &i.CustomShortCode); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCampaign = `-- name: GetCampaign :one
+SELECT c.id, c.user_id, c.name, c.description, c.starts_at, c.ends_at, c.links_expired_at, c.created_at, c.updated_at, COUNT(l.id) AS link_count
+FROM campaigns c
+LEFT JOIN links l ON l.campaign_id = c.id AND l.deleted_at IS NULL
+WHERE c.id = $1 AND c.user_id = $2
+GROUP BY c.id
+`
+
+type GetCampaignParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+type GetCampaignRow struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	Name           string
+	Description    sql.NullString
+	StartsAt       sql.NullTime
+	EndsAt         sql.NullTime
+	LinksExpiredAt sql.NullTime
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	LinkCount      int64
+}
+
+func (q *Queries) GetCampaign(ctx context.Context, arg GetCampaignParams) (GetCampaignRow, error) {
+	row := q.db.QueryRowContext(ctx, getCampaign, arg.ID, arg.UserID)
+	var i GetCampaignRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.LinksExpiredAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LinkCount,
+	)
+	return i, err
+}
+
+const getCampaignLeaderboard = `-- name: GetCampaignLeaderboard :many
+SELECT l.id, l.original_url, l.short_code, l.custom_short_code, COUNT(cl.id) AS total_clicks
+FROM links l
+LEFT JOIN click_logs cl ON (cl.code = l.short_code OR cl.code = l.custom_short_code)
+    AND cl.clicked_at BETWEEN $1::timestamp AND $2::timestamp
+WHERE l.campaign_id = $3::uuid AND l.user_id = $4 AND l.deleted_at IS NULL
+GROUP BY l.id
+ORDER BY total_clicks DESC, l.created_at ASC
+LIMIT $5
+`
+
+type GetCampaignLeaderboardParams struct {
+	FromDate   time.Time
+	ToDate     time.Time
+	CampaignID uuid.UUID
+	UserID     uuid.UUID
+	Limit      int32
+}
+
+type GetCampaignLeaderboardRow struct {
+	ID              uuid.UUID
+	OriginalUrl     string
+	ShortCode       string
+	CustomShortCode sql.NullString
+	TotalClicks     int64
+}
+
+func (q *Queries) GetCampaignLeaderboard(ctx context.Context, arg GetCampaignLeaderboardParams) ([]GetCampaignLeaderboardRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCampaignLeaderboard,
+		arg.FromDate,
+		arg.ToDate,
+		arg.CampaignID,
+		arg.UserID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCampaignLeaderboardRow
+	for rows.Next() {
+		var i GetCampaignLeaderboardRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OriginalUrl,
+			&i.ShortCode,
+			&i.CustomShortCode,
+			&i.TotalClicks,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCampaigns = `-- name: GetCampaigns :many
+SELECT c.id, c.user_id, c.name, c.description, c.starts_at, c.ends_at, c.links_expired_at, c.created_at, c.updated_at, COUNT(l.id) AS link_count
+FROM campaigns c
+LEFT JOIN links l ON l.campaign_id = c.id AND l.deleted_at IS NULL
+WHERE c.user_id = $1
+GROUP BY c.id
+ORDER BY c.created_at DESC
+LIMIT $3
+OFFSET $2
+`
+
+type GetCampaignsParams struct {
+	UserID uuid.UUID
+	Offset int32
+	Limit  int32
+}
+
+type GetCampaignsRow struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	Name           string
+	Description    sql.NullString
+	StartsAt       sql.NullTime
+	EndsAt         sql.NullTime
+	LinksExpiredAt sql.NullTime
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	LinkCount      int64
+}
+
+func (q *Queries) GetCampaigns(ctx context.Context, arg GetCampaignsParams) ([]GetCampaignsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCampaigns, arg.UserID, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCampaignsRow
+	for rows.Next() {
+		var i GetCampaignsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Description,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.LinksExpiredAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LinkCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEndedCampaigns = `-- name: GetEndedCampaigns :many
+SELECT id, user_id, name, description, starts_at, ends_at, links_expired_at, created_at, updated_at FROM campaigns
+WHERE ends_at <= NOW() AND links_expired_at IS NULL
+ORDER BY ends_at ASC
+LIMIT $1
+`
+
+func (q *Queries) GetEndedCampaigns(ctx context.Context, batchSize int32) ([]Campaign, error) {
+	rows, err := q.db.QueryContext(ctx, getEndedCampaigns, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Campaign
+	for rows.Next() {
+		var i Campaign
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Description,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.LinksExpiredAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertCampaign = `-- name: InsertCampaign :one
+INSERT INTO campaigns(
+    user_id,
+    name,
+    description,
+    starts_at,
+    ends_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+RETURNING id, user_id, name, description, starts_at, ends_at, links_expired_at, created_at, updated_at
+`
+
+type InsertCampaignParams struct {
+	UserID      uuid.UUID
+	Name        string
+	Description sql.NullString
+	StartsAt    sql.NullTime
+	EndsAt      sql.NullTime
+}
+
+func (q *Queries) InsertCampaign(ctx context.Context, arg InsertCampaignParams) (Campaign, error) {
+	row := q.db.QueryRowContext(ctx, insertCampaign,
+		arg.UserID,
+		arg.Name,
+		arg.Description,
+		arg.StartsAt,
+		arg.EndsAt,
+	)
+	var i Campaign
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.LinksExpiredAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const markCampaignLinksExpired = `-- name: MarkCampaignLinksExpired :exec
+UPDATE campaigns SET links_expired_at = NOW()
+WHERE id = $1 AND ends_at = $2::timestamptz
+`
+
+type MarkCampaignLinksExpiredParams struct {
+	ID     uuid.UUID
+	EndsAt time.Time
+}
+
+// The end date is compared so that a campaign extended in the meantime is
+// expired again at its new end.
+func (q *Queries) MarkCampaignLinksExpired(ctx context.Context, arg MarkCampaignLinksExpiredParams) error {
+	_, err := q.db.ExecContext(ctx, markCampaignLinksExpired, arg.ID, arg.EndsAt)
+	return err
+}
+
+const removeLinkFromCampaign = `-- name: RemoveLinkFromCampaign :execrows
+UPDATE links SET campaign_id = NULL, updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND campaign_id = $3::uuid AND deleted_at IS NULL
+`
+
+type RemoveLinkFromCampaignParams struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	CampaignID uuid.UUID
+}
+
+func (q *Queries) RemoveLinkFromCampaign(ctx context.Context, arg RemoveLinkFromCampaignParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, removeLinkFromCampaign, arg.ID, arg.UserID, arg.CampaignID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateCampaign = `-- name: UpdateCampaign :one
+UPDATE campaigns SET
+name = $1,
+description = $2,
+starts_at = $3,
+links_expired_at = CASE WHEN ends_at IS DISTINCT FROM $4 THEN NULL ELSE links_expired_at END,
+ends_at = $4,
+updated_at = NOW()
+WHERE id = $5 AND user_id = $6
+RETURNING id, user_id, name, description, starts_at, ends_at, links_expired_at, created_at, updated_at
+`
+
+type UpdateCampaignParams struct {
+	Name        string
+	Description sql.NullString
+	StartsAt    sql.NullTime
+	EndsAt      sql.NullTime
+	ID          uuid.UUID
+	UserID      uuid.UUID
+}
+
+// Moving the end date re-arms the bulk expiry for the new date.
+func (q *Queries) UpdateCampaign(ctx context.Context, arg UpdateCampaignParams) (Campaign, error) {
+	row := q.db.QueryRowContext(ctx, updateCampaign,
+		arg.Name,
+		arg.Description,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.ID,
+		arg.UserID,
+	)
+	var i Campaign
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.LinksExpiredAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
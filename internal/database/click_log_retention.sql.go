@@ -0,0 +1,163 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: click_log_retention.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const anonymizeClickLogIPs = `-- name: AnonymizeClickLogIPs :execrows
+UPDATE click_logs
+SET ip_address = anonymize_ip(ip_address),
+    ip_anonymized = TRUE
+WHERE (id, clicked_at) IN (
+    SELECT cl.id, cl.clicked_at FROM click_logs cl
+    WHERE cl.clicked_at < $1::timestamptz
+      AND NOT cl.ip_anonymized
+      AND cl.ip_address IS NOT NULL
+    LIMIT $2
+)
+`
+
+type AnonymizeClickLogIPsParams struct {
+	Before    time.Time
+	BatchSize int32
+}
+
+func (q *Queries) AnonymizeClickLogIPs(ctx context.Context, arg AnonymizeClickLogIPsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, anonymizeClickLogIPs, arg.Before, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteClickLogsBefore = `-- name: DeleteClickLogsBefore :execrows
+DELETE FROM click_logs
+WHERE clicked_at < $1::timestamptz
+`
+
+func (q *Queries) DeleteClickLogsBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteClickLogsBefore, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const dropClickLogPartition = `-- name: DropClickLogPartition :exec
+SELECT drop_click_log_partition($1::date)
+`
+
+func (q *Queries) DropClickLogPartition(ctx context.Context, month time.Time) error {
+	_, err := q.db.ExecContext(ctx, dropClickLogPartition, month)
+	return err
+}
+
+const ensureClickLogPartition = `-- name: EnsureClickLogPartition :one
+SELECT ensure_click_log_partition($1::date)::boolean AS created
+`
+
+func (q *Queries) EnsureClickLogPartition(ctx context.Context, month time.Time) (bool, error) {
+	row := q.db.QueryRowContext(ctx, ensureClickLogPartition, month)
+	var created bool
+	err := row.Scan(&created)
+	return created, err
+}
+
+const getClickLogPartitionsBefore = `-- name: GetClickLogPartitionsBefore :many
+SELECT month FROM click_log_partitions
+WHERE month < $1::date
+ORDER BY month
+`
+
+func (q *Queries) GetClickLogPartitionsBefore(ctx context.Context, before time.Time) ([]time.Time, error) {
+	rows, err := q.db.QueryContext(ctx, getClickLogPartitionsBefore, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []time.Time
+	for rows.Next() {
+		var month time.Time
+		if err := rows.Scan(&month); err != nil {
+			return nil, err
+		}
+		items = append(items, month)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getClickLogsForArchive = `-- name: GetClickLogsForArchive :many
+SELECT id, ip_address, user_agent, referrer, clicked_at, code, country, device_type, traffic, browser, ip_anonymized, region, city, asn, link_id FROM click_logs
+WHERE clicked_at >= $1::timestamptz
+  AND clicked_at < $2::timestamptz
+  AND (clicked_at, id) > ($3::timestamptz, $4::uuid)
+ORDER BY clicked_at, id
+LIMIT $5
+`
+
+type GetClickLogsForArchiveParams struct {
+	FromTime       time.Time
+	ToTime         time.Time
+	AfterClickedAt time.Time
+	AfterID        uuid.UUID
+	BatchSize      int32
+}
+
+func (q *Queries) GetClickLogsForArchive(ctx context.Context, arg GetClickLogsForArchiveParams) ([]ClickLog, error) {
+	rows, err := q.db.QueryContext(ctx, getClickLogsForArchive,
+		arg.FromTime,
+		arg.ToTime,
+		arg.AfterClickedAt,
+		arg.AfterID,
+		arg.BatchSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClickLog
+	for rows.Next() {
+		var i ClickLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.IpAddress,
+			&i.UserAgent,
+			&i.Referrer,
+			&i.ClickedAt,
+			&i.Code,
+			&i.Country,
+			&i.DeviceType,
+			&i.Traffic,
+			&i.Browser,
+			&i.IpAnonymized,
+			&i.Region,
+			&i.City,
+			&i.Asn,
+			&i.LinkID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -20,14 +20,16 @@ SELECT
 FROM click_logs cl
 LEFT JOIN links l ON l.short_code = cl.code OR l.custom_short_code = cl.code
 WHERE cl.clicked_at BETWEEN $2::timestamp AND $3::timestamp AND l.user_id = $1 AND l.deleted_at IS NULL
+  AND ($4::uuid IS NULL OR l.campaign_id = $4::uuid)
 GROUP BY cl.browser
 ORDER BY total DESC
 `
 
 type GetBrowserUsageParams struct {
-	UserID   uuid.UUID
-	FromDate time.Time
-	ToDate   time.Time
+	UserID     uuid.UUID
+	FromDate   time.Time
+	ToDate     time.Time
+	CampaignID uuid.NullUUID
 }
 
 type GetBrowserUsageRow struct {
@@ -36,7 +38,12 @@ type GetBrowserUsageRow struct {
 }
 
 func (q *Queries) GetBrowserUsage(ctx context.Context, arg GetBrowserUsageParams) ([]GetBrowserUsageRow, error) {
-	rows, err := q.db.QueryContext(ctx, getBrowserUsage, arg.UserID, arg.FromDate, arg.ToDate)
+	rows, err := q.db.QueryContext(ctx, getBrowserUsage,
+		arg.UserID,
+		arg.FromDate,
+		arg.ToDate,
+		arg.CampaignID,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -65,14 +72,18 @@ SELECT
 FROM click_logs cl
 LEFT JOIN links l ON l.short_code = cl.code OR l.custom_short_code = cl.code
 WHERE cl.clicked_at BETWEEN $2::timestamp AND $3::timestamp AND l.user_id = $1 AND l.deleted_at IS NULL
+  AND ($4::uuid IS NULL OR l.campaign_id = $4::uuid)
+  AND ($5::uuid IS NULL OR l.id = $5::uuid)
 GROUP BY DATE_TRUNC('day', cl.clicked_at)
 ORDER BY date ASC
 `
 
 type GetByDateRangeParams struct {
-	UserID   uuid.UUID
-	FromDate time.Time
-	ToDate   time.Time
+	UserID     uuid.UUID
+	FromDate   time.Time
+	ToDate     time.Time
+	CampaignID uuid.NullUUID
+	LinkID     uuid.NullUUID
 }
 
 type GetByDateRangeRow struct {
@@ -81,7 +92,13 @@ type GetByDateRangeRow struct {
 }
 
 func (q *Queries) GetByDateRange(ctx context.Context, arg GetByDateRangeParams) ([]GetByDateRangeRow, error) {
-	rows, err := q.db.QueryContext(ctx, getByDateRange, arg.UserID, arg.FromDate, arg.ToDate)
+	rows, err := q.db.QueryContext(ctx, getByDateRange,
+		arg.UserID,
+		arg.FromDate,
+		arg.ToDate,
+		arg.CampaignID,
+		arg.LinkID,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -110,14 +127,16 @@ SELECT
 FROM click_logs cl
 LEFT JOIN links l ON l.short_code = cl.code OR l.custom_short_code = cl.code
 WHERE cl.clicked_at BETWEEN $2::timestamp AND $3::timestamp AND l.user_id = $1 AND l.deleted_at IS NULL
+  AND ($4::uuid IS NULL OR l.campaign_id = $4::uuid)
 GROUP BY cl.device_type
 ORDER BY total DESC
 `
 
 type GetDeviceBreakdownParams struct {
-	UserID   uuid.UUID
-	FromDate time.Time
-	ToDate   time.Time
+	UserID     uuid.UUID
+	FromDate   time.Time
+	ToDate     time.Time
+	CampaignID uuid.NullUUID
 }
 
 type GetDeviceBreakdownRow struct {
@@ -126,7 +145,12 @@ type GetDeviceBreakdownRow struct {
 }
 
 func (q *Queries) GetDeviceBreakdown(ctx context.Context, arg GetDeviceBreakdownParams) ([]GetDeviceBreakdownRow, error) {
-	rows, err := q.db.QueryContext(ctx, getDeviceBreakdown, arg.UserID, arg.FromDate, arg.ToDate)
+	rows, err := q.db.QueryContext(ctx, getDeviceBreakdown,
+		arg.UserID,
+		arg.FromDate,
+		arg.ToDate,
+		arg.CampaignID,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -199,6 +223,68 @@ func (q *Queries) GetDeviceBreakdownSingle(ctx context.Context, arg GetDeviceBre
 	return items, nil
 }
 
+const getTopCities = `-- name: GetTopCities :many
+SELECT 
+    COALESCE(cl.country, 'Unknown') AS country,
+    COALESCE(cl.region, 'Unknown') AS region,
+    COALESCE(cl.city, 'Unknown') AS city,
+    COUNT(*) AS total
+FROM click_logs cl
+LEFT JOIN links l ON l.short_code = cl.code OR l.custom_short_code = cl.code
+WHERE cl.clicked_at BETWEEN $2::timestamp AND $3::timestamp AND l.user_id = $1 AND l.deleted_at IS NULL
+  AND ($4::uuid IS NULL OR l.campaign_id = $4::uuid)
+GROUP BY cl.country, cl.region, cl.city
+ORDER BY total DESC
+LIMIT 10
+`
+
+type GetTopCitiesParams struct {
+	UserID     uuid.UUID
+	FromDate   time.Time
+	ToDate     time.Time
+	CampaignID uuid.NullUUID
+}
+
+type GetTopCitiesRow struct {
+	Country string
+	Region  string
+	City    string
+	Total   int64
+}
+
+func (q *Queries) GetTopCities(ctx context.Context, arg GetTopCitiesParams) ([]GetTopCitiesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTopCities,
+		arg.UserID,
+		arg.FromDate,
+		arg.ToDate,
+		arg.CampaignID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTopCitiesRow
+	for rows.Next() {
+		var i GetTopCitiesRow
+		if err := rows.Scan(
+			&i.Country,
+			&i.Region,
+			&i.City,
+			&i.Total,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTopCountries = `-- name: GetTopCountries :many
 SELECT 
     COALESCE(cl.country, 'Unknown') AS country,
@@ -206,15 +292,17 @@ SELECT
 FROM click_logs cl
 LEFT JOIN links l ON l.short_code = cl.code OR l.custom_short_code = cl.code
 WHERE cl.clicked_at BETWEEN $2::timestamp AND $3::timestamp AND l.user_id = $1 AND l.deleted_at IS NULL
+  AND ($4::uuid IS NULL OR l.campaign_id = $4::uuid)
 GROUP BY cl.country
 ORDER BY total DESC
 LIMIT 10
 `
 
 type GetTopCountriesParams struct {
-	UserID   uuid.UUID
-	FromDate time.Time
-	ToDate   time.Time
+	UserID     uuid.UUID
+	FromDate   time.Time
+	ToDate     time.Time
+	CampaignID uuid.NullUUID
 }
 
 type GetTopCountriesRow struct {
@@ -223,7 +311,12 @@ type GetTopCountriesRow struct {
 }
 
 func (q *Queries) GetTopCountries(ctx context.Context, arg GetTopCountriesParams) ([]GetTopCountriesRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTopCountries, arg.UserID, arg.FromDate, arg.ToDate)
+	rows, err := q.db.QueryContext(ctx, getTopCountries,
+		arg.UserID,
+		arg.FromDate,
+		arg.ToDate,
+		arg.CampaignID,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -297,6 +390,61 @@ func (q *Queries) GetTopCountriesSingle(ctx context.Context, arg GetTopCountries
 	return items, nil
 }
 
+const getTopRegions = `-- name: GetTopRegions :many
+SELECT 
+    COALESCE(cl.country, 'Unknown') AS country,
+    COALESCE(cl.region, 'Unknown') AS region,
+    COUNT(*) AS total
+FROM click_logs cl
+LEFT JOIN links l ON l.short_code = cl.code OR l.custom_short_code = cl.code
+WHERE cl.clicked_at BETWEEN $2::timestamp AND $3::timestamp AND l.user_id = $1 AND l.deleted_at IS NULL
+  AND ($4::uuid IS NULL OR l.campaign_id = $4::uuid)
+GROUP BY cl.country, cl.region
+ORDER BY total DESC
+LIMIT 10
+`
+
+type GetTopRegionsParams struct {
+	UserID     uuid.UUID
+	FromDate   time.Time
+	ToDate     time.Time
+	CampaignID uuid.NullUUID
+}
+
+type GetTopRegionsRow struct {
+	Country string
+	Region  string
+	Total   int64
+}
+
+func (q *Queries) GetTopRegions(ctx context.Context, arg GetTopRegionsParams) ([]GetTopRegionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTopRegions,
+		arg.UserID,
+		arg.FromDate,
+		arg.ToDate,
+		arg.CampaignID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTopRegionsRow
+	for rows.Next() {
+		var i GetTopRegionsRow
+		if err := rows.Scan(&i.Country, &i.Region, &i.Total); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTotalClicks = `-- name: GetTotalClicks :one
 SELECT 
     COUNT(*) AS total
@@ -305,16 +453,26 @@ LEFT JOIN links l ON l.short_code = cl.code OR l.custom_short_code = cl.code
 WHERE cl.clicked_at BETWEEN $2::timestamp AND $3::timestamp
   AND l.user_id = $1
   AND l.deleted_at IS NULL
+  AND ($4::uuid IS NULL OR l.campaign_id = $4::uuid)
+  AND ($5::uuid IS NULL OR l.id = $5::uuid)
 `
 
 type GetTotalClicksParams struct {
-	UserID   uuid.UUID
-	FromDate time.Time
-	ToDate   time.Time
+	UserID     uuid.UUID
+	FromDate   time.Time
+	ToDate     time.Time
+	CampaignID uuid.NullUUID
+	LinkID     uuid.NullUUID
 }
 
 func (q *Queries) GetTotalClicks(ctx context.Context, arg GetTotalClicksParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getTotalClicks, arg.UserID, arg.FromDate, arg.ToDate)
+	row := q.db.QueryRowContext(ctx, getTotalClicks,
+		arg.UserID,
+		arg.FromDate,
+		arg.ToDate,
+		arg.CampaignID,
+		arg.LinkID,
+	)
 	var total int64
 	err := row.Scan(&total)
 	return total, err
@@ -327,14 +485,16 @@ SELECT
 FROM click_logs cl
 LEFT JOIN links l ON l.short_code = cl.code OR l.custom_short_code = cl.code
 WHERE cl.clicked_at BETWEEN $2::timestamp AND $3::timestamp AND l.user_id = $1 AND l.deleted_at IS NULL
+  AND ($4::uuid IS NULL OR l.campaign_id = $4::uuid)
 GROUP BY cl.traffic
 ORDER BY total DESC
 `
 
 type GetTrafficSourcesParams struct {
-	UserID   uuid.UUID
-	FromDate time.Time
-	ToDate   time.Time
+	UserID     uuid.UUID
+	FromDate   time.Time
+	ToDate     time.Time
+	CampaignID uuid.NullUUID
 }
 
 type GetTrafficSourcesRow struct {
@@ -343,7 +503,12 @@ type GetTrafficSourcesRow struct {
 }
 
 func (q *Queries) GetTrafficSources(ctx context.Context, arg GetTrafficSourcesParams) ([]GetTrafficSourcesRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTrafficSources, arg.UserID, arg.FromDate, arg.ToDate)
+	rows, err := q.db.QueryContext(ctx, getTrafficSources,
+		arg.UserID,
+		arg.FromDate,
+		arg.ToDate,
+		arg.CampaignID,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -374,7 +539,11 @@ INSERT INTO click_logs (
     country,
     traffic,
     device_type,
-    browser
+    browser,
+    region,
+    city,
+    asn,
+    link_id
 ) VALUES (
     $1,
     $2,
@@ -383,9 +552,13 @@ INSERT INTO click_logs (
     $5,
     $6,
     $7,
-    $8
+    $8,
+    $9,
+    $10,
+    $11,
+    (SELECT l.id FROM links l WHERE l.short_code = $1 OR l.custom_short_code = $1 LIMIT 1)
 )
-RETURNING id, ip_address, user_agent, referrer, clicked_at, code, country, device_type, traffic, browser
+RETURNING id, ip_address, user_agent, referrer, clicked_at, code, country, device_type, traffic, browser, ip_anonymized, region, city, asn, link_id
 `
 
 type InsertClickLogParams struct {
@@ -397,6 +570,9 @@ type InsertClickLogParams struct {
 	Traffic    sql.NullString
 	DeviceType sql.NullString
 	Browser    sql.NullString
+	Region     sql.NullString
+	City       sql.NullString
+	Asn        sql.NullInt64
 }
 
 func (q *Queries) InsertClickLog(ctx context.Context, arg InsertClickLogParams) (ClickLog, error) {
@@ -409,6 +585,9 @@ func (q *Queries) InsertClickLog(ctx context.Context, arg InsertClickLogParams)
 		arg.Traffic,
 		arg.DeviceType,
 		arg.Browser,
+		arg.Region,
+		arg.City,
+		arg.Asn,
 	)
 	var i ClickLog
 	err := row.Scan(
@@ -422,6 +601,76 @@ func (q *Queries) InsertClickLog(ctx context.Context, arg InsertClickLogParams)
 		&i.DeviceType,
 		&i.Traffic,
 		&i.Browser,
+		&i.IpAnonymized,
+		&i.Region,
+		&i.City,
+		&i.Asn,
+		&i.LinkID,
 	)
 	return i, err
 }
+
+const insertSpooledClickLog = `-- name: InsertSpooledClickLog :exec
+INSERT INTO click_logs (
+    code,
+    ip_address,
+    user_agent,
+    referrer,
+    country,
+    traffic,
+    device_type,
+    browser,
+    region,
+    city,
+    asn,
+    clicked_at,
+    link_id
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    (SELECT l.id FROM links l WHERE l.short_code = $1 OR l.custom_short_code = $1 LIMIT 1)
+)
+`
+
+type InsertSpooledClickLogParams struct {
+	Code       string
+	IpAddress  sql.NullString
+	UserAgent  sql.NullString
+	Referrer   sql.NullString
+	Country    sql.NullString
+	Traffic    sql.NullString
+	DeviceType sql.NullString
+	Browser    sql.NullString
+	Region     sql.NullString
+	City       sql.NullString
+	Asn        sql.NullInt64
+	ClickedAt  time.Time
+}
+
+func (q *Queries) InsertSpooledClickLog(ctx context.Context, arg InsertSpooledClickLogParams) error {
+	_, err := q.db.ExecContext(ctx, insertSpooledClickLog,
+		arg.Code,
+		arg.IpAddress,
+		arg.UserAgent,
+		arg.Referrer,
+		arg.Country,
+		arg.Traffic,
+		arg.DeviceType,
+		arg.Browser,
+		arg.Region,
+		arg.City,
+		arg.Asn,
+		arg.ClickedAt,
+	)
+	return err
+}
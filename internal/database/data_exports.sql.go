@@ -0,0 +1,400 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: data_exports.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const claimPendingDataExports = `-- name: ClaimPendingDataExports :many
+UPDATE data_exports SET status = 'processing', attempts = attempts + 1, lease_until = $1::timestamptz
+WHERE id IN (
+    SELECT de.id FROM data_exports de
+    WHERE de.status = 'pending' OR (de.status = 'processing' AND de.lease_until < NOW())
+    ORDER BY de.created_at
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, user_id, status, attempts, lease_until, blob_key, size_bytes, error, created_at, completed_at, expires_at
+`
+
+type ClaimPendingDataExportsParams struct {
+	LeaseUntil time.Time
+	BatchSize  int32
+}
+
+func (q *Queries) ClaimPendingDataExports(ctx context.Context, arg ClaimPendingDataExportsParams) ([]DataExport, error) {
+	rows, err := q.db.QueryContext(ctx, claimPendingDataExports, arg.LeaseUntil, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DataExport
+	for rows.Next() {
+		var i DataExport
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Status,
+			&i.Attempts,
+			&i.LeaseUntil,
+			&i.BlobKey,
+			&i.SizeBytes,
+			&i.Error,
+			&i.CreatedAt,
+			&i.CompletedAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const completeDataExport = `-- name: CompleteDataExport :exec
+UPDATE data_exports SET
+    status = 'ready',
+    lease_until = NULL,
+    blob_key = $1,
+    size_bytes = $2,
+    error = NULL,
+    completed_at = NOW(),
+    expires_at = $3
+WHERE id = $4
+`
+
+type CompleteDataExportParams struct {
+	BlobKey   sql.NullString
+	SizeBytes sql.NullInt64
+	ExpiresAt sql.NullTime
+	ID        uuid.UUID
+}
+
+func (q *Queries) CompleteDataExport(ctx context.Context, arg CompleteDataExportParams) error {
+	_, err := q.db.ExecContext(ctx, completeDataExport,
+		arg.BlobKey,
+		arg.SizeBytes,
+		arg.ExpiresAt,
+		arg.ID,
+	)
+	return err
+}
+
+const deleteDataExport = `-- name: DeleteDataExport :exec
+DELETE FROM data_exports WHERE id = $1
+`
+
+func (q *Queries) DeleteDataExport(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteDataExport, id)
+	return err
+}
+
+const failDataExport = `-- name: FailDataExport :exec
+UPDATE data_exports SET
+    status = $1,
+    lease_until = NULL,
+    error = $2,
+    completed_at = CASE WHEN $1 = 'failed' THEN NOW() END,
+    expires_at = $3
+WHERE id = $4
+`
+
+type FailDataExportParams struct {
+	Status    string
+	Error     sql.NullString
+	ExpiresAt sql.NullTime
+	ID        uuid.UUID
+}
+
+func (q *Queries) FailDataExport(ctx context.Context, arg FailDataExportParams) error {
+	_, err := q.db.ExecContext(ctx, failDataExport,
+		arg.Status,
+		arg.Error,
+		arg.ExpiresAt,
+		arg.ID,
+	)
+	return err
+}
+
+const getDataExport = `-- name: GetDataExport :one
+SELECT id, user_id, status, attempts, lease_until, blob_key, size_bytes, error, created_at, completed_at, expires_at FROM data_exports
+WHERE id = $1 AND user_id = $2
+`
+
+type GetDataExportParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetDataExport(ctx context.Context, arg GetDataExportParams) (DataExport, error) {
+	row := q.db.QueryRowContext(ctx, getDataExport, arg.ID, arg.UserID)
+	var i DataExport
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.Attempts,
+		&i.LeaseUntil,
+		&i.BlobKey,
+		&i.SizeBytes,
+		&i.Error,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getExpiredDataExports = `-- name: GetExpiredDataExports :many
+SELECT id, user_id, status, attempts, lease_until, blob_key, size_bytes, error, created_at, completed_at, expires_at FROM data_exports
+WHERE expires_at < NOW()
+ORDER BY expires_at
+LIMIT $1
+`
+
+func (q *Queries) GetExpiredDataExports(ctx context.Context, limit int32) ([]DataExport, error) {
+	rows, err := q.db.QueryContext(ctx, getExpiredDataExports, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DataExport
+	for rows.Next() {
+		var i DataExport
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Status,
+			&i.Attempts,
+			&i.LeaseUntil,
+			&i.BlobKey,
+			&i.SizeBytes,
+			&i.Error,
+			&i.CreatedAt,
+			&i.CompletedAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLatestDataExport = `-- name: GetLatestDataExport :one
+SELECT id, user_id, status, attempts, lease_until, blob_key, size_bytes, error, created_at, completed_at, expires_at FROM data_exports
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestDataExport(ctx context.Context, userID uuid.UUID) (DataExport, error) {
+	row := q.db.QueryRowContext(ctx, getLatestDataExport, userID)
+	var i DataExport
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.Attempts,
+		&i.LeaseUntil,
+		&i.BlobKey,
+		&i.SizeBytes,
+		&i.Error,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getUserClickLogsForExport = `-- name: GetUserClickLogsForExport :many
+SELECT cl.id, cl.ip_address, cl.user_agent, cl.referrer, cl.clicked_at, cl.code, cl.country, cl.device_type, cl.traffic, cl.browser, cl.ip_anonymized, cl.region, cl.city, cl.asn, cl.link_id FROM click_logs cl
+WHERE cl.link_id IN (SELECT l.id FROM links l WHERE l.user_id = $1)
+  AND (cl.clicked_at, cl.id) > ($2::timestamptz, $3::uuid)
+ORDER BY cl.clicked_at, cl.id
+LIMIT $4
+`
+
+type GetUserClickLogsForExportParams struct {
+	UserID         uuid.UUID
+	AfterClickedAt time.Time
+	AfterID        uuid.UUID
+	BatchSize      int32
+}
+
+func (q *Queries) GetUserClickLogsForExport(ctx context.Context, arg GetUserClickLogsForExportParams) ([]ClickLog, error) {
+	rows, err := q.db.QueryContext(ctx, getUserClickLogsForExport,
+		arg.UserID,
+		arg.AfterClickedAt,
+		arg.AfterID,
+		arg.BatchSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClickLog
+	for rows.Next() {
+		var i ClickLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.IpAddress,
+			&i.UserAgent,
+			&i.Referrer,
+			&i.ClickedAt,
+			&i.Code,
+			&i.Country,
+			&i.DeviceType,
+			&i.Traffic,
+			&i.Browser,
+			&i.IpAnonymized,
+			&i.Region,
+			&i.City,
+			&i.Asn,
+			&i.LinkID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserDataExportBlobKeys = `-- name: GetUserDataExportBlobKeys :many
+SELECT blob_key::text FROM data_exports
+WHERE user_id = $1 AND blob_key IS NOT NULL
+`
+
+func (q *Queries) GetUserDataExportBlobKeys(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getUserDataExportBlobKeys, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var blob_key string
+		if err := rows.Scan(&blob_key); err != nil {
+			return nil, err
+		}
+		items = append(items, blob_key)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserLinksForExport = `-- name: GetUserLinksForExport :many
+SELECT id, original_url, short_code, custom_short_code, user_id, expired_at, created_at, updated_at, deleted_at, expiry_notified_at, meta_title, meta_description, meta_image_url, meta_favicon_url, meta_fetched_at, health_status, consecutive_failures, last_checked_at, taken_down_at, takedown_reason, taken_down_by, active_from, prelaunch_url, campaign_id, ios_deep_link, ios_store_url, android_deep_link, android_store_url, force_preview, public_stats, stats_share_token FROM links
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) GetUserLinksForExport(ctx context.Context, userID uuid.UUID) ([]Link, error) {
+	rows, err := q.db.QueryContext(ctx, getUserLinksForExport, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Link
+	for rows.Next() {
+		var i Link
+		if err := rows.Scan(
+			&i.ID,
+			&i.OriginalUrl,
+			&i.ShortCode,
+			&i.CustomShortCode,
+			&i.UserID,
+			&i.ExpiredAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ExpiryNotifiedAt,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.MetaImageUrl,
+			&i.MetaFaviconUrl,
+			&i.MetaFetchedAt,
+			&i.HealthStatus,
+			&i.ConsecutiveFailures,
+			&i.LastCheckedAt,
+			&i.TakenDownAt,
+			&i.TakedownReason,
+			&i.TakenDownBy,
+			&i.ActiveFrom,
+			&i.PrelaunchUrl,
+			&i.CampaignID,
+			&i.IosDeepLink,
+			&i.IosStoreUrl,
+			&i.AndroidDeepLink,
+			&i.AndroidStoreUrl,
+			&i.ForcePreview,
+			&i.PublicStats,
+			&i.StatsShareToken,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertDataExport = `-- name: InsertDataExport :one
+INSERT INTO data_exports(user_id) VALUES ($1)
+RETURNING id, user_id, status, attempts, lease_until, blob_key, size_bytes, error, created_at, completed_at, expires_at
+`
+
+func (q *Queries) InsertDataExport(ctx context.Context, userID uuid.UUID) (DataExport, error) {
+	row := q.db.QueryRowContext(ctx, insertDataExport, userID)
+	var i DataExport
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.Attempts,
+		&i.LeaseUntil,
+		&i.BlobKey,
+		&i.SizeBytes,
+		&i.Error,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
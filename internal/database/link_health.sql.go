@@ -0,0 +1,213 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: link_health.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const claimLinksForHealthCheck = `-- name: ClaimLinksForHealthCheck :many
+UPDATE links SET last_checked_at = NOW()
+WHERE id IN (
+    SELECT l.id FROM links l
+    WHERE l.deleted_at IS NULL
+      AND (l.expired_at IS NULL OR l.expired_at > NOW())
+      AND (l.active_from IS NULL OR l.active_from <= NOW())
+      AND (l.last_checked_at IS NULL OR l.last_checked_at <= $1::timestamptz)
+    ORDER BY l.last_checked_at NULLS FIRST
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, original_url
+`
+
+type ClaimLinksForHealthCheckParams struct {
+	CheckedBefore time.Time
+	BatchSize     int32
+}
+
+type ClaimLinksForHealthCheckRow struct {
+	ID          uuid.UUID
+	OriginalUrl string
+}
+
+func (q *Queries) ClaimLinksForHealthCheck(ctx context.Context, arg ClaimLinksForHealthCheckParams) ([]ClaimLinksForHealthCheckRow, error) {
+	rows, err := q.db.QueryContext(ctx, claimLinksForHealthCheck, arg.CheckedBefore, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimLinksForHealthCheckRow
+	for rows.Next() {
+		var i ClaimLinksForHealthCheckRow
+		if err := rows.Scan(&i.ID, &i.OriginalUrl); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteLinkHealthChecksBefore = `-- name: DeleteLinkHealthChecksBefore :exec
+DELETE FROM link_health_checks WHERE checked_at < $1
+`
+
+func (q *Queries) DeleteLinkHealthChecksBefore(ctx context.Context, checkedAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteLinkHealthChecksBefore, checkedAt)
+	return err
+}
+
+const getLinkHealthChecks = `-- name: GetLinkHealthChecks :many
+SELECT id, link_id, success, status_code, latency_ms, redirect_chain, error, checked_at FROM link_health_checks WHERE link_id = $1 ORDER BY checked_at DESC LIMIT $2
+`
+
+type GetLinkHealthChecksParams struct {
+	LinkID uuid.UUID
+	Limit  int32
+}
+
+func (q *Queries) GetLinkHealthChecks(ctx context.Context, arg GetLinkHealthChecksParams) ([]LinkHealthCheck, error) {
+	rows, err := q.db.QueryContext(ctx, getLinkHealthChecks, arg.LinkID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LinkHealthCheck
+	for rows.Next() {
+		var i LinkHealthCheck
+		if err := rows.Scan(
+			&i.ID,
+			&i.LinkID,
+			&i.Success,
+			&i.StatusCode,
+			&i.LatencyMs,
+			pq.Array(&i.RedirectChain),
+			&i.Error,
+			&i.CheckedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertLinkHealthCheck = `-- name: InsertLinkHealthCheck :exec
+INSERT INTO link_health_checks(
+    link_id,
+    success,
+    status_code,
+    latency_ms,
+    redirect_chain,
+    error
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+)
+`
+
+type InsertLinkHealthCheckParams struct {
+	LinkID        uuid.UUID
+	Success       bool
+	StatusCode    sql.NullInt32
+	LatencyMs     int32
+	RedirectChain []string
+	Error         sql.NullString
+}
+
+func (q *Queries) InsertLinkHealthCheck(ctx context.Context, arg InsertLinkHealthCheckParams) error {
+	_, err := q.db.ExecContext(ctx, insertLinkHealthCheck,
+		arg.LinkID,
+		arg.Success,
+		arg.StatusCode,
+		arg.LatencyMs,
+		pq.Array(arg.RedirectChain),
+		arg.Error,
+	)
+	return err
+}
+
+const markLinkHealthFailure = `-- name: MarkLinkHealthFailure :one
+UPDATE links SET
+consecutive_failures = consecutive_failures + 1,
+health_status = CASE WHEN consecutive_failures + 1 >= $1::int THEN 'broken' ELSE health_status END
+WHERE id = $2
+RETURNING id, original_url, short_code, custom_short_code, user_id, expired_at, created_at, updated_at, deleted_at, expiry_notified_at, meta_title, meta_description, meta_image_url, meta_favicon_url, meta_fetched_at, health_status, consecutive_failures, last_checked_at, taken_down_at, takedown_reason, taken_down_by, active_from, prelaunch_url, campaign_id, ios_deep_link, ios_store_url, android_deep_link, android_store_url, force_preview, public_stats, stats_share_token
+`
+
+type MarkLinkHealthFailureParams struct {
+	FailureThreshold int32
+	ID               uuid.UUID
+}
+
+func (q *Queries) MarkLinkHealthFailure(ctx context.Context, arg MarkLinkHealthFailureParams) (Link, error) {
+	row := q.db.QueryRowContext(ctx, markLinkHealthFailure, arg.FailureThreshold, arg.ID)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.OriginalUrl,
+		&i.ShortCode,
+		&i.CustomShortCode,
+		&i.UserID,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ExpiryNotifiedAt,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaImageUrl,
+		&i.MetaFaviconUrl,
+		&i.MetaFetchedAt,
+		&i.HealthStatus,
+		&i.ConsecutiveFailures,
+		&i.LastCheckedAt,
+		&i.TakenDownAt,
+		&i.TakedownReason,
+		&i.TakenDownBy,
+		&i.ActiveFrom,
+		&i.PrelaunchUrl,
+		&i.CampaignID,
+		&i.IosDeepLink,
+		&i.IosStoreUrl,
+		&i.AndroidDeepLink,
+		&i.AndroidStoreUrl,
+		&i.ForcePreview,
+		&i.PublicStats,
+		&i.StatsShareToken,
+	)
+	return i, err
+}
+
+const markLinkHealthy = `-- name: MarkLinkHealthy :exec
+UPDATE links SET health_status = 'healthy', consecutive_failures = 0 WHERE id = $1
+`
+
+func (q *Queries) MarkLinkHealthy(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, markLinkHealthy, id)
+	return err
+}
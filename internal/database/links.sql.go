@@ -13,6 +13,67 @@ import (
 	"github.com/google/uuid"
 )
 
+const claimExpiredLinks = `-- name: ClaimExpiredLinks :many
+UPDATE links SET expiry_notified_at = NOW()
+WHERE expired_at <= NOW() AND expiry_notified_at IS NULL AND deleted_at IS NULL
+RETURNING id, original_url, short_code, custom_short_code, user_id, expired_at, created_at, updated_at, deleted_at, expiry_notified_at, meta_title, meta_description, meta_image_url, meta_favicon_url, meta_fetched_at, health_status, consecutive_failures, last_checked_at, taken_down_at, takedown_reason, taken_down_by, active_from, prelaunch_url, campaign_id, ios_deep_link, ios_store_url, android_deep_link, android_store_url, force_preview, public_stats, stats_share_token
+`
+
+func (q *Queries) ClaimExpiredLinks(ctx context.Context) ([]Link, error) {
+	rows, err := q.db.QueryContext(ctx, claimExpiredLinks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Link
+	for rows.Next() {
+		var i Link
+		if err := rows.Scan(
+			&i.ID,
+			&i.OriginalUrl,
+			&i.ShortCode,
+			&i.CustomShortCode,
+			&i.UserID,
+			&i.ExpiredAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ExpiryNotifiedAt,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.MetaImageUrl,
+			&i.MetaFaviconUrl,
+			&i.MetaFetchedAt,
+			&i.HealthStatus,
+			&i.ConsecutiveFailures,
+			&i.LastCheckedAt,
+			&i.TakenDownAt,
+			&i.TakedownReason,
+			&i.TakenDownBy,
+			&i.ActiveFrom,
+			&i.PrelaunchUrl,
+			&i.CampaignID,
+			&i.IosDeepLink,
+			&i.IosStoreUrl,
+			&i.AndroidDeepLink,
+			&i.AndroidStoreUrl,
+			&i.ForcePreview,
+			&i.PublicStats,
+			&i.StatsShareToken,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const deleteLink = `-- name: DeleteLink :exec
 UPDATE links SET deleted_at = NOW() WHERE id = $1 AND user_id = $2
 `
@@ -28,7 +89,7 @@ func (q *Queries) DeleteLink(ctx context.Context, arg DeleteLinkParams) error {
 }
 
 const getLink = `-- name: GetLink :one
-SELECT l.id, l.original_url, l.short_code, l.custom_short_code, l.user_id, l.expired_at, l.created_at, l.updated_at, l.deleted_at, COUNT(cl.id) as counts FROM links l
+SELECT l.id, l.original_url, l.short_code, l.custom_short_code, l.user_id, l.expired_at, l.created_at, l.updated_at, l.deleted_at, l.expiry_notified_at, l.meta_title, l.meta_description, l.meta_image_url, l.meta_favicon_url, l.meta_fetched_at, l.health_status, l.consecutive_failures, l.last_checked_at, l.taken_down_at, l.takedown_reason, l.taken_down_by, l.active_from, l.prelaunch_url, l.campaign_id, l.ios_deep_link, l.ios_store_url, l.android_deep_link, l.android_store_url, l.force_preview, l.public_stats, l.stats_share_token, COUNT(cl.id) as counts FROM links l
 LEFT JOIN click_logs cl ON cl.code = l.short_code OR cl.code = l.custom_short_code
 WHERE l.user_id = $1 AND deleted_at IS NULL AND l.id = $2 
 GROUP BY l.id
@@ -41,16 +102,38 @@ type GetLinkParams struct {
 }
 
 type GetLinkRow struct {
-	ID              uuid.UUID
-	OriginalUrl     string
-	ShortCode       string
-	CustomShortCode sql.NullString
-	UserID          uuid.UUID
-	ExpiredAt       sql.NullTime
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
-	DeletedAt       sql.NullTime
-	Counts          int64
+	ID                  uuid.UUID
+	OriginalUrl         string
+	ShortCode           string
+	CustomShortCode     sql.NullString
+	UserID              uuid.UUID
+	ExpiredAt           sql.NullTime
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	DeletedAt           sql.NullTime
+	ExpiryNotifiedAt    sql.NullTime
+	MetaTitle           sql.NullString
+	MetaDescription     sql.NullString
+	MetaImageUrl        sql.NullString
+	MetaFaviconUrl      sql.NullString
+	MetaFetchedAt       sql.NullTime
+	HealthStatus        string
+	ConsecutiveFailures int32
+	LastCheckedAt       sql.NullTime
+	TakenDownAt         sql.NullTime
+	TakedownReason      sql.NullString
+	TakenDownBy         uuid.NullUUID
+	ActiveFrom          sql.NullTime
+	PrelaunchUrl        sql.NullString
+	CampaignID          uuid.NullUUID
+	IosDeepLink         sql.NullString
+	IosStoreUrl         sql.NullString
+	AndroidDeepLink     sql.NullString
+	AndroidStoreUrl     sql.NullString
+	ForcePreview        bool
+	PublicStats         bool
+	StatsShareToken     sql.NullString
+	Counts              int64
 }
 
 func (q *Queries) GetLink(ctx context.Context, arg GetLinkParams) (GetLinkRow, error) {
@@ -66,44 +149,178 @@ func (q *Queries) GetLink(ctx context.Context, arg GetLinkParams) (GetLinkRow, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ExpiryNotifiedAt,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaImageUrl,
+		&i.MetaFaviconUrl,
+		&i.MetaFetchedAt,
+		&i.HealthStatus,
+		&i.ConsecutiveFailures,
+		&i.LastCheckedAt,
+		&i.TakenDownAt,
+		&i.TakedownReason,
+		&i.TakenDownBy,
+		&i.ActiveFrom,
+		&i.PrelaunchUrl,
+		&i.CampaignID,
+		&i.IosDeepLink,
+		&i.IosStoreUrl,
+		&i.AndroidDeepLink,
+		&i.AndroidStoreUrl,
+		&i.ForcePreview,
+		&i.PublicStats,
+		&i.StatsShareToken,
 		&i.Counts,
 	)
 	return i, err
 }
 
+const getLinkByCode = `-- name: GetLinkByCode :one
+SELECT id, user_id FROM links WHERE (short_code = $1 OR custom_short_code = $1) AND deleted_at IS NULL
+`
+
+type GetLinkByCodeRow struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetLinkByCode(ctx context.Context, shortCode string) (GetLinkByCodeRow, error) {
+	row := q.db.QueryRowContext(ctx, getLinkByCode, shortCode)
+	var i GetLinkByCodeRow
+	err := row.Scan(&i.ID, &i.UserID)
+	return i, err
+}
+
+const getLinkByStatsToken = `-- name: GetLinkByStatsToken :one
+SELECT id, user_id, original_url, short_code, custom_short_code, created_at FROM links
+WHERE stats_share_token = $1 AND public_stats AND deleted_at IS NULL AND taken_down_at IS NULL
+`
+
+type GetLinkByStatsTokenRow struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	OriginalUrl     string
+	ShortCode       string
+	CustomShortCode sql.NullString
+	CreatedAt       time.Time
+}
+
+func (q *Queries) GetLinkByStatsToken(ctx context.Context, statsShareToken sql.NullString) (GetLinkByStatsTokenRow, error) {
+	row := q.db.QueryRowContext(ctx, getLinkByStatsToken, statsShareToken)
+	var i GetLinkByStatsTokenRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.OriginalUrl,
+		&i.ShortCode,
+		&i.CustomShortCode,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLinkPreview = `-- name: GetLinkPreview :one
+SELECT original_url, meta_title, meta_description, meta_image_url, health_status,
+    taken_down_at, takedown_reason, active_from, expired_at, created_at
+FROM links WHERE (short_code = $1 OR custom_short_code = $1) AND deleted_at IS NULL
+`
+
+type GetLinkPreviewRow struct {
+	OriginalUrl     string
+	MetaTitle       sql.NullString
+	MetaDescription sql.NullString
+	MetaImageUrl    sql.NullString
+	HealthStatus    string
+	TakenDownAt     sql.NullTime
+	TakedownReason  sql.NullString
+	ActiveFrom      sql.NullTime
+	ExpiredAt       sql.NullTime
+	CreatedAt       time.Time
+}
+
+func (q *Queries) GetLinkPreview(ctx context.Context, shortCode string) (GetLinkPreviewRow, error) {
+	row := q.db.QueryRowContext(ctx, getLinkPreview, shortCode)
+	var i GetLinkPreviewRow
+	err := row.Scan(
+		&i.OriginalUrl,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaImageUrl,
+		&i.HealthStatus,
+		&i.TakenDownAt,
+		&i.TakedownReason,
+		&i.ActiveFrom,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getLinks = `-- name: GetLinks :many
-SELECT l.id, l.original_url, l.short_code, l.custom_short_code, l.user_id, l.expired_at, l.created_at, l.updated_at, l.deleted_at, COUNT(cl.id) as counts 
+SELECT l.id, l.original_url, l.short_code, l.custom_short_code, l.user_id, l.expired_at, l.created_at, l.updated_at, l.deleted_at, l.expiry_notified_at, l.meta_title, l.meta_description, l.meta_image_url, l.meta_favicon_url, l.meta_fetched_at, l.health_status, l.consecutive_failures, l.last_checked_at, l.taken_down_at, l.takedown_reason, l.taken_down_by, l.active_from, l.prelaunch_url, l.campaign_id, l.ios_deep_link, l.ios_store_url, l.android_deep_link, l.android_store_url, l.force_preview, l.public_stats, l.stats_share_token, COUNT(cl.id) as counts 
 FROM links l
 LEFT JOIN click_logs cl ON cl.code = l.short_code OR cl.code = l.custom_short_code
 WHERE l.user_id = $1 AND l.deleted_at IS NULL
+  AND ($4::text IS NULL OR l.health_status = $4::text)
+  AND (
+    $5::text IS NULL
+    OR ($5::text = 'scheduled' AND l.active_from > NOW())
+    OR ($5::text = 'active' AND (l.active_from IS NULL OR l.active_from <= NOW()) AND (l.expired_at IS NULL OR l.expired_at > NOW()))
+    OR ($5::text = 'expired' AND l.expired_at <= NOW())
+  )
 GROUP BY l.id
 ORDER BY
-  CASE WHEN $4::text = 'created_at' THEN l.created_at END DESC,
-  CASE WHEN $4::text = 'updated_at' THEN l.updated_at END DESC,
-  CASE WHEN $4::text = 'expired_at' THEN l.expired_at END DESC,
-  CASE WHEN $4::text = 'counts' THEN COUNT(cl.id) END DESC
+  CASE WHEN $6::text = 'created_at' THEN l.created_at END DESC,
+  CASE WHEN $6::text = 'updated_at' THEN l.updated_at END DESC,
+  CASE WHEN $6::text = 'expired_at' THEN l.expired_at END DESC,
+  CASE WHEN $6::text = 'counts' THEN COUNT(cl.id) END DESC
 LIMIT $3
 OFFSET $2
 `
 
 type GetLinksParams struct {
-	UserID  uuid.UUID
-	Offset  int32
-	Limit   int32
-	OrderBy string
+	UserID       uuid.UUID
+	Offset       int32
+	Limit        int32
+	HealthStatus sql.NullString
+	State        sql.NullString
+	OrderBy      string
 }
 
 type GetLinksRow struct {
-	ID              uuid.UUID
-	OriginalUrl     string
-	ShortCode       string
-	CustomShortCode sql.NullString
-	UserID          uuid.UUID
-	ExpiredAt       sql.NullTime
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
-	DeletedAt       sql.NullTime
-	Counts          int64
+	ID                  uuid.UUID
+	OriginalUrl         string
+	ShortCode           string
+	CustomShortCode     sql.NullString
+	UserID              uuid.UUID
+	ExpiredAt           sql.NullTime
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	DeletedAt           sql.NullTime
+	ExpiryNotifiedAt    sql.NullTime
+	MetaTitle           sql.NullString
+	MetaDescription     sql.NullString
+	MetaImageUrl        sql.NullString
+	MetaFaviconUrl      sql.NullString
+	MetaFetchedAt       sql.NullTime
+	HealthStatus        string
+	ConsecutiveFailures int32
+	LastCheckedAt       sql.NullTime
+	TakenDownAt         sql.NullTime
+	TakedownReason      sql.NullString
+	TakenDownBy         uuid.NullUUID
+	ActiveFrom          sql.NullTime
+	PrelaunchUrl        sql.NullString
+	CampaignID          uuid.NullUUID
+	IosDeepLink         sql.NullString
+	IosStoreUrl         sql.NullString
+	AndroidDeepLink     sql.NullString
+	AndroidStoreUrl     sql.NullString
+	ForcePreview        bool
+	PublicStats         bool
+	StatsShareToken     sql.NullString
+	Counts              int64
 }
 
 func (q *Queries) GetLinks(ctx context.Context, arg GetLinksParams) ([]GetLinksRow, error) {
@@ -111,6 +328,8 @@ func (q *Queries) GetLinks(ctx context.Context, arg GetLinksParams) ([]GetLinksR
 		arg.UserID,
 		arg.Offset,
 		arg.Limit,
+		arg.HealthStatus,
+		arg.State,
 		arg.OrderBy,
 	)
 	if err != nil {
@@ -130,6 +349,28 @@ func (q *Queries) GetLinks(ctx context.Context, arg GetLinksParams) ([]GetLinksR
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.ExpiryNotifiedAt,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.MetaImageUrl,
+			&i.MetaFaviconUrl,
+			&i.MetaFetchedAt,
+			&i.HealthStatus,
+			&i.ConsecutiveFailures,
+			&i.LastCheckedAt,
+			&i.TakenDownAt,
+			&i.TakedownReason,
+			&i.TakenDownBy,
+			&i.ActiveFrom,
+			&i.PrelaunchUrl,
+			&i.CampaignID,
+			&i.IosDeepLink,
+			&i.IosStoreUrl,
+			&i.AndroidDeepLink,
+			&i.AndroidStoreUrl,
+			&i.ForcePreview,
+			&i.PublicStats,
+			&i.StatsShareToken,
 			&i.Counts,
 		); err != nil {
 			return nil, err
@@ -146,14 +387,42 @@ func (q *Queries) GetLinks(ctx context.Context, arg GetLinksParams) ([]GetLinksR
 }
 
 const getRedirectLink = `-- name: GetRedirectLink :one
-SELECT original_url FROM links WHERE (short_code = $1 OR custom_short_code = $1) AND deleted_at IS NULL
+SELECT original_url, taken_down_at, takedown_reason, active_from, expired_at, prelaunch_url,
+    ios_deep_link, ios_store_url, android_deep_link, android_store_url, force_preview
+FROM links WHERE (short_code = $1 OR custom_short_code = $1) AND deleted_at IS NULL
 `
 
-func (q *Queries) GetRedirectLink(ctx context.Context, shortCode string) (string, error) {
+type GetRedirectLinkRow struct {
+	OriginalUrl     string
+	TakenDownAt     sql.NullTime
+	TakedownReason  sql.NullString
+	ActiveFrom      sql.NullTime
+	ExpiredAt       sql.NullTime
+	PrelaunchUrl    sql.NullString
+	IosDeepLink     sql.NullString
+	IosStoreUrl     sql.NullString
+	AndroidDeepLink sql.NullString
+	AndroidStoreUrl sql.NullString
+	ForcePreview    bool
+}
+
+func (q *Queries) GetRedirectLink(ctx context.Context, shortCode string) (GetRedirectLinkRow, error) {
 	row := q.db.QueryRowContext(ctx, getRedirectLink, shortCode)
-	var original_url string
-	err := row.Scan(&original_url)
-	return original_url, err
+	var i GetRedirectLinkRow
+	err := row.Scan(
+		&i.OriginalUrl,
+		&i.TakenDownAt,
+		&i.TakedownReason,
+		&i.ActiveFrom,
+		&i.ExpiredAt,
+		&i.PrelaunchUrl,
+		&i.IosDeepLink,
+		&i.IosStoreUrl,
+		&i.AndroidDeepLink,
+		&i.AndroidStoreUrl,
+		&i.ForcePreview,
+	)
+	return i, err
 }
 
 const getTotalActiveLinks = `-- name: GetTotalActiveLinks :one
@@ -173,15 +442,29 @@ INSERT INTO links(
     short_code,
     custom_short_code,
     user_id,
-    expired_at
+    expired_at,
+    active_from,
+    prelaunch_url,
+    ios_deep_link,
+    ios_store_url,
+    android_deep_link,
+    android_store_url,
+    force_preview
 ) VALUES (
     $1, 
     $2, 
     $3, 
     $4, 
-    $5
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12
 ) 
-RETURNING id, original_url, short_code, custom_short_code, user_id, expired_at, created_at, updated_at, deleted_at
+RETURNING id, original_url, short_code, custom_short_code, user_id, expired_at, created_at, updated_at, deleted_at, expiry_notified_at, meta_title, meta_description, meta_image_url, meta_favicon_url, meta_fetched_at, health_status, consecutive_failures, last_checked_at, taken_down_at, takedown_reason, taken_down_by, active_from, prelaunch_url, campaign_id, ios_deep_link, ios_store_url, android_deep_link, android_store_url, force_preview, public_stats, stats_share_token
 `
 
 type InsertLinkParams struct {
@@ -190,6 +473,13 @@ type InsertLinkParams struct {
 	CustomShortCode sql.NullString
 	UserID          uuid.UUID
 	ExpiredAt       sql.NullTime
+	ActiveFrom      sql.NullTime
+	PrelaunchUrl    sql.NullString
+	IosDeepLink     sql.NullString
+	IosStoreUrl     sql.NullString
+	AndroidDeepLink sql.NullString
+	AndroidStoreUrl sql.NullString
+	ForcePreview    bool
 }
 
 func (q *Queries) InsertLink(ctx context.Context, arg InsertLinkParams) (Link, error) {
@@ -199,7 +489,65 @@ func (q *Queries) InsertLink(ctx context.Context, arg InsertLinkParams) (Link, e
 		arg.CustomShortCode,
 		arg.UserID,
 		arg.ExpiredAt,
+		arg.ActiveFrom,
+		arg.PrelaunchUrl,
+		arg.IosDeepLink,
+		arg.IosStoreUrl,
+		arg.AndroidDeepLink,
+		arg.AndroidStoreUrl,
+		arg.ForcePreview,
+	)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.OriginalUrl,
+		&i.ShortCode,
+		&i.CustomShortCode,
+		&i.UserID,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ExpiryNotifiedAt,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaImageUrl,
+		&i.MetaFaviconUrl,
+		&i.MetaFetchedAt,
+		&i.HealthStatus,
+		&i.ConsecutiveFailures,
+		&i.LastCheckedAt,
+		&i.TakenDownAt,
+		&i.TakedownReason,
+		&i.TakenDownBy,
+		&i.ActiveFrom,
+		&i.PrelaunchUrl,
+		&i.CampaignID,
+		&i.IosDeepLink,
+		&i.IosStoreUrl,
+		&i.AndroidDeepLink,
+		&i.AndroidStoreUrl,
+		&i.ForcePreview,
+		&i.PublicStats,
+		&i.StatsShareToken,
 	)
+	return i, err
+}
+
+const rotateLinkStatsToken = `-- name: RotateLinkStatsToken :one
+UPDATE links SET stats_share_token = $1::text, updated_at = NOW()
+WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL
+RETURNING id, original_url, short_code, custom_short_code, user_id, expired_at, created_at, updated_at, deleted_at, expiry_notified_at, meta_title, meta_description, meta_image_url, meta_favicon_url, meta_fetched_at, health_status, consecutive_failures, last_checked_at, taken_down_at, takedown_reason, taken_down_by, active_from, prelaunch_url, campaign_id, ios_deep_link, ios_store_url, android_deep_link, android_store_url, force_preview, public_stats, stats_share_token
+`
+
+type RotateLinkStatsTokenParams struct {
+	StatsShareToken string
+	ID              uuid.UUID
+	UserID          uuid.UUID
+}
+
+func (q *Queries) RotateLinkStatsToken(ctx context.Context, arg RotateLinkStatsTokenParams) (Link, error) {
+	row := q.db.QueryRowContext(ctx, rotateLinkStatsToken, arg.StatsShareToken, arg.ID, arg.UserID)
 	var i Link
 	err := row.Scan(
 		&i.ID,
@@ -211,12 +559,110 @@ func (q *Queries) InsertLink(ctx context.Context, arg InsertLinkParams) (Link, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ExpiryNotifiedAt,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaImageUrl,
+		&i.MetaFaviconUrl,
+		&i.MetaFetchedAt,
+		&i.HealthStatus,
+		&i.ConsecutiveFailures,
+		&i.LastCheckedAt,
+		&i.TakenDownAt,
+		&i.TakedownReason,
+		&i.TakenDownBy,
+		&i.ActiveFrom,
+		&i.PrelaunchUrl,
+		&i.CampaignID,
+		&i.IosDeepLink,
+		&i.IosStoreUrl,
+		&i.AndroidDeepLink,
+		&i.AndroidStoreUrl,
+		&i.ForcePreview,
+		&i.PublicStats,
+		&i.StatsShareToken,
 	)
 	return i, err
 }
 
-const updateLink = `-- name: UpdateLink :exec
-UPDATE links SET custom_short_code = $1, original_url = $2, expired_at = $3 WHERE id = $4 AND deleted_at IS NULL
+const setLinkPublicStats = `-- name: SetLinkPublicStats :one
+UPDATE links SET
+public_stats = $1, stats_share_token = COALESCE(stats_share_token, $2::text), updated_at = NOW()
+WHERE id = $3 AND user_id = $4 AND deleted_at IS NULL
+RETURNING id, original_url, short_code, custom_short_code, user_id, expired_at, created_at, updated_at, deleted_at, expiry_notified_at, meta_title, meta_description, meta_image_url, meta_favicon_url, meta_fetched_at, health_status, consecutive_failures, last_checked_at, taken_down_at, takedown_reason, taken_down_by, active_from, prelaunch_url, campaign_id, ios_deep_link, ios_store_url, android_deep_link, android_store_url, force_preview, public_stats, stats_share_token
+`
+
+type SetLinkPublicStatsParams struct {
+	PublicStats     bool
+	StatsShareToken string
+	ID              uuid.UUID
+	UserID          uuid.UUID
+}
+
+// The share token is kept when public stats are turned off, so turning them
+// back on restores the links that were handed out.
+func (q *Queries) SetLinkPublicStats(ctx context.Context, arg SetLinkPublicStatsParams) (Link, error) {
+	row := q.db.QueryRowContext(ctx, setLinkPublicStats,
+		arg.PublicStats,
+		arg.StatsShareToken,
+		arg.ID,
+		arg.UserID,
+	)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.OriginalUrl,
+		&i.ShortCode,
+		&i.CustomShortCode,
+		&i.UserID,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ExpiryNotifiedAt,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaImageUrl,
+		&i.MetaFaviconUrl,
+		&i.MetaFetchedAt,
+		&i.HealthStatus,
+		&i.ConsecutiveFailures,
+		&i.LastCheckedAt,
+		&i.TakenDownAt,
+		&i.TakedownReason,
+		&i.TakenDownBy,
+		&i.ActiveFrom,
+		&i.PrelaunchUrl,
+		&i.CampaignID,
+		&i.IosDeepLink,
+		&i.IosStoreUrl,
+		&i.AndroidDeepLink,
+		&i.AndroidStoreUrl,
+		&i.ForcePreview,
+		&i.PublicStats,
+		&i.StatsShareToken,
+	)
+	return i, err
+}
+
+const shortCodeExists = `-- name: ShortCodeExists :one
+SELECT EXISTS(SELECT 1 FROM links WHERE short_code = $1 OR custom_short_code = $1)
+`
+
+func (q *Queries) ShortCodeExists(ctx context.Context, shortCode string) (bool, error) {
+	row := q.db.QueryRowContext(ctx, shortCodeExists, shortCode)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const updateLink = `-- name: UpdateLink :one
+UPDATE links SET
+custom_short_code = $1, original_url = $2, expired_at = $3, active_from = $6, prelaunch_url = $7,
+ios_deep_link = $8, ios_store_url = $9, android_deep_link = $10, android_store_url = $11,
+force_preview = $12, expiry_notified_at = NULL, updated_at = NOW()
+WHERE id = $4 AND user_id = $5 AND deleted_at IS NULL
+RETURNING id, original_url, short_code, custom_short_code, user_id, expired_at, created_at, updated_at, deleted_at, expiry_notified_at, meta_title, meta_description, meta_image_url, meta_favicon_url, meta_fetched_at, health_status, consecutive_failures, last_checked_at, taken_down_at, takedown_reason, taken_down_by, active_from, prelaunch_url, campaign_id, ios_deep_link, ios_store_url, android_deep_link, android_store_url, force_preview, public_stats, stats_share_token
 `
 
 type UpdateLinkParams struct {
@@ -224,14 +670,91 @@ type UpdateLinkParams struct {
 	OriginalUrl     string
 	ExpiredAt       sql.NullTime
 	ID              uuid.UUID
+	UserID          uuid.UUID
+	ActiveFrom      sql.NullTime
+	PrelaunchUrl    sql.NullString
+	IosDeepLink     sql.NullString
+	IosStoreUrl     sql.NullString
+	AndroidDeepLink sql.NullString
+	AndroidStoreUrl sql.NullString
+	ForcePreview    bool
 }
 
-func (q *Queries) UpdateLink(ctx context.Context, arg UpdateLinkParams) error {
-	_, err := q.db.ExecContext(ctx, updateLink,
+func (q *Queries) UpdateLink(ctx context.Context, arg UpdateLinkParams) (Link, error) {
+	row := q.db.QueryRowContext(ctx, updateLink,
 		arg.CustomShortCode,
 		arg.OriginalUrl,
 		arg.ExpiredAt,
 		arg.ID,
+		arg.UserID,
+		arg.ActiveFrom,
+		arg.PrelaunchUrl,
+		arg.IosDeepLink,
+		arg.IosStoreUrl,
+		arg.AndroidDeepLink,
+		arg.AndroidStoreUrl,
+		arg.ForcePreview,
+	)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.OriginalUrl,
+		&i.ShortCode,
+		&i.CustomShortCode,
+		&i.UserID,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ExpiryNotifiedAt,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaImageUrl,
+		&i.MetaFaviconUrl,
+		&i.MetaFetchedAt,
+		&i.HealthStatus,
+		&i.ConsecutiveFailures,
+		&i.LastCheckedAt,
+		&i.TakenDownAt,
+		&i.TakedownReason,
+		&i.TakenDownBy,
+		&i.ActiveFrom,
+		&i.PrelaunchUrl,
+		&i.CampaignID,
+		&i.IosDeepLink,
+		&i.IosStoreUrl,
+		&i.AndroidDeepLink,
+		&i.AndroidStoreUrl,
+		&i.ForcePreview,
+		&i.PublicStats,
+		&i.StatsShareToken,
+	)
+	return i, err
+}
+
+const updateLinkMetadata = `-- name: UpdateLinkMetadata :exec
+UPDATE links SET
+meta_title = $1, meta_description = $2, meta_image_url = $3, meta_favicon_url = $4, meta_fetched_at = NOW()
+WHERE id = $5 AND original_url = $6
+`
+
+type UpdateLinkMetadataParams struct {
+	MetaTitle       sql.NullString
+	MetaDescription sql.NullString
+	MetaImageUrl    sql.NullString
+	MetaFaviconUrl  sql.NullString
+	ID              uuid.UUID
+	OriginalUrl     string
+}
+
+func (q *Queries) UpdateLinkMetadata(ctx context.Context, arg UpdateLinkMetadataParams) error {
+	_, err := q.db.ExecContext(ctx, updateLinkMetadata,
+		arg.MetaTitle,
+		arg.MetaDescription,
+		arg.MetaImageUrl,
+		arg.MetaFaviconUrl,
+		arg.ID,
+		arg.OriginalUrl,
 	)
 	return err
 }
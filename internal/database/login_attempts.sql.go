@@ -0,0 +1,155 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: login_attempts.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const getEmailLoginFailures = `-- name: GetEmailLoginFailures :one
+SELECT
+    COUNT(*) AS failures,
+    COALESCE(MAX(la.created_at), 'epoch')::timestamptz AS last_failure_at
+FROM login_attempts la
+WHERE la.email = $1
+  AND la.success = FALSE
+  AND la.created_at > $2::timestamptz
+  AND la.created_at > COALESCE(
+    (SELECT MAX(s.created_at) FROM login_attempts s WHERE s.email = $1 AND s.success = TRUE),
+    'epoch'
+  )
+`
+
+type GetEmailLoginFailuresParams struct {
+	Email string
+	Since time.Time
+}
+
+type GetEmailLoginFailuresRow struct {
+	Failures      int64
+	LastFailureAt time.Time
+}
+
+func (q *Queries) GetEmailLoginFailures(ctx context.Context, arg GetEmailLoginFailuresParams) (GetEmailLoginFailuresRow, error) {
+	row := q.db.QueryRowContext(ctx, getEmailLoginFailures, arg.Email, arg.Since)
+	var i GetEmailLoginFailuresRow
+	err := row.Scan(&i.Failures, &i.LastFailureAt)
+	return i, err
+}
+
+const getIPLoginFailures = `-- name: GetIPLoginFailures :one
+SELECT
+    COUNT(*) AS failures,
+    COALESCE(MAX(created_at), 'epoch')::timestamptz AS last_failure_at
+FROM login_attempts
+WHERE ip_address = $1
+  AND success = FALSE
+  AND created_at > $2::timestamptz
+`
+
+type GetIPLoginFailuresParams struct {
+	IpAddress string
+	Since     time.Time
+}
+
+type GetIPLoginFailuresRow struct {
+	Failures      int64
+	LastFailureAt time.Time
+}
+
+func (q *Queries) GetIPLoginFailures(ctx context.Context, arg GetIPLoginFailuresParams) (GetIPLoginFailuresRow, error) {
+	row := q.db.QueryRowContext(ctx, getIPLoginFailures, arg.IpAddress, arg.Since)
+	var i GetIPLoginFailuresRow
+	err := row.Scan(&i.Failures, &i.LastFailureAt)
+	return i, err
+}
+
+const getUserLoginAttempts = `-- name: GetUserLoginAttempts :many
+SELECT id, user_id, email, ip_address, user_agent, success, reason, created_at FROM login_attempts
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2
+`
+
+type GetUserLoginAttemptsParams struct {
+	UserID uuid.NullUUID
+	Limit  int32
+}
+
+func (q *Queries) GetUserLoginAttempts(ctx context.Context, arg GetUserLoginAttemptsParams) ([]LoginAttempt, error) {
+	rows, err := q.db.QueryContext(ctx, getUserLoginAttempts, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LoginAttempt
+	for rows.Next() {
+		var i LoginAttempt
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Email,
+			&i.IpAddress,
+			&i.UserAgent,
+			&i.Success,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertLoginAttempt = `-- name: InsertLoginAttempt :exec
+INSERT INTO login_attempts(
+    user_id,
+    email,
+    ip_address,
+    user_agent,
+    success,
+    reason
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+)
+`
+
+type InsertLoginAttemptParams struct {
+	UserID    uuid.NullUUID
+	Email     string
+	IpAddress string
+	UserAgent sql.NullString
+	Success   bool
+	Reason    sql.NullString
+}
+
+func (q *Queries) InsertLoginAttempt(ctx context.Context, arg InsertLoginAttemptParams) error {
+	_, err := q.db.ExecContext(ctx, insertLoginAttempt,
+		arg.UserID,
+		arg.Email,
+		arg.IpAddress,
+		arg.UserAgent,
+		arg.Success,
+		arg.Reason,
+	)
+	return err
+}
@@ -0,0 +1,140 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+)
+
+// openTestDB connects to TEST_DATABASE_URL with a fresh schema first on the
+// search path, and drops the schema when the test ends. Tests are skipped
+// when no database is configured.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The search path is a session setting, so every query has to go through
+	// the same connection.
+	db.SetMaxOpenConns(1)
+
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if _, err := db.Exec(fmt.Sprintf("CREATE SCHEMA %s; SET search_path TO %s, public", schema, schema)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Exec(fmt.Sprintf("DROP SCHEMA %s CASCADE", schema))
+		db.Close()
+	})
+
+	return db
+}
+
+// migrate applies the Up section of every migration after from, up to and
+// including to.
+func migrate(t *testing.T, db *sql.DB, from, to int) {
+	t.Helper()
+
+	files, err := filepath.Glob("schema/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		var version int
+		if _, err := fmt.Sscanf(filepath.Base(file), "%05d_", &version); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if version <= from || version > to {
+			continue
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		up, _, _ := strings.Cut(string(content), "-- +goose Down")
+		if _, err := db.Exec(up); err != nil {
+			t.Fatalf("migrating %s: %v", filepath.Base(file), err)
+		}
+	}
+}
+
+func TestMigrateClicksOfRenamedCodes(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	migrate(t, db, 0, 18)
+
+	var userId, linkId uuid.UUID
+	if err := db.QueryRow(`INSERT INTO users (name, email) VALUES ('Ada', 'ada@example.com') RETURNING id`).Scan(&userId); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow(`INSERT INTO links (original_url, short_code, custom_short_code, user_id)
+		VALUES ('https://example.com', 'abc123', 'spring', $1) RETURNING id`, userId).Scan(&linkId); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO click_logs (code, ip_address, clicked_at) VALUES ('spring', '192.0.2.10', NOW() - INTERVAL '1 year')`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`UPDATE links SET custom_short_code = 'summer' WHERE id = $1`, linkId); err != nil {
+		t.Fatal(err)
+	}
+
+	migrate(t, db, 18, 1<<30)
+
+	// A click far enough ahead lands in the default partition until its
+	// month is created, and the code is renamed in between.
+	future := time.Now().UTC().AddDate(1, 0, 0)
+	if _, err := db.Exec(`INSERT INTO click_logs (code, ip_address, clicked_at) VALUES ('summer', '192.0.2.20', $1)`, future); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`UPDATE links SET custom_short_code = 'autumn' WHERE id = $1`, linkId); err != nil {
+		t.Fatal(err)
+	}
+
+	queries := database.New(db)
+	created, err := queries.EnsureClickLogPartition(ctx, future)
+	if err != nil || !created {
+		t.Fatalf("EnsureClickLogPartition = %v, %v; want a new partition", created, err)
+	}
+	var defaultRows int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM click_logs_default`).Scan(&defaultRows); err != nil {
+		t.Fatal(err)
+	}
+	if defaultRows != 0 {
+		t.Fatalf("%d clicks left in the default partition", defaultRows)
+	}
+
+	anonymize := database.AnonymizeClickLogIPsParams{Before: time.Now(), BatchSize: 100}
+	if n, err := queries.AnonymizeClickLogIPs(ctx, anonymize); err != nil || n != 1 {
+		t.Fatalf("AnonymizeClickLogIPs = %d, %v; want 1", n, err)
+	}
+	if n, err := queries.AnonymizeClickLogIPs(ctx, anonymize); err != nil || n != 0 {
+		t.Fatalf("second AnonymizeClickLogIPs = %d, %v; want nothing left", n, err)
+	}
+
+	var ip string
+	if err := db.QueryRow(`SELECT ip_address FROM click_logs WHERE code = 'spring'`).Scan(&ip); err != nil {
+		t.Fatal(err)
+	}
+	if ip != "192.0.2.0" {
+		t.Fatalf("anonymized IP = %s, want 192.0.2.0", ip)
+	}
+}
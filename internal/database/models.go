@@ -6,34 +6,128 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-type ClickLog struct {
+type AuditEvent struct {
 	ID         uuid.UUID
+	OwnerID    uuid.UUID
+	ActorID    uuid.NullUUID
+	Action     string
+	TargetType string
+	TargetID   uuid.NullUUID
+	Changes    json.RawMessage
 	IpAddress  sql.NullString
 	UserAgent  sql.NullString
-	Referrer   sql.NullString
-	ClickedAt  time.Time
-	Code       string
-	Country    sql.NullString
-	DeviceType sql.NullString
-	Traffic    sql.NullString
-	Browser    sql.NullString
+	CreatedAt  time.Time
+}
+
+type Campaign struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	Name           string
+	Description    sql.NullString
+	StartsAt       sql.NullTime
+	EndsAt         sql.NullTime
+	LinksExpiredAt sql.NullTime
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type ClickLog struct {
+	ID           uuid.UUID
+	IpAddress    sql.NullString
+	UserAgent    sql.NullString
+	Referrer     sql.NullString
+	ClickedAt    time.Time
+	Code         string
+	Country      sql.NullString
+	DeviceType   sql.NullString
+	Traffic      sql.NullString
+	Browser      sql.NullString
+	IpAnonymized bool
+	Region       sql.NullString
+	City         sql.NullString
+	Asn          sql.NullInt64
+	LinkID       uuid.NullUUID
+}
+
+type ClickLogPartition struct {
+	Month     time.Time
+	CreatedAt time.Time
+}
+
+type DataExport struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Status      string
+	Attempts    int32
+	LeaseUntil  sql.NullTime
+	BlobKey     sql.NullString
+	SizeBytes   sql.NullInt64
+	Error       sql.NullString
+	CreatedAt   time.Time
+	CompletedAt sql.NullTime
+	ExpiresAt   sql.NullTime
 }
 
 type Link struct {
-	ID              uuid.UUID
-	OriginalUrl     string
-	ShortCode       string
-	CustomShortCode sql.NullString
-	UserID          uuid.UUID
-	ExpiredAt       sql.NullTime
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
-	DeletedAt       sql.NullTime
+	ID                  uuid.UUID
+	OriginalUrl         string
+	ShortCode           string
+	CustomShortCode     sql.NullString
+	UserID              uuid.UUID
+	ExpiredAt           sql.NullTime
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	DeletedAt           sql.NullTime
+	ExpiryNotifiedAt    sql.NullTime
+	MetaTitle           sql.NullString
+	MetaDescription     sql.NullString
+	MetaImageUrl        sql.NullString
+	MetaFaviconUrl      sql.NullString
+	MetaFetchedAt       sql.NullTime
+	HealthStatus        string
+	ConsecutiveFailures int32
+	LastCheckedAt       sql.NullTime
+	TakenDownAt         sql.NullTime
+	TakedownReason      sql.NullString
+	TakenDownBy         uuid.NullUUID
+	ActiveFrom          sql.NullTime
+	PrelaunchUrl        sql.NullString
+	CampaignID          uuid.NullUUID
+	IosDeepLink         sql.NullString
+	IosStoreUrl         sql.NullString
+	AndroidDeepLink     sql.NullString
+	AndroidStoreUrl     sql.NullString
+	ForcePreview        bool
+	PublicStats         bool
+	StatsShareToken     sql.NullString
+}
+
+type LinkHealthCheck struct {
+	ID            uuid.UUID
+	LinkID        uuid.UUID
+	Success       bool
+	StatusCode    sql.NullInt32
+	LatencyMs     int32
+	RedirectChain []string
+	Error         sql.NullString
+	CheckedAt     time.Time
+}
+
+type LoginAttempt struct {
+	ID        uuid.UUID
+	UserID    uuid.NullUUID
+	Email     string
+	IpAddress string
+	UserAgent sql.NullString
+	Success   bool
+	Reason    sql.NullString
+	CreatedAt time.Time
 }
 
 type RefreshToken struct {
@@ -47,15 +141,65 @@ type RefreshToken struct {
 }
 
 type User struct {
-	ID              uuid.UUID
-	Name            string
-	Email           string
-	PasswordHash    sql.NullString
-	IsActive        bool
-	IsVerified      bool
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
-	DeletedAt       sql.NullTime
-	GoogleID        sql.NullString
-	ProfileImageUrl sql.NullString
+	ID                uuid.UUID
+	Name              string
+	Email             string
+	PasswordHash      sql.NullString
+	IsActive          bool
+	IsVerified        bool
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	DeletedAt         sql.NullTime
+	GoogleID          sql.NullString
+	ProfileImageUrl   sql.NullString
+	TotpSecret        sql.NullString
+	TotpEnabled       bool
+	Role              string
+	PasswordChangedAt sql.NullTime
+	TotpLastStep      sql.NullInt64
+}
+
+type UserRecoveryCode struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	CodeHash  string
+	UsedAt    sql.NullTime
+	CreatedAt time.Time
+}
+
+type UserToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Purpose   string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+	CreatedAt time.Time
+}
+
+type Webhook struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Url       string
+	Secret    string
+	Events    []string
+	IsActive  bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt sql.NullTime
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	WebhookID      uuid.UUID
+	Event          string
+	Payload        json.RawMessage
+	Status         string
+	Attempts       int32
+	NextAttemptAt  time.Time
+	LastStatusCode sql.NullInt32
+	LastError      sql.NullString
+	DeliveredAt    sql.NullTime
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
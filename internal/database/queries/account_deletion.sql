@@ -0,0 +1,20 @@
+-- name: GetDueAccountDeletions :many
+SELECT * FROM users
+WHERE deleted_at IS NOT NULL AND deleted_at <= @deleted_before::timestamptz
+ORDER BY deleted_at
+LIMIT @batch_size;
+
+-- name: GetUserLinkCodes :many
+SELECT short_code, custom_short_code FROM links
+WHERE user_id = $1;
+
+-- name: DeleteUserClickLogs :execrows
+DELETE FROM click_logs
+WHERE link_id IN (SELECT l.id FROM links l WHERE l.user_id = $1);
+
+-- name: PurgeAuditEvents :one
+SELECT purge_audit_events(@owner_id::uuid)::bigint AS purged;
+
+-- name: PurgeUser :execrows
+DELETE FROM users
+WHERE id = $1 AND deleted_at IS NOT NULL;
@@ -0,0 +1,72 @@
+-- name: AdminGetUsers :many
+SELECT
+    u.id,
+    u.name,
+    u.email,
+    u.role,
+    u.is_active,
+    u.is_verified,
+    u.created_at,
+    (SELECT COUNT(*) FROM links l WHERE l.user_id = u.id AND l.deleted_at IS NULL) AS link_count
+FROM users u
+WHERE u.deleted_at IS NULL
+  AND (@search::text = '' OR u.email ILIKE '%' || @search::text || '%' OR u.name ILIKE '%' || @search::text || '%')
+  AND (sqlc.narg('is_active')::boolean IS NULL OR u.is_active = sqlc.narg('is_active')::boolean)
+ORDER BY u.created_at DESC
+LIMIT $1
+OFFSET $2;
+
+-- name: SetUserActive :one
+UPDATE users SET is_active = $1, updated_at = NOW()
+WHERE id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SetUserRole :one
+UPDATE users SET role = $1, updated_at = NOW()
+WHERE id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: PromoteUsersToAdmin :exec
+UPDATE users SET role = 'admin', updated_at = NOW()
+WHERE LOWER(email) = ANY(@emails::text[]) AND role <> 'admin' AND deleted_at IS NULL;
+
+-- name: AdminGetLinks :many
+SELECT l.*, u.email AS owner_email
+FROM links l
+JOIN users u ON u.id = l.user_id
+WHERE l.deleted_at IS NULL
+  AND (@search::text = '' OR l.short_code = @search::text OR l.custom_short_code = @search::text OR l.original_url ILIKE '%' || @search::text || '%')
+  AND (NOT @taken_down_only::boolean OR l.taken_down_at IS NOT NULL)
+ORDER BY l.created_at DESC
+LIMIT $1
+OFFSET $2;
+
+-- name: AdminGetLink :one
+SELECT l.*, COUNT(cl.id) as counts FROM links l
+LEFT JOIN click_logs cl ON cl.code = l.short_code OR cl.code = l.custom_short_code
+WHERE l.id = $1 AND l.deleted_at IS NULL
+GROUP BY l.id
+LIMIT 1;
+
+-- name: TakeDownLink :one
+UPDATE links SET taken_down_at = NOW(), takedown_reason = $1, taken_down_by = $2, updated_at = NOW()
+WHERE id = $3 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreLink :one
+UPDATE links SET taken_down_at = NULL, takedown_reason = NULL, taken_down_by = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: GetPlatformGrowth :many
+SELECT
+    d.day::timestamptz AS day,
+    (SELECT COUNT(*) FROM users u WHERE u.created_at >= d.day AND u.created_at < d.day + INTERVAL '1 day') AS new_users,
+    (SELECT COUNT(*) FROM links l WHERE l.created_at >= d.day AND l.created_at < d.day + INTERVAL '1 day') AS new_links,
+    (SELECT COUNT(*) FROM click_logs cl WHERE cl.clicked_at >= d.day AND cl.clicked_at < d.day + INTERVAL '1 day') AS clicks
+FROM generate_series(
+    DATE_TRUNC('day', @from_date::timestamptz),
+    DATE_TRUNC('day', @to_date::timestamptz),
+    INTERVAL '1 day'
+) AS d(day)
+ORDER BY d.day ASC;
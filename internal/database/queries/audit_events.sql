@@ -0,0 +1,30 @@
+-- name: InsertAuditEvent :exec
+INSERT INTO audit_events(
+    owner_id,
+    actor_id,
+    action,
+    target_type,
+    target_id,
+    changes,
+    ip_address,
+    user_agent
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+);
+
+-- name: GetAuditEvents :many
+SELECT * FROM audit_events
+WHERE owner_id = $1
+  AND (sqlc.narg('target_type')::text IS NULL OR target_type = sqlc.narg('target_type')::text)
+  AND (sqlc.narg('target_id')::uuid IS NULL OR target_id = sqlc.narg('target_id')::uuid)
+  AND (sqlc.narg('action')::text IS NULL OR action = sqlc.narg('action')::text)
+ORDER BY created_at DESC
+LIMIT $2
+OFFSET $3;
@@ -0,0 +1,89 @@
+-- name: InsertCampaign :one
+INSERT INTO campaigns(
+    user_id,
+    name,
+    description,
+    starts_at,
+    ends_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+RETURNING *;
+
+-- name: GetCampaigns :many
+SELECT c.*, COUNT(l.id) AS link_count
+FROM campaigns c
+LEFT JOIN links l ON l.campaign_id = c.id AND l.deleted_at IS NULL
+WHERE c.user_id = $1
+GROUP BY c.id
+ORDER BY c.created_at DESC
+LIMIT $3
+OFFSET $2;
+
+-- name: GetCampaign :one
+SELECT c.*, COUNT(l.id) AS link_count
+FROM campaigns c
+LEFT JOIN links l ON l.campaign_id = c.id AND l.deleted_at IS NULL
+WHERE c.id = $1 AND c.user_id = $2
+GROUP BY c.id;
+
+-- name: UpdateCampaign :one
+-- Moving the end date re-arms the bulk expiry for the new date.
+UPDATE campaigns SET
+name = @name,
+description = @description,
+starts_at = @starts_at,
+links_expired_at = CASE WHEN ends_at IS DISTINCT FROM @ends_at THEN NULL ELSE links_expired_at END,
+ends_at = @ends_at,
+updated_at = NOW()
+WHERE id = @id AND user_id = @user_id
+RETURNING *;
+
+-- name: DeleteCampaign :exec
+DELETE FROM campaigns WHERE id = $1 AND user_id = $2;
+
+-- name: AddLinksToCampaign :many
+UPDATE links SET campaign_id = @campaign_id::uuid, updated_at = NOW()
+WHERE user_id = @user_id AND id = ANY(@link_ids::uuid[]) AND deleted_at IS NULL
+RETURNING id;
+
+-- name: RemoveLinkFromCampaign :execrows
+UPDATE links SET campaign_id = NULL, updated_at = NOW()
+WHERE id = @id AND user_id = @user_id AND campaign_id = @campaign_id::uuid AND deleted_at IS NULL;
+
+-- name: GetCampaignLeaderboard :many
+SELECT l.id, l.original_url, l.short_code, l.custom_short_code, COUNT(cl.id) AS total_clicks
+FROM links l
+LEFT JOIN click_logs cl ON (cl.code = l.short_code OR cl.code = l.custom_short_code)
+    AND cl.clicked_at BETWEEN @from_date::timestamp AND @to_date::timestamp
+WHERE l.campaign_id = @campaign_id::uuid AND l.user_id = @user_id AND l.deleted_at IS NULL
+GROUP BY l.id
+ORDER BY total_clicks DESC, l.created_at ASC
+LIMIT sqlc.arg('limit');
+
+-- name: GetEndedCampaigns :many
+SELECT * FROM campaigns
+WHERE ends_at <= NOW() AND links_expired_at IS NULL
+ORDER BY ends_at ASC
+LIMIT @batch_size;
+
+-- name: MarkCampaignLinksExpired :exec
+-- The end date is compared so that a campaign extended in the meantime is
+-- expired again at its new end.
+UPDATE campaigns SET links_expired_at = NOW()
+WHERE id = @id AND ends_at = @ends_at::timestamptz;
+
+-- name: ExpireCampaignLinks :many
+-- Links that expire before the campaign ends keep their own date. Links that
+-- are only scheduled to start after it ended are left alone, since they
+-- cannot expire before they start.
+UPDATE links SET expired_at = @ends_at::timestamptz, expiry_notified_at = NULL, updated_at = NOW()
+WHERE campaign_id = @campaign_id::uuid
+  AND deleted_at IS NULL
+  AND (expired_at IS NULL OR expired_at > @ends_at::timestamptz)
+  AND (active_from IS NULL OR active_from < @ends_at::timestamptz)
+RETURNING short_code, custom_short_code;
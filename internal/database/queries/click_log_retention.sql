@@ -0,0 +1,34 @@
+-- name: EnsureClickLogPartition :one
+SELECT ensure_click_log_partition(@month::date)::boolean AS created;
+
+-- name: GetClickLogPartitionsBefore :many
+SELECT month FROM click_log_partitions
+WHERE month < @before::date
+ORDER BY month;
+
+-- name: DropClickLogPartition :exec
+SELECT drop_click_log_partition(@month::date);
+
+-- name: GetClickLogsForArchive :many
+SELECT * FROM click_logs
+WHERE clicked_at >= @from_time::timestamptz
+  AND clicked_at < @to_time::timestamptz
+  AND (clicked_at, id) > (@after_clicked_at::timestamptz, @after_id::uuid)
+ORDER BY clicked_at, id
+LIMIT @batch_size;
+
+-- name: DeleteClickLogsBefore :execrows
+DELETE FROM click_logs
+WHERE clicked_at < @before::timestamptz;
+
+-- name: AnonymizeClickLogIPs :execrows
+UPDATE click_logs
+SET ip_address = anonymize_ip(ip_address),
+    ip_anonymized = TRUE
+WHERE (id, clicked_at) IN (
+    SELECT cl.id, cl.clicked_at FROM click_logs cl
+    WHERE cl.clicked_at < @before::timestamptz
+      AND NOT cl.ip_anonymized
+      AND cl.ip_address IS NOT NULL
+    LIMIT @batch_size
+);
@@ -7,7 +7,11 @@ INSERT INTO click_logs (
     country,
     traffic,
     device_type,
-    browser
+    browser,
+    region,
+    city,
+    asn,
+    link_id
 ) VALUES (
     $1,
     $2,
@@ -16,10 +20,45 @@ INSERT INTO click_logs (
     $5,
     $6,
     $7,
-    $8
+    $8,
+    $9,
+    $10,
+    $11,
+    (SELECT l.id FROM links l WHERE l.short_code = $1 OR l.custom_short_code = $1 LIMIT 1)
 )
 RETURNING *;
 
+-- name: InsertSpooledClickLog :exec
+INSERT INTO click_logs (
+    code,
+    ip_address,
+    user_agent,
+    referrer,
+    country,
+    traffic,
+    device_type,
+    browser,
+    region,
+    city,
+    asn,
+    clicked_at,
+    link_id
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    @clicked_at,
+    (SELECT l.id FROM links l WHERE l.short_code = $1 OR l.custom_short_code = $1 LIMIT 1)
+);
+
 -- name: GetTotalClicks :one
 SELECT 
     COUNT(*) AS total
@@ -27,7 +66,9 @@ FROM click_logs cl
 LEFT JOIN links l ON l.short_code = cl.code OR l.custom_short_code = cl.code
 WHERE cl.clicked_at BETWEEN @from_date::timestamp AND @to_date::timestamp
   AND l.user_id = $1
-  AND l.deleted_at IS NULL;
+  AND l.deleted_at IS NULL
+  AND (sqlc.narg('campaign_id')::uuid IS NULL OR l.campaign_id = sqlc.narg('campaign_id')::uuid)
+  AND (sqlc.narg('link_id')::uuid IS NULL OR l.id = sqlc.narg('link_id')::uuid);
 
 -- name: GetByDateRange :many
 SELECT 
@@ -36,6 +77,8 @@ SELECT
 FROM click_logs cl
 LEFT JOIN links l ON l.short_code = cl.code OR l.custom_short_code = cl.code
 WHERE cl.clicked_at BETWEEN @from_date::timestamp AND @to_date::timestamp AND l.user_id = $1 AND l.deleted_at IS NULL
+  AND (sqlc.narg('campaign_id')::uuid IS NULL OR l.campaign_id = sqlc.narg('campaign_id')::uuid)
+  AND (sqlc.narg('link_id')::uuid IS NULL OR l.id = sqlc.narg('link_id')::uuid)
 GROUP BY DATE_TRUNC('day', cl.clicked_at)
 ORDER BY date ASC;
 
@@ -46,6 +89,7 @@ SELECT
 FROM click_logs cl
 LEFT JOIN links l ON l.short_code = cl.code OR l.custom_short_code = cl.code
 WHERE cl.clicked_at BETWEEN @from_date::timestamp AND @to_date::timestamp AND l.user_id = $1 AND l.deleted_at IS NULL
+  AND (sqlc.narg('campaign_id')::uuid IS NULL OR l.campaign_id = sqlc.narg('campaign_id')::uuid)
 GROUP BY cl.device_type
 ORDER BY total DESC;
 
@@ -66,10 +110,38 @@ SELECT
 FROM click_logs cl
 LEFT JOIN links l ON l.short_code = cl.code OR l.custom_short_code = cl.code
 WHERE cl.clicked_at BETWEEN @from_date::timestamp AND @to_date::timestamp AND l.user_id = $1 AND l.deleted_at IS NULL
+  AND (sqlc.narg('campaign_id')::uuid IS NULL OR l.campaign_id = sqlc.narg('campaign_id')::uuid)
 GROUP BY cl.country
 ORDER BY total DESC
 LIMIT 10;
 
+-- name: GetTopRegions :many
+SELECT 
+    COALESCE(cl.country, 'Unknown') AS country,
+    COALESCE(cl.region, 'Unknown') AS region,
+    COUNT(*) AS total
+FROM click_logs cl
+LEFT JOIN links l ON l.short_code = cl.code OR l.custom_short_code = cl.code
+WHERE cl.clicked_at BETWEEN @from_date::timestamp AND @to_date::timestamp AND l.user_id = $1 AND l.deleted_at IS NULL
+  AND (sqlc.narg('campaign_id')::uuid IS NULL OR l.campaign_id = sqlc.narg('campaign_id')::uuid)
+GROUP BY cl.country, cl.region
+ORDER BY total DESC
+LIMIT 10;
+
+-- name: GetTopCities :many
+SELECT 
+    COALESCE(cl.country, 'Unknown') AS country,
+    COALESCE(cl.region, 'Unknown') AS region,
+    COALESCE(cl.city, 'Unknown') AS city,
+    COUNT(*) AS total
+FROM click_logs cl
+LEFT JOIN links l ON l.short_code = cl.code OR l.custom_short_code = cl.code
+WHERE cl.clicked_at BETWEEN @from_date::timestamp AND @to_date::timestamp AND l.user_id = $1 AND l.deleted_at IS NULL
+  AND (sqlc.narg('campaign_id')::uuid IS NULL OR l.campaign_id = sqlc.narg('campaign_id')::uuid)
+GROUP BY cl.country, cl.region, cl.city
+ORDER BY total DESC
+LIMIT 10;
+
 -- name: GetTopCountriesSingle :many
 SELECT 
     COALESCE(cl.country, 'Unknown') AS country,
@@ -88,6 +160,7 @@ SELECT
 FROM click_logs cl
 LEFT JOIN links l ON l.short_code = cl.code OR l.custom_short_code = cl.code
 WHERE cl.clicked_at BETWEEN @from_date::timestamp AND @to_date::timestamp AND l.user_id = $1 AND l.deleted_at IS NULL
+  AND (sqlc.narg('campaign_id')::uuid IS NULL OR l.campaign_id = sqlc.narg('campaign_id')::uuid)
 GROUP BY cl.traffic
 ORDER BY total DESC;
 
@@ -98,5 +171,6 @@ SELECT
 FROM click_logs cl
 LEFT JOIN links l ON l.short_code = cl.code OR l.custom_short_code = cl.code
 WHERE cl.clicked_at BETWEEN @from_date::timestamp AND @to_date::timestamp AND l.user_id = $1 AND l.deleted_at IS NULL
+  AND (sqlc.narg('campaign_id')::uuid IS NULL OR l.campaign_id = sqlc.narg('campaign_id')::uuid)
 GROUP BY cl.browser
 ORDER BY total DESC;
\ No newline at end of file
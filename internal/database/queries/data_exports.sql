@@ -0,0 +1,69 @@
+-- name: InsertDataExport :one
+INSERT INTO data_exports(user_id) VALUES ($1)
+RETURNING *;
+
+-- name: GetLatestDataExport :one
+SELECT * FROM data_exports
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: GetDataExport :one
+SELECT * FROM data_exports
+WHERE id = $1 AND user_id = $2;
+
+-- name: ClaimPendingDataExports :many
+UPDATE data_exports SET status = 'processing', attempts = attempts + 1, lease_until = @lease_until::timestamptz
+WHERE id IN (
+    SELECT de.id FROM data_exports de
+    WHERE de.status = 'pending' OR (de.status = 'processing' AND de.lease_until < NOW())
+    ORDER BY de.created_at
+    LIMIT @batch_size
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteDataExport :exec
+UPDATE data_exports SET
+    status = 'ready',
+    lease_until = NULL,
+    blob_key = $1,
+    size_bytes = $2,
+    error = NULL,
+    completed_at = NOW(),
+    expires_at = $3
+WHERE id = $4;
+
+-- name: FailDataExport :exec
+UPDATE data_exports SET
+    status = $1,
+    lease_until = NULL,
+    error = $2,
+    completed_at = CASE WHEN $1 = 'failed' THEN NOW() END,
+    expires_at = $3
+WHERE id = $4;
+
+-- name: GetExpiredDataExports :many
+SELECT * FROM data_exports
+WHERE expires_at < NOW()
+ORDER BY expires_at
+LIMIT $1;
+
+-- name: DeleteDataExport :exec
+DELETE FROM data_exports WHERE id = $1;
+
+-- name: GetUserDataExportBlobKeys :many
+SELECT blob_key::text FROM data_exports
+WHERE user_id = $1 AND blob_key IS NOT NULL;
+
+-- name: GetUserLinksForExport :many
+SELECT * FROM links
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: GetUserClickLogsForExport :many
+SELECT cl.* FROM click_logs cl
+WHERE cl.link_id IN (SELECT l.id FROM links l WHERE l.user_id = $1)
+  AND (cl.clicked_at, cl.id) > (@after_clicked_at::timestamptz, @after_id::uuid)
+ORDER BY cl.clicked_at, cl.id
+LIMIT @batch_size;
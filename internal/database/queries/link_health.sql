@@ -0,0 +1,46 @@
+-- name: ClaimLinksForHealthCheck :many
+UPDATE links SET last_checked_at = NOW()
+WHERE id IN (
+    SELECT l.id FROM links l
+    WHERE l.deleted_at IS NULL
+      AND (l.expired_at IS NULL OR l.expired_at > NOW())
+      AND (l.active_from IS NULL OR l.active_from <= NOW())
+      AND (l.last_checked_at IS NULL OR l.last_checked_at <= @checked_before::timestamptz)
+    ORDER BY l.last_checked_at NULLS FIRST
+    LIMIT @batch_size
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, original_url;
+
+-- name: InsertLinkHealthCheck :exec
+INSERT INTO link_health_checks(
+    link_id,
+    success,
+    status_code,
+    latency_ms,
+    redirect_chain,
+    error
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+);
+
+-- name: MarkLinkHealthy :exec
+UPDATE links SET health_status = 'healthy', consecutive_failures = 0 WHERE id = $1;
+
+-- name: MarkLinkHealthFailure :one
+UPDATE links SET
+consecutive_failures = consecutive_failures + 1,
+health_status = CASE WHEN consecutive_failures + 1 >= @failure_threshold::int THEN 'broken' ELSE health_status END
+WHERE id = @id
+RETURNING *;
+
+-- name: GetLinkHealthChecks :many
+SELECT * FROM link_health_checks WHERE link_id = $1 ORDER BY checked_at DESC LIMIT $2;
+
+-- name: DeleteLinkHealthChecksBefore :exec
+DELETE FROM link_health_checks WHERE checked_at < $1;
@@ -4,18 +4,46 @@ INSERT INTO links(
     short_code,
     custom_short_code,
     user_id,
-    expired_at
+    expired_at,
+    active_from,
+    prelaunch_url,
+    ios_deep_link,
+    ios_store_url,
+    android_deep_link,
+    android_store_url,
+    force_preview
 ) VALUES (
     $1, 
     $2, 
     $3, 
     $4, 
-    $5
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12
 ) 
 RETURNING *;
 
 -- name: GetRedirectLink :one
-SELECT original_url FROM links WHERE (short_code = $1 OR custom_short_code = $1) AND deleted_at IS NULL;
+SELECT original_url, taken_down_at, takedown_reason, active_from, expired_at, prelaunch_url,
+    ios_deep_link, ios_store_url, android_deep_link, android_store_url, force_preview
+FROM links WHERE (short_code = $1 OR custom_short_code = $1) AND deleted_at IS NULL;
+
+-- name: GetLinkPreview :one
+SELECT original_url, meta_title, meta_description, meta_image_url, health_status,
+    taken_down_at, takedown_reason, active_from, expired_at, created_at
+FROM links WHERE (short_code = $1 OR custom_short_code = $1) AND deleted_at IS NULL;
+
+-- name: GetLinkByStatsToken :one
+SELECT id, user_id, original_url, short_code, custom_short_code, created_at FROM links
+WHERE stats_share_token = $1 AND public_stats AND deleted_at IS NULL AND taken_down_at IS NULL;
+
+-- name: GetLinkByCode :one
+SELECT id, user_id FROM links WHERE (short_code = $1 OR custom_short_code = $1) AND deleted_at IS NULL;
 
 -- name: GetLink :one
 SELECT l.*, COUNT(cl.id) as counts FROM links l
@@ -29,6 +57,13 @@ SELECT l.*, COUNT(cl.id) as counts
 FROM links l
 LEFT JOIN click_logs cl ON cl.code = l.short_code OR cl.code = l.custom_short_code
 WHERE l.user_id = $1 AND l.deleted_at IS NULL
+  AND (sqlc.narg('health_status')::text IS NULL OR l.health_status = sqlc.narg('health_status')::text)
+  AND (
+    sqlc.narg('state')::text IS NULL
+    OR (sqlc.narg('state')::text = 'scheduled' AND l.active_from > NOW())
+    OR (sqlc.narg('state')::text = 'active' AND (l.active_from IS NULL OR l.active_from <= NOW()) AND (l.expired_at IS NULL OR l.expired_at > NOW()))
+    OR (sqlc.narg('state')::text = 'expired' AND l.expired_at <= NOW())
+  )
 GROUP BY l.id
 ORDER BY
   CASE WHEN @order_by::text = 'created_at' THEN l.created_at END DESC,
@@ -38,11 +73,42 @@ ORDER BY
 LIMIT $3
 OFFSET $2;
 
--- name: UpdateLink :exec
-UPDATE links SET custom_short_code = $1, original_url = $2, expired_at = $3 WHERE id = $4 AND deleted_at IS NULL;
+-- name: UpdateLink :one
+UPDATE links SET
+custom_short_code = $1, original_url = $2, expired_at = $3, active_from = $6, prelaunch_url = $7,
+ios_deep_link = $8, ios_store_url = $9, android_deep_link = $10, android_store_url = $11,
+force_preview = $12, expiry_notified_at = NULL, updated_at = NOW()
+WHERE id = $4 AND user_id = $5 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SetLinkPublicStats :one
+-- The share token is kept when public stats are turned off, so turning them
+-- back on restores the links that were handed out.
+UPDATE links SET
+public_stats = @public_stats, stats_share_token = COALESCE(stats_share_token, @stats_share_token::text), updated_at = NOW()
+WHERE id = @id AND user_id = @user_id AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RotateLinkStatsToken :one
+UPDATE links SET stats_share_token = @stats_share_token::text, updated_at = NOW()
+WHERE id = @id AND user_id = @user_id AND deleted_at IS NULL
+RETURNING *;
 
 -- name: GetTotalActiveLinks :one
 SELECT COUNT(*) as total FROM links l WHERE l.user_id = $1 AND l.deleted_at IS NULL;
 
 -- name: DeleteLink :exec
-UPDATE links SET deleted_at = NOW() WHERE id = $1 AND user_id = $2;
\ No newline at end of file
+UPDATE links SET deleted_at = NOW() WHERE id = $1 AND user_id = $2;
+
+-- name: ClaimExpiredLinks :many
+UPDATE links SET expiry_notified_at = NOW()
+WHERE expired_at <= NOW() AND expiry_notified_at IS NULL AND deleted_at IS NULL
+RETURNING *;
+
+-- name: UpdateLinkMetadata :exec
+UPDATE links SET
+meta_title = $1, meta_description = $2, meta_image_url = $3, meta_favicon_url = $4, meta_fetched_at = NOW()
+WHERE id = @id AND original_url = @original_url;
+
+-- name: ShortCodeExists :one
+SELECT EXISTS(SELECT 1 FROM links WHERE short_code = $1 OR custom_short_code = $1);
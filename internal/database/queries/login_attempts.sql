@@ -0,0 +1,44 @@
+-- name: InsertLoginAttempt :exec
+INSERT INTO login_attempts(
+    user_id,
+    email,
+    ip_address,
+    user_agent,
+    success,
+    reason
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+);
+
+-- name: GetEmailLoginFailures :one
+SELECT
+    COUNT(*) AS failures,
+    COALESCE(MAX(la.created_at), 'epoch')::timestamptz AS last_failure_at
+FROM login_attempts la
+WHERE la.email = @email
+  AND la.success = FALSE
+  AND la.created_at > @since::timestamptz
+  AND la.created_at > COALESCE(
+    (SELECT MAX(s.created_at) FROM login_attempts s WHERE s.email = @email AND s.success = TRUE),
+    'epoch'
+  );
+
+-- name: GetIPLoginFailures :one
+SELECT
+    COUNT(*) AS failures,
+    COALESCE(MAX(created_at), 'epoch')::timestamptz AS last_failure_at
+FROM login_attempts
+WHERE ip_address = @ip_address
+  AND success = FALSE
+  AND created_at > @since::timestamptz;
+
+-- name: GetUserLoginAttempts :many
+SELECT * FROM login_attempts
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
@@ -0,0 +1,32 @@
+-- name: SetUserTotpSecret :exec
+UPDATE users SET totp_secret = $1, totp_enabled = FALSE, totp_last_step = NULL, updated_at = NOW()
+WHERE id = $2 AND deleted_at IS NULL;
+
+-- name: EnableUserTotp :exec
+UPDATE users SET totp_enabled = TRUE, updated_at = NOW()
+WHERE id = $1 AND totp_secret IS NOT NULL AND deleted_at IS NULL;
+
+-- name: DisableUserTotp :exec
+UPDATE users SET totp_secret = NULL, totp_enabled = FALSE, totp_last_step = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: ClaimUserTotpStep :execrows
+UPDATE users SET totp_last_step = @step::bigint
+WHERE id = @id AND (totp_last_step IS NULL OR totp_last_step < @step::bigint);
+
+-- name: InsertRecoveryCode :exec
+INSERT INTO user_recovery_codes(
+    user_id,
+    code_hash
+) VALUES (
+    $1,
+    $2
+);
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM user_recovery_codes WHERE user_id = $1;
+
+-- name: ConsumeRecoveryCode :one
+UPDATE user_recovery_codes SET used_at = NOW()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+RETURNING *;
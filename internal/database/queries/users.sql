@@ -45,4 +45,23 @@ INSERT INTO users(
     TRUE,
     $4
 )
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: MarkUserVerified :one
+UPDATE users SET is_verified = TRUE, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: UpdateUserPassword :exec
+UPDATE users SET password_hash = $1, password_changed_at = NOW(), updated_at = NOW()
+WHERE id = $2 AND deleted_at IS NULL;
+
+-- name: SoftDeleteUser :one
+UPDATE users SET deleted_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreUser :one
+UPDATE users SET deleted_at = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
@@ -0,0 +1,93 @@
+-- name: InsertWebhook :one
+INSERT INTO webhooks(
+    user_id,
+    url,
+    secret,
+    events
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+RETURNING *;
+
+-- name: GetWebhooks :many
+SELECT * FROM webhooks
+WHERE user_id = $1 AND deleted_at IS NULL
+ORDER BY created_at DESC;
+
+-- name: GetWebhook :one
+SELECT * FROM webhooks
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL;
+
+-- name: DeleteWebhook :exec
+UPDATE webhooks SET deleted_at = NOW() WHERE id = $1 AND user_id = $2;
+
+-- name: DisableWebhook :exec
+UPDATE webhooks SET is_active = FALSE, updated_at = NOW() WHERE id = $1;
+
+-- name: EnableWebhook :one
+UPDATE webhooks SET is_active = TRUE, updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: GetSubscribedWebhooks :many
+SELECT * FROM webhooks
+WHERE user_id = $1 AND @event::text = ANY(events) AND is_active = TRUE AND deleted_at IS NULL;
+
+-- name: InsertWebhookDelivery :one
+INSERT INTO webhook_deliveries(
+    webhook_id,
+    event,
+    payload
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+RETURNING *;
+
+-- name: GetWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE webhook_id = $1
+ORDER BY created_at DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ClaimDueWebhookDeliveries :many
+-- Deliveries of deleted webhooks are never sent. Those of inactive webhooks
+-- stay pending until the webhook is enabled again.
+UPDATE webhook_deliveries d SET next_attempt_at = @lease_until::timestamptz
+FROM webhooks w
+WHERE w.id = d.webhook_id
+  AND d.id IN (
+    SELECT wd.id FROM webhook_deliveries wd
+    JOIN webhooks ww ON ww.id = wd.webhook_id
+    WHERE wd.status = 'pending' AND wd.next_attempt_at <= NOW()
+      AND ww.deleted_at IS NULL AND ww.is_active
+    ORDER BY wd.next_attempt_at
+    LIMIT @batch_size
+    FOR UPDATE OF wd SKIP LOCKED
+  )
+RETURNING d.id, d.webhook_id, d.event, d.payload, d.attempts, d.created_at, w.url, w.secret;
+
+-- name: UpdateWebhookDeliveryAttempt :exec
+UPDATE webhook_deliveries SET
+    status = $1,
+    attempts = attempts + 1,
+    next_attempt_at = $2,
+    last_status_code = $3,
+    last_error = $4,
+    delivered_at = $5,
+    updated_at = NOW()
+WHERE id = $6;
+
+-- name: RedeliverWebhookDelivery :one
+UPDATE webhook_deliveries SET
+    status = 'pending',
+    attempts = 0,
+    next_attempt_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND webhook_id = $2
+RETURNING *;
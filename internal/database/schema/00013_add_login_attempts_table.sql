@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE login_attempts (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID,
+    email       VARCHAR(255) NOT NULL,
+    ip_address  VARCHAR(255) NOT NULL,
+    user_agent  VARCHAR(512),
+    success     BOOLEAN NOT NULL,
+    reason      VARCHAR(64),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_login_attempts_email ON login_attempts(email, created_at DESC);
+CREATE INDEX idx_login_attempts_ip ON login_attempts(ip_address, created_at DESC);
+CREATE INDEX idx_login_attempts_user_id ON login_attempts(user_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE login_attempts;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE links ADD COLUMN meta_title TEXT;
+ALTER TABLE links ADD COLUMN meta_description TEXT;
+ALTER TABLE links ADD COLUMN meta_image_url TEXT;
+ALTER TABLE links ADD COLUMN meta_favicon_url TEXT;
+ALTER TABLE links ADD COLUMN meta_fetched_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE links DROP COLUMN IF EXISTS meta_fetched_at;
+ALTER TABLE links DROP COLUMN IF EXISTS meta_favicon_url;
+ALTER TABLE links DROP COLUMN IF EXISTS meta_image_url;
+ALTER TABLE links DROP COLUMN IF EXISTS meta_description;
+ALTER TABLE links DROP COLUMN IF EXISTS meta_title;
+-- +goose StatementEnd
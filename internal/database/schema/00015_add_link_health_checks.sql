@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE links ADD COLUMN health_status VARCHAR(16) NOT NULL DEFAULT 'unknown';
+ALTER TABLE links ADD COLUMN consecutive_failures INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE links ADD COLUMN last_checked_at TIMESTAMPTZ;
+
+CREATE INDEX idx_links_last_checked_at ON links(last_checked_at NULLS FIRST) WHERE deleted_at IS NULL;
+
+CREATE TABLE link_health_checks (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    link_id         UUID NOT NULL,
+    success         BOOLEAN NOT NULL,
+    status_code     INTEGER,
+    latency_ms      INTEGER NOT NULL,
+    redirect_chain  TEXT[] NOT NULL DEFAULT '{}',
+    error           TEXT,
+    checked_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (link_id) REFERENCES links(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_link_health_checks_link_id ON link_health_checks(link_id, checked_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE link_health_checks;
+DROP INDEX IF EXISTS idx_links_last_checked_at;
+ALTER TABLE links DROP COLUMN IF EXISTS last_checked_at;
+ALTER TABLE links DROP COLUMN IF EXISTS consecutive_failures;
+ALTER TABLE links DROP COLUMN IF EXISTS health_status;
+-- +goose StatementEnd
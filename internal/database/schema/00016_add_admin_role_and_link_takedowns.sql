@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin'));
+
+ALTER TABLE links ADD COLUMN taken_down_at TIMESTAMPTZ;
+ALTER TABLE links ADD COLUMN takedown_reason TEXT;
+ALTER TABLE links ADD COLUMN taken_down_by UUID REFERENCES users(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE links DROP COLUMN IF EXISTS taken_down_by;
+ALTER TABLE links DROP COLUMN IF EXISTS takedown_reason;
+ALTER TABLE links DROP COLUMN IF EXISTS taken_down_at;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+-- +goose StatementEnd
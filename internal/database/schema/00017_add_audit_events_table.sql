@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE audit_events (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id    UUID NOT NULL,
+    actor_id    UUID,
+    action      VARCHAR(64) NOT NULL,
+    target_type VARCHAR(32) NOT NULL,
+    target_id   UUID,
+    changes     JSONB NOT NULL DEFAULT '{}',
+    ip_address  VARCHAR(64),
+    user_agent  VARCHAR(512),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_events_owner_id ON audit_events(owner_id, created_at DESC);
+CREATE INDEX idx_audit_events_target ON audit_events(target_type, target_id);
+
+-- Audit events are append-only. Owner and actor ids are deliberately not
+-- foreign keys so the history outlives the rows it describes.
+CREATE FUNCTION prevent_audit_event_changes() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+BEFORE UPDATE OR DELETE ON audit_events
+FOR EACH ROW EXECUTE FUNCTION prevent_audit_event_changes();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE audit_events;
+DROP FUNCTION IF EXISTS prevent_audit_event_changes();
+-- +goose StatementEnd
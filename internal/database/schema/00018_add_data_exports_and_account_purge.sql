@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE data_exports (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id         UUID NOT NULL,
+    status          VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'ready', 'failed')),
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    lease_until     TIMESTAMPTZ,
+    blob_key        TEXT,
+    size_bytes      BIGINT,
+    error           TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at    TIMESTAMPTZ,
+    expires_at      TIMESTAMPTZ,
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_data_exports_user_id ON data_exports(user_id, created_at DESC);
+CREATE INDEX idx_data_exports_pending ON data_exports(created_at) WHERE status IN ('pending', 'processing');
+CREATE INDEX idx_data_exports_expires_at ON data_exports(expires_at) WHERE expires_at IS NOT NULL;
+
+CREATE INDEX idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Audit events stay append-only, except that purge_audit_events may erase
+-- the history of an account that is being permanently deleted.
+CREATE OR REPLACE FUNCTION prevent_audit_event_changes() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' AND current_setting('audit_events.purging', true) = 'on' THEN
+        RETURN OLD;
+    END IF;
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION purge_audit_events(p_owner_id UUID) RETURNS BIGINT AS $$
+DECLARE
+    purged BIGINT;
+BEGIN
+    PERFORM set_config('audit_events.purging', 'on', true);
+    DELETE FROM audit_events WHERE owner_id = p_owner_id;
+    GET DIAGNOSTICS purged = ROW_COUNT;
+    PERFORM set_config('audit_events.purging', 'off', true);
+    RETURN purged;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS purge_audit_events(UUID);
+
+CREATE OR REPLACE FUNCTION prevent_audit_event_changes() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS idx_users_deleted_at;
+DROP TABLE data_exports;
+-- +goose StatementEnd
@@ -0,0 +1,163 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE click_logs RENAME TO click_logs_unpartitioned;
+ALTER TABLE click_logs_unpartitioned RENAME CONSTRAINT click_logs_pkey TO click_logs_unpartitioned_pkey;
+ALTER INDEX idx_click_logs_code RENAME TO idx_click_logs_unpartitioned_code;
+
+-- Clicks are partitioned by calendar month (UTC) so retention can drop a
+-- whole month at once and date range scans only touch the months involved.
+-- The primary key has to include the partition key. chk_code_exists is not
+-- carried over: it is checked again whenever a row is copied or updated, which
+-- fails for clicks of a custom code that has since been renamed.
+CREATE TABLE click_logs (
+    id              UUID NOT NULL DEFAULT gen_random_uuid(),
+    ip_address      VARCHAR(255),
+    user_agent      VARCHAR(255),
+    referrer        VARCHAR(255),
+    clicked_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    code            VARCHAR(255) NOT NULL,
+    country         TEXT,
+    device_type     TEXT,
+    traffic         TEXT,
+    browser         TEXT,
+    ip_anonymized   BOOLEAN NOT NULL DEFAULT FALSE,
+
+    PRIMARY KEY (id, clicked_at)
+) PARTITION BY RANGE (clicked_at);
+
+CREATE INDEX idx_click_logs_code ON click_logs(code);
+CREATE INDEX idx_click_logs_clicked_at ON click_logs(clicked_at);
+CREATE INDEX idx_click_logs_ip_pending ON click_logs(clicked_at) WHERE NOT ip_anonymized AND ip_address IS NOT NULL;
+
+-- Monthly partitions that currently exist, keyed by the first day of the month.
+CREATE TABLE click_log_partitions (
+    month       DATE PRIMARY KEY,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- ensure_click_log_partition creates the partition holding p_month and
+-- reports whether it had to. Clicks of that month that were written to the
+-- default partition in the meantime are moved into it.
+CREATE FUNCTION ensure_click_log_partition(p_month DATE) RETURNS BOOLEAN AS $$
+DECLARE
+    month_start    DATE := date_trunc('month', p_month)::date;
+    range_start    TIMESTAMPTZ := month_start::timestamp AT TIME ZONE 'UTC';
+    range_end      TIMESTAMPTZ := (month_start + INTERVAL '1 month')::timestamp AT TIME ZONE 'UTC';
+    partition_name TEXT := 'click_logs_p' || to_char(month_start, 'YYYY_MM');
+BEGIN
+    LOCK TABLE click_log_partitions IN EXCLUSIVE MODE;
+
+    IF to_regclass(partition_name) IS NOT NULL THEN
+        RETURN FALSE;
+    END IF;
+
+    CREATE TEMP TABLE click_logs_moving (LIKE click_logs);
+    WITH moved AS (
+        DELETE FROM click_logs_default
+        WHERE clicked_at >= range_start AND clicked_at < range_end
+        RETURNING *
+    )
+    INSERT INTO click_logs_moving SELECT * FROM moved;
+
+    EXECUTE format(
+        'CREATE TABLE %I PARTITION OF click_logs FOR VALUES FROM (%L) TO (%L)',
+        partition_name, range_start, range_end
+    );
+
+    INSERT INTO click_logs SELECT * FROM click_logs_moving;
+    DROP TABLE click_logs_moving;
+
+    INSERT INTO click_log_partitions (month) VALUES (month_start)
+    ON CONFLICT (month) DO NOTHING;
+    RETURN TRUE;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION drop_click_log_partition(p_month DATE) RETURNS VOID AS $$
+DECLARE
+    month_start DATE := date_trunc('month', p_month)::date;
+BEGIN
+    EXECUTE format('DROP TABLE IF EXISTS %I', 'click_logs_p' || to_char(month_start, 'YYYY_MM'));
+    DELETE FROM click_log_partitions WHERE month = month_start;
+END;
+$$ LANGUAGE plpgsql;
+
+-- anonymize_ip keeps the /24 network of an IPv4 address and the /48 of an
+-- IPv6 address, which is still enough to tell countries apart.
+CREATE FUNCTION anonymize_ip(p_ip TEXT) RETURNS TEXT AS $$
+DECLARE
+    addr INET;
+BEGIN
+    IF p_ip IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    BEGIN
+        addr := p_ip::inet;
+    EXCEPTION WHEN invalid_text_representation THEN
+        RETURN NULL;
+    END;
+
+    IF family(addr) = 4 THEN
+        RETURN host(network(set_masklen(addr, 24)));
+    END IF;
+    RETURN host(network(set_masklen(addr, 48)));
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+DO $$
+BEGIN
+    EXECUTE 'CREATE TABLE click_logs_default PARTITION OF click_logs DEFAULT';
+END
+$$;
+
+SELECT ensure_click_log_partition(month)
+FROM (
+    SELECT DISTINCT date_trunc('month', clicked_at AT TIME ZONE 'UTC')::date AS month
+    FROM click_logs_unpartitioned
+    UNION
+    SELECT (date_trunc('month', NOW() AT TIME ZONE 'UTC') + n * INTERVAL '1 month')::date
+    FROM generate_series(0, 2) AS n
+) months;
+
+INSERT INTO click_logs (id, ip_address, user_agent, referrer, clicked_at, code, country, device_type, traffic, browser)
+SELECT id, ip_address, user_agent, referrer, clicked_at, code, country, device_type, traffic, browser
+FROM click_logs_unpartitioned;
+
+DROP TABLE click_logs_unpartitioned;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE TABLE click_logs_unpartitioned (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    ip_address  VARCHAR(255),
+    user_agent  VARCHAR(255),
+    referrer    VARCHAR(255),
+    clicked_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    code        VARCHAR(255) NOT NULL,
+    country     TEXT,
+    device_type TEXT,
+    traffic     TEXT,
+    browser     TEXT
+);
+
+INSERT INTO click_logs_unpartitioned (id, ip_address, user_agent, referrer, clicked_at, code, country, device_type, traffic, browser)
+SELECT id, ip_address, user_agent, referrer, clicked_at, code, country, device_type, traffic, browser
+FROM click_logs;
+
+-- Clicks of renamed codes would fail the check, so only new rows are held
+-- to it.
+ALTER TABLE click_logs_unpartitioned ADD CONSTRAINT chk_code_exists
+    CHECK (check_code_exists(code)) NOT VALID;
+
+DROP TABLE click_logs;
+DROP TABLE click_log_partitions;
+DROP FUNCTION IF EXISTS ensure_click_log_partition(DATE);
+DROP FUNCTION IF EXISTS drop_click_log_partition(DATE);
+DROP FUNCTION IF EXISTS anonymize_ip(TEXT);
+
+ALTER TABLE click_logs_unpartitioned RENAME TO click_logs;
+ALTER TABLE click_logs RENAME CONSTRAINT click_logs_unpartitioned_pkey TO click_logs_pkey;
+CREATE INDEX idx_click_logs_code ON click_logs(code);
+-- +goose StatementEnd
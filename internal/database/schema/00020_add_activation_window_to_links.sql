@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE links ADD COLUMN active_from TIMESTAMPTZ;
+ALTER TABLE links ADD COLUMN prelaunch_url TEXT;
+
+ALTER TABLE links ADD CONSTRAINT chk_links_activation_window
+    CHECK (active_from IS NULL OR expired_at IS NULL OR active_from < expired_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE links DROP CONSTRAINT IF EXISTS chk_links_activation_window;
+ALTER TABLE links DROP COLUMN IF EXISTS prelaunch_url;
+ALTER TABLE links DROP COLUMN IF EXISTS active_from;
+-- +goose StatementEnd
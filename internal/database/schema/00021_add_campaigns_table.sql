@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE campaigns (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id             UUID NOT NULL,
+    name                VARCHAR(255) NOT NULL,
+    description         TEXT,
+    starts_at           TIMESTAMPTZ,
+    ends_at             TIMESTAMPTZ,
+    -- links_expired_at is set once the links of an ended campaign have been
+    -- expired, so the campaign worker handles each end only once.
+    links_expired_at    TIMESTAMPTZ,
+
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT chk_campaigns_dates CHECK (starts_at IS NULL OR ends_at IS NULL OR starts_at < ends_at)
+);
+
+CREATE INDEX idx_campaigns_user_id ON campaigns(user_id);
+CREATE INDEX idx_campaigns_ends_at ON campaigns(ends_at) WHERE links_expired_at IS NULL;
+
+ALTER TABLE links ADD COLUMN campaign_id UUID REFERENCES campaigns(id) ON DELETE SET NULL;
+CREATE INDEX idx_links_campaign_id ON links(campaign_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_links_campaign_id;
+ALTER TABLE links DROP COLUMN IF EXISTS campaign_id;
+DROP TABLE IF EXISTS campaigns;
+-- +goose StatementEnd
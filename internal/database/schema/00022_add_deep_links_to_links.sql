@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Deep links open a mobile app instead of original_url. The store URLs are
+-- where visitors go when the app does not open.
+ALTER TABLE links ADD COLUMN ios_deep_link TEXT;
+ALTER TABLE links ADD COLUMN ios_store_url TEXT;
+ALTER TABLE links ADD COLUMN android_deep_link TEXT;
+ALTER TABLE links ADD COLUMN android_store_url TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE links DROP COLUMN IF EXISTS android_store_url;
+ALTER TABLE links DROP COLUMN IF EXISTS android_deep_link;
+ALTER TABLE links DROP COLUMN IF EXISTS ios_store_url;
+ALTER TABLE links DROP COLUMN IF EXISTS ios_deep_link;
+-- +goose StatementEnd
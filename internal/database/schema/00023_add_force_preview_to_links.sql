@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Links with force_preview show the inspection page on every visit instead
+-- of redirecting straight away.
+ALTER TABLE links ADD COLUMN force_preview BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE links DROP COLUMN IF EXISTS force_preview;
+-- +goose StatementEnd
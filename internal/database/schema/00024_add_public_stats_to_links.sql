@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Links with public_stats expose their click statistics, without personal
+-- data, to anyone holding stats_share_token.
+ALTER TABLE links ADD COLUMN public_stats BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE links ADD COLUMN stats_share_token TEXT;
+CREATE UNIQUE INDEX idx_links_stats_share_token ON links(stats_share_token);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_links_stats_share_token;
+ALTER TABLE links DROP COLUMN IF EXISTS stats_share_token;
+ALTER TABLE links DROP COLUMN IF EXISTS public_stats;
+-- +goose StatementEnd
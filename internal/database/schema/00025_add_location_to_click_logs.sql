@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Region, city and autonomous system of the visitor, resolved from the IP
+-- address when the click is recorded. Adding the columns to the partitioned
+-- table adds them to every partition.
+ALTER TABLE click_logs ADD COLUMN region TEXT;
+ALTER TABLE click_logs ADD COLUMN city TEXT;
+ALTER TABLE click_logs ADD COLUMN asn BIGINT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE click_logs DROP COLUMN IF EXISTS asn;
+ALTER TABLE click_logs DROP COLUMN IF EXISTS city;
+ALTER TABLE click_logs DROP COLUMN IF EXISTS region;
+-- +goose StatementEnd
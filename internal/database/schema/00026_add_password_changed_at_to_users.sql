@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Access and refresh tokens issued before the password last changed are no
+-- longer accepted.
+ALTER TABLE users ADD COLUMN password_changed_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS password_changed_at;
+-- +goose StatementEnd
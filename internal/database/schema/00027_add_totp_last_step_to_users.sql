@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The 30 second time step of the last TOTP code that was accepted. Codes of
+-- that step or earlier are rejected so a code cannot be used twice.
+ALTER TABLE users ADD COLUMN totp_last_step BIGINT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS totp_last_step;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Databases partitioned before chk_code_exists was left out of 00019 still
+-- have it. It rejects updates to clicks of renamed custom codes, which keeps
+-- the IP anonymiser retrying the same batch, and partition moves of them.
+ALTER TABLE click_logs DROP CONSTRAINT IF EXISTS chk_code_exists;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE click_logs ADD CONSTRAINT chk_code_exists
+    CHECK (check_code_exists(code)) NOT VALID;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The link a click was recorded for. Clicks keep pointing at their link
+-- after its custom code is renamed, so they can still be found when the
+-- owner's data is exported or purged. Clicks recorded under a code that was
+-- renamed before this migration cannot be attributed and stay NULL.
+ALTER TABLE click_logs ADD COLUMN link_id UUID;
+
+UPDATE click_logs cl SET link_id = l.id
+FROM links l
+WHERE cl.code = l.short_code OR cl.code = l.custom_short_code;
+
+CREATE INDEX idx_click_logs_link_id ON click_logs(link_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_click_logs_link_id;
+ALTER TABLE click_logs DROP COLUMN IF EXISTS link_id;
+-- +goose StatementEnd
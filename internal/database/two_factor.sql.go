@@ -0,0 +1,119 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: two_factor.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const claimUserTotpStep = `-- name: ClaimUserTotpStep :execrows
+UPDATE users SET totp_last_step = $1::bigint
+WHERE id = $2 AND (totp_last_step IS NULL OR totp_last_step < $1::bigint)
+`
+
+type ClaimUserTotpStepParams struct {
+	Step int64
+	ID   uuid.UUID
+}
+
+func (q *Queries) ClaimUserTotpStep(ctx context.Context, arg ClaimUserTotpStepParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, claimUserTotpStep, arg.Step, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const consumeRecoveryCode = `-- name: ConsumeRecoveryCode :one
+UPDATE user_recovery_codes SET used_at = NOW()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+RETURNING id, user_id, code_hash, used_at, created_at
+`
+
+type ConsumeRecoveryCodeParams struct {
+	UserID   uuid.UUID
+	CodeHash string
+}
+
+func (q *Queries) ConsumeRecoveryCode(ctx context.Context, arg ConsumeRecoveryCodeParams) (UserRecoveryCode, error) {
+	row := q.db.QueryRowContext(ctx, consumeRecoveryCode, arg.UserID, arg.CodeHash)
+	var i UserRecoveryCode
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CodeHash,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteRecoveryCodes = `-- name: DeleteRecoveryCodes :exec
+DELETE FROM user_recovery_codes WHERE user_id = $1
+`
+
+func (q *Queries) DeleteRecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteRecoveryCodes, userID)
+	return err
+}
+
+const disableUserTotp = `-- name: DisableUserTotp :exec
+UPDATE users SET totp_secret = NULL, totp_enabled = FALSE, totp_last_step = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) DisableUserTotp(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, disableUserTotp, id)
+	return err
+}
+
+const enableUserTotp = `-- name: EnableUserTotp :exec
+UPDATE users SET totp_enabled = TRUE, updated_at = NOW()
+WHERE id = $1 AND totp_secret IS NOT NULL AND deleted_at IS NULL
+`
+
+func (q *Queries) EnableUserTotp(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, enableUserTotp, id)
+	return err
+}
+
+const insertRecoveryCode = `-- name: InsertRecoveryCode :exec
+INSERT INTO user_recovery_codes(
+    user_id,
+    code_hash
+) VALUES (
+    $1,
+    $2
+)
+`
+
+type InsertRecoveryCodeParams struct {
+	UserID   uuid.UUID
+	CodeHash string
+}
+
+func (q *Queries) InsertRecoveryCode(ctx context.Context, arg InsertRecoveryCodeParams) error {
+	_, err := q.db.ExecContext(ctx, insertRecoveryCode, arg.UserID, arg.CodeHash)
+	return err
+}
+
+const setUserTotpSecret = `-- name: SetUserTotpSecret :exec
+UPDATE users SET totp_secret = $1, totp_enabled = FALSE, totp_last_step = NULL, updated_at = NOW()
+WHERE id = $2 AND deleted_at IS NULL
+`
+
+type SetUserTotpSecretParams struct {
+	TotpSecret sql.NullString
+	ID         uuid.UUID
+}
+
+func (q *Queries) SetUserTotpSecret(ctx context.Context, arg SetUserTotpSecretParams) error {
+	_, err := q.db.ExecContext(ctx, setUserTotpSecret, arg.TotpSecret, arg.ID)
+	return err
+}
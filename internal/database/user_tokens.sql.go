@@ -0,0 +1,96 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: user_tokens.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const consumeUserToken = `-- name: ConsumeUserToken :one
+UPDATE user_tokens SET used_at = NOW()
+WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+RETURNING id, user_id, purpose, token_hash, expires_at, used_at, created_at
+`
+
+type ConsumeUserTokenParams struct {
+	TokenHash string
+	Purpose   string
+}
+
+func (q *Queries) ConsumeUserToken(ctx context.Context, arg ConsumeUserTokenParams) (UserToken, error) {
+	row := q.db.QueryRowContext(ctx, consumeUserToken, arg.TokenHash, arg.Purpose)
+	var i UserToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Purpose,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertUserToken = `-- name: InsertUserToken :one
+INSERT INTO user_tokens(
+    user_id,
+    purpose,
+    token_hash,
+    expires_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+RETURNING id, user_id, purpose, token_hash, expires_at, used_at, created_at
+`
+
+type InsertUserTokenParams struct {
+	UserID    uuid.UUID
+	Purpose   string
+	TokenHash string
+	ExpiresAt time.Time
+}
+
+func (q *Queries) InsertUserToken(ctx context.Context, arg InsertUserTokenParams) (UserToken, error) {
+	row := q.db.QueryRowContext(ctx, insertUserToken,
+		arg.UserID,
+		arg.Purpose,
+		arg.TokenHash,
+		arg.ExpiresAt,
+	)
+	var i UserToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Purpose,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const invalidateUserTokens = `-- name: InvalidateUserTokens :exec
+UPDATE user_tokens SET used_at = NOW()
+WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+`
+
+type InvalidateUserTokensParams struct {
+	UserID  uuid.UUID
+	Purpose string
+}
+
+func (q *Queries) InvalidateUserTokens(ctx context.Context, arg InvalidateUserTokensParams) error {
+	_, err := q.db.ExecContext(ctx, invalidateUserTokens, arg.UserID, arg.Purpose)
+	return err
+}
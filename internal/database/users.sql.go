@@ -13,7 +13,7 @@ import (
 )
 
 const getUser = `-- name: GetUser :one
-SELECT id, name, email, password_hash, is_active, is_verified, created_at, updated_at, deleted_at, google_id, profile_image_url
+SELECT id, name, email, password_hash, is_active, is_verified, created_at, updated_at, deleted_at, google_id, profile_image_url, totp_secret, totp_enabled, role, password_changed_at, totp_last_step
 FROM users
 WHERE id = $1 AND deleted_at IS NULL
 `
@@ -33,12 +33,17 @@ func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.DeletedAt,
 		&i.GoogleID,
 		&i.ProfileImageUrl,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.Role,
+		&i.PasswordChangedAt,
+		&i.TotpLastStep,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, name, email, password_hash, is_active, is_verified, created_at, updated_at, deleted_at, google_id, profile_image_url
+SELECT id, name, email, password_hash, is_active, is_verified, created_at, updated_at, deleted_at, google_id, profile_image_url, totp_secret, totp_enabled, role, password_changed_at, totp_last_step
 FROM users 
 WHERE email = $1 AND deleted_at IS NULL
 `
@@ -58,12 +63,17 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.DeletedAt,
 		&i.GoogleID,
 		&i.ProfileImageUrl,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.Role,
+		&i.PasswordChangedAt,
+		&i.TotpLastStep,
 	)
 	return i, err
 }
 
 const getUserByGoogleID = `-- name: GetUserByGoogleID :one
-SELECT id, name, email, password_hash, is_active, is_verified, created_at, updated_at, deleted_at, google_id, profile_image_url
+SELECT id, name, email, password_hash, is_active, is_verified, created_at, updated_at, deleted_at, google_id, profile_image_url, totp_secret, totp_enabled, role, password_changed_at, totp_last_step
 FROM users
 WHERE google_id = $1 AND deleted_at IS NULL
 `
@@ -83,6 +93,11 @@ func (q *Queries) GetUserByGoogleID(ctx context.Context, googleID sql.NullString
 		&i.DeletedAt,
 		&i.GoogleID,
 		&i.ProfileImageUrl,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.Role,
+		&i.PasswordChangedAt,
+		&i.TotpLastStep,
 	)
 	return i, err
 }
@@ -97,7 +112,7 @@ INSERT INTO users(
     $2,
     $3
 )
-RETURNING id, name, email, password_hash, is_active, is_verified, created_at, updated_at, deleted_at, google_id, profile_image_url
+RETURNING id, name, email, password_hash, is_active, is_verified, created_at, updated_at, deleted_at, google_id, profile_image_url, totp_secret, totp_enabled, role, password_changed_at, totp_last_step
 `
 
 type InsertUserParams struct {
@@ -121,6 +136,11 @@ func (q *Queries) InsertUser(ctx context.Context, arg InsertUserParams) (User, e
 		&i.DeletedAt,
 		&i.GoogleID,
 		&i.ProfileImageUrl,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.Role,
+		&i.PasswordChangedAt,
+		&i.TotpLastStep,
 	)
 	return i, err
 }
@@ -139,7 +159,7 @@ INSERT INTO users(
     TRUE,
     $4
 )
-RETURNING id, name, email, password_hash, is_active, is_verified, created_at, updated_at, deleted_at, google_id, profile_image_url
+RETURNING id, name, email, password_hash, is_active, is_verified, created_at, updated_at, deleted_at, google_id, profile_image_url, totp_secret, totp_enabled, role, password_changed_at, totp_last_step
 `
 
 type InsertUserWithGoogleParams struct {
@@ -169,6 +189,101 @@ func (q *Queries) InsertUserWithGoogle(ctx context.Context, arg InsertUserWithGo
 		&i.DeletedAt,
 		&i.GoogleID,
 		&i.ProfileImageUrl,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.Role,
+		&i.PasswordChangedAt,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const markUserVerified = `-- name: MarkUserVerified :one
+UPDATE users SET is_verified = TRUE, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, name, email, password_hash, is_active, is_verified, created_at, updated_at, deleted_at, google_id, profile_image_url, totp_secret, totp_enabled, role, password_changed_at, totp_last_step
+`
+
+func (q *Queries) MarkUserVerified(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, markUserVerified, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.PasswordHash,
+		&i.IsActive,
+		&i.IsVerified,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.GoogleID,
+		&i.ProfileImageUrl,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.Role,
+		&i.PasswordChangedAt,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users SET deleted_at = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, name, email, password_hash, is_active, is_verified, created_at, updated_at, deleted_at, google_id, profile_image_url, totp_secret, totp_enabled, role, password_changed_at, totp_last_step
+`
+
+func (q *Queries) RestoreUser(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, restoreUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.PasswordHash,
+		&i.IsActive,
+		&i.IsVerified,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.GoogleID,
+		&i.ProfileImageUrl,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.Role,
+		&i.PasswordChangedAt,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const softDeleteUser = `-- name: SoftDeleteUser :one
+UPDATE users SET deleted_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, name, email, password_hash, is_active, is_verified, created_at, updated_at, deleted_at, google_id, profile_image_url, totp_secret, totp_enabled, role, password_changed_at, totp_last_step
+`
+
+func (q *Queries) SoftDeleteUser(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, softDeleteUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.PasswordHash,
+		&i.IsActive,
+		&i.IsVerified,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.GoogleID,
+		&i.ProfileImageUrl,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.Role,
+		&i.PasswordChangedAt,
+		&i.TotpLastStep,
 	)
 	return i, err
 }
@@ -177,7 +292,7 @@ const updateUser = `-- name: UpdateUser :one
 UPDATE users SET
 name = $1, email = $2, password_hash = $3, is_verified = $4, profile_image_url = $5
 WHERE id = $6 AND deleted_at IS NULL
-RETURNING id, name, email, password_hash, is_active, is_verified, created_at, updated_at, deleted_at, google_id, profile_image_url
+RETURNING id, name, email, password_hash, is_active, is_verified, created_at, updated_at, deleted_at, google_id, profile_image_url, totp_secret, totp_enabled, role, password_changed_at, totp_last_step
 `
 
 type UpdateUserParams struct {
@@ -211,6 +326,26 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.DeletedAt,
 		&i.GoogleID,
 		&i.ProfileImageUrl,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.Role,
+		&i.PasswordChangedAt,
+		&i.TotpLastStep,
 	)
 	return i, err
 }
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users SET password_hash = $1, password_changed_at = NOW(), updated_at = NOW()
+WHERE id = $2 AND deleted_at IS NULL
+`
+
+type UpdateUserPasswordParams struct {
+	PasswordHash sql.NullString
+	ID           uuid.UUID
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserPassword, arg.PasswordHash, arg.ID)
+	return err
+}
@@ -0,0 +1,443 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: webhooks.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const claimDueWebhookDeliveries = `-- name: ClaimDueWebhookDeliveries :many
+UPDATE webhook_deliveries d SET next_attempt_at = $1::timestamptz
+FROM webhooks w
+WHERE w.id = d.webhook_id
+  AND d.id IN (
+    SELECT wd.id FROM webhook_deliveries wd
+    JOIN webhooks ww ON ww.id = wd.webhook_id
+    WHERE wd.status = 'pending' AND wd.next_attempt_at <= NOW()
+      AND ww.deleted_at IS NULL AND ww.is_active
+    ORDER BY wd.next_attempt_at
+    LIMIT $2
+    FOR UPDATE OF wd SKIP LOCKED
+  )
+RETURNING d.id, d.webhook_id, d.event, d.payload, d.attempts, d.created_at, w.url, w.secret
+`
+
+type ClaimDueWebhookDeliveriesParams struct {
+	LeaseUntil time.Time
+	BatchSize  int32
+}
+
+type ClaimDueWebhookDeliveriesRow struct {
+	ID        uuid.UUID
+	WebhookID uuid.UUID
+	Event     string
+	Payload   json.RawMessage
+	Attempts  int32
+	CreatedAt time.Time
+	Url       string
+	Secret    string
+}
+
+// Deliveries of deleted webhooks are never sent. Those of inactive webhooks
+// stay pending until the webhook is enabled again.
+func (q *Queries) ClaimDueWebhookDeliveries(ctx context.Context, arg ClaimDueWebhookDeliveriesParams) ([]ClaimDueWebhookDeliveriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, claimDueWebhookDeliveries, arg.LeaseUntil, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimDueWebhookDeliveriesRow
+	for rows.Next() {
+		var i ClaimDueWebhookDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.Event,
+			&i.Payload,
+			&i.Attempts,
+			&i.CreatedAt,
+			&i.Url,
+			&i.Secret,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteWebhook = `-- name: DeleteWebhook :exec
+UPDATE webhooks SET deleted_at = NOW() WHERE id = $1 AND user_id = $2
+`
+
+type DeleteWebhookParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) DeleteWebhook(ctx context.Context, arg DeleteWebhookParams) error {
+	_, err := q.db.ExecContext(ctx, deleteWebhook, arg.ID, arg.UserID)
+	return err
+}
+
+const disableWebhook = `-- name: DisableWebhook :exec
+UPDATE webhooks SET is_active = FALSE, updated_at = NOW() WHERE id = $1
+`
+
+func (q *Queries) DisableWebhook(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, disableWebhook, id)
+	return err
+}
+
+const enableWebhook = `-- name: EnableWebhook :one
+UPDATE webhooks SET is_active = TRUE, updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+RETURNING id, user_id, url, secret, events, is_active, created_at, updated_at, deleted_at
+`
+
+type EnableWebhookParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) EnableWebhook(ctx context.Context, arg EnableWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, enableWebhook, arg.ID, arg.UserID)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getSubscribedWebhooks = `-- name: GetSubscribedWebhooks :many
+SELECT id, user_id, url, secret, events, is_active, created_at, updated_at, deleted_at FROM webhooks
+WHERE user_id = $1 AND $2::text = ANY(events) AND is_active = TRUE AND deleted_at IS NULL
+`
+
+type GetSubscribedWebhooksParams struct {
+	UserID uuid.UUID
+	Event  string
+}
+
+func (q *Queries) GetSubscribedWebhooks(ctx context.Context, arg GetSubscribedWebhooksParams) ([]Webhook, error) {
+	rows, err := q.db.QueryContext(ctx, getSubscribedWebhooks, arg.UserID, arg.Event)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webhook
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Url,
+			&i.Secret,
+			pq.Array(&i.Events),
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWebhook = `-- name: GetWebhook :one
+SELECT id, user_id, url, secret, events, is_active, created_at, updated_at, deleted_at FROM webhooks
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+`
+
+type GetWebhookParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetWebhook(ctx context.Context, arg GetWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, getWebhook, arg.ID, arg.UserID)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getWebhookDeliveries = `-- name: GetWebhookDeliveries :many
+SELECT id, webhook_id, event, payload, status, attempts, next_attempt_at, last_status_code, last_error, delivered_at, created_at, updated_at FROM webhook_deliveries
+WHERE webhook_id = $1
+ORDER BY created_at DESC
+LIMIT $2
+OFFSET $3
+`
+
+type GetWebhookDeliveriesParams struct {
+	WebhookID uuid.UUID
+	Limit     int32
+	Offset    int32
+}
+
+func (q *Queries) GetWebhookDeliveries(ctx context.Context, arg GetWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhookDeliveries, arg.WebhookID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.Event,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastStatusCode,
+			&i.LastError,
+			&i.DeliveredAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWebhooks = `-- name: GetWebhooks :many
+SELECT id, user_id, url, secret, events, is_active, created_at, updated_at, deleted_at FROM webhooks
+WHERE user_id = $1 AND deleted_at IS NULL
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetWebhooks(ctx context.Context, userID uuid.UUID) ([]Webhook, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhooks, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webhook
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Url,
+			&i.Secret,
+			pq.Array(&i.Events),
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWebhook = `-- name: InsertWebhook :one
+INSERT INTO webhooks(
+    user_id,
+    url,
+    secret,
+    events
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+RETURNING id, user_id, url, secret, events, is_active, created_at, updated_at, deleted_at
+`
+
+type InsertWebhookParams struct {
+	UserID uuid.UUID
+	Url    string
+	Secret string
+	Events []string
+}
+
+func (q *Queries) InsertWebhook(ctx context.Context, arg InsertWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, insertWebhook,
+		arg.UserID,
+		arg.Url,
+		arg.Secret,
+		pq.Array(arg.Events),
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const insertWebhookDelivery = `-- name: InsertWebhookDelivery :one
+INSERT INTO webhook_deliveries(
+    webhook_id,
+    event,
+    payload
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+RETURNING id, webhook_id, event, payload, status, attempts, next_attempt_at, last_status_code, last_error, delivered_at, created_at, updated_at
+`
+
+type InsertWebhookDeliveryParams struct {
+	WebhookID uuid.UUID
+	Event     string
+	Payload   json.RawMessage
+}
+
+func (q *Queries) InsertWebhookDelivery(ctx context.Context, arg InsertWebhookDeliveryParams) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, insertWebhookDelivery, arg.WebhookID, arg.Event, arg.Payload)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.WebhookID,
+		&i.Event,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LastStatusCode,
+		&i.LastError,
+		&i.DeliveredAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const redeliverWebhookDelivery = `-- name: RedeliverWebhookDelivery :one
+UPDATE webhook_deliveries SET
+    status = 'pending',
+    attempts = 0,
+    next_attempt_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND webhook_id = $2
+RETURNING id, webhook_id, event, payload, status, attempts, next_attempt_at, last_status_code, last_error, delivered_at, created_at, updated_at
+`
+
+type RedeliverWebhookDeliveryParams struct {
+	ID        uuid.UUID
+	WebhookID uuid.UUID
+}
+
+func (q *Queries) RedeliverWebhookDelivery(ctx context.Context, arg RedeliverWebhookDeliveryParams) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, redeliverWebhookDelivery, arg.ID, arg.WebhookID)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.WebhookID,
+		&i.Event,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LastStatusCode,
+		&i.LastError,
+		&i.DeliveredAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateWebhookDeliveryAttempt = `-- name: UpdateWebhookDeliveryAttempt :exec
+UPDATE webhook_deliveries SET
+    status = $1,
+    attempts = attempts + 1,
+    next_attempt_at = $2,
+    last_status_code = $3,
+    last_error = $4,
+    delivered_at = $5,
+    updated_at = NOW()
+WHERE id = $6
+`
+
+type UpdateWebhookDeliveryAttemptParams struct {
+	Status         string
+	NextAttemptAt  time.Time
+	LastStatusCode sql.NullInt32
+	LastError      sql.NullString
+	DeliveredAt    sql.NullTime
+	ID             uuid.UUID
+}
+
+func (q *Queries) UpdateWebhookDeliveryAttempt(ctx context.Context, arg UpdateWebhookDeliveryAttemptParams) error {
+	_, err := q.db.ExecContext(ctx, updateWebhookDeliveryAttempt,
+		arg.Status,
+		arg.NextAttemptAt,
+		arg.LastStatusCode,
+		arg.LastError,
+		arg.DeliveredAt,
+		arg.ID,
+	)
+	return err
+}
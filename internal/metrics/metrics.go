@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+)
+
+const namespace = "pendekin"
+
+var (
+	RequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests by route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	RedirectCacheResults = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "redirect_cache_results_total",
+		Help:      "Redirect cache lookups by result (hit, miss or error).",
+	}, []string{"result"})
+
+	ClickInsertFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "click_insert_failures_total",
+		Help:      "Clicks that could not be recorded, by where the redirect was resolved from.",
+	}, []string{"source"})
+
+	CircuitBreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "circuit_breaker_state",
+		Help:      "1 for the current state of each circuit breaker, 0 for the others.",
+	}, []string{"dependency", "state"})
+
+	SpooledClicks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "click_spool_total",
+		Help:      "Clicks written to, replayed from or dropped by the disk spool.",
+	}, []string{"result"})
+
+	ClickStreamDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "click_stream_dropped_total",
+		Help:      "Click events not delivered to a stream that fell behind.",
+	})
+)
+
+// RegisterPools exposes connection pool statistics of the database and redis
+// clients.
+func RegisterPools(db *sql.DB, rdb *redis.Client) {
+	prometheus.MustRegister(
+		collectors.NewDBStatsCollector(db, "postgres"),
+		newRedisPoolCollector(rdb),
+	)
+}
@@ -1,13 +1,22 @@
 package middlewares
 
 import (
+	"database/sql"
+	"errors"
 	"strings"
 
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/services"
 	"github.com/andriawan24/link-short/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
-func RequiredAuth() gin.HandlerFunc {
+// RequiredAuth lets requests with a valid access token through. The user is
+// read from the database on every request, so suspending or deleting an
+// account, or resetting its password, locks it out right away instead of
+// when its token expires. The loaded user is stored as "user" for the
+// middlewares and handlers that follow.
+func RequiredAuth(tokenService services.TokenService, userService services.UserService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 		if authHeader == "" {
@@ -25,14 +34,58 @@ func RequiredAuth() gin.HandlerFunc {
 
 		token := headerParts[1]
 
-		claim, err := utils.ParseToken(token)
+		claim, err := tokenService.ParseAccessToken(token)
 		if err != nil {
 			utils.RespondUnauthorized(ctx, "Unauthorized: "+err.Error())
 			ctx.Abort()
 			return
 		}
 
+		// Deleted accounts are hidden from GetUserByID for their whole grace
+		// period.
+		user, err := userService.GetUserByID(ctx.Request.Context(), claim.UserId)
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				utils.HandleErrorResponse(ctx, err)
+				ctx.Abort()
+				return
+			}
+			utils.RespondUnauthorized(ctx, "Unauthorized")
+			ctx.Abort()
+			return
+		}
+
+		if tokenService.Revoked(user, claim) {
+			utils.RespondUnauthorized(ctx, "Unauthorized: token issued before the password was changed")
+			ctx.Abort()
+			return
+		}
+
+		if !user.IsActive {
+			utils.RespondForbidden(ctx, "account is suspended")
+			ctx.Abort()
+			return
+		}
+
 		ctx.Set("user_id", claim.UserId)
+		ctx.Set("user", user)
+		ctx.Next()
+	}
+}
+
+// RequiredAdmin only lets active administrators through. It must run after
+// RequiredAuth, whose user was read from the database for this request, so
+// demoting or suspending an admin takes effect immediately.
+func RequiredAdmin() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user := ctx.MustGet("user").(database.User)
+
+		if user.Role != utils.RoleAdmin || !user.IsActive {
+			utils.RespondForbidden(ctx, "administrator access required")
+			ctx.Abort()
+			return
+		}
+
 		ctx.Next()
 	}
 }
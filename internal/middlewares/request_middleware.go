@@ -0,0 +1,86 @@
+package middlewares
+
+import (
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/metrics"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	RequestIDHeader     = "X-Request-ID"
+	maxRequestIDLength  = 128
+	unmatchedRouteLabel = "unmatched"
+)
+
+// RequestID reuses the caller's X-Request-ID when it looks sane, otherwise
+// generates one, and stores it on the request context so every log line of
+// the request carries it.
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestId := ctx.GetHeader(RequestIDHeader)
+		if !isValidRequestID(requestId) {
+			requestId = uuid.NewString()
+		}
+
+		ctx.Set("request_id", requestId)
+		ctx.Request = ctx.Request.WithContext(utils.ContextWithRequestID(ctx.Request.Context(), requestId))
+		ctx.Header(RequestIDHeader, requestId)
+
+		ctx.Next()
+	}
+}
+
+// RequestLogger logs one line per request and records its latency under the
+// matched route pattern, so paths like /:code do not explode label values.
+func RequestLogger() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		ctx.Next()
+
+		latency := time.Since(start)
+		status := ctx.Writer.Status()
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRouteLabel
+		}
+
+		metrics.RequestDuration.
+			WithLabelValues(ctx.Request.Method, route, strconv.Itoa(status)).
+			Observe(latency.Seconds())
+
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		}
+
+		slog.Log(ctx.Request.Context(), level, "request",
+			"method", ctx.Request.Method,
+			"path", ctx.Request.URL.Path,
+			"route", route,
+			"status", status,
+			"latency_ms", latency.Milliseconds(),
+			"client_ip", ctx.ClientIP(),
+			"bytes", ctx.Writer.Size(),
+		)
+	}
+}
+
+func isValidRequestID(requestId string) bool {
+	if requestId == "" || len(requestId) > maxRequestIDLength {
+		return false
+	}
+
+	for _, r := range requestId {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
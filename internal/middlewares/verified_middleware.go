@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"github.com/andriawan24/link-short/internal/services"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequiredVerifiedEmail rejects users who have not confirmed their email
+// address yet. It must run after RequiredAuth.
+func RequiredVerifiedEmail(userService services.UserService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userId := ctx.MustGet("user_id").(uuid.UUID)
+
+		user, err := userService.GetUserByID(ctx.Request.Context(), userId)
+		if err != nil {
+			utils.HandleErrorResponse(ctx, err)
+			ctx.Abort()
+			return
+		}
+
+		if !user.IsVerified {
+			utils.RespondForbidden(ctx, "email address is not verified")
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
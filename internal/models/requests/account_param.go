@@ -0,0 +1,25 @@
+package requests
+
+type VerifyEmailParam struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ForgotPasswordParam struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordParam struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type DeleteAccountParam struct {
+	// Password is required when the account has one, Code when two-factor
+	// authentication is enabled.
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+type RestoreAccountParam struct {
+	Token string `json:"token" binding:"required"`
+}
@@ -0,0 +1,9 @@
+package requests
+
+type TakeDownLinkParam struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+type UpdateUserRoleParam struct {
+	Role string `json:"role" binding:"required,oneof=user admin"`
+}
@@ -0,0 +1,26 @@
+package requests
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InsertCampaignParam struct {
+	Name        string     `json:"name" binding:"required,max=255"`
+	Description *string    `json:"description"`
+	StartsAt    *time.Time `json:"starts_at"`
+	// EndsAt expires every link of the campaign once it has passed.
+	EndsAt *time.Time `json:"ends_at"`
+}
+
+type UpdateCampaignParam struct {
+	Name        string     `json:"name" binding:"required,max=255"`
+	Description *string    `json:"description"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+}
+
+type CampaignLinksParam struct {
+	LinkIDs []uuid.UUID `json:"link_ids" binding:"required,min=1,max=100"`
+}
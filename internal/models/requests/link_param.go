@@ -3,7 +3,39 @@ package requests
 import "time"
 
 type InsertLinkParam struct {
-	OriginalURL     string     `json:"original_url" binding:"required"`
+	OriginalURL     string     `json:"original_url" binding:"required,http_url"`
 	CustomShortCode *string    `json:"custom_short_code"`
 	ExpiredAt       *time.Time `json:"expired_at"`
+	// ActiveFrom schedules the link: it only redirects from this moment on.
+	ActiveFrom *time.Time `json:"active_from"`
+	// PrelaunchURL is where visitors are sent before ActiveFrom instead of
+	// getting a "not yet available" response.
+	PrelaunchURL *string `json:"prelaunch_url" binding:"omitempty,http_url"`
+	// IOSDeepLink and AndroidDeepLink open the app on that platform, e.g.
+	// myapp://product/42. The store URLs are where visitors without the app
+	// are sent instead of the original URL.
+	IOSDeepLink     *string `json:"ios_deep_link"`
+	IOSStoreURL     *string `json:"ios_store_url" binding:"omitempty,http_url"`
+	AndroidDeepLink *string `json:"android_deep_link"`
+	AndroidStoreURL *string `json:"android_store_url" binding:"omitempty,http_url"`
+	// ForcePreview shows the preview page on every visit, so visitors see
+	// the destination before they follow it.
+	ForcePreview *bool `json:"force_preview"`
+}
+
+type UpdateLinkParam struct {
+	OriginalURL     string     `json:"original_url" binding:"required,http_url"`
+	CustomShortCode *string    `json:"custom_short_code"`
+	ExpiredAt       *time.Time `json:"expired_at"`
+	ActiveFrom      *time.Time `json:"active_from"`
+	PrelaunchURL    *string    `json:"prelaunch_url" binding:"omitempty,http_url"`
+	IOSDeepLink     *string    `json:"ios_deep_link"`
+	IOSStoreURL     *string    `json:"ios_store_url" binding:"omitempty,http_url"`
+	AndroidDeepLink *string    `json:"android_deep_link"`
+	AndroidStoreURL *string    `json:"android_store_url" binding:"omitempty,http_url"`
+	ForcePreview    *bool      `json:"force_preview"`
+}
+
+type PublicStatsParam struct {
+	Enabled *bool `json:"enabled" binding:"required"`
 }
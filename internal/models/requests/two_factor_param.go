@@ -0,0 +1,15 @@
+package requests
+
+type TwoFactorCodeParam struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorVerifyParam struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+type TwoFactorDisableParam struct {
+	Password string `json:"password"`
+	Code     string `json:"code" binding:"required"`
+}
@@ -0,0 +1,6 @@
+package requests
+
+type InsertWebhookParam struct {
+	URL    string   `json:"url" binding:"required,http_url"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=link.created link.updated link.deleted link.expired link.broken click.recorded"`
+}
@@ -0,0 +1,45 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/google/uuid"
+)
+
+type DataExportResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Status      string     `json:"status"`
+	SizeBytes   *int64     `json:"size_bytes"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+type AccountDeletionResponse struct {
+	PurgeAfter time.Time `json:"purge_after"`
+}
+
+func MapDataExportResponse(export database.DataExport) DataExportResponse {
+	response := DataExportResponse{
+		ID:        export.ID,
+		Status:    export.Status,
+		CreatedAt: export.CreatedAt,
+	}
+
+	if export.SizeBytes.Valid {
+		response.SizeBytes = &export.SizeBytes.Int64
+	}
+	if export.CompletedAt.Valid {
+		response.CompletedAt = &export.CompletedAt.Time
+	}
+	if export.ExpiresAt.Valid {
+		response.ExpiresAt = &export.ExpiresAt.Time
+	}
+	if export.Status == "ready" {
+		response.DownloadURL = "/account/export/" + export.ID.String() + "/download"
+	}
+
+	return response
+}
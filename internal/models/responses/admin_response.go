@@ -0,0 +1,141 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/google/uuid"
+)
+
+type AdminUserResponse struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	Role       string    `json:"role"`
+	IsActive   bool      `json:"is_active"`
+	IsVerified bool      `json:"is_verified"`
+	LinkCount  int64     `json:"link_count"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type AdminLinkResponse struct {
+	LinkResponse
+	OwnerID    uuid.UUID `json:"owner_id"`
+	OwnerEmail string    `json:"owner_email"`
+}
+
+type PlatformGrowthResponse struct {
+	TimeRange string              `json:"time_range"`
+	FromDate  time.Time           `json:"from_date"`
+	ToDate    time.Time           `json:"to_date"`
+	NewUsers  int64               `json:"new_users"`
+	NewLinks  int64               `json:"new_links"`
+	Clicks    int64               `json:"clicks"`
+	Days      []PlatformGrowthDay `json:"days"`
+}
+
+type PlatformGrowthDay struct {
+	Date     time.Time `json:"date"`
+	NewUsers int64     `json:"new_users"`
+	NewLinks int64     `json:"new_links"`
+	Clicks   int64     `json:"clicks"`
+}
+
+func MapAdminUserResponses(users []database.AdminGetUsersRow) []AdminUserResponse {
+	response := make([]AdminUserResponse, len(users))
+
+	for idx, user := range users {
+		response[idx] = AdminUserResponse{
+			ID:         user.ID,
+			Name:       user.Name,
+			Email:      user.Email,
+			Role:       user.Role,
+			IsActive:   user.IsActive,
+			IsVerified: user.IsVerified,
+			LinkCount:  user.LinkCount,
+			CreatedAt:  user.CreatedAt,
+		}
+	}
+
+	return response
+}
+
+func MapAdminUserResponse(user database.User) AdminUserResponse {
+	return AdminUserResponse{
+		ID:         user.ID,
+		Name:       user.Name,
+		Email:      user.Email,
+		Role:       user.Role,
+		IsActive:   user.IsActive,
+		IsVerified: user.IsVerified,
+		CreatedAt:  user.CreatedAt,
+	}
+}
+
+func MapAdminLinkResponses(links []database.AdminGetLinksRow) []AdminLinkResponse {
+	response := make([]AdminLinkResponse, len(links))
+
+	for idx, link := range links {
+		var customShortCode *string = nil
+		if link.CustomShortCode.Valid {
+			customShortCode = &link.CustomShortCode.String
+		}
+
+		var expiredAt *time.Time = nil
+		if link.ExpiredAt.Valid {
+			expiredAt = &link.ExpiredAt.Time
+		}
+
+		response[idx] = AdminLinkResponse{
+			LinkResponse: LinkResponse{
+				ID:              link.ID,
+				OriginalURL:     link.OriginalUrl,
+				ShortCode:       link.ShortCode,
+				CustomShortCode: customShortCode,
+				ExpiredAt:       expiredAt,
+				ActiveFrom:      nullTimePtr(link.ActiveFrom),
+				CampaignID:      nullUUIDPtr(link.CampaignID),
+				PrelaunchURL:    nullStringPtr(link.PrelaunchUrl),
+				IOSDeepLink:     nullStringPtr(link.IosDeepLink),
+				IOSStoreURL:     nullStringPtr(link.IosStoreUrl),
+				AndroidDeepLink: nullStringPtr(link.AndroidDeepLink),
+				AndroidStoreURL: nullStringPtr(link.AndroidStoreUrl),
+				ForcePreview:    link.ForcePreview,
+				PublicStats:     link.PublicStats,
+				StatsShareToken: nullStringPtr(link.StatsShareToken),
+				State:           linkState(link.ActiveFrom, link.ExpiredAt),
+				CreatedAt:       link.CreatedAt,
+				Metadata:        mapLinkMetadata(link.MetaTitle, link.MetaDescription, link.MetaImageUrl, link.MetaFaviconUrl, link.MetaFetchedAt),
+				HealthStatus:    link.HealthStatus,
+				Takedown:        mapLinkTakedown(link.TakenDownAt, link.TakedownReason),
+			},
+			OwnerID:    link.UserID,
+			OwnerEmail: link.OwnerEmail,
+		}
+	}
+
+	return response
+}
+
+func MapPlatformGrowthResponse(timeRange string, from time.Time, to time.Time, rows []database.GetPlatformGrowthRow) PlatformGrowthResponse {
+	response := PlatformGrowthResponse{
+		TimeRange: timeRange,
+		FromDate:  from,
+		ToDate:    to,
+		Days:      make([]PlatformGrowthDay, len(rows)),
+	}
+
+	for idx, row := range rows {
+		response.NewUsers += row.NewUsers
+		response.NewLinks += row.NewLinks
+		response.Clicks += row.Clicks
+		response.Days[idx] = PlatformGrowthDay{
+			Date:     row.Day,
+			NewUsers: row.NewUsers,
+			NewLinks: row.NewLinks,
+			Clicks:   row.Clicks,
+		}
+	}
+
+	return response
+}
@@ -1,6 +1,7 @@
 package responses
 
 import (
+	"strings"
 	"time"
 
 	"github.com/andriawan24/link-short/internal/database"
@@ -25,6 +26,8 @@ type AnalyticsResponse struct {
 	Overviews        []AnalyticOverview `json:"overviews"`
 	DeviceBreakdowns []TypeValue        `json:"device_breakdowns"`
 	TopCountries     []TypeValue        `json:"top_countries"`
+	TopRegions       []TypeValue        `json:"top_regions"`
+	TopCities        []TypeValue        `json:"top_cities"`
 	TrafficSources   []TypeValue        `json:"traffic_sources"`
 	BrowserUsages    []TypeValue        `json:"browser_usages"`
 }
@@ -96,6 +99,48 @@ func MapTopCountries(rows []database.GetTopCountriesRow) []TypeValue {
 	return result
 }
 
+func MapTopRegions(rows []database.GetTopRegionsRow) []TypeValue {
+	var result []TypeValue
+
+	for _, item := range rows {
+		result = append(result, TypeValue{
+			Type:  locationLabel(item.Region, item.Country),
+			Value: item.Total,
+		})
+	}
+
+	return result
+}
+
+func MapTopCities(rows []database.GetTopCitiesRow) []TypeValue {
+	var result []TypeValue
+
+	for _, item := range rows {
+		result = append(result, TypeValue{
+			Type:  locationLabel(item.City, item.Region, item.Country),
+			Value: item.Total,
+		})
+	}
+
+	return result
+}
+
+// locationLabel joins the known parts of a location, most specific first,
+// so "Bandung, West Java, ID" and a city missing its region still read well.
+func locationLabel(parts ...string) string {
+	var known []string
+	for _, part := range parts {
+		if part != "" && !strings.EqualFold(part, "unknown") {
+			known = append(known, part)
+		}
+	}
+
+	if len(known) == 0 {
+		return "Unknown"
+	}
+	return strings.Join(known, ", ")
+}
+
 func MapTopCountriesSingle(rows []database.GetTopCountriesSingleRow) []TypeValue {
 	var result []TypeValue
 
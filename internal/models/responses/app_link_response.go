@@ -0,0 +1,31 @@
+package responses
+
+// AppleAppSiteAssociation is served at
+// /.well-known/apple-app-site-association so iOS opens short links in the
+// configured apps.
+type AppleAppSiteAssociation struct {
+	AppLinks AppleAppLinks `json:"applinks"`
+}
+
+type AppleAppLinks struct {
+	Apps    []string             `json:"apps"`
+	Details []AppleAppLinkDetail `json:"details"`
+}
+
+type AppleAppLinkDetail struct {
+	AppIDs     []string            `json:"appIDs"`
+	Components []map[string]string `json:"components"`
+}
+
+// AssetLinkStatement is one entry of /.well-known/assetlinks.json, which
+// lets Android open short links in the configured apps.
+type AssetLinkStatement struct {
+	Relation []string        `json:"relation"`
+	Target   AssetLinkTarget `json:"target"`
+}
+
+type AssetLinkTarget struct {
+	Namespace              string   `json:"namespace"`
+	PackageName            string   `json:"package_name"`
+	SHA256CertFingerprints []string `json:"sha256_cert_fingerprints"`
+}
@@ -0,0 +1,51 @@
+package responses
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/google/uuid"
+)
+
+type AuditEventResponse struct {
+	ID         uuid.UUID       `json:"id"`
+	ActorID    *uuid.UUID      `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   *uuid.UUID      `json:"target_id"`
+	Changes    json.RawMessage `json:"changes" swaggertype:"object"`
+	IPAddress  *string         `json:"ip_address"`
+	UserAgent  *string         `json:"user_agent"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+func MapAuditEventResponses(events []database.AuditEvent) []AuditEventResponse {
+	response := make([]AuditEventResponse, len(events))
+
+	for idx, event := range events {
+		var actorId *uuid.UUID = nil
+		if event.ActorID.Valid {
+			actorId = &event.ActorID.UUID
+		}
+
+		var targetId *uuid.UUID = nil
+		if event.TargetID.Valid {
+			targetId = &event.TargetID.UUID
+		}
+
+		response[idx] = AuditEventResponse{
+			ID:         event.ID,
+			ActorID:    actorId,
+			Action:     event.Action,
+			TargetType: event.TargetType,
+			TargetID:   targetId,
+			Changes:    event.Changes,
+			IPAddress:  nullStringPtr(event.IpAddress),
+			UserAgent:  nullStringPtr(event.UserAgent),
+			CreatedAt:  event.CreatedAt,
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,87 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/google/uuid"
+)
+
+type CampaignResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+	// LinksExpiredAt is when the links were expired because the campaign
+	// ended.
+	LinksExpiredAt *time.Time `json:"links_expired_at"`
+	LinkCount      int64      `json:"link_count"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type CampaignAnalyticsResponse struct {
+	Campaign         CampaignResponse           `json:"campaign"`
+	TimeRange        string                     `json:"time_range"`
+	FromDate         time.Time                  `json:"from_date"`
+	ToDate           time.Time                  `json:"to_date"`
+	TotalClicks      int64                      `json:"total_clicks"`
+	AvgDailyClick    int64                      `json:"avg_daily_click"`
+	Overviews        []AnalyticOverview         `json:"overviews"`
+	DeviceBreakdowns []TypeValue                `json:"device_breakdowns"`
+	TopCountries     []TypeValue                `json:"top_countries"`
+	TopRegions       []TypeValue                `json:"top_regions"`
+	TopCities        []TypeValue                `json:"top_cities"`
+	TrafficSources   []TypeValue                `json:"traffic_sources"`
+	BrowserUsages    []TypeValue                `json:"browser_usages"`
+	Leaderboard      []CampaignLinkStatResponse `json:"leaderboard"`
+}
+
+type CampaignLinkStatResponse struct {
+	LinkID          uuid.UUID `json:"link_id"`
+	OriginalURL     string    `json:"original_url"`
+	ShortCode       string    `json:"short_code"`
+	CustomShortCode *string   `json:"custom_short_code"`
+	TotalClicks     int64     `json:"total_clicks"`
+}
+
+func MapCampaignResponse(campaign database.GetCampaignRow) CampaignResponse {
+	return CampaignResponse{
+		ID:             campaign.ID,
+		Name:           campaign.Name,
+		Description:    nullStringPtr(campaign.Description),
+		StartsAt:       nullTimePtr(campaign.StartsAt),
+		EndsAt:         nullTimePtr(campaign.EndsAt),
+		LinksExpiredAt: nullTimePtr(campaign.LinksExpiredAt),
+		LinkCount:      campaign.LinkCount,
+		CreatedAt:      campaign.CreatedAt,
+		UpdatedAt:      campaign.UpdatedAt,
+	}
+}
+
+func MapCampaignResponses(campaigns []database.GetCampaignsRow) []CampaignResponse {
+	response := make([]CampaignResponse, len(campaigns))
+
+	for idx, campaign := range campaigns {
+		response[idx] = MapCampaignResponse(database.GetCampaignRow(campaign))
+	}
+
+	return response
+}
+
+func MapCampaignLeaderboard(rows []database.GetCampaignLeaderboardRow) []CampaignLinkStatResponse {
+	var result []CampaignLinkStatResponse
+
+	for _, item := range rows {
+		result = append(result, CampaignLinkStatResponse{
+			LinkID:          item.ID,
+			OriginalURL:     item.OriginalUrl,
+			ShortCode:       item.ShortCode,
+			CustomShortCode: nullStringPtr(item.CustomShortCode),
+			TotalClicks:     item.TotalClicks,
+		})
+	}
+
+	return result
+}
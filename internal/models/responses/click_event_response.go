@@ -0,0 +1,34 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/google/uuid"
+)
+
+type ClickEventResponse struct {
+	ID         uuid.UUID `json:"id"`
+	LinkID     uuid.UUID `json:"link_id"`
+	Code       string    `json:"code"`
+	Country    string    `json:"country"`
+	DeviceType string    `json:"device_type"`
+	Browser    string    `json:"browser"`
+	Traffic    string    `json:"traffic"`
+	Referrer   string    `json:"referrer"`
+	ClickedAt  time.Time `json:"clicked_at"`
+}
+
+func MapClickEventResponse(linkId uuid.UUID, clickLog database.ClickLog) ClickEventResponse {
+	return ClickEventResponse{
+		ID:         clickLog.ID,
+		LinkID:     linkId,
+		Code:       clickLog.Code,
+		Country:    clickLog.Country.String,
+		DeviceType: clickLog.DeviceType.String,
+		Browser:    clickLog.Browser.String,
+		Traffic:    clickLog.Traffic.String,
+		Referrer:   clickLog.Referrer.String,
+		ClickedAt:  clickLog.ClickedAt,
+	}
+}
@@ -0,0 +1,15 @@
+package responses
+
+type HealthResponse struct {
+	// Status is ok when every dependency is up, degraded when redirects can
+	// still be served and unavailable otherwise.
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyHealth `json:"dependencies,omitempty"`
+}
+
+type DependencyHealth struct {
+	Status    string  `json:"status"`
+	Circuit   string  `json:"circuit"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
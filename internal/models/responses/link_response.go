@@ -1,6 +1,7 @@
 package responses
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/andriawan24/link-short/internal/database"
@@ -8,15 +9,60 @@ import (
 )
 
 type LinkResponse struct {
-	ID               uuid.UUID   `json:"id"`
-	OriginalURL      string      `json:"original_url"`
-	ShortCode        string      `json:"short_code"`
-	CustomShortCode  *string     `json:"custom_short_code"`
-	ClickCount       int64       `json:"click_count"`
-	ExpiredAt        *time.Time  `json:"expired_at"`
-	CreatedAt        time.Time   `json:"created_at"`
-	DeviceBreakdowns []TypeValue `json:"device_breakdowns"`
-	TopCountries     []TypeValue `json:"top_countries"`
+	ID               uuid.UUID             `json:"id"`
+	OriginalURL      string                `json:"original_url"`
+	ShortCode        string                `json:"short_code"`
+	CustomShortCode  *string               `json:"custom_short_code"`
+	ClickCount       int64                 `json:"click_count"`
+	ExpiredAt        *time.Time            `json:"expired_at"`
+	ActiveFrom       *time.Time            `json:"active_from"`
+	PrelaunchURL     *string               `json:"prelaunch_url"`
+	IOSDeepLink      *string               `json:"ios_deep_link"`
+	IOSStoreURL      *string               `json:"ios_store_url"`
+	AndroidDeepLink  *string               `json:"android_deep_link"`
+	AndroidStoreURL  *string               `json:"android_store_url"`
+	ForcePreview     bool                  `json:"force_preview"`
+	PublicStats      bool                  `json:"public_stats"`
+	StatsShareToken  *string               `json:"stats_share_token"`
+	State            string                `json:"state"`
+	CampaignID       *uuid.UUID            `json:"campaign_id"`
+	CreatedAt        time.Time             `json:"created_at"`
+	DeviceBreakdowns []TypeValue           `json:"device_breakdowns"`
+	TopCountries     []TypeValue           `json:"top_countries"`
+	Metadata         *LinkMetadataResponse `json:"metadata"`
+	HealthStatus     string                `json:"health_status"`
+	Health           *LinkHealthResponse   `json:"health,omitempty"`
+	Takedown         *LinkTakedownResponse `json:"takedown"`
+}
+
+type LinkTakedownResponse struct {
+	Reason      string    `json:"reason"`
+	TakenDownAt time.Time `json:"taken_down_at"`
+}
+
+type LinkHealthResponse struct {
+	Status              string                    `json:"status"`
+	ConsecutiveFailures int32                     `json:"consecutive_failures"`
+	LastCheckedAt       *time.Time                `json:"last_checked_at"`
+	History             []LinkHealthCheckResponse `json:"history"`
+}
+
+type LinkHealthCheckResponse struct {
+	ID            uuid.UUID `json:"id"`
+	Success       bool      `json:"success"`
+	StatusCode    *int32    `json:"status_code"`
+	LatencyMs     int32     `json:"latency_ms"`
+	RedirectChain []string  `json:"redirect_chain"`
+	Error         *string   `json:"error"`
+	CheckedAt     time.Time `json:"checked_at"`
+}
+
+type LinkMetadataResponse struct {
+	Title       *string   `json:"title"`
+	Description *string   `json:"description"`
+	ImageURL    *string   `json:"image_url"`
+	FaviconURL  *string   `json:"favicon_url"`
+	FetchedAt   time.Time `json:"fetched_at"`
 }
 
 func MapLinkResponses(links []database.GetLinksRow) []LinkResponse {
@@ -39,15 +85,29 @@ func MapLinkResponses(links []database.GetLinksRow) []LinkResponse {
 			ShortCode:       link.ShortCode,
 			CustomShortCode: customShortCode,
 			ExpiredAt:       expiredAt,
+			ActiveFrom:      nullTimePtr(link.ActiveFrom),
+			CampaignID:      nullUUIDPtr(link.CampaignID),
+			PrelaunchURL:    nullStringPtr(link.PrelaunchUrl),
+			IOSDeepLink:     nullStringPtr(link.IosDeepLink),
+			IOSStoreURL:     nullStringPtr(link.IosStoreUrl),
+			AndroidDeepLink: nullStringPtr(link.AndroidDeepLink),
+			AndroidStoreURL: nullStringPtr(link.AndroidStoreUrl),
+			ForcePreview:    link.ForcePreview,
+			PublicStats:     link.PublicStats,
+			StatsShareToken: nullStringPtr(link.StatsShareToken),
+			State:           linkState(link.ActiveFrom, link.ExpiredAt),
 			ClickCount:      link.Counts,
 			CreatedAt:       link.CreatedAt,
+			Metadata:        mapLinkMetadata(link.MetaTitle, link.MetaDescription, link.MetaImageUrl, link.MetaFaviconUrl, link.MetaFetchedAt),
+			HealthStatus:    link.HealthStatus,
+			Takedown:        mapLinkTakedown(link.TakenDownAt, link.TakedownReason),
 		}
 	}
 
 	return response
 }
 
-func MapLinkResponse(link database.GetLinkRow, totalClicks int64, devices []TypeValue, countries []TypeValue) LinkResponse {
+func MapLinkResponse(link database.GetLinkRow, totalClicks int64, devices []TypeValue, countries []TypeValue, healthChecks []database.LinkHealthCheck) LinkResponse {
 	var customShortCode *string = nil
 	if link.CustomShortCode.Valid {
 		customShortCode = &link.CustomShortCode.String
@@ -64,10 +124,25 @@ func MapLinkResponse(link database.GetLinkRow, totalClicks int64, devices []Type
 		ShortCode:        link.ShortCode,
 		CustomShortCode:  customShortCode,
 		ExpiredAt:        expiredAt,
+		ActiveFrom:       nullTimePtr(link.ActiveFrom),
+		CampaignID:       nullUUIDPtr(link.CampaignID),
+		PrelaunchURL:     nullStringPtr(link.PrelaunchUrl),
+		IOSDeepLink:      nullStringPtr(link.IosDeepLink),
+		IOSStoreURL:      nullStringPtr(link.IosStoreUrl),
+		AndroidDeepLink:  nullStringPtr(link.AndroidDeepLink),
+		AndroidStoreURL:  nullStringPtr(link.AndroidStoreUrl),
+		ForcePreview:     link.ForcePreview,
+		PublicStats:      link.PublicStats,
+		StatsShareToken:  nullStringPtr(link.StatsShareToken),
+		State:            linkState(link.ActiveFrom, link.ExpiredAt),
 		CreatedAt:        link.CreatedAt,
 		ClickCount:       totalClicks,
 		DeviceBreakdowns: devices,
 		TopCountries:     countries,
+		Metadata:         mapLinkMetadata(link.MetaTitle, link.MetaDescription, link.MetaImageUrl, link.MetaFaviconUrl, link.MetaFetchedAt),
+		HealthStatus:     link.HealthStatus,
+		Health:           mapLinkHealth(link.HealthStatus, link.ConsecutiveFailures, link.LastCheckedAt, healthChecks),
+		Takedown:         mapLinkTakedown(link.TakenDownAt, link.TakedownReason),
 	}
 
 	return response
@@ -90,8 +165,121 @@ func MapLinkDetailResponse(link database.Link) LinkResponse {
 		ShortCode:       link.ShortCode,
 		CustomShortCode: customShortCode,
 		ExpiredAt:       expiredAt,
+		ActiveFrom:      nullTimePtr(link.ActiveFrom),
+		CampaignID:      nullUUIDPtr(link.CampaignID),
+		PrelaunchURL:    nullStringPtr(link.PrelaunchUrl),
+		IOSDeepLink:     nullStringPtr(link.IosDeepLink),
+		IOSStoreURL:     nullStringPtr(link.IosStoreUrl),
+		AndroidDeepLink: nullStringPtr(link.AndroidDeepLink),
+		AndroidStoreURL: nullStringPtr(link.AndroidStoreUrl),
+		ForcePreview:    link.ForcePreview,
+		PublicStats:     link.PublicStats,
+		StatsShareToken: nullStringPtr(link.StatsShareToken),
+		State:           linkState(link.ActiveFrom, link.ExpiredAt),
 		CreatedAt:       link.CreatedAt,
+		Metadata:        mapLinkMetadata(link.MetaTitle, link.MetaDescription, link.MetaImageUrl, link.MetaFaviconUrl, link.MetaFetchedAt),
+		HealthStatus:    link.HealthStatus,
+		Takedown:        mapLinkTakedown(link.TakenDownAt, link.TakedownReason),
 	}
 
 	return response
 }
+
+// mapLinkMetadata returns nil until the destination has been fetched.
+func mapLinkMetadata(title, description, imageURL, faviconURL sql.NullString, fetchedAt sql.NullTime) *LinkMetadataResponse {
+	if !fetchedAt.Valid {
+		return nil
+	}
+
+	return &LinkMetadataResponse{
+		Title:       nullStringPtr(title),
+		Description: nullStringPtr(description),
+		ImageURL:    nullStringPtr(imageURL),
+		FaviconURL:  nullStringPtr(faviconURL),
+		FetchedAt:   fetchedAt.Time,
+	}
+}
+
+// mapLinkHealth returns nil when no history was loaded, which keeps the
+// health block out of list and webhook payloads.
+func mapLinkHealth(status string, consecutiveFailures int32, lastCheckedAt sql.NullTime, checks []database.LinkHealthCheck) *LinkHealthResponse {
+	if checks == nil {
+		return nil
+	}
+
+	var checkedAt *time.Time = nil
+	if lastCheckedAt.Valid {
+		checkedAt = &lastCheckedAt.Time
+	}
+
+	history := make([]LinkHealthCheckResponse, len(checks))
+	for idx, check := range checks {
+		var statusCode *int32 = nil
+		if check.StatusCode.Valid {
+			statusCode = &check.StatusCode.Int32
+		}
+
+		history[idx] = LinkHealthCheckResponse{
+			ID:            check.ID,
+			Success:       check.Success,
+			StatusCode:    statusCode,
+			LatencyMs:     check.LatencyMs,
+			RedirectChain: check.RedirectChain,
+			Error:         nullStringPtr(check.Error),
+			CheckedAt:     check.CheckedAt,
+		}
+	}
+
+	return &LinkHealthResponse{
+		Status:              status,
+		ConsecutiveFailures: consecutiveFailures,
+		LastCheckedAt:       checkedAt,
+		History:             history,
+	}
+}
+
+func mapLinkTakedown(takenDownAt sql.NullTime, reason sql.NullString) *LinkTakedownResponse {
+	if !takenDownAt.Valid {
+		return nil
+	}
+
+	return &LinkTakedownResponse{
+		Reason:      reason.String,
+		TakenDownAt: takenDownAt.Time,
+	}
+}
+
+// linkState mirrors utils.LinkStateAt, which cannot be used here because
+// utils depends on this package.
+func linkState(activeFrom sql.NullTime, expiredAt sql.NullTime) string {
+	now := time.Now()
+	switch {
+	case activeFrom.Valid && activeFrom.Time.After(now):
+		return "scheduled"
+	case expiredAt.Valid && !expiredAt.Time.After(now):
+		return "expired"
+	default:
+		return "active"
+	}
+}
+
+func nullTimePtr(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
+}
+
+func nullStringPtr(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+	return &value.String
+}
+
+func nullUUIDPtr(value uuid.NullUUID) *uuid.UUID {
+	if !value.Valid {
+		return nil
+	}
+	return &value.UUID
+}
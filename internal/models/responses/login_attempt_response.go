@@ -0,0 +1,34 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/google/uuid"
+)
+
+type LoginAttemptResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Success   bool      `json:"success"`
+	Reason    string    `json:"reason"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func MapLoginAttemptResponses(attempts []database.LoginAttempt) []LoginAttemptResponse {
+	response := make([]LoginAttemptResponse, len(attempts))
+
+	for idx, attempt := range attempts {
+		response[idx] = LoginAttemptResponse{
+			ID:        attempt.ID,
+			Success:   attempt.Success,
+			Reason:    attempt.Reason.String,
+			IPAddress: attempt.IpAddress,
+			UserAgent: attempt.UserAgent.String,
+			CreatedAt: attempt.CreatedAt,
+		}
+	}
+
+	return response
+}
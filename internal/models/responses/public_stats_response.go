@@ -0,0 +1,55 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+)
+
+// PublicLinkStatsResponse is what anyone holding the share token of a link
+// sees. It only carries aggregates, never IP addresses, user agents or
+// referrers of individual clicks.
+type PublicLinkStatsResponse struct {
+	ShortCode        string             `json:"short_code"`
+	CustomShortCode  *string            `json:"custom_short_code"`
+	OriginalURL      string             `json:"original_url"`
+	CreatedAt        time.Time          `json:"created_at"`
+	TimeRange        string             `json:"time_range"`
+	FromDate         time.Time          `json:"from_date"`
+	ToDate           time.Time          `json:"to_date"`
+	TotalClicks      int64              `json:"total_clicks"`
+	Overviews        []AnalyticOverview `json:"overviews"`
+	DeviceBreakdowns []TypeValue        `json:"device_breakdowns"`
+	TopCountries     []TypeValue        `json:"top_countries"`
+	// GeneratedAt tells how stale a cached response is.
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+type PublicStatsSettingsResponse struct {
+	Enabled    bool    `json:"enabled"`
+	ShareToken *string `json:"share_token"`
+}
+
+func MapPublicLinkStats(link database.GetLinkByStatsTokenRow, timeRange string, from time.Time, to time.Time, totalClicks int64, overviews []database.GetByDateRangeRow, devices []database.GetDeviceBreakdownSingleRow, countries []database.GetTopCountriesSingleRow) PublicLinkStatsResponse {
+	return PublicLinkStatsResponse{
+		ShortCode:        link.ShortCode,
+		CustomShortCode:  nullStringPtr(link.CustomShortCode),
+		OriginalURL:      link.OriginalUrl,
+		CreatedAt:        link.CreatedAt,
+		TimeRange:        timeRange,
+		FromDate:         from,
+		ToDate:           to,
+		TotalClicks:      totalClicks,
+		Overviews:        MapAnalyticsResponse(overviews),
+		DeviceBreakdowns: MapDeviceBreakdownSingle(devices),
+		TopCountries:     MapTopCountriesSingle(countries),
+		GeneratedAt:      time.Now(),
+	}
+}
+
+func MapPublicStatsSettings(link database.Link) PublicStatsSettingsResponse {
+	return PublicStatsSettingsResponse{
+		Enabled:    link.PublicStats,
+		ShareToken: nullStringPtr(link.StatsShareToken),
+	}
+}
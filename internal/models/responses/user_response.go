@@ -7,12 +7,14 @@ import (
 )
 
 type UserResponse struct {
-	ID              uuid.UUID `json:"id"`
-	Name            string    `json:"name"`
-	Email           string    `json:"email"`
-	IsActive        bool      `json:"is_active"`
-	IsVerified      bool      `json:"is_verified"`
-	ProfileImageUrl string    `json:"profile_image_url"`
+	ID               uuid.UUID `json:"id"`
+	Name             string    `json:"name"`
+	Email            string    `json:"email"`
+	IsActive         bool      `json:"is_active"`
+	IsVerified       bool      `json:"is_verified"`
+	ProfileImageUrl  string    `json:"profile_image_url"`
+	TwoFactorEnabled bool      `json:"two_factor_enabled"`
+	Role             string    `json:"role"`
 }
 
 type LoginResponse struct {
@@ -23,4 +25,15 @@ type LoginResponse struct {
 	User                  UserResponse `json:"user"`
 	AuthURL               string       `json:"auth_url"`
 	State                 string       `json:"state"`
+	TwoFactorRequired     bool         `json:"two_factor_required"`
+	ChallengeToken        string       `json:"challenge_token,omitempty"`
+}
+
+type TwoFactorEnrollResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TwoFactorConfirmResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
 }
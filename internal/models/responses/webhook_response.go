@@ -0,0 +1,106 @@
+package responses
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/google/uuid"
+)
+
+type WebhookResponse struct {
+	ID        uuid.UUID `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	IsActive  bool      `json:"is_active"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             uuid.UUID       `json:"id"`
+	WebhookID      uuid.UUID       `json:"webhook_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status"`
+	Attempts       int32           `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+	LastStatusCode *int32          `json:"last_status_code"`
+	LastError      *string         `json:"last_error"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// WebhookPayload is the envelope POSTed to webhook endpoints.
+type WebhookPayload struct {
+	ID        uuid.UUID       `json:"id"`
+	Event     string          `json:"event"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+func MapWebhookResponse(webhook database.Webhook) WebhookResponse {
+	return WebhookResponse{
+		ID:        webhook.ID,
+		URL:       webhook.Url,
+		Events:    webhook.Events,
+		IsActive:  webhook.IsActive,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+func MapWebhookResponses(webhooks []database.Webhook) []WebhookResponse {
+	response := make([]WebhookResponse, len(webhooks))
+
+	for idx, webhook := range webhooks {
+		response[idx] = MapWebhookResponse(webhook)
+	}
+
+	return response
+}
+
+func MapWebhookDeliveryResponse(delivery database.WebhookDelivery) WebhookDeliveryResponse {
+	var nextAttemptAt *time.Time = nil
+	if delivery.Status == "pending" {
+		nextAttemptAt = &delivery.NextAttemptAt
+	}
+
+	var lastStatusCode *int32 = nil
+	if delivery.LastStatusCode.Valid {
+		lastStatusCode = &delivery.LastStatusCode.Int32
+	}
+
+	var lastError *string = nil
+	if delivery.LastError.Valid {
+		lastError = &delivery.LastError.String
+	}
+
+	var deliveredAt *time.Time = nil
+	if delivery.DeliveredAt.Valid {
+		deliveredAt = &delivery.DeliveredAt.Time
+	}
+
+	return WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		Event:          delivery.Event,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  nextAttemptAt,
+		LastStatusCode: lastStatusCode,
+		LastError:      lastError,
+		DeliveredAt:    deliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
+
+func MapWebhookDeliveryResponses(deliveries []database.WebhookDelivery) []WebhookDeliveryResponse {
+	response := make([]WebhookDeliveryResponse, len(deliveries))
+
+	for idx, delivery := range deliveries {
+		response[idx] = MapWebhookDeliveryResponse(delivery)
+	}
+
+	return response
+}
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/google/uuid"
+)
+
+func (s *Store) GetDueAccountDeletions(ctx context.Context, arg database.GetDueAccountDeletionsParams) ([]database.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var users []database.User
+	for _, user := range s.users {
+		if user.DeletedAt.Valid && !user.DeletedAt.Time.After(arg.DeletedBefore) {
+			users = append(users, *user)
+		}
+	}
+	slices.SortStableFunc(users, func(a, b database.User) int {
+		return a.DeletedAt.Time.Compare(b.DeletedAt.Time)
+	})
+	return page(users, arg.BatchSize, 0), nil
+}
+
+func (s *Store) GetUserLinkCodes(ctx context.Context, userID uuid.UUID) ([]database.GetUserLinkCodesRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var codes []database.GetUserLinkCodesRow
+	for _, link := range s.links {
+		if link.UserID == userID {
+			codes = append(codes, database.GetUserLinkCodesRow{
+				ShortCode:       link.ShortCode,
+				CustomShortCode: link.CustomShortCode,
+			})
+		}
+	}
+	return codes, nil
+}
+
+func (s *Store) DeleteUserClickLogs(ctx context.Context, userID uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before := len(s.clickLogs)
+	s.clickLogs = slices.DeleteFunc(s.clickLogs, func(click *database.ClickLog) bool {
+		return s.userOwnsClick(userID, click)
+	})
+	return int64(before - len(s.clickLogs)), nil
+}
+
+func (s *Store) PurgeAuditEvents(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before := len(s.auditEvents)
+	s.auditEvents = slices.DeleteFunc(s.auditEvents, func(event *database.AuditEvent) bool {
+		return event.OwnerID == ownerID
+	})
+	return int64(before - len(s.auditEvents)), nil
+}
+
+// PurgeUser deletes a soft deleted user along with every row whose foreign
+// key cascades from it.
+func (s *Store) PurgeUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index := slices.IndexFunc(s.users, func(user *database.User) bool {
+		return user.ID == id && user.DeletedAt.Valid
+	})
+	if index < 0 {
+		return 0, nil
+	}
+	s.users = slices.Delete(s.users, index, index+1)
+
+	var linkIDs, webhookIDs []uuid.UUID
+	for _, link := range s.links {
+		if link.UserID == id {
+			linkIDs = append(linkIDs, link.ID)
+		}
+		if link.TakenDownBy.Valid && link.TakenDownBy.UUID == id {
+			link.TakenDownBy = uuid.NullUUID{}
+		}
+	}
+	for _, webhook := range s.webhooks {
+		if webhook.UserID == id {
+			webhookIDs = append(webhookIDs, webhook.ID)
+		}
+	}
+
+	s.links = slices.DeleteFunc(s.links, func(link *database.Link) bool { return link.UserID == id })
+	s.healthChecks = slices.DeleteFunc(s.healthChecks, func(check *database.LinkHealthCheck) bool {
+		return slices.Contains(linkIDs, check.LinkID)
+	})
+	s.webhooks = slices.DeleteFunc(s.webhooks, func(webhook *database.Webhook) bool { return webhook.UserID == id })
+	s.deliveries = slices.DeleteFunc(s.deliveries, func(delivery *database.WebhookDelivery) bool {
+		return slices.Contains(webhookIDs, delivery.WebhookID)
+	})
+	s.userTokens = slices.DeleteFunc(s.userTokens, func(token *database.UserToken) bool { return token.UserID == id })
+	s.recoveryCodes = slices.DeleteFunc(s.recoveryCodes, func(code *database.UserRecoveryCode) bool { return code.UserID == id })
+	s.loginAttempts = slices.DeleteFunc(s.loginAttempts, func(attempt *database.LoginAttempt) bool {
+		return attempt.UserID.Valid && attempt.UserID.UUID == id
+	})
+	s.dataExports = slices.DeleteFunc(s.dataExports, func(export *database.DataExport) bool { return export.UserID == id })
+	s.campaigns = slices.DeleteFunc(s.campaigns, func(campaign *database.Campaign) bool { return campaign.UserID == id })
+	return 1, nil
+}
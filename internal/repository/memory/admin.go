@@ -0,0 +1,245 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/google/uuid"
+)
+
+func (s *Store) AdminGetUsers(ctx context.Context, arg database.AdminGetUsersParams) ([]database.AdminGetUsersRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var rows []database.AdminGetUsersRow
+	for _, user := range slices.Backward(s.users) {
+		if user.DeletedAt.Valid {
+			continue
+		}
+		if arg.Search != "" && !containsFold(user.Email, arg.Search) && !containsFold(user.Name, arg.Search) {
+			continue
+		}
+		if arg.IsActive.Valid && user.IsActive != arg.IsActive.Bool {
+			continue
+		}
+
+		rows = append(rows, database.AdminGetUsersRow{
+			ID:         user.ID,
+			Name:       user.Name,
+			Email:      user.Email,
+			Role:       user.Role,
+			IsActive:   user.IsActive,
+			IsVerified: user.IsVerified,
+			CreatedAt:  user.CreatedAt,
+			LinkCount:  s.countUserLinks(user.ID),
+		})
+	}
+	return page(rows, arg.Limit, arg.Offset), nil
+}
+
+func (s *Store) countUserLinks(userID uuid.UUID) int64 {
+	var total int64
+	for _, link := range s.links {
+		if link.UserID == userID && !link.DeletedAt.Valid {
+			total++
+		}
+	}
+	return total
+}
+
+func (s *Store) SetUserActive(ctx context.Context, arg database.SetUserActiveParams) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := s.userByID(arg.ID)
+	if user == nil {
+		return database.User{}, sql.ErrNoRows
+	}
+
+	user.IsActive = arg.IsActive
+	user.UpdatedAt = now()
+	return *user, nil
+}
+
+func (s *Store) SetUserRole(ctx context.Context, arg database.SetUserRoleParams) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if arg.Role != "user" && arg.Role != "admin" {
+		return database.User{}, checkViolation("users_role_check")
+	}
+
+	user := s.userByID(arg.ID)
+	if user == nil {
+		return database.User{}, sql.ErrNoRows
+	}
+
+	user.Role = arg.Role
+	user.UpdatedAt = now()
+	return *user, nil
+}
+
+func (s *Store) PromoteUsersToAdmin(ctx context.Context, emails []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.users {
+		if !user.DeletedAt.Valid && user.Role != "admin" && slices.Contains(emails, strings.ToLower(user.Email)) {
+			user.Role = "admin"
+			user.UpdatedAt = now()
+		}
+	}
+	return nil
+}
+
+func (s *Store) AdminGetLinks(ctx context.Context, arg database.AdminGetLinksParams) ([]database.AdminGetLinksRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var rows []database.AdminGetLinksRow
+	for _, link := range slices.Backward(s.links) {
+		if link.DeletedAt.Valid {
+			continue
+		}
+		if arg.Search != "" && !linkHasCode(link, arg.Search) && !containsFold(link.OriginalUrl, arg.Search) {
+			continue
+		}
+		if arg.TakenDownOnly && !link.TakenDownAt.Valid {
+			continue
+		}
+
+		owner := s.findOwner(link.UserID)
+		if owner == nil {
+			continue
+		}
+
+		row := linkRow(link)
+		rows = append(rows, database.AdminGetLinksRow{
+			ID:                  row.ID,
+			OriginalUrl:         row.OriginalUrl,
+			ShortCode:           row.ShortCode,
+			CustomShortCode:     row.CustomShortCode,
+			UserID:              row.UserID,
+			ExpiredAt:           row.ExpiredAt,
+			CreatedAt:           row.CreatedAt,
+			UpdatedAt:           row.UpdatedAt,
+			DeletedAt:           row.DeletedAt,
+			ExpiryNotifiedAt:    row.ExpiryNotifiedAt,
+			MetaTitle:           row.MetaTitle,
+			MetaDescription:     row.MetaDescription,
+			MetaImageUrl:        row.MetaImageUrl,
+			MetaFaviconUrl:      row.MetaFaviconUrl,
+			MetaFetchedAt:       row.MetaFetchedAt,
+			HealthStatus:        row.HealthStatus,
+			ConsecutiveFailures: row.ConsecutiveFailures,
+			LastCheckedAt:       row.LastCheckedAt,
+			TakenDownAt:         row.TakenDownAt,
+			TakedownReason:      row.TakedownReason,
+			TakenDownBy:         row.TakenDownBy,
+			ActiveFrom:          row.ActiveFrom,
+			PrelaunchUrl:        row.PrelaunchUrl,
+			CampaignID:          row.CampaignID,
+			IosDeepLink:         row.IosDeepLink,
+			IosStoreUrl:         row.IosStoreUrl,
+			AndroidDeepLink:     row.AndroidDeepLink,
+			AndroidStoreUrl:     row.AndroidStoreUrl,
+			ForcePreview:        row.ForcePreview,
+			PublicStats:         row.PublicStats,
+			StatsShareToken:     row.StatsShareToken,
+			OwnerEmail:          owner.Email,
+		})
+	}
+	return page(rows, arg.Limit, arg.Offset), nil
+}
+
+// findOwner includes soft deleted users, as the admin link queries join on
+// users without filtering them out.
+func (s *Store) findOwner(id uuid.UUID) *database.User {
+	for _, user := range s.users {
+		if user.ID == id {
+			return user
+		}
+	}
+	return nil
+}
+
+func (s *Store) AdminGetLink(ctx context.Context, id uuid.UUID) (database.AdminGetLinkRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	link := s.linkByID(id)
+	if link == nil {
+		return database.AdminGetLinkRow{}, sql.ErrNoRows
+	}
+
+	row := database.AdminGetLinkRow(linkRow(link))
+	row.Counts = s.clickCount(link)
+	return row, nil
+}
+
+func (s *Store) TakeDownLink(ctx context.Context, arg database.TakeDownLinkParams) (database.Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link := s.linkByID(arg.ID)
+	if link == nil {
+		return database.Link{}, sql.ErrNoRows
+	}
+
+	link.TakenDownAt = sql.NullTime{Time: now(), Valid: true}
+	link.TakedownReason = arg.TakedownReason
+	link.TakenDownBy = arg.TakenDownBy
+	link.UpdatedAt = link.TakenDownAt.Time
+	return *link, nil
+}
+
+func (s *Store) RestoreLink(ctx context.Context, id uuid.UUID) (database.Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link := s.linkByID(id)
+	if link == nil {
+		return database.Link{}, sql.ErrNoRows
+	}
+
+	link.TakenDownAt = sql.NullTime{}
+	link.TakedownReason = sql.NullString{}
+	link.TakenDownBy = uuid.NullUUID{}
+	link.UpdatedAt = now()
+	return *link, nil
+}
+
+func (s *Store) GetPlatformGrowth(ctx context.Context, arg database.GetPlatformGrowthParams) ([]database.GetPlatformGrowthRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	inDay := func(t time.Time, day time.Time) bool {
+		return !t.Before(day) && t.Before(day.AddDate(0, 0, 1))
+	}
+
+	var rows []database.GetPlatformGrowthRow
+	for day := truncateDay(arg.FromDate); !day.After(truncateDay(arg.ToDate)); day = day.AddDate(0, 0, 1) {
+		row := database.GetPlatformGrowthRow{Day: day}
+		for _, user := range s.users {
+			if inDay(user.CreatedAt, day) {
+				row.NewUsers++
+			}
+		}
+		for _, link := range s.links {
+			if inDay(link.CreatedAt, day) {
+				row.NewLinks++
+			}
+		}
+		for _, click := range s.clickLogs {
+			if inDay(click.ClickedAt, day) {
+				row.Clicks++
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
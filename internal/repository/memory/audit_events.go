@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/google/uuid"
+)
+
+func (s *Store) InsertAuditEvent(ctx context.Context, arg database.InsertAuditEventParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changes := slices.Clone(arg.Changes)
+	if changes == nil {
+		changes = json.RawMessage("{}")
+	}
+
+	s.auditEvents = append(s.auditEvents, &database.AuditEvent{
+		ID:         uuid.New(),
+		OwnerID:    arg.OwnerID,
+		ActorID:    arg.ActorID,
+		Action:     arg.Action,
+		TargetType: arg.TargetType,
+		TargetID:   arg.TargetID,
+		Changes:    changes,
+		IpAddress:  arg.IpAddress,
+		UserAgent:  arg.UserAgent,
+		CreatedAt:  now(),
+	})
+	return nil
+}
+
+func (s *Store) GetAuditEvents(ctx context.Context, arg database.GetAuditEventsParams) ([]database.AuditEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var events []database.AuditEvent
+	for _, event := range slices.Backward(s.auditEvents) {
+		if event.OwnerID != arg.OwnerID {
+			continue
+		}
+		if arg.TargetType.Valid && event.TargetType != arg.TargetType.String {
+			continue
+		}
+		if arg.TargetID.Valid && (!event.TargetID.Valid || event.TargetID.UUID != arg.TargetID.UUID) {
+			continue
+		}
+		if arg.Action.Valid && event.Action != arg.Action.String {
+			continue
+		}
+
+		row := *event
+		row.Changes = slices.Clone(event.Changes)
+		events = append(events, row)
+	}
+	return page(events, arg.Limit, arg.Offset), nil
+}
@@ -0,0 +1,291 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/google/uuid"
+)
+
+func (s *Store) campaignByID(id uuid.UUID) *database.Campaign {
+	for _, campaign := range s.campaigns {
+		if campaign.ID == id {
+			return campaign
+		}
+	}
+	return nil
+}
+
+// checkCampaignDates enforces chk_campaigns_dates.
+func checkCampaignDates(startsAt sql.NullTime, endsAt sql.NullTime) error {
+	if startsAt.Valid && endsAt.Valid && !startsAt.Time.Before(endsAt.Time) {
+		return checkViolation("chk_campaigns_dates")
+	}
+	return nil
+}
+
+// campaignLinkCount mirrors the LEFT JOIN on live links of the campaign.
+func (s *Store) campaignLinkCount(id uuid.UUID) int64 {
+	var count int64
+	for _, link := range s.links {
+		if !link.DeletedAt.Valid && link.CampaignID.Valid && link.CampaignID.UUID == id {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *Store) InsertCampaign(ctx context.Context, arg database.InsertCampaignParams) (database.Campaign, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.userByID(arg.UserID) == nil {
+		return database.Campaign{}, foreignKeyViolation("campaigns_user_id_fkey")
+	}
+	if err := checkCampaignDates(arg.StartsAt, arg.EndsAt); err != nil {
+		return database.Campaign{}, err
+	}
+
+	campaign := database.Campaign{
+		ID:          uuid.New(),
+		UserID:      arg.UserID,
+		Name:        arg.Name,
+		Description: arg.Description,
+		StartsAt:    arg.StartsAt,
+		EndsAt:      arg.EndsAt,
+		CreatedAt:   now(),
+	}
+	campaign.UpdatedAt = campaign.CreatedAt
+
+	s.campaigns = append(s.campaigns, &campaign)
+	return campaign, nil
+}
+
+func (s *Store) GetCampaigns(ctx context.Context, arg database.GetCampaignsParams) ([]database.GetCampaignsRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var rows []database.GetCampaignsRow
+	for _, campaign := range slices.Backward(s.campaigns) {
+		if campaign.UserID != arg.UserID {
+			continue
+		}
+
+		rows = append(rows, database.GetCampaignsRow{
+			ID:             campaign.ID,
+			UserID:         campaign.UserID,
+			Name:           campaign.Name,
+			Description:    campaign.Description,
+			StartsAt:       campaign.StartsAt,
+			EndsAt:         campaign.EndsAt,
+			LinksExpiredAt: campaign.LinksExpiredAt,
+			CreatedAt:      campaign.CreatedAt,
+			UpdatedAt:      campaign.UpdatedAt,
+			LinkCount:      s.campaignLinkCount(campaign.ID),
+		})
+	}
+	return page(rows, arg.Limit, arg.Offset), nil
+}
+
+func (s *Store) GetCampaign(ctx context.Context, arg database.GetCampaignParams) (database.GetCampaignRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	campaign := s.campaignByID(arg.ID)
+	if campaign == nil || campaign.UserID != arg.UserID {
+		return database.GetCampaignRow{}, sql.ErrNoRows
+	}
+
+	return database.GetCampaignRow{
+		ID:             campaign.ID,
+		UserID:         campaign.UserID,
+		Name:           campaign.Name,
+		Description:    campaign.Description,
+		StartsAt:       campaign.StartsAt,
+		EndsAt:         campaign.EndsAt,
+		LinksExpiredAt: campaign.LinksExpiredAt,
+		CreatedAt:      campaign.CreatedAt,
+		UpdatedAt:      campaign.UpdatedAt,
+		LinkCount:      s.campaignLinkCount(campaign.ID),
+	}, nil
+}
+
+func (s *Store) UpdateCampaign(ctx context.Context, arg database.UpdateCampaignParams) (database.Campaign, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	campaign := s.campaignByID(arg.ID)
+	if campaign == nil || campaign.UserID != arg.UserID {
+		return database.Campaign{}, sql.ErrNoRows
+	}
+	if err := checkCampaignDates(arg.StartsAt, arg.EndsAt); err != nil {
+		return database.Campaign{}, err
+	}
+
+	if campaign.EndsAt != arg.EndsAt {
+		campaign.LinksExpiredAt = sql.NullTime{}
+	}
+	campaign.Name = arg.Name
+	campaign.Description = arg.Description
+	campaign.StartsAt = arg.StartsAt
+	campaign.EndsAt = arg.EndsAt
+	campaign.UpdatedAt = now()
+	return *campaign, nil
+}
+
+func (s *Store) DeleteCampaign(ctx context.Context, arg database.DeleteCampaignParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	campaign := s.campaignByID(arg.ID)
+	if campaign == nil || campaign.UserID != arg.UserID {
+		return nil
+	}
+
+	for _, link := range s.links {
+		if link.CampaignID.Valid && link.CampaignID.UUID == campaign.ID {
+			link.CampaignID = uuid.NullUUID{}
+		}
+	}
+	s.campaigns = slices.DeleteFunc(s.campaigns, func(c *database.Campaign) bool { return c.ID == campaign.ID })
+	return nil
+}
+
+func (s *Store) AddLinksToCampaign(ctx context.Context, arg database.AddLinksToCampaignParams) ([]uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.campaignByID(arg.CampaignID) == nil {
+		return nil, foreignKeyViolation("links_campaign_id_fkey")
+	}
+
+	var ids []uuid.UUID
+	for _, link := range s.links {
+		if link.DeletedAt.Valid || link.UserID != arg.UserID || !slices.Contains(arg.LinkIds, link.ID) {
+			continue
+		}
+
+		link.CampaignID = uuid.NullUUID{UUID: arg.CampaignID, Valid: true}
+		link.UpdatedAt = now()
+		ids = append(ids, link.ID)
+	}
+	return ids, nil
+}
+
+func (s *Store) RemoveLinkFromCampaign(ctx context.Context, arg database.RemoveLinkFromCampaignParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link := s.findLink(func(link *database.Link) bool {
+		return link.ID == arg.ID && link.UserID == arg.UserID && link.CampaignID.Valid && link.CampaignID.UUID == arg.CampaignID
+	})
+	if link == nil {
+		return 0, nil
+	}
+
+	link.CampaignID = uuid.NullUUID{}
+	link.UpdatedAt = now()
+	return 1, nil
+}
+
+func (s *Store) GetCampaignLeaderboard(ctx context.Context, arg database.GetCampaignLeaderboardParams) ([]database.GetCampaignLeaderboardRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type entry struct {
+		row       database.GetCampaignLeaderboardRow
+		createdAt int64
+	}
+
+	var entries []entry
+	for _, link := range s.links {
+		if link.DeletedAt.Valid || link.UserID != arg.UserID || !link.CampaignID.Valid || link.CampaignID.UUID != arg.CampaignID {
+			continue
+		}
+
+		var total int64
+		for _, click := range s.clickLogs {
+			if linkHasCode(link, click.Code) && !click.ClickedAt.Before(arg.FromDate) && !click.ClickedAt.After(arg.ToDate) {
+				total++
+			}
+		}
+
+		entries = append(entries, entry{
+			row: database.GetCampaignLeaderboardRow{
+				ID:              link.ID,
+				OriginalUrl:     link.OriginalUrl,
+				ShortCode:       link.ShortCode,
+				CustomShortCode: link.CustomShortCode,
+				TotalClicks:     total,
+			},
+			createdAt: link.CreatedAt.UnixNano(),
+		})
+	}
+
+	slices.SortStableFunc(entries, func(a, b entry) int {
+		return cmp.Or(cmp.Compare(b.row.TotalClicks, a.row.TotalClicks), cmp.Compare(a.createdAt, b.createdAt))
+	})
+
+	var rows []database.GetCampaignLeaderboardRow
+	for _, entry := range page(entries, arg.Limit, 0) {
+		rows = append(rows, entry.row)
+	}
+	return rows, nil
+}
+
+func (s *Store) GetEndedCampaigns(ctx context.Context, batchSize int32) ([]database.Campaign, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	current := now()
+
+	var campaigns []database.Campaign
+	for _, campaign := range s.campaigns {
+		if campaign.EndsAt.Valid && !campaign.EndsAt.Time.After(current) && !campaign.LinksExpiredAt.Valid {
+			campaigns = append(campaigns, *campaign)
+		}
+	}
+
+	slices.SortStableFunc(campaigns, func(a, b database.Campaign) int {
+		return a.EndsAt.Time.Compare(b.EndsAt.Time)
+	})
+	return page(campaigns, batchSize, 0), nil
+}
+
+func (s *Store) MarkCampaignLinksExpired(ctx context.Context, arg database.MarkCampaignLinksExpiredParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	campaign := s.campaignByID(arg.ID)
+	if campaign != nil && campaign.EndsAt.Valid && campaign.EndsAt.Time.Equal(arg.EndsAt) {
+		campaign.LinksExpiredAt = sql.NullTime{Time: now(), Valid: true}
+	}
+	return nil
+}
+
+func (s *Store) ExpireCampaignLinks(ctx context.Context, arg database.ExpireCampaignLinksParams) ([]database.ExpireCampaignLinksRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rows []database.ExpireCampaignLinksRow
+	for _, link := range s.links {
+		if link.DeletedAt.Valid || !link.CampaignID.Valid || link.CampaignID.UUID != arg.CampaignID {
+			continue
+		}
+		if link.ExpiredAt.Valid && !link.ExpiredAt.Time.After(arg.EndsAt) {
+			continue
+		}
+		if link.ActiveFrom.Valid && !link.ActiveFrom.Time.Before(arg.EndsAt) {
+			continue
+		}
+
+		link.ExpiredAt = sql.NullTime{Time: arg.EndsAt, Valid: true}
+		link.ExpiryNotifiedAt = sql.NullTime{}
+		link.UpdatedAt = now()
+		rows = append(rows, database.ExpireCampaignLinksRow{ShortCode: link.ShortCode, CustomShortCode: link.CustomShortCode})
+	}
+	return rows, nil
+}
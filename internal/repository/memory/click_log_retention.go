@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"net/netip"
+	"slices"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+)
+
+func (s *Store) EnsureClickLogPartition(ctx context.Context, month time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	month = truncateMonth(month)
+	if slices.ContainsFunc(s.clickLogPartitions, month.Equal) {
+		return false, nil
+	}
+	s.clickLogPartitions = append(s.clickLogPartitions, month)
+	slices.SortFunc(s.clickLogPartitions, time.Time.Compare)
+	return true, nil
+}
+
+func (s *Store) GetClickLogPartitionsBefore(ctx context.Context, before time.Time) ([]time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var months []time.Time
+	for _, month := range s.clickLogPartitions {
+		if month.Before(truncateDay(before)) {
+			months = append(months, month)
+		}
+	}
+	return months, nil
+}
+
+func (s *Store) DropClickLogPartition(ctx context.Context, month time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := truncateMonth(month)
+	end := start.AddDate(0, 1, 0)
+	s.clickLogs = slices.DeleteFunc(s.clickLogs, func(click *database.ClickLog) bool {
+		return !click.ClickedAt.Before(start) && click.ClickedAt.Before(end)
+	})
+	s.clickLogPartitions = slices.DeleteFunc(s.clickLogPartitions, start.Equal)
+	return nil
+}
+
+func (s *Store) GetClickLogsForArchive(ctx context.Context, arg database.GetClickLogsForArchiveParams) ([]database.ClickLog, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var clicks []database.ClickLog
+	for _, click := range s.clickLogs {
+		if click.ClickedAt.Before(arg.FromTime) || !click.ClickedAt.Before(arg.ToTime) {
+			continue
+		}
+		// (clicked_at, id) > (@after_clicked_at, @after_id)
+		if c := cmp.Or(click.ClickedAt.Compare(arg.AfterClickedAt), cmp.Compare(click.ID.String(), arg.AfterID.String())); c <= 0 {
+			continue
+		}
+		clicks = append(clicks, *click)
+	}
+	slices.SortFunc(clicks, func(a, b database.ClickLog) int {
+		return cmp.Or(a.ClickedAt.Compare(b.ClickedAt), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+	return page(clicks, arg.BatchSize, 0), nil
+}
+
+func (s *Store) DeleteClickLogsBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := len(s.clickLogs)
+	s.clickLogs = slices.DeleteFunc(s.clickLogs, func(click *database.ClickLog) bool {
+		return click.ClickedAt.Before(before)
+	})
+	return int64(count - len(s.clickLogs)), nil
+}
+
+func (s *Store) AnonymizeClickLogIPs(ctx context.Context, arg database.AnonymizeClickLogIPsParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var updated int64
+	for _, click := range s.clickLogs {
+		if updated >= int64(arg.BatchSize) {
+			break
+		}
+		if !click.ClickedAt.Before(arg.Before) || click.IpAnonymized || !click.IpAddress.Valid {
+			continue
+		}
+		click.IpAddress.String, click.IpAddress.Valid = anonymizeIP(click.IpAddress.String)
+		click.IpAnonymized = true
+		updated++
+	}
+	return updated, nil
+}
+
+// anonymizeIP mirrors the anonymize_ip SQL function: the /24 of an IPv4
+// address or the /48 of an IPv6 address, and NULL for anything unparsable.
+func anonymizeIP(ip string) (string, bool) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return "", false
+	}
+
+	bits := 48
+	if addr.Is4() {
+		bits = 24
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return "", false
+	}
+	return prefix.Addr().String(), true
+}
+
+// truncateMonth mirrors DATE_TRUNC('month', ...) in a UTC session.
+func truncateMonth(t time.Time) time.Time {
+	year, month, _ := t.UTC().Date()
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
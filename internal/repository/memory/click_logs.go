@@ -0,0 +1,316 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/google/uuid"
+)
+
+// clickFilter selects clicks on live links of a user within a time range,
+// optionally narrowed to one link or the links of one campaign.
+type clickFilter struct {
+	userID     uuid.UUID
+	linkID     uuid.UUID
+	campaignID uuid.NullUUID
+	from       time.Time
+	to         time.Time
+}
+
+// userClicks mirrors the LEFT JOIN of click_logs on links shared by the
+// analytics queries. A click is returned once per link it matches.
+func (s *Store) userClicks(filter clickFilter) []*database.ClickLog {
+	var clicks []*database.ClickLog
+	for _, click := range s.clickLogs {
+		if click.ClickedAt.Before(filter.from) || click.ClickedAt.After(filter.to) {
+			continue
+		}
+
+		for _, link := range s.links {
+			if link.DeletedAt.Valid || link.UserID != filter.userID || !linkHasCode(link, click.Code) {
+				continue
+			}
+			if filter.linkID != uuid.Nil && link.ID != filter.linkID {
+				continue
+			}
+			if filter.campaignID.Valid && link.CampaignID != filter.campaignID {
+				continue
+			}
+			clicks = append(clicks, click)
+		}
+	}
+	return clicks
+}
+
+type groupTotal struct {
+	key   string
+	total int64
+}
+
+// groupClicks counts clicks by key, largest group first.
+func groupClicks(clicks []*database.ClickLog, key func(*database.ClickLog) string) []groupTotal {
+	totals := make(map[string]int64)
+	for _, click := range clicks {
+		totals[key(click)]++
+	}
+
+	groups := make([]groupTotal, 0, len(totals))
+	for key, total := range totals {
+		groups = append(groups, groupTotal{key: key, total: total})
+	}
+
+	slices.SortFunc(groups, func(a, b groupTotal) int {
+		return cmp.Or(cmp.Compare(b.total, a.total), cmp.Compare(a.key, b.key))
+	})
+	return groups
+}
+
+func deviceType(click *database.ClickLog) string {
+	if click.DeviceType.Valid {
+		return click.DeviceType.String
+	}
+	return "Unknown"
+}
+
+func country(click *database.ClickLog) string {
+	if click.Country.Valid {
+		return click.Country.String
+	}
+	return "Unknown"
+}
+
+func region(click *database.ClickLog) string {
+	if click.Region.Valid {
+		return click.Region.String
+	}
+	return "Unknown"
+}
+
+func city(click *database.ClickLog) string {
+	if click.City.Valid {
+		return click.City.String
+	}
+	return "Unknown"
+}
+
+func trafficSource(click *database.ClickLog) string {
+	if click.Traffic.Valid {
+		return click.Traffic.String
+	}
+	return "Direct"
+}
+
+func browser(click *database.ClickLog) string {
+	if click.Browser.Valid {
+		return click.Browser.String
+	}
+	return "Unknown"
+}
+
+func (s *Store) InsertClickLog(ctx context.Context, arg database.InsertClickLogParams) (database.ClickLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	click := database.ClickLog{
+		ID:         uuid.New(),
+		IpAddress:  arg.IpAddress,
+		UserAgent:  arg.UserAgent,
+		Referrer:   arg.Referrer,
+		ClickedAt:  now(),
+		Code:       arg.Code,
+		Country:    arg.Country,
+		DeviceType: arg.DeviceType,
+		Traffic:    arg.Traffic,
+		Browser:    arg.Browser,
+		Region:     arg.Region,
+		City:       arg.City,
+		Asn:        arg.Asn,
+		LinkID:     s.linkIDForCode(arg.Code),
+	}
+	s.clickLogs = append(s.clickLogs, &click)
+	return click, nil
+}
+
+func (s *Store) InsertSpooledClickLog(ctx context.Context, arg database.InsertSpooledClickLogParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clickLogs = append(s.clickLogs, &database.ClickLog{
+		ID:         uuid.New(),
+		IpAddress:  arg.IpAddress,
+		UserAgent:  arg.UserAgent,
+		Referrer:   arg.Referrer,
+		ClickedAt:  arg.ClickedAt,
+		Code:       arg.Code,
+		Country:    arg.Country,
+		DeviceType: arg.DeviceType,
+		Traffic:    arg.Traffic,
+		Browser:    arg.Browser,
+		Region:     arg.Region,
+		City:       arg.City,
+		Asn:        arg.Asn,
+		LinkID:     s.linkIDForCode(arg.Code),
+	})
+	return nil
+}
+
+// linkIDForCode mirrors the link_id lookup of the click inserts, which also
+// matches deleted links. Callers hold s.mu.
+func (s *Store) linkIDForCode(code string) uuid.NullUUID {
+	for _, link := range s.links {
+		if linkHasCode(link, code) {
+			return uuid.NullUUID{UUID: link.ID, Valid: true}
+		}
+	}
+	return uuid.NullUUID{}
+}
+
+func (s *Store) GetTotalClicks(ctx context.Context, arg database.GetTotalClicksParams) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clicks := s.userClicks(clickFilter{userID: arg.UserID, linkID: arg.LinkID.UUID, campaignID: arg.CampaignID, from: arg.FromDate, to: arg.ToDate})
+	return int64(len(clicks)), nil
+}
+
+func (s *Store) GetByDateRange(ctx context.Context, arg database.GetByDateRangeParams) ([]database.GetByDateRangeRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	totals := make(map[time.Time]int64)
+	for _, click := range s.userClicks(clickFilter{userID: arg.UserID, linkID: arg.LinkID.UUID, campaignID: arg.CampaignID, from: arg.FromDate, to: arg.ToDate}) {
+		totals[truncateDay(click.ClickedAt)]++
+	}
+
+	rows := make([]database.GetByDateRangeRow, 0, len(totals))
+	for date, total := range totals {
+		rows = append(rows, database.GetByDateRangeRow{Date: date, TotalClick: total})
+	}
+
+	slices.SortFunc(rows, func(a, b database.GetByDateRangeRow) int {
+		return a.Date.Compare(b.Date)
+	})
+	return rows, nil
+}
+
+func (s *Store) GetDeviceBreakdown(ctx context.Context, arg database.GetDeviceBreakdownParams) ([]database.GetDeviceBreakdownRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clicks := s.userClicks(clickFilter{userID: arg.UserID, campaignID: arg.CampaignID, from: arg.FromDate, to: arg.ToDate})
+
+	var rows []database.GetDeviceBreakdownRow
+	for _, group := range groupClicks(clicks, deviceType) {
+		rows = append(rows, database.GetDeviceBreakdownRow{DeviceType: group.key, Total: group.total})
+	}
+	return rows, nil
+}
+
+func (s *Store) GetDeviceBreakdownSingle(ctx context.Context, arg database.GetDeviceBreakdownSingleParams) ([]database.GetDeviceBreakdownSingleRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clicks := s.userClicks(clickFilter{userID: arg.UserID, linkID: arg.ID, from: arg.FromDate, to: arg.ToDate})
+
+	var rows []database.GetDeviceBreakdownSingleRow
+	for _, group := range groupClicks(clicks, deviceType) {
+		rows = append(rows, database.GetDeviceBreakdownSingleRow{DeviceType: group.key, Total: group.total})
+	}
+	return rows, nil
+}
+
+func (s *Store) GetTopCountries(ctx context.Context, arg database.GetTopCountriesParams) ([]database.GetTopCountriesRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clicks := s.userClicks(clickFilter{userID: arg.UserID, campaignID: arg.CampaignID, from: arg.FromDate, to: arg.ToDate})
+
+	var rows []database.GetTopCountriesRow
+	for _, group := range page(groupClicks(clicks, country), 10, 0) {
+		rows = append(rows, database.GetTopCountriesRow{Country: group.key, Total: group.total})
+	}
+	return rows, nil
+}
+
+func (s *Store) GetTopCountriesSingle(ctx context.Context, arg database.GetTopCountriesSingleParams) ([]database.GetTopCountriesSingleRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clicks := s.userClicks(clickFilter{userID: arg.UserID, linkID: arg.ID, from: arg.FromDate, to: arg.ToDate})
+
+	var rows []database.GetTopCountriesSingleRow
+	for _, group := range page(groupClicks(clicks, country), 10, 0) {
+		rows = append(rows, database.GetTopCountriesSingleRow{Country: group.key, Total: group.total})
+	}
+	return rows, nil
+}
+
+// locationKey joins the location parts of a click into one group key. The
+// unit separator does not occur in place names.
+func locationKey(parts ...func(*database.ClickLog) string) func(*database.ClickLog) string {
+	return func(click *database.ClickLog) string {
+		values := make([]string, len(parts))
+		for i, part := range parts {
+			values[i] = part(click)
+		}
+		return strings.Join(values, "\x1f")
+	}
+}
+
+func (s *Store) GetTopRegions(ctx context.Context, arg database.GetTopRegionsParams) ([]database.GetTopRegionsRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clicks := s.userClicks(clickFilter{userID: arg.UserID, campaignID: arg.CampaignID, from: arg.FromDate, to: arg.ToDate})
+
+	var rows []database.GetTopRegionsRow
+	for _, group := range page(groupClicks(clicks, locationKey(country, region)), 10, 0) {
+		parts := strings.Split(group.key, "\x1f")
+		rows = append(rows, database.GetTopRegionsRow{Country: parts[0], Region: parts[1], Total: group.total})
+	}
+	return rows, nil
+}
+
+func (s *Store) GetTopCities(ctx context.Context, arg database.GetTopCitiesParams) ([]database.GetTopCitiesRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clicks := s.userClicks(clickFilter{userID: arg.UserID, campaignID: arg.CampaignID, from: arg.FromDate, to: arg.ToDate})
+
+	var rows []database.GetTopCitiesRow
+	for _, group := range page(groupClicks(clicks, locationKey(country, region, city)), 10, 0) {
+		parts := strings.Split(group.key, "\x1f")
+		rows = append(rows, database.GetTopCitiesRow{Country: parts[0], Region: parts[1], City: parts[2], Total: group.total})
+	}
+	return rows, nil
+}
+
+func (s *Store) GetTrafficSources(ctx context.Context, arg database.GetTrafficSourcesParams) ([]database.GetTrafficSourcesRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clicks := s.userClicks(clickFilter{userID: arg.UserID, campaignID: arg.CampaignID, from: arg.FromDate, to: arg.ToDate})
+
+	var rows []database.GetTrafficSourcesRow
+	for _, group := range groupClicks(clicks, trafficSource) {
+		rows = append(rows, database.GetTrafficSourcesRow{TrafficSource: group.key, Total: group.total})
+	}
+	return rows, nil
+}
+
+func (s *Store) GetBrowserUsage(ctx context.Context, arg database.GetBrowserUsageParams) ([]database.GetBrowserUsageRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clicks := s.userClicks(clickFilter{userID: arg.UserID, campaignID: arg.CampaignID, from: arg.FromDate, to: arg.ToDate})
+
+	var rows []database.GetBrowserUsageRow
+	for _, group := range groupClicks(clicks, browser) {
+		rows = append(rows, database.GetBrowserUsageRow{Browser: group.key, Total: group.total})
+	}
+	return rows, nil
+}
@@ -0,0 +1,36 @@
+package memory
+
+import "context"
+
+func (s *Store) GetTotalActiveUsers(ctx context.Context) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var total int64
+	for _, user := range s.users {
+		if user.IsActive && !user.DeletedAt.Valid {
+			total++
+		}
+	}
+	return total, nil
+}
+
+func (s *Store) GetTotalLinksCreated(ctx context.Context) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var total int64
+	for _, link := range s.links {
+		if !link.DeletedAt.Valid {
+			total++
+		}
+	}
+	return total, nil
+}
+
+func (s *Store) GetGlobalTotalClicks(ctx context.Context) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.clickLogs)), nil
+}
@@ -0,0 +1,203 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/google/uuid"
+)
+
+func (s *Store) InsertDataExport(ctx context.Context, userID uuid.UUID) (database.DataExport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !slices.ContainsFunc(s.users, func(user *database.User) bool { return user.ID == userID }) {
+		return database.DataExport{}, foreignKeyViolation("data_exports_user_id_fkey")
+	}
+
+	export := database.DataExport{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Status:    "pending",
+		CreatedAt: now(),
+	}
+	s.dataExports = append(s.dataExports, &export)
+	return export, nil
+}
+
+func (s *Store) GetLatestDataExport(ctx context.Context, userID uuid.UUID) (database.DataExport, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, export := range slices.Backward(s.dataExports) {
+		if export.UserID == userID {
+			return *export, nil
+		}
+	}
+	return database.DataExport{}, sql.ErrNoRows
+}
+
+func (s *Store) GetDataExport(ctx context.Context, arg database.GetDataExportParams) (database.DataExport, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, export := range s.dataExports {
+		if export.ID == arg.ID && export.UserID == arg.UserID {
+			return *export, nil
+		}
+	}
+	return database.DataExport{}, sql.ErrNoRows
+}
+
+func (s *Store) ClaimPendingDataExports(ctx context.Context, arg database.ClaimPendingDataExportsParams) ([]database.DataExport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := now()
+	var claimed []database.DataExport
+	for _, export := range s.dataExports {
+		if len(claimed) == int(arg.BatchSize) {
+			break
+		}
+		expired := export.Status == "processing" && export.LeaseUntil.Valid && export.LeaseUntil.Time.Before(current)
+		if export.Status != "pending" && !expired {
+			continue
+		}
+
+		export.Status = "processing"
+		export.Attempts++
+		export.LeaseUntil = sql.NullTime{Time: arg.LeaseUntil, Valid: true}
+		claimed = append(claimed, *export)
+	}
+	return claimed, nil
+}
+
+func (s *Store) CompleteDataExport(ctx context.Context, arg database.CompleteDataExportParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if export := s.dataExportByID(arg.ID); export != nil {
+		export.Status = "ready"
+		export.LeaseUntil = sql.NullTime{}
+		export.BlobKey = arg.BlobKey
+		export.SizeBytes = arg.SizeBytes
+		export.Error = sql.NullString{}
+		export.CompletedAt = sql.NullTime{Time: now(), Valid: true}
+		export.ExpiresAt = arg.ExpiresAt
+	}
+	return nil
+}
+
+func (s *Store) FailDataExport(ctx context.Context, arg database.FailDataExportParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if export := s.dataExportByID(arg.ID); export != nil {
+		export.Status = arg.Status
+		export.LeaseUntil = sql.NullTime{}
+		export.Error = arg.Error
+		export.CompletedAt = sql.NullTime{}
+		if arg.Status == "failed" {
+			export.CompletedAt = sql.NullTime{Time: now(), Valid: true}
+		}
+		export.ExpiresAt = arg.ExpiresAt
+	}
+	return nil
+}
+
+func (s *Store) GetExpiredDataExports(ctx context.Context, limit int32) ([]database.DataExport, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	current := now()
+	var exports []database.DataExport
+	for _, export := range s.dataExports {
+		if export.ExpiresAt.Valid && export.ExpiresAt.Time.Before(current) {
+			exports = append(exports, *export)
+		}
+	}
+	slices.SortStableFunc(exports, func(a, b database.DataExport) int {
+		return a.ExpiresAt.Time.Compare(b.ExpiresAt.Time)
+	})
+	return page(exports, limit, 0), nil
+}
+
+func (s *Store) DeleteDataExport(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.dataExports = slices.DeleteFunc(s.dataExports, func(export *database.DataExport) bool {
+		return export.ID == id
+	})
+	return nil
+}
+
+func (s *Store) GetUserDataExportBlobKeys(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var keys []string
+	for _, export := range s.dataExports {
+		if export.UserID == userID && export.BlobKey.Valid {
+			keys = append(keys, export.BlobKey.String)
+		}
+	}
+	return keys, nil
+}
+
+func (s *Store) GetUserLinksForExport(ctx context.Context, userID uuid.UUID) ([]database.Link, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var links []database.Link
+	for _, link := range s.links {
+		if link.UserID == userID {
+			links = append(links, *link)
+		}
+	}
+	slices.SortStableFunc(links, func(a, b database.Link) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return links, nil
+}
+
+func (s *Store) GetUserClickLogsForExport(ctx context.Context, arg database.GetUserClickLogsForExportParams) ([]database.ClickLog, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var clicks []database.ClickLog
+	for _, click := range s.clickLogs {
+		if !s.userOwnsClick(arg.UserID, click) {
+			continue
+		}
+		// (clicked_at, id) > (@after_clicked_at, @after_id)
+		if c := cmp.Or(click.ClickedAt.Compare(arg.AfterClickedAt), cmp.Compare(click.ID.String(), arg.AfterID.String())); c <= 0 {
+			continue
+		}
+		clicks = append(clicks, *click)
+	}
+	slices.SortFunc(clicks, func(a, b database.ClickLog) int {
+		return cmp.Or(a.ClickedAt.Compare(b.ClickedAt), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+	return page(clicks, arg.BatchSize, 0), nil
+}
+
+func (s *Store) dataExportByID(id uuid.UUID) *database.DataExport {
+	for _, export := range s.dataExports {
+		if export.ID == id {
+			return export
+		}
+	}
+	return nil
+}
+
+// userOwnsClick mirrors link_id IN (SELECT id FROM links WHERE user_id = $1),
+// which includes deleted links. Callers hold s.mu.
+func (s *Store) userOwnsClick(userID uuid.UUID, click *database.ClickLog) bool {
+	return click.LinkID.Valid && slices.ContainsFunc(s.links, func(link *database.Link) bool {
+		return link.UserID == userID && link.ID == click.LinkID.UUID
+	})
+}
@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/google/uuid"
+)
+
+func (s *Store) ClaimLinksForHealthCheck(ctx context.Context, arg database.ClaimLinksForHealthCheckParams) ([]database.ClaimLinksForHealthCheckRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := now()
+
+	var due []*database.Link
+	for _, link := range s.links {
+		if link.DeletedAt.Valid {
+			continue
+		}
+		if link.ExpiredAt.Valid && !link.ExpiredAt.Time.After(current) {
+			continue
+		}
+		if link.ActiveFrom.Valid && link.ActiveFrom.Time.After(current) {
+			continue
+		}
+		if link.LastCheckedAt.Valid && link.LastCheckedAt.Time.After(arg.CheckedBefore) {
+			continue
+		}
+		due = append(due, link)
+	}
+
+	// ORDER BY last_checked_at NULLS FIRST
+	slices.SortStableFunc(due, func(a, b *database.Link) int {
+		switch {
+		case !a.LastCheckedAt.Valid && !b.LastCheckedAt.Valid:
+			return 0
+		case !a.LastCheckedAt.Valid:
+			return -1
+		case !b.LastCheckedAt.Valid:
+			return 1
+		}
+		return a.LastCheckedAt.Time.Compare(b.LastCheckedAt.Time)
+	})
+
+	var claimed []database.ClaimLinksForHealthCheckRow
+	for _, link := range page(due, arg.BatchSize, 0) {
+		link.LastCheckedAt = sql.NullTime{Time: current, Valid: true}
+		claimed = append(claimed, database.ClaimLinksForHealthCheckRow{ID: link.ID, OriginalUrl: link.OriginalUrl})
+	}
+	return claimed, nil
+}
+
+func (s *Store) InsertLinkHealthCheck(ctx context.Context, arg database.InsertLinkHealthCheckParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !slices.ContainsFunc(s.links, func(link *database.Link) bool { return link.ID == arg.LinkID }) {
+		return foreignKeyViolation("link_health_checks_link_id_fkey")
+	}
+
+	s.healthChecks = append(s.healthChecks, &database.LinkHealthCheck{
+		ID:            uuid.New(),
+		LinkID:        arg.LinkID,
+		Success:       arg.Success,
+		StatusCode:    arg.StatusCode,
+		LatencyMs:     arg.LatencyMs,
+		RedirectChain: cloneStrings(arg.RedirectChain),
+		Error:         arg.Error,
+		CheckedAt:     now(),
+	})
+	return nil
+}
+
+func (s *Store) MarkLinkHealthy(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, link := range s.links {
+		if link.ID == id {
+			link.HealthStatus = "healthy"
+			link.ConsecutiveFailures = 0
+		}
+	}
+	return nil
+}
+
+func (s *Store) MarkLinkHealthFailure(ctx context.Context, arg database.MarkLinkHealthFailureParams) (database.Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, link := range s.links {
+		if link.ID == arg.ID {
+			link.ConsecutiveFailures++
+			if link.ConsecutiveFailures >= arg.FailureThreshold {
+				link.HealthStatus = "broken"
+			}
+			return *link, nil
+		}
+	}
+	return database.Link{}, sql.ErrNoRows
+}
+
+func (s *Store) GetLinkHealthChecks(ctx context.Context, arg database.GetLinkHealthChecksParams) ([]database.LinkHealthCheck, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var checks []database.LinkHealthCheck
+	for _, check := range slices.Backward(s.healthChecks) {
+		if check.LinkID == arg.LinkID {
+			row := *check
+			row.RedirectChain = cloneStrings(check.RedirectChain)
+			checks = append(checks, row)
+		}
+	}
+	return page(checks, arg.Limit, 0), nil
+}
+
+func (s *Store) DeleteLinkHealthChecksBefore(ctx context.Context, checkedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.healthChecks = slices.DeleteFunc(s.healthChecks, func(check *database.LinkHealthCheck) bool {
+		return check.CheckedAt.Before(checkedAt)
+	})
+	return nil
+}
@@ -0,0 +1,440 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/google/uuid"
+)
+
+// linkHasCode mirrors short_code = $1 OR custom_short_code = $1.
+func linkHasCode(link *database.Link, code string) bool {
+	return link.ShortCode == code || (link.CustomShortCode.Valid && link.CustomShortCode.String == code)
+}
+
+// findLink returns the live link matching match. Callers hold s.mu.
+func (s *Store) findLink(match func(*database.Link) bool) *database.Link {
+	for _, link := range s.links {
+		if !link.DeletedAt.Valid && match(link) {
+			return link
+		}
+	}
+	return nil
+}
+
+func (s *Store) linkByID(id uuid.UUID) *database.Link {
+	return s.findLink(func(link *database.Link) bool { return link.ID == id })
+}
+
+// checkLinkUnique enforces links_short_code_key and
+// links_custom_short_code_key, which also cover soft deleted rows.
+func (s *Store) checkLinkUnique(id uuid.UUID, shortCode string, customShortCode sql.NullString) error {
+	for _, link := range s.links {
+		if link.ID == id {
+			continue
+		}
+		if link.ShortCode == shortCode {
+			return uniqueViolation("links_short_code_key")
+		}
+		if customShortCode.Valid && link.CustomShortCode.Valid && link.CustomShortCode.String == customShortCode.String {
+			return uniqueViolation("links_custom_short_code_key")
+		}
+	}
+	return nil
+}
+
+// clickCount mirrors the LEFT JOIN on click_logs used for link counts.
+func (s *Store) clickCount(link *database.Link) int64 {
+	var count int64
+	for _, click := range s.clickLogs {
+		if linkHasCode(link, click.Code) {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *Store) InsertLink(ctx context.Context, arg database.InsertLinkParams) (database.Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkLinkUnique(uuid.Nil, arg.ShortCode, arg.CustomShortCode); err != nil {
+		return database.Link{}, err
+	}
+	if s.userByID(arg.UserID) == nil {
+		return database.Link{}, foreignKeyViolation("links_user_id_fkey")
+	}
+
+	link := database.Link{
+		ID:              uuid.New(),
+		OriginalUrl:     arg.OriginalUrl,
+		ShortCode:       arg.ShortCode,
+		CustomShortCode: arg.CustomShortCode,
+		UserID:          arg.UserID,
+		ExpiredAt:       arg.ExpiredAt,
+		ActiveFrom:      arg.ActiveFrom,
+		PrelaunchUrl:    arg.PrelaunchUrl,
+		IosDeepLink:     arg.IosDeepLink,
+		IosStoreUrl:     arg.IosStoreUrl,
+		AndroidDeepLink: arg.AndroidDeepLink,
+		AndroidStoreUrl: arg.AndroidStoreUrl,
+		ForcePreview:    arg.ForcePreview,
+		CreatedAt:       now(),
+		HealthStatus:    "unknown",
+	}
+	link.UpdatedAt = link.CreatedAt
+
+	s.links = append(s.links, &link)
+	return link, nil
+}
+
+func (s *Store) GetRedirectLink(ctx context.Context, shortCode string) (database.GetRedirectLinkRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	link := s.findLink(func(link *database.Link) bool { return linkHasCode(link, shortCode) })
+	if link == nil {
+		return database.GetRedirectLinkRow{}, sql.ErrNoRows
+	}
+
+	return database.GetRedirectLinkRow{
+		OriginalUrl:     link.OriginalUrl,
+		TakenDownAt:     link.TakenDownAt,
+		TakedownReason:  link.TakedownReason,
+		ActiveFrom:      link.ActiveFrom,
+		ExpiredAt:       link.ExpiredAt,
+		PrelaunchUrl:    link.PrelaunchUrl,
+		IosDeepLink:     link.IosDeepLink,
+		IosStoreUrl:     link.IosStoreUrl,
+		AndroidDeepLink: link.AndroidDeepLink,
+		AndroidStoreUrl: link.AndroidStoreUrl,
+		ForcePreview:    link.ForcePreview,
+	}, nil
+}
+
+func (s *Store) GetLinkByStatsToken(ctx context.Context, statsShareToken sql.NullString) (database.GetLinkByStatsTokenRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	link := s.findLink(func(link *database.Link) bool {
+		return link.PublicStats && link.StatsShareToken.Valid && link.StatsShareToken == statsShareToken && !link.TakenDownAt.Valid
+	})
+	if link == nil {
+		return database.GetLinkByStatsTokenRow{}, sql.ErrNoRows
+	}
+
+	return database.GetLinkByStatsTokenRow{
+		ID:              link.ID,
+		UserID:          link.UserID,
+		OriginalUrl:     link.OriginalUrl,
+		ShortCode:       link.ShortCode,
+		CustomShortCode: link.CustomShortCode,
+		CreatedAt:       link.CreatedAt,
+	}, nil
+}
+
+func (s *Store) GetLinkPreview(ctx context.Context, shortCode string) (database.GetLinkPreviewRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	link := s.findLink(func(link *database.Link) bool { return linkHasCode(link, shortCode) })
+	if link == nil {
+		return database.GetLinkPreviewRow{}, sql.ErrNoRows
+	}
+
+	return database.GetLinkPreviewRow{
+		OriginalUrl:     link.OriginalUrl,
+		MetaTitle:       link.MetaTitle,
+		MetaDescription: link.MetaDescription,
+		MetaImageUrl:    link.MetaImageUrl,
+		HealthStatus:    link.HealthStatus,
+		TakenDownAt:     link.TakenDownAt,
+		TakedownReason:  link.TakedownReason,
+		ActiveFrom:      link.ActiveFrom,
+		ExpiredAt:       link.ExpiredAt,
+		CreatedAt:       link.CreatedAt,
+	}, nil
+}
+
+func (s *Store) GetLinkByCode(ctx context.Context, shortCode string) (database.GetLinkByCodeRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	link := s.findLink(func(link *database.Link) bool { return linkHasCode(link, shortCode) })
+	if link == nil {
+		return database.GetLinkByCodeRow{}, sql.ErrNoRows
+	}
+
+	return database.GetLinkByCodeRow{ID: link.ID, UserID: link.UserID}, nil
+}
+
+func (s *Store) GetLink(ctx context.Context, arg database.GetLinkParams) (database.GetLinkRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	link := s.findLink(func(link *database.Link) bool { return link.ID == arg.ID && link.UserID == arg.UserID })
+	if link == nil {
+		return database.GetLinkRow{}, sql.ErrNoRows
+	}
+
+	row := database.GetLinkRow(linkRow(link))
+	row.Counts = s.clickCount(link)
+	return row, nil
+}
+
+func (s *Store) GetLinks(ctx context.Context, arg database.GetLinksParams) ([]database.GetLinksRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	current := now()
+
+	var rows []database.GetLinksRow
+	for _, link := range s.links {
+		if link.DeletedAt.Valid || link.UserID != arg.UserID {
+			continue
+		}
+		if arg.HealthStatus.Valid && link.HealthStatus != arg.HealthStatus.String {
+			continue
+		}
+		if arg.State.Valid && string(utils.LinkStateAt(link.ActiveFrom, link.ExpiredAt, current)) != arg.State.String {
+			continue
+		}
+
+		row := database.GetLinksRow(linkRow(link))
+		row.Counts = s.clickCount(link)
+		rows = append(rows, row)
+	}
+
+	slices.SortStableFunc(rows, func(a, b database.GetLinksRow) int {
+		switch arg.OrderBy {
+		case "created_at":
+			return b.CreatedAt.Compare(a.CreatedAt)
+		case "updated_at":
+			return b.UpdatedAt.Compare(a.UpdatedAt)
+		case "expired_at":
+			return compareNullTimeDesc(a.ExpiredAt, b.ExpiredAt)
+		case "counts":
+			return cmp.Compare(b.Counts, a.Counts)
+		}
+		return 0
+	})
+
+	return page(rows, arg.Limit, arg.Offset), nil
+}
+
+// compareNullTimeDesc orders like a DESC column in Postgres, where NULLs
+// come first.
+func compareNullTimeDesc(a sql.NullTime, b sql.NullTime) int {
+	switch {
+	case !a.Valid && !b.Valid:
+		return 0
+	case !a.Valid:
+		return -1
+	case !b.Valid:
+		return 1
+	}
+	return b.Time.Compare(a.Time)
+}
+
+func (s *Store) UpdateLink(ctx context.Context, arg database.UpdateLinkParams) (database.Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link := s.findLink(func(link *database.Link) bool { return link.ID == arg.ID && link.UserID == arg.UserID })
+	if link == nil {
+		return database.Link{}, sql.ErrNoRows
+	}
+	if err := s.checkLinkUnique(link.ID, link.ShortCode, arg.CustomShortCode); err != nil {
+		return database.Link{}, err
+	}
+
+	link.CustomShortCode = arg.CustomShortCode
+	link.OriginalUrl = arg.OriginalUrl
+	link.ExpiredAt = arg.ExpiredAt
+	link.ActiveFrom = arg.ActiveFrom
+	link.PrelaunchUrl = arg.PrelaunchUrl
+	link.IosDeepLink = arg.IosDeepLink
+	link.IosStoreUrl = arg.IosStoreUrl
+	link.AndroidDeepLink = arg.AndroidDeepLink
+	link.AndroidStoreUrl = arg.AndroidStoreUrl
+	link.ForcePreview = arg.ForcePreview
+	link.ExpiryNotifiedAt = sql.NullTime{}
+	link.UpdatedAt = now()
+	return *link, nil
+}
+
+func (s *Store) SetLinkPublicStats(ctx context.Context, arg database.SetLinkPublicStatsParams) (database.Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link := s.findLink(func(link *database.Link) bool { return link.ID == arg.ID && link.UserID == arg.UserID })
+	if link == nil {
+		return database.Link{}, sql.ErrNoRows
+	}
+
+	link.PublicStats = arg.PublicStats
+	if !link.StatsShareToken.Valid {
+		link.StatsShareToken = sql.NullString{String: arg.StatsShareToken, Valid: true}
+	}
+	link.UpdatedAt = now()
+	return *link, nil
+}
+
+func (s *Store) RotateLinkStatsToken(ctx context.Context, arg database.RotateLinkStatsTokenParams) (database.Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link := s.findLink(func(link *database.Link) bool { return link.ID == arg.ID && link.UserID == arg.UserID })
+	if link == nil {
+		return database.Link{}, sql.ErrNoRows
+	}
+
+	link.StatsShareToken = sql.NullString{String: arg.StatsShareToken, Valid: true}
+	link.UpdatedAt = now()
+	return *link, nil
+}
+
+func (s *Store) GetTotalActiveLinks(ctx context.Context, userID uuid.UUID) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var total int64
+	for _, link := range s.links {
+		if !link.DeletedAt.Valid && link.UserID == userID {
+			total++
+		}
+	}
+	return total, nil
+}
+
+func (s *Store) DeleteLink(ctx context.Context, arg database.DeleteLinkParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, link := range s.links {
+		if link.ID == arg.ID && link.UserID == arg.UserID {
+			link.DeletedAt = sql.NullTime{Time: now(), Valid: true}
+		}
+	}
+	return nil
+}
+
+func (s *Store) ClaimExpiredLinks(ctx context.Context) ([]database.Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := now()
+	var claimed []database.Link
+	for _, link := range s.links {
+		if link.DeletedAt.Valid || link.ExpiryNotifiedAt.Valid || !link.ExpiredAt.Valid || link.ExpiredAt.Time.After(current) {
+			continue
+		}
+
+		link.ExpiryNotifiedAt = sql.NullTime{Time: current, Valid: true}
+		claimed = append(claimed, *link)
+	}
+	return claimed, nil
+}
+
+func (s *Store) UpdateLinkMetadata(ctx context.Context, arg database.UpdateLinkMetadataParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, link := range s.links {
+		if link.ID == arg.ID && link.OriginalUrl == arg.OriginalUrl {
+			link.MetaTitle = arg.MetaTitle
+			link.MetaDescription = arg.MetaDescription
+			link.MetaImageUrl = arg.MetaImageUrl
+			link.MetaFaviconUrl = arg.MetaFaviconUrl
+			link.MetaFetchedAt = sql.NullTime{Time: now(), Valid: true}
+		}
+	}
+	return nil
+}
+
+func (s *Store) ShortCodeExists(ctx context.Context, shortCode string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.ContainsFunc(s.links, func(link *database.Link) bool {
+		return linkHasCode(link, shortCode)
+	}), nil
+}
+
+// linkWithCounts has the columns of l.* plus a click count, which is the
+// shape of GetLinkRow and AdminGetLinkRow.
+type linkWithCounts struct {
+	ID                  uuid.UUID
+	OriginalUrl         string
+	ShortCode           string
+	CustomShortCode     sql.NullString
+	UserID              uuid.UUID
+	ExpiredAt           sql.NullTime
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	DeletedAt           sql.NullTime
+	ExpiryNotifiedAt    sql.NullTime
+	MetaTitle           sql.NullString
+	MetaDescription     sql.NullString
+	MetaImageUrl        sql.NullString
+	MetaFaviconUrl      sql.NullString
+	MetaFetchedAt       sql.NullTime
+	HealthStatus        string
+	ConsecutiveFailures int32
+	LastCheckedAt       sql.NullTime
+	TakenDownAt         sql.NullTime
+	TakedownReason      sql.NullString
+	TakenDownBy         uuid.NullUUID
+	ActiveFrom          sql.NullTime
+	PrelaunchUrl        sql.NullString
+	CampaignID          uuid.NullUUID
+	IosDeepLink         sql.NullString
+	IosStoreUrl         sql.NullString
+	AndroidDeepLink     sql.NullString
+	AndroidStoreUrl     sql.NullString
+	ForcePreview        bool
+	PublicStats         bool
+	StatsShareToken     sql.NullString
+	Counts              int64
+}
+
+func linkRow(link *database.Link) linkWithCounts {
+	return linkWithCounts{
+		ID:                  link.ID,
+		OriginalUrl:         link.OriginalUrl,
+		ShortCode:           link.ShortCode,
+		CustomShortCode:     link.CustomShortCode,
+		UserID:              link.UserID,
+		ExpiredAt:           link.ExpiredAt,
+		CreatedAt:           link.CreatedAt,
+		UpdatedAt:           link.UpdatedAt,
+		DeletedAt:           link.DeletedAt,
+		ExpiryNotifiedAt:    link.ExpiryNotifiedAt,
+		MetaTitle:           link.MetaTitle,
+		MetaDescription:     link.MetaDescription,
+		MetaImageUrl:        link.MetaImageUrl,
+		MetaFaviconUrl:      link.MetaFaviconUrl,
+		MetaFetchedAt:       link.MetaFetchedAt,
+		HealthStatus:        link.HealthStatus,
+		ConsecutiveFailures: link.ConsecutiveFailures,
+		LastCheckedAt:       link.LastCheckedAt,
+		TakenDownAt:         link.TakenDownAt,
+		TakedownReason:      link.TakedownReason,
+		TakenDownBy:         link.TakenDownBy,
+		ActiveFrom:          link.ActiveFrom,
+		PrelaunchUrl:        link.PrelaunchUrl,
+		CampaignID:          link.CampaignID,
+		IosDeepLink:         link.IosDeepLink,
+		IosStoreUrl:         link.IosStoreUrl,
+		AndroidDeepLink:     link.AndroidDeepLink,
+		AndroidStoreUrl:     link.AndroidStoreUrl,
+		ForcePreview:        link.ForcePreview,
+		PublicStats:         link.PublicStats,
+		StatsShareToken:     link.StatsShareToken,
+	}
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/google/uuid"
+)
+
+func (s *Store) InsertLoginAttempt(ctx context.Context, arg database.InsertLoginAttemptParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.loginAttempts = append(s.loginAttempts, &database.LoginAttempt{
+		ID:        uuid.New(),
+		UserID:    arg.UserID,
+		Email:     arg.Email,
+		IpAddress: arg.IpAddress,
+		UserAgent: arg.UserAgent,
+		Success:   arg.Success,
+		Reason:    arg.Reason,
+		CreatedAt: now(),
+	})
+	return nil
+}
+
+// GetEmailLoginFailures counts failures since the later of since and the
+// last successful sign-in for the email.
+func (s *Store) GetEmailLoginFailures(ctx context.Context, arg database.GetEmailLoginFailuresParams) (database.GetEmailLoginFailuresRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	lastSuccess := epoch
+	for _, attempt := range s.loginAttempts {
+		if attempt.Email == arg.Email && attempt.Success && attempt.CreatedAt.After(lastSuccess) {
+			lastSuccess = attempt.CreatedAt
+		}
+	}
+
+	row := database.GetEmailLoginFailuresRow{LastFailureAt: epoch}
+	for _, attempt := range s.loginAttempts {
+		if attempt.Email == arg.Email && !attempt.Success && attempt.CreatedAt.After(arg.Since) && attempt.CreatedAt.After(lastSuccess) {
+			row.Failures++
+			if attempt.CreatedAt.After(row.LastFailureAt) {
+				row.LastFailureAt = attempt.CreatedAt
+			}
+		}
+	}
+	return row, nil
+}
+
+func (s *Store) GetIPLoginFailures(ctx context.Context, arg database.GetIPLoginFailuresParams) (database.GetIPLoginFailuresRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	row := database.GetIPLoginFailuresRow{LastFailureAt: epoch}
+	for _, attempt := range s.loginAttempts {
+		if attempt.IpAddress == arg.IpAddress && !attempt.Success && attempt.CreatedAt.After(arg.Since) {
+			row.Failures++
+			if attempt.CreatedAt.After(row.LastFailureAt) {
+				row.LastFailureAt = attempt.CreatedAt
+			}
+		}
+	}
+	return row, nil
+}
+
+func (s *Store) GetUserLoginAttempts(ctx context.Context, arg database.GetUserLoginAttemptsParams) ([]database.LoginAttempt, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var attempts []database.LoginAttempt
+	for _, attempt := range slices.Backward(s.loginAttempts) {
+		if arg.UserID.Valid && attempt.UserID.Valid && attempt.UserID.UUID == arg.UserID.UUID {
+			attempts = append(attempts, *attempt)
+		}
+	}
+	return page(attempts, arg.Limit, 0), nil
+}
@@ -0,0 +1,108 @@
+// Package memory is an in-memory repository.Store for tests. It keeps the
+// behaviour of the Postgres queries that callers rely on: soft deleted rows
+// are hidden, missing rows return sql.ErrNoRows and unique or foreign key
+// violations return the same *pq.Error codes and constraint names.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository"
+	"github.com/lib/pq"
+)
+
+// Store is safe for concurrent use. Rows are kept in insertion order and
+// always returned as copies.
+type Store struct {
+	mu sync.RWMutex
+
+	users         []*database.User
+	userTokens    []*database.UserToken
+	recoveryCodes []*database.UserRecoveryCode
+	loginAttempts []*database.LoginAttempt
+	links         []*database.Link
+	healthChecks  []*database.LinkHealthCheck
+	clickLogs     []*database.ClickLog
+	webhooks      []*database.Webhook
+	deliveries    []*database.WebhookDelivery
+	auditEvents   []*database.AuditEvent
+	dataExports   []*database.DataExport
+	campaigns     []*database.Campaign
+
+	// clickLogPartitions stands in for the click_log_partitions registry;
+	// clicks themselves are not partitioned.
+	clickLogPartitions []time.Time
+}
+
+var _ repository.Store = (*Store)(nil)
+
+func New() *Store {
+	return &Store{}
+}
+
+// PingContext lets the store stand in for the database in readiness checks.
+func (s *Store) PingContext(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// epoch is what the login attempt queries return when there is no failure.
+var epoch = time.Unix(0, 0).UTC()
+
+func now() time.Time {
+	return time.Now().UTC()
+}
+
+func uniqueViolation(constraint string) error {
+	return &pq.Error{
+		Code:       "23505",
+		Message:    fmt.Sprintf("duplicate key value violates unique constraint %q", constraint),
+		Constraint: constraint,
+	}
+}
+
+func foreignKeyViolation(constraint string) error {
+	return &pq.Error{
+		Code:       "23503",
+		Message:    fmt.Sprintf("insert or update violates foreign key constraint %q", constraint),
+		Constraint: constraint,
+	}
+}
+
+func checkViolation(constraint string) error {
+	return &pq.Error{
+		Code:       "23514",
+		Message:    fmt.Sprintf("new row violates check constraint %q", constraint),
+		Constraint: constraint,
+	}
+}
+
+// containsFold mirrors column ILIKE '%' || search || '%'.
+func containsFold(value string, search string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(search))
+}
+
+// page applies LIMIT and OFFSET.
+func page[T any](rows []T, limit int32, offset int32) []T {
+	start := min(max(int(offset), 0), len(rows))
+	end := min(start+max(int(limit), 0), len(rows))
+	return rows[start:end]
+}
+
+// truncateDay mirrors DATE_TRUNC('day', ...) in a UTC session.
+func truncateDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func cloneStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return slices.Clone(values)
+}
@@ -0,0 +1,333 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/google/uuid"
+)
+
+// findUser returns the live user matching match. Callers hold s.mu.
+func (s *Store) findUser(match func(*database.User) bool) *database.User {
+	for _, user := range s.users {
+		if !user.DeletedAt.Valid && match(user) {
+			return user
+		}
+	}
+	return nil
+}
+
+func (s *Store) userByID(id uuid.UUID) *database.User {
+	return s.findUser(func(user *database.User) bool { return user.ID == id })
+}
+
+// checkUserUnique enforces users_email_key and users_google_id_key, which
+// also cover soft deleted rows. Callers hold s.mu.
+func (s *Store) checkUserUnique(id uuid.UUID, email string, googleID sql.NullString) error {
+	for _, user := range s.users {
+		if user.ID == id {
+			continue
+		}
+		if user.Email == email {
+			return uniqueViolation("users_email_key")
+		}
+		if googleID.Valid && user.GoogleID.Valid && user.GoogleID.String == googleID.String {
+			return uniqueViolation("users_google_id_key")
+		}
+	}
+	return nil
+}
+
+func (s *Store) insertUser(user database.User) (database.User, error) {
+	if err := s.checkUserUnique(uuid.Nil, user.Email, user.GoogleID); err != nil {
+		return database.User{}, err
+	}
+
+	user.ID = uuid.New()
+	user.IsActive = true
+	user.Role = "user"
+	user.CreatedAt = now()
+	user.UpdatedAt = user.CreatedAt
+
+	s.users = append(s.users, &user)
+	return user, nil
+}
+
+func (s *Store) GetUser(ctx context.Context, id uuid.UUID) (database.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user := s.userByID(id)
+	if user == nil {
+		return database.User{}, sql.ErrNoRows
+	}
+	return *user, nil
+}
+
+func (s *Store) GetUserByEmail(ctx context.Context, email string) (database.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user := s.findUser(func(user *database.User) bool { return user.Email == email })
+	if user == nil {
+		return database.User{}, sql.ErrNoRows
+	}
+	return *user, nil
+}
+
+func (s *Store) GetUserByGoogleID(ctx context.Context, googleID sql.NullString) (database.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user := s.findUser(func(user *database.User) bool {
+		return googleID.Valid && user.GoogleID.Valid && user.GoogleID.String == googleID.String
+	})
+	if user == nil {
+		return database.User{}, sql.ErrNoRows
+	}
+	return *user, nil
+}
+
+func (s *Store) InsertUser(ctx context.Context, arg database.InsertUserParams) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.insertUser(database.User{
+		Name:         arg.Name,
+		Email:        arg.Email,
+		PasswordHash: arg.PasswordHash,
+	})
+}
+
+func (s *Store) InsertUserWithGoogle(ctx context.Context, arg database.InsertUserWithGoogleParams) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.insertUser(database.User{
+		Name:            arg.Name,
+		Email:           arg.Email,
+		GoogleID:        arg.GoogleID,
+		IsVerified:      true,
+		ProfileImageUrl: arg.ProfileImageUrl,
+	})
+}
+
+func (s *Store) UpdateUser(ctx context.Context, arg database.UpdateUserParams) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := s.userByID(arg.ID)
+	if user == nil {
+		return database.User{}, sql.ErrNoRows
+	}
+	if err := s.checkUserUnique(user.ID, arg.Email, sql.NullString{}); err != nil {
+		return database.User{}, err
+	}
+
+	user.Name = arg.Name
+	user.Email = arg.Email
+	user.PasswordHash = arg.PasswordHash
+	user.IsVerified = arg.IsVerified
+	user.ProfileImageUrl = arg.ProfileImageUrl
+	return *user, nil
+}
+
+func (s *Store) MarkUserVerified(ctx context.Context, id uuid.UUID) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := s.userByID(id)
+	if user == nil {
+		return database.User{}, sql.ErrNoRows
+	}
+
+	user.IsVerified = true
+	user.UpdatedAt = now()
+	return *user, nil
+}
+
+func (s *Store) UpdateUserPassword(ctx context.Context, arg database.UpdateUserPasswordParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user := s.userByID(arg.ID); user != nil {
+		user.PasswordHash = arg.PasswordHash
+		user.PasswordChangedAt = sql.NullTime{Time: now(), Valid: true}
+		user.UpdatedAt = now()
+	}
+	return nil
+}
+
+func (s *Store) InsertUserToken(ctx context.Context, arg database.InsertUserTokenParams) (database.UserToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, token := range s.userTokens {
+		if token.TokenHash == arg.TokenHash {
+			return database.UserToken{}, uniqueViolation("user_tokens_token_hash_key")
+		}
+	}
+	if s.userByID(arg.UserID) == nil {
+		return database.UserToken{}, foreignKeyViolation("user_tokens_user_id_fkey")
+	}
+
+	token := database.UserToken{
+		ID:        uuid.New(),
+		UserID:    arg.UserID,
+		Purpose:   arg.Purpose,
+		TokenHash: arg.TokenHash,
+		ExpiresAt: arg.ExpiresAt,
+		CreatedAt: now(),
+	}
+	s.userTokens = append(s.userTokens, &token)
+	return token, nil
+}
+
+func (s *Store) ConsumeUserToken(ctx context.Context, arg database.ConsumeUserTokenParams) (database.UserToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := now()
+	for _, token := range s.userTokens {
+		if token.TokenHash == arg.TokenHash && token.Purpose == arg.Purpose && !token.UsedAt.Valid && token.ExpiresAt.After(current) {
+			token.UsedAt = sql.NullTime{Time: current, Valid: true}
+			return *token, nil
+		}
+	}
+	return database.UserToken{}, sql.ErrNoRows
+}
+
+func (s *Store) InvalidateUserTokens(ctx context.Context, arg database.InvalidateUserTokensParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := now()
+	for _, token := range s.userTokens {
+		if token.UserID == arg.UserID && token.Purpose == arg.Purpose && !token.UsedAt.Valid {
+			token.UsedAt = sql.NullTime{Time: current, Valid: true}
+		}
+	}
+	return nil
+}
+
+func (s *Store) SetUserTotpSecret(ctx context.Context, arg database.SetUserTotpSecretParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user := s.userByID(arg.ID); user != nil {
+		user.TotpSecret = arg.TotpSecret
+		user.TotpEnabled = false
+		user.TotpLastStep = sql.NullInt64{}
+		user.UpdatedAt = now()
+	}
+	return nil
+}
+
+func (s *Store) EnableUserTotp(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user := s.userByID(id); user != nil && user.TotpSecret.Valid {
+		user.TotpEnabled = true
+		user.UpdatedAt = now()
+	}
+	return nil
+}
+
+func (s *Store) DisableUserTotp(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user := s.userByID(id); user != nil {
+		user.TotpSecret = sql.NullString{}
+		user.TotpEnabled = false
+		user.TotpLastStep = sql.NullInt64{}
+		user.UpdatedAt = now()
+	}
+	return nil
+}
+
+func (s *Store) ClaimUserTotpStep(ctx context.Context, arg database.ClaimUserTotpStepParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := s.userByID(arg.ID)
+	if user == nil || (user.TotpLastStep.Valid && user.TotpLastStep.Int64 >= arg.Step) {
+		return 0, nil
+	}
+
+	user.TotpLastStep = sql.NullInt64{Int64: arg.Step, Valid: true}
+	return 1, nil
+}
+
+func (s *Store) InsertRecoveryCode(ctx context.Context, arg database.InsertRecoveryCodeParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, code := range s.recoveryCodes {
+		if code.UserID == arg.UserID && code.CodeHash == arg.CodeHash {
+			return uniqueViolation("idx_user_recovery_codes_user_hash")
+		}
+	}
+
+	s.recoveryCodes = append(s.recoveryCodes, &database.UserRecoveryCode{
+		ID:        uuid.New(),
+		UserID:    arg.UserID,
+		CodeHash:  arg.CodeHash,
+		CreatedAt: now(),
+	})
+	return nil
+}
+
+func (s *Store) DeleteRecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recoveryCodes = slices.DeleteFunc(s.recoveryCodes, func(code *database.UserRecoveryCode) bool {
+		return code.UserID == userID
+	})
+	return nil
+}
+
+func (s *Store) ConsumeRecoveryCode(ctx context.Context, arg database.ConsumeRecoveryCodeParams) (database.UserRecoveryCode, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, code := range s.recoveryCodes {
+		if code.UserID == arg.UserID && code.CodeHash == arg.CodeHash && !code.UsedAt.Valid {
+			code.UsedAt = sql.NullTime{Time: now(), Valid: true}
+			return *code, nil
+		}
+	}
+	return database.UserRecoveryCode{}, sql.ErrNoRows
+}
+
+func (s *Store) SoftDeleteUser(ctx context.Context, id uuid.UUID) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := s.userByID(id)
+	if user == nil {
+		return database.User{}, sql.ErrNoRows
+	}
+
+	user.DeletedAt = sql.NullTime{Time: now(), Valid: true}
+	user.UpdatedAt = user.DeletedAt.Time
+	return *user, nil
+}
+
+func (s *Store) RestoreUser(ctx context.Context, id uuid.UUID) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.users {
+		if user.ID == id && user.DeletedAt.Valid {
+			user.DeletedAt = sql.NullTime{}
+			user.UpdatedAt = now()
+			return *user, nil
+		}
+	}
+	return database.User{}, sql.ErrNoRows
+}
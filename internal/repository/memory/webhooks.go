@@ -0,0 +1,235 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/google/uuid"
+)
+
+func copyWebhook(webhook *database.Webhook) database.Webhook {
+	row := *webhook
+	row.Events = cloneStrings(webhook.Events)
+	return row
+}
+
+func copyDelivery(delivery *database.WebhookDelivery) database.WebhookDelivery {
+	row := *delivery
+	row.Payload = slices.Clone(delivery.Payload)
+	return row
+}
+
+func (s *Store) webhookByID(id uuid.UUID) *database.Webhook {
+	for _, webhook := range s.webhooks {
+		if webhook.ID == id {
+			return webhook
+		}
+	}
+	return nil
+}
+
+func (s *Store) InsertWebhook(ctx context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.userByID(arg.UserID) == nil {
+		return database.Webhook{}, foreignKeyViolation("webhooks_user_id_fkey")
+	}
+
+	webhook := database.Webhook{
+		ID:        uuid.New(),
+		UserID:    arg.UserID,
+		Url:       arg.Url,
+		Secret:    arg.Secret,
+		Events:    cloneStrings(arg.Events),
+		IsActive:  true,
+		CreatedAt: now(),
+	}
+	webhook.UpdatedAt = webhook.CreatedAt
+
+	s.webhooks = append(s.webhooks, &webhook)
+	return copyWebhook(&webhook), nil
+}
+
+func (s *Store) GetWebhooks(ctx context.Context, userID uuid.UUID) ([]database.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var webhooks []database.Webhook
+	for _, webhook := range slices.Backward(s.webhooks) {
+		if webhook.UserID == userID && !webhook.DeletedAt.Valid {
+			webhooks = append(webhooks, copyWebhook(webhook))
+		}
+	}
+	return webhooks, nil
+}
+
+func (s *Store) GetWebhook(ctx context.Context, arg database.GetWebhookParams) (database.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	webhook := s.webhookByID(arg.ID)
+	if webhook == nil || webhook.UserID != arg.UserID || webhook.DeletedAt.Valid {
+		return database.Webhook{}, sql.ErrNoRows
+	}
+	return copyWebhook(webhook), nil
+}
+
+func (s *Store) DeleteWebhook(ctx context.Context, arg database.DeleteWebhookParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if webhook := s.webhookByID(arg.ID); webhook != nil && webhook.UserID == arg.UserID {
+		webhook.DeletedAt = sql.NullTime{Time: now(), Valid: true}
+	}
+	return nil
+}
+
+func (s *Store) DisableWebhook(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if webhook := s.webhookByID(id); webhook != nil {
+		webhook.IsActive = false
+		webhook.UpdatedAt = now()
+	}
+	return nil
+}
+
+func (s *Store) EnableWebhook(ctx context.Context, arg database.EnableWebhookParams) (database.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	webhook := s.webhookByID(arg.ID)
+	if webhook == nil || webhook.UserID != arg.UserID || webhook.DeletedAt.Valid {
+		return database.Webhook{}, sql.ErrNoRows
+	}
+
+	webhook.IsActive = true
+	webhook.UpdatedAt = now()
+	return copyWebhook(webhook), nil
+}
+
+func (s *Store) GetSubscribedWebhooks(ctx context.Context, arg database.GetSubscribedWebhooksParams) ([]database.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var webhooks []database.Webhook
+	for _, webhook := range s.webhooks {
+		if webhook.UserID == arg.UserID && webhook.IsActive && !webhook.DeletedAt.Valid && slices.Contains(webhook.Events, arg.Event) {
+			webhooks = append(webhooks, copyWebhook(webhook))
+		}
+	}
+	return webhooks, nil
+}
+
+func (s *Store) InsertWebhookDelivery(ctx context.Context, arg database.InsertWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.webhookByID(arg.WebhookID) == nil {
+		return database.WebhookDelivery{}, foreignKeyViolation("webhook_deliveries_webhook_id_fkey")
+	}
+
+	delivery := database.WebhookDelivery{
+		ID:        uuid.New(),
+		WebhookID: arg.WebhookID,
+		Event:     arg.Event,
+		Payload:   slices.Clone(arg.Payload),
+		Status:    "pending",
+		CreatedAt: now(),
+	}
+	delivery.NextAttemptAt = delivery.CreatedAt
+	delivery.UpdatedAt = delivery.CreatedAt
+
+	s.deliveries = append(s.deliveries, &delivery)
+	return copyDelivery(&delivery), nil
+}
+
+func (s *Store) GetWebhookDeliveries(ctx context.Context, arg database.GetWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var deliveries []database.WebhookDelivery
+	for _, delivery := range slices.Backward(s.deliveries) {
+		if delivery.WebhookID == arg.WebhookID {
+			deliveries = append(deliveries, copyDelivery(delivery))
+		}
+	}
+	return page(deliveries, arg.Limit, arg.Offset), nil
+}
+
+func (s *Store) ClaimDueWebhookDeliveries(ctx context.Context, arg database.ClaimDueWebhookDeliveriesParams) ([]database.ClaimDueWebhookDeliveriesRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := now()
+
+	var due []*database.WebhookDelivery
+	for _, delivery := range s.deliveries {
+		webhook := s.webhookByID(delivery.WebhookID)
+		if webhook == nil || !webhook.IsActive || webhook.DeletedAt.Valid {
+			continue
+		}
+		if delivery.Status == "pending" && !delivery.NextAttemptAt.After(current) {
+			due = append(due, delivery)
+		}
+	}
+
+	slices.SortStableFunc(due, func(a, b *database.WebhookDelivery) int {
+		return a.NextAttemptAt.Compare(b.NextAttemptAt)
+	})
+
+	var claimed []database.ClaimDueWebhookDeliveriesRow
+	for _, delivery := range page(due, arg.BatchSize, 0) {
+		webhook := s.webhookByID(delivery.WebhookID)
+		delivery.NextAttemptAt = arg.LeaseUntil
+		claimed = append(claimed, database.ClaimDueWebhookDeliveriesRow{
+			ID:        delivery.ID,
+			WebhookID: delivery.WebhookID,
+			Event:     delivery.Event,
+			Payload:   slices.Clone(delivery.Payload),
+			Attempts:  delivery.Attempts,
+			CreatedAt: delivery.CreatedAt,
+			Url:       webhook.Url,
+			Secret:    webhook.Secret,
+		})
+	}
+	return claimed, nil
+}
+
+func (s *Store) UpdateWebhookDeliveryAttempt(ctx context.Context, arg database.UpdateWebhookDeliveryAttemptParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, delivery := range s.deliveries {
+		if delivery.ID == arg.ID {
+			delivery.Status = arg.Status
+			delivery.Attempts++
+			delivery.NextAttemptAt = arg.NextAttemptAt
+			delivery.LastStatusCode = arg.LastStatusCode
+			delivery.LastError = arg.LastError
+			delivery.DeliveredAt = arg.DeliveredAt
+			delivery.UpdatedAt = now()
+		}
+	}
+	return nil
+}
+
+func (s *Store) RedeliverWebhookDelivery(ctx context.Context, arg database.RedeliverWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, delivery := range s.deliveries {
+		if delivery.ID == arg.ID && delivery.WebhookID == arg.WebhookID {
+			delivery.Status = "pending"
+			delivery.Attempts = 0
+			delivery.NextAttemptAt = now()
+			delivery.UpdatedAt = delivery.NextAttemptAt
+			return copyDelivery(delivery), nil
+		}
+	}
+	return database.WebhookDelivery{}, sql.ErrNoRows
+}
@@ -0,0 +1,202 @@
+// Package repository defines the storage each service depends on, grouped by
+// aggregate. *database.Queries is the Postgres implementation; package
+// memory provides one for tests that must not need a database.
+//
+// Method names and parameter types follow the generated sqlc queries, so a
+// new query only has to be added to the interface of its aggregate and to the
+// in-memory store.
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/google/uuid"
+)
+
+// UserRepository covers accounts together with the tokens and second
+// factors that belong to them.
+type UserRepository interface {
+	GetUser(ctx context.Context, id uuid.UUID) (database.User, error)
+	GetUserByEmail(ctx context.Context, email string) (database.User, error)
+	GetUserByGoogleID(ctx context.Context, googleID sql.NullString) (database.User, error)
+	InsertUser(ctx context.Context, arg database.InsertUserParams) (database.User, error)
+	InsertUserWithGoogle(ctx context.Context, arg database.InsertUserWithGoogleParams) (database.User, error)
+	UpdateUser(ctx context.Context, arg database.UpdateUserParams) (database.User, error)
+	MarkUserVerified(ctx context.Context, id uuid.UUID) (database.User, error)
+	UpdateUserPassword(ctx context.Context, arg database.UpdateUserPasswordParams) error
+	SoftDeleteUser(ctx context.Context, id uuid.UUID) (database.User, error)
+	RestoreUser(ctx context.Context, id uuid.UUID) (database.User, error)
+
+	InsertUserToken(ctx context.Context, arg database.InsertUserTokenParams) (database.UserToken, error)
+	ConsumeUserToken(ctx context.Context, arg database.ConsumeUserTokenParams) (database.UserToken, error)
+	InvalidateUserTokens(ctx context.Context, arg database.InvalidateUserTokensParams) error
+
+	SetUserTotpSecret(ctx context.Context, arg database.SetUserTotpSecretParams) error
+	EnableUserTotp(ctx context.Context, id uuid.UUID) error
+	DisableUserTotp(ctx context.Context, id uuid.UUID) error
+	ClaimUserTotpStep(ctx context.Context, arg database.ClaimUserTotpStepParams) (int64, error)
+	InsertRecoveryCode(ctx context.Context, arg database.InsertRecoveryCodeParams) error
+	DeleteRecoveryCodes(ctx context.Context, userID uuid.UUID) error
+	ConsumeRecoveryCode(ctx context.Context, arg database.ConsumeRecoveryCodeParams) (database.UserRecoveryCode, error)
+}
+
+type LoginAttemptRepository interface {
+	InsertLoginAttempt(ctx context.Context, arg database.InsertLoginAttemptParams) error
+	GetEmailLoginFailures(ctx context.Context, arg database.GetEmailLoginFailuresParams) (database.GetEmailLoginFailuresRow, error)
+	GetIPLoginFailures(ctx context.Context, arg database.GetIPLoginFailuresParams) (database.GetIPLoginFailuresRow, error)
+	GetUserLoginAttempts(ctx context.Context, arg database.GetUserLoginAttemptsParams) ([]database.LoginAttempt, error)
+}
+
+// LinkRepository covers links, their metadata and destination health.
+type LinkRepository interface {
+	InsertLink(ctx context.Context, arg database.InsertLinkParams) (database.Link, error)
+	GetRedirectLink(ctx context.Context, shortCode string) (database.GetRedirectLinkRow, error)
+	GetLinkPreview(ctx context.Context, shortCode string) (database.GetLinkPreviewRow, error)
+	GetLinkByCode(ctx context.Context, shortCode string) (database.GetLinkByCodeRow, error)
+	GetLinkByStatsToken(ctx context.Context, statsShareToken sql.NullString) (database.GetLinkByStatsTokenRow, error)
+	GetLink(ctx context.Context, arg database.GetLinkParams) (database.GetLinkRow, error)
+	GetLinks(ctx context.Context, arg database.GetLinksParams) ([]database.GetLinksRow, error)
+	UpdateLink(ctx context.Context, arg database.UpdateLinkParams) (database.Link, error)
+	SetLinkPublicStats(ctx context.Context, arg database.SetLinkPublicStatsParams) (database.Link, error)
+	RotateLinkStatsToken(ctx context.Context, arg database.RotateLinkStatsTokenParams) (database.Link, error)
+	GetTotalActiveLinks(ctx context.Context, userID uuid.UUID) (int64, error)
+	DeleteLink(ctx context.Context, arg database.DeleteLinkParams) error
+	ClaimExpiredLinks(ctx context.Context) ([]database.Link, error)
+	UpdateLinkMetadata(ctx context.Context, arg database.UpdateLinkMetadataParams) error
+	ShortCodeExists(ctx context.Context, shortCode string) (bool, error)
+
+	ClaimLinksForHealthCheck(ctx context.Context, arg database.ClaimLinksForHealthCheckParams) ([]database.ClaimLinksForHealthCheckRow, error)
+	InsertLinkHealthCheck(ctx context.Context, arg database.InsertLinkHealthCheckParams) error
+	MarkLinkHealthy(ctx context.Context, id uuid.UUID) error
+	MarkLinkHealthFailure(ctx context.Context, arg database.MarkLinkHealthFailureParams) (database.Link, error)
+	GetLinkHealthChecks(ctx context.Context, arg database.GetLinkHealthChecksParams) ([]database.LinkHealthCheck, error)
+	DeleteLinkHealthChecksBefore(ctx context.Context, checkedAt time.Time) error
+}
+
+type ClickLogRepository interface {
+	InsertClickLog(ctx context.Context, arg database.InsertClickLogParams) (database.ClickLog, error)
+	InsertSpooledClickLog(ctx context.Context, arg database.InsertSpooledClickLogParams) error
+	GetTotalClicks(ctx context.Context, arg database.GetTotalClicksParams) (int64, error)
+	GetByDateRange(ctx context.Context, arg database.GetByDateRangeParams) ([]database.GetByDateRangeRow, error)
+	GetDeviceBreakdown(ctx context.Context, arg database.GetDeviceBreakdownParams) ([]database.GetDeviceBreakdownRow, error)
+	GetDeviceBreakdownSingle(ctx context.Context, arg database.GetDeviceBreakdownSingleParams) ([]database.GetDeviceBreakdownSingleRow, error)
+	GetTopCountries(ctx context.Context, arg database.GetTopCountriesParams) ([]database.GetTopCountriesRow, error)
+	GetTopCountriesSingle(ctx context.Context, arg database.GetTopCountriesSingleParams) ([]database.GetTopCountriesSingleRow, error)
+	GetTopRegions(ctx context.Context, arg database.GetTopRegionsParams) ([]database.GetTopRegionsRow, error)
+	GetTopCities(ctx context.Context, arg database.GetTopCitiesParams) ([]database.GetTopCitiesRow, error)
+	GetTrafficSources(ctx context.Context, arg database.GetTrafficSourcesParams) ([]database.GetTrafficSourcesRow, error)
+	GetBrowserUsage(ctx context.Context, arg database.GetBrowserUsageParams) ([]database.GetBrowserUsageRow, error)
+}
+
+// CampaignRepository covers campaigns and the links that belong to them.
+// Campaign analytics reuse the ClickLogRepository queries with a campaign ID.
+type CampaignRepository interface {
+	InsertCampaign(ctx context.Context, arg database.InsertCampaignParams) (database.Campaign, error)
+	GetCampaigns(ctx context.Context, arg database.GetCampaignsParams) ([]database.GetCampaignsRow, error)
+	GetCampaign(ctx context.Context, arg database.GetCampaignParams) (database.GetCampaignRow, error)
+	UpdateCampaign(ctx context.Context, arg database.UpdateCampaignParams) (database.Campaign, error)
+	DeleteCampaign(ctx context.Context, arg database.DeleteCampaignParams) error
+	AddLinksToCampaign(ctx context.Context, arg database.AddLinksToCampaignParams) ([]uuid.UUID, error)
+	RemoveLinkFromCampaign(ctx context.Context, arg database.RemoveLinkFromCampaignParams) (int64, error)
+	GetCampaignLeaderboard(ctx context.Context, arg database.GetCampaignLeaderboardParams) ([]database.GetCampaignLeaderboardRow, error)
+	GetEndedCampaigns(ctx context.Context, batchSize int32) ([]database.Campaign, error)
+	MarkCampaignLinksExpired(ctx context.Context, arg database.MarkCampaignLinksExpiredParams) error
+	ExpireCampaignLinks(ctx context.Context, arg database.ExpireCampaignLinksParams) ([]database.ExpireCampaignLinksRow, error)
+}
+
+type DashboardRepository interface {
+	GetTotalActiveUsers(ctx context.Context) (int64, error)
+	GetTotalLinksCreated(ctx context.Context) (int64, error)
+	GetGlobalTotalClicks(ctx context.Context) (int64, error)
+}
+
+type WebhookRepository interface {
+	InsertWebhook(ctx context.Context, arg database.InsertWebhookParams) (database.Webhook, error)
+	GetWebhooks(ctx context.Context, userID uuid.UUID) ([]database.Webhook, error)
+	GetWebhook(ctx context.Context, arg database.GetWebhookParams) (database.Webhook, error)
+	DeleteWebhook(ctx context.Context, arg database.DeleteWebhookParams) error
+	DisableWebhook(ctx context.Context, id uuid.UUID) error
+	EnableWebhook(ctx context.Context, arg database.EnableWebhookParams) (database.Webhook, error)
+	GetSubscribedWebhooks(ctx context.Context, arg database.GetSubscribedWebhooksParams) ([]database.Webhook, error)
+	InsertWebhookDelivery(ctx context.Context, arg database.InsertWebhookDeliveryParams) (database.WebhookDelivery, error)
+	GetWebhookDeliveries(ctx context.Context, arg database.GetWebhookDeliveriesParams) ([]database.WebhookDelivery, error)
+	ClaimDueWebhookDeliveries(ctx context.Context, arg database.ClaimDueWebhookDeliveriesParams) ([]database.ClaimDueWebhookDeliveriesRow, error)
+	UpdateWebhookDeliveryAttempt(ctx context.Context, arg database.UpdateWebhookDeliveryAttemptParams) error
+	RedeliverWebhookDelivery(ctx context.Context, arg database.RedeliverWebhookDeliveryParams) (database.WebhookDelivery, error)
+}
+
+type AuditRepository interface {
+	InsertAuditEvent(ctx context.Context, arg database.InsertAuditEventParams) error
+	GetAuditEvents(ctx context.Context, arg database.GetAuditEventsParams) ([]database.AuditEvent, error)
+}
+
+// DataExportRepository covers personal data exports and the rows that go
+// into them.
+type DataExportRepository interface {
+	InsertDataExport(ctx context.Context, userID uuid.UUID) (database.DataExport, error)
+	GetLatestDataExport(ctx context.Context, userID uuid.UUID) (database.DataExport, error)
+	GetDataExport(ctx context.Context, arg database.GetDataExportParams) (database.DataExport, error)
+	ClaimPendingDataExports(ctx context.Context, arg database.ClaimPendingDataExportsParams) ([]database.DataExport, error)
+	CompleteDataExport(ctx context.Context, arg database.CompleteDataExportParams) error
+	FailDataExport(ctx context.Context, arg database.FailDataExportParams) error
+	GetExpiredDataExports(ctx context.Context, limit int32) ([]database.DataExport, error)
+	DeleteDataExport(ctx context.Context, id uuid.UUID) error
+	GetUserDataExportBlobKeys(ctx context.Context, userID uuid.UUID) ([]string, error)
+	GetUserLinksForExport(ctx context.Context, userID uuid.UUID) ([]database.Link, error)
+	GetUserClickLogsForExport(ctx context.Context, arg database.GetUserClickLogsForExportParams) ([]database.ClickLog, error)
+}
+
+// AccountDeletionRepository permanently removes accounts whose deletion
+// grace period is over.
+type AccountDeletionRepository interface {
+	GetDueAccountDeletions(ctx context.Context, arg database.GetDueAccountDeletionsParams) ([]database.User, error)
+	GetUserLinkCodes(ctx context.Context, userID uuid.UUID) ([]database.GetUserLinkCodesRow, error)
+	GetUserDataExportBlobKeys(ctx context.Context, userID uuid.UUID) ([]string, error)
+	DeleteUserClickLogs(ctx context.Context, userID uuid.UUID) (int64, error)
+	PurgeAuditEvents(ctx context.Context, ownerID uuid.UUID) (int64, error)
+	PurgeUser(ctx context.Context, id uuid.UUID) (int64, error)
+}
+
+// ClickLogRetentionRepository manages the monthly click_logs partitions and
+// enforces the retention policy on them.
+type ClickLogRetentionRepository interface {
+	EnsureClickLogPartition(ctx context.Context, month time.Time) (bool, error)
+	GetClickLogPartitionsBefore(ctx context.Context, before time.Time) ([]time.Time, error)
+	DropClickLogPartition(ctx context.Context, month time.Time) error
+	GetClickLogsForArchive(ctx context.Context, arg database.GetClickLogsForArchiveParams) ([]database.ClickLog, error)
+	DeleteClickLogsBefore(ctx context.Context, before time.Time) (int64, error)
+	AnonymizeClickLogIPs(ctx context.Context, arg database.AnonymizeClickLogIPsParams) (int64, error)
+}
+
+type AdminRepository interface {
+	AdminGetUsers(ctx context.Context, arg database.AdminGetUsersParams) ([]database.AdminGetUsersRow, error)
+	SetUserActive(ctx context.Context, arg database.SetUserActiveParams) (database.User, error)
+	SetUserRole(ctx context.Context, arg database.SetUserRoleParams) (database.User, error)
+	PromoteUsersToAdmin(ctx context.Context, emails []string) error
+	AdminGetLinks(ctx context.Context, arg database.AdminGetLinksParams) ([]database.AdminGetLinksRow, error)
+	AdminGetLink(ctx context.Context, id uuid.UUID) (database.AdminGetLinkRow, error)
+	TakeDownLink(ctx context.Context, arg database.TakeDownLinkParams) (database.Link, error)
+	RestoreLink(ctx context.Context, id uuid.UUID) (database.Link, error)
+	GetPlatformGrowth(ctx context.Context, arg database.GetPlatformGrowthParams) ([]database.GetPlatformGrowthRow, error)
+}
+
+// Store is everything the application persists.
+type Store interface {
+	UserRepository
+	LoginAttemptRepository
+	LinkRepository
+	ClickLogRepository
+	CampaignRepository
+	DashboardRepository
+	WebhookRepository
+	AuditRepository
+	DataExportRepository
+	AccountDeletionRepository
+	ClickLogRetentionRepository
+	AdminRepository
+}
+
+var _ Store = (*database.Queries)(nil)
@@ -0,0 +1,81 @@
+package resilience
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/metrics"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+)
+
+var circuitStates = []utils.CircuitState{utils.CircuitClosed, utils.CircuitOpen, utils.CircuitHalfOpen}
+
+// NewBreaker returns a circuit breaker for dependency that logs its state
+// changes and exports its state as a metric.
+func NewBreaker(dependency string, threshold int, openTimeout time.Duration) *utils.CircuitBreaker {
+	breaker := utils.NewCircuitBreaker(dependency, threshold, openTimeout)
+	breaker.OnStateChange = func(name string, from utils.CircuitState, to utils.CircuitState) {
+		slog.Warn("circuit breaker state changed", "dependency", name, "from", from, "to", to)
+		setStateMetric(name, to)
+	}
+
+	setStateMetric(dependency, utils.CircuitClosed)
+
+	return breaker
+}
+
+func setStateMetric(dependency string, current utils.CircuitState) {
+	for _, state := range circuitStates {
+		value := 0.0
+		if state == current {
+			value = 1
+		}
+		metrics.CircuitBreakerState.WithLabelValues(dependency, string(state)).Set(value)
+	}
+}
+
+// IsUnavailable reports whether a database error means Postgres could not
+// be reached or could not serve the query, as opposed to the query itself
+// being rejected.
+func IsUnavailable(err error) bool {
+	if err == nil || errors.Is(err, sql.ErrNoRows) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if errors.Is(err, utils.ErrCircuitOpen) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		if pqErr.Code == "57014" { // query_canceled
+			return false
+		}
+
+		switch pqErr.Code.Class() {
+		case "08", "53", "57": // connection exception, insufficient resources, operator intervention
+			return true
+		}
+		return false
+	}
+
+	// Anything else comes from the driver or the network, e.g. a refused
+	// connection or a timeout.
+	return true
+}
+
+// isRedisUnavailable mirrors IsUnavailable for Redis. Missing keys and
+// errors replied by the server do not count.
+func isRedisUnavailable(err error) bool {
+	if err == nil || errors.Is(err, redis.Nil) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var redisErr redis.Error
+	return !errors.As(err, &redisErr)
+}
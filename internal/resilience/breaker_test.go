@@ -0,0 +1,199 @@
+package resilience
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestIsUnavailable(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"no rows", sql.ErrNoRows, false},
+		{"wrapped no rows", fmt.Errorf("get link: %w", sql.ErrNoRows), false},
+		{"canceled", context.Canceled, false},
+		{"circuit open", utils.ErrCircuitOpen, true},
+		{"query canceled", &pq.Error{Code: "57014"}, false},
+		{"connection failure", &pq.Error{Code: "08006"}, true},
+		{"too many connections", &pq.Error{Code: "53300"}, true},
+		{"admin shutdown", &pq.Error{Code: "57P01"}, true},
+		{"unique violation", &pq.Error{Code: "23505"}, false},
+		{"syntax error", &pq.Error{Code: "42601"}, false},
+		{"refused connection", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, true},
+		{"deadline", context.DeadlineExceeded, true},
+	} {
+		if got := IsUnavailable(tc.err); got != tc.want {
+			t.Errorf("IsUnavailable(%s) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+// replyError is an error replied by the Redis server.
+type replyError string
+
+func (e replyError) Error() string { return string(e) }
+func (replyError) RedisError()     {}
+
+func TestIsRedisUnavailable(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"missing key", redis.Nil, false},
+		{"canceled", context.Canceled, false},
+		{"reply error", replyError("WRONGTYPE Operation against a key holding the wrong kind of value"), false},
+		{"refused connection", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, true},
+		{"deadline", context.DeadlineExceeded, true},
+	} {
+		if got := isRedisUnavailable(tc.err); got != tc.want {
+			t.Errorf("isRedisUnavailable(%s) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestProtectRedis(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	defer rdb.Close()
+
+	breaker := utils.NewCircuitBreaker("redis", 2, time.Hour)
+	ProtectRedis(rdb, breaker)
+
+	// Replies from a healthy server never open the circuit.
+	for range 3 {
+		if err := rdb.Get(ctx, "missing").Err(); !errors.Is(err, redis.Nil) {
+			t.Fatalf("GET missing: %v", err)
+		}
+	}
+	mr.Set("list", "x")
+	for range 3 {
+		if err := rdb.LPush(ctx, "list", "y").Err(); err == nil {
+			t.Fatal("LPUSH on a string succeeded")
+		}
+	}
+	if breaker.State() != utils.CircuitClosed {
+		t.Fatalf("state = %s after server replies, want closed", breaker.State())
+	}
+
+	mr.Close()
+	for range 2 {
+		if err := rdb.Ping(ctx).Err(); err == nil || errors.Is(err, utils.ErrCircuitOpen) {
+			t.Fatalf("PING to a stopped server: %v", err)
+		}
+	}
+	if breaker.State() != utils.CircuitOpen {
+		t.Fatalf("state = %s after the server went away, want open", breaker.State())
+	}
+	if err := rdb.Ping(ctx).Err(); !errors.Is(err, utils.ErrCircuitOpen) {
+		t.Fatalf("PING while open: %v, want %v", err, utils.ErrCircuitOpen)
+	}
+
+	_, err := rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, "counter")
+		return nil
+	})
+	if !errors.Is(err, utils.ErrCircuitOpen) {
+		t.Fatalf("pipeline while open: %v, want %v", err, utils.ErrCircuitOpen)
+	}
+}
+
+// fakeDB fails every call with err and counts how often it was reached.
+type fakeDB struct {
+	err   error
+	calls int
+}
+
+func (f *fakeDB) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	f.calls++
+	return nil, f.err
+}
+
+func (f *fakeDB) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	f.calls++
+	return nil, f.err
+}
+
+func (f *fakeDB) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	f.calls++
+	return nil, f.err
+}
+
+func (f *fakeDB) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	f.calls++
+	return nil
+}
+
+func TestWrapDBTX(t *testing.T) {
+	ctx := context.Background()
+	db := &fakeDB{err: &pq.Error{Code: "23505"}}
+	breaker := utils.NewCircuitBreaker("postgres", 2, time.Hour)
+	wrapped := WrapDBTX(db, breaker)
+
+	// Rejected queries say nothing about the health of the database.
+	for range 3 {
+		if _, err := wrapped.ExecContext(ctx, "INSERT"); !errors.Is(err, db.err) {
+			t.Fatalf("ExecContext: %v", err)
+		}
+	}
+	if breaker.State() != utils.CircuitClosed {
+		t.Fatalf("state = %s after constraint violations, want closed", breaker.State())
+	}
+
+	db.err = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	if _, err := wrapped.QueryContext(ctx, "SELECT"); !errors.Is(err, db.err) {
+		t.Fatalf("QueryContext: %v", err)
+	}
+	if _, err := wrapped.PrepareContext(ctx, "SELECT"); !errors.Is(err, db.err) {
+		t.Fatalf("PrepareContext: %v", err)
+	}
+	if breaker.State() != utils.CircuitOpen {
+		t.Fatalf("state = %s after connection failures, want open", breaker.State())
+	}
+
+	calls := db.calls
+	if _, err := wrapped.ExecContext(ctx, "INSERT"); !errors.Is(err, utils.ErrCircuitOpen) {
+		t.Fatalf("ExecContext while open: %v, want %v", err, utils.ErrCircuitOpen)
+	}
+	if _, err := wrapped.QueryContext(ctx, "SELECT"); !errors.Is(err, utils.ErrCircuitOpen) {
+		t.Fatalf("QueryContext while open: %v, want %v", err, utils.ErrCircuitOpen)
+	}
+	if db.calls != calls {
+		t.Fatal("the database was reached while the circuit was open")
+	}
+}
+
+func TestWrapDBTXQueryRowWhileOpen(t *testing.T) {
+	// Nothing listens on port 1, and the wrapper must not try to connect.
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1 connect_timeout=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	breaker := utils.NewCircuitBreaker("postgres", 1, time.Hour)
+	breaker.Allow()
+	breaker.Record(true)
+
+	var value int
+	err = WrapDBTX(db, breaker).QueryRowContext(context.Background(), "SELECT 1").Scan(&value)
+	if !errors.Is(err, utils.ErrCircuitOpen) {
+		t.Fatalf("Scan while open: %v, want %v", err, utils.ErrCircuitOpen)
+	}
+}
@@ -0,0 +1,79 @@
+package resilience
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/utils"
+)
+
+type protectedDB struct {
+	db      database.DBTX
+	breaker *utils.CircuitBreaker
+}
+
+// WrapDBTX guards every query with breaker. While the circuit is open
+// queries fail immediately with utils.ErrCircuitOpen.
+func WrapDBTX(db database.DBTX, breaker *utils.CircuitBreaker) database.DBTX {
+	return &protectedDB{db: db, breaker: breaker}
+}
+
+func (p *protectedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if err := p.breaker.Allow(); err != nil {
+		return nil, err
+	}
+
+	result, err := p.db.ExecContext(ctx, query, args...)
+	p.breaker.Record(IsUnavailable(err))
+	return result, err
+}
+
+func (p *protectedDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	if err := p.breaker.Allow(); err != nil {
+		return nil, err
+	}
+
+	stmt, err := p.db.PrepareContext(ctx, query)
+	p.breaker.Record(IsUnavailable(err))
+	return stmt, err
+}
+
+func (p *protectedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if err := p.breaker.Allow(); err != nil {
+		return nil, err
+	}
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	p.breaker.Record(IsUnavailable(err))
+	return rows, err
+}
+
+func (p *protectedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if err := p.breaker.Allow(); err != nil {
+		// sql.Row cannot be built with an error from outside database/sql.
+		// A context that is already done makes database/sql return its
+		// error without touching a connection, which carries the breaker
+		// error through to Scan.
+		return p.db.QueryRowContext(openCircuitContext{Context: ctx}, query, args...)
+	}
+
+	row := p.db.QueryRowContext(ctx, query, args...)
+	p.breaker.Record(IsUnavailable(row.Err()))
+	return row
+}
+
+var closedChannel = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+type openCircuitContext struct {
+	context.Context
+}
+
+func (openCircuitContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (openCircuitContext) Done() <-chan struct{}       { return closedChannel }
+func (openCircuitContext) Err() error                  { return utils.ErrCircuitOpen }
@@ -0,0 +1,50 @@
+package resilience
+
+import (
+	"context"
+
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+type redisBreakerHook struct {
+	breaker *utils.CircuitBreaker
+}
+
+// ProtectRedis guards every command sent through rdb with breaker, so
+// features depending on Redis fail fast while it is down.
+func ProtectRedis(rdb *redis.Client, breaker *utils.CircuitBreaker) {
+	rdb.AddHook(redisBreakerHook{breaker: breaker})
+}
+
+func (h redisBreakerHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h redisBreakerHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := h.breaker.Allow(); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+
+		err := next(ctx, cmd)
+		h.breaker.Record(isRedisUnavailable(err))
+		return err
+	}
+}
+
+func (h redisBreakerHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := h.breaker.Allow(); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+
+		err := next(ctx, cmds)
+		h.breaker.Record(isRedisUnavailable(err))
+		return err
+	}
+}
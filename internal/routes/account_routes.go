@@ -0,0 +1,257 @@
+package routes
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/models/requests"
+	"github.com/andriawan24/link-short/internal/models/responses"
+	"github.com/andriawan24/link-short/internal/services"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type accountRoutes struct {
+	userService         services.UserService
+	accountService      services.AccountService
+	twoFactorService    services.TwoFactorService
+	dataExportService   services.DataExportService
+	loginAttemptService services.LoginAttemptService
+	auditService        services.AuditService
+}
+
+func NewAccountRoutes(userService services.UserService, accountService services.AccountService, twoFactorService services.TwoFactorService, dataExportService services.DataExportService, loginAttemptService services.LoginAttemptService, auditService services.AuditService) accountRoutes {
+	return accountRoutes{
+		userService:         userService,
+		accountService:      accountService,
+		twoFactorService:    twoFactorService,
+		dataExportService:   dataExportService,
+		loginAttemptService: loginAttemptService,
+		auditService:        auditService,
+	}
+}
+
+// ExportData godoc
+// @Summary      Export personal data
+// @Description  Get the latest export of the authenticated user's profile, links and click logs. A new export is queued (202) unless one is still being built or can still be downloaded; poll until status is ready, then fetch download_url. The ZIP holds profile.json, links.json, links.csv and click_logs.csv.
+// @Tags         Account
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  responses.BaseResponse{data=responses.DataExportResponse}
+// @Success      202  {object}  responses.BaseResponse{data=responses.DataExportResponse}
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /account/export [get]
+func (r *accountRoutes) ExportData(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+
+	export, created, err := r.dataExportService.Request(ctx.Request.Context(), userId)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	if created {
+		recordAudit(ctx, r.auditService, services.AuditEntry{
+			OwnerID:    userId,
+			ActorID:    uuid.NullUUID{UUID: userId, Valid: true},
+			Action:     utils.AuditActionDataExported,
+			TargetType: utils.AuditTargetUser,
+			TargetID:   uuid.NullUUID{UUID: userId, Valid: true},
+		})
+	}
+
+	status := http.StatusOK
+	if export.Status != services.DataExportStatusReady {
+		status = http.StatusAccepted
+	}
+
+	utils.ResponsdJson(ctx, status, "successfully get data export", responses.MapDataExportResponse(export))
+}
+
+// DownloadDataExport godoc
+// @Summary      Download a data export
+// @Description  Download a finished personal data export as a ZIP file
+// @Tags         Account
+// @Produce      application/zip
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Export ID"
+// @Success      200  {file}    file
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      409  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /account/export/{id}/download [get]
+func (r *accountRoutes) DownloadDataExport(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+
+	exportId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	archive, err := r.dataExportService.Download(ctx.Request.Context(), userId, exportId)
+	if err != nil {
+		if errors.Is(err, services.ErrDataExportNotReady) {
+			utils.RespondConflict(ctx, err.Error())
+			return
+		}
+
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="pendek-in-export-`+exportId.String()+`.zip"`)
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Data(http.StatusOK, "application/zip", archive)
+}
+
+// DeleteAccount godoc
+// @Summary      Delete account
+// @Description  Schedule the authenticated user's account for deletion. The account is disabled right away and an email with a restore link is sent; after the grace period its links, click logs, uploads and tokens are erased. Requires the password (when one is set) and, with two-factor authentication enabled, a current code; wrong ones count towards the sign-in throttling. Access tokens stop working right away, while the account's links keep redirecting until the account is purged so that a restore loses nothing.
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body requests.DeleteAccountParam true "Password and code"
+// @Success      202  {object}  responses.BaseResponse{data=responses.AccountDeletionResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      429  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /account [delete]
+func (r *accountRoutes) DeleteAccount(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+
+	var param requests.DeleteAccountParam
+
+	err := ctx.ShouldBindJSON(&param)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	user, err := r.userService.GetUserByID(ctx.Request.Context(), userId)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	err = r.loginAttemptService.CheckAllowed(ctx.Request.Context(), user.Email, ctx.ClientIP())
+	if err != nil {
+		handleLoginAttemptError(ctx, err)
+		return
+	}
+
+	if user.PasswordHash.Valid {
+		err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash.String), []byte(param.Password))
+		if err != nil {
+			r.recordFailedAttempt(ctx, user, services.LoginReasonInvalidPassword)
+			utils.RespondUnauthorized(ctx, "invalid password")
+			return
+		}
+	}
+
+	if user.TotpEnabled {
+		err = r.twoFactorService.Verify(ctx.Request.Context(), user, param.Code)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidTwoFactorCode) {
+				r.recordFailedAttempt(ctx, user, services.LoginReasonInvalidTwoFactor)
+			}
+			handleTwoFactorError(ctx, err)
+			return
+		}
+	}
+
+	purgeAfter, err := r.accountService.RequestDeletion(ctx.Request.Context(), user)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	recordAudit(ctx, r.auditService, services.AuditEntry{
+		OwnerID:    user.ID,
+		ActorID:    uuid.NullUUID{UUID: user.ID, Valid: true},
+		Action:     utils.AuditActionDeletionRequested,
+		TargetType: utils.AuditTargetUser,
+		TargetID:   uuid.NullUUID{UUID: user.ID, Valid: true},
+		After:      gin.H{"purge_after": purgeAfter},
+	})
+
+	utils.ResponsdJson(ctx, http.StatusAccepted, "account scheduled for deletion", responses.AccountDeletionResponse{
+		PurgeAfter: purgeAfter,
+	})
+}
+
+// RestoreAccount godoc
+// @Summary      Restore a deleted account
+// @Description  Cancel a pending account deletion using the token from the deletion email. Only possible until the grace period is over.
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Param        request body requests.RestoreAccountParam true "Restore token"
+// @Success      200  {object}  responses.BaseResponse{data=responses.UserResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /account/restore [post]
+func (r *accountRoutes) RestoreAccount(ctx *gin.Context) {
+	var param requests.RestoreAccountParam
+
+	err := ctx.ShouldBindJSON(&param)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	user, err := r.accountService.RestoreAccount(ctx.Request.Context(), param.Token)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidToken) {
+			utils.RespondBadRequest(ctx, err.Error())
+			return
+		}
+
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	recordAudit(ctx, r.auditService, services.AuditEntry{
+		OwnerID:    user.ID,
+		ActorID:    uuid.NullUUID{UUID: user.ID, Valid: true},
+		Action:     utils.AuditActionAccountRestored,
+		TargetType: utils.AuditTargetUser,
+		TargetID:   uuid.NullUUID{UUID: user.ID, Valid: true},
+	})
+
+	response := responses.UserResponse{
+		ID:               user.ID,
+		Name:             user.Name,
+		Email:            user.Email,
+		IsActive:         user.IsActive,
+		IsVerified:       user.IsVerified,
+		ProfileImageUrl:  user.ProfileImageUrl.String,
+		TwoFactorEnabled: user.TotpEnabled,
+		Role:             user.Role,
+	}
+
+	utils.RespondOK(ctx, "successfully restore account", response)
+}
+
+// recordFailedAttempt counts a wrong password or code given to confirm a
+// sensitive change like a failed sign-in, so guessing them is throttled too.
+func (r *accountRoutes) recordFailedAttempt(ctx *gin.Context, user database.User, reason string) {
+	err := r.loginAttemptService.RecordAttempt(ctx.Request.Context(), services.LoginAttempt{
+		UserID:    uuid.NullUUID{UUID: user.ID, Valid: true},
+		Email:     user.Email,
+		IPAddress: ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+		Reason:    reason,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "failed to record login attempt", "email", user.Email, "error", err)
+	}
+}
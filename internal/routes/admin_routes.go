@@ -0,0 +1,448 @@
+package routes
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/models/requests"
+	"github.com/andriawan24/link-short/internal/models/responses"
+	"github.com/andriawan24/link-short/internal/services"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type adminRoutes struct {
+	adminService       services.AdminService
+	clickLogService    services.ClickLogService
+	cacheService       services.CacheService
+	publicStatsService services.PublicStatsService
+	auditService       services.AuditService
+}
+
+func NewAdminRoutes(adminService services.AdminService, clickLogService services.ClickLogService, cacheService services.CacheService, publicStatsService services.PublicStatsService, auditService services.AuditService) adminRoutes {
+	return adminRoutes{
+		adminService:       adminService,
+		clickLogService:    clickLogService,
+		cacheService:       cacheService,
+		publicStatsService: publicStatsService,
+		auditService:       auditService,
+	}
+}
+
+// GetUsers godoc
+// @Summary      List users
+// @Description  List and search user accounts. Requires the admin role.
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        search  query     string  false  "Match against name or email"
+// @Param        status  query     string  false  "Account status"  Enums(active, suspended)
+// @Param        page    query     int     false  "Page number"     default(1)
+// @Param        limit   query     int     false  "Items per page"  default(20)
+// @Success      200  {object}  responses.BaseResponse{data=[]responses.AdminUserResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      403  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /admin/users [get]
+func (r *adminRoutes) GetUsers(ctx *gin.Context) {
+	limit, offset, err := parsePagination(ctx)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	var isActive sql.NullBool
+	switch ctx.Query("status") {
+	case "":
+	case "active":
+		isActive = sql.NullBool{Bool: true, Valid: true}
+	case "suspended":
+		isActive = sql.NullBool{Bool: false, Valid: true}
+	default:
+		utils.RespondBadRequest(ctx, "invalid status value: "+ctx.Query("status")+". Valid values are: active, suspended")
+		return
+	}
+
+	users, err := r.adminService.GetUsers(ctx.Request.Context(), ctx.Query("search"), isActive, limit, offset)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	utils.RespondOK(ctx, "successfully get users", responses.MapAdminUserResponses(users))
+}
+
+// SuspendUser godoc
+// @Summary      Suspend a user
+// @Description  Deactivate an account so it can no longer sign in or use the API; tokens issued before are rejected right away. Requires the admin role.
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  responses.BaseResponse{data=responses.AdminUserResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      403  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /admin/users/{id}/suspend [post]
+func (r *adminRoutes) SuspendUser(ctx *gin.Context) {
+	r.setUserActive(ctx, false)
+}
+
+// ReactivateUser godoc
+// @Summary      Reactivate a user
+// @Description  Reactivate a suspended account. Requires the admin role.
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  responses.BaseResponse{data=responses.AdminUserResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      403  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /admin/users/{id}/reactivate [post]
+func (r *adminRoutes) ReactivateUser(ctx *gin.Context) {
+	r.setUserActive(ctx, true)
+}
+
+// UpdateUserRole godoc
+// @Summary      Change a user's role
+// @Description  Grant or revoke the admin role. Requires the admin role.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                        true  "User ID"
+// @Param        request  body      requests.UpdateUserRoleParam  true  "New role"
+// @Success      200  {object}  responses.BaseResponse{data=responses.AdminUserResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      403  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /admin/users/{id}/role [put]
+func (r *adminRoutes) UpdateUserRole(ctx *gin.Context) {
+	adminId := ctx.MustGet("user_id").(uuid.UUID)
+
+	userId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	var body requests.UpdateUserRoleParam
+
+	err = ctx.ShouldBindJSON(&body)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	if userId == adminId {
+		utils.RespondBadRequest(ctx, "you cannot change your own role")
+		return
+	}
+
+	user, err := r.adminService.SetUserRole(ctx.Request.Context(), userId, body.Role)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	recordAudit(ctx, r.auditService, services.AuditEntry{
+		OwnerID:    user.ID,
+		ActorID:    uuid.NullUUID{UUID: adminId, Valid: true},
+		Action:     utils.AuditActionUserRoleChanged,
+		TargetType: utils.AuditTargetUser,
+		TargetID:   uuid.NullUUID{UUID: user.ID, Valid: true},
+		After:      gin.H{"role": user.Role},
+	})
+
+	utils.RespondOK(ctx, "successfully update user role", responses.MapAdminUserResponse(user))
+}
+
+// GetLinks godoc
+// @Summary      List links
+// @Description  List and search links of every user. Requires the admin role.
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        search      query     string  false  "Exact short code or part of the destination URL"
+// @Param        taken_down  query     bool    false  "Only return taken down links"
+// @Param        page        query     int     false  "Page number"     default(1)
+// @Param        limit       query     int     false  "Items per page"  default(20)
+// @Success      200  {object}  responses.BaseResponse{data=[]responses.AdminLinkResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      403  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /admin/links [get]
+func (r *adminRoutes) GetLinks(ctx *gin.Context) {
+	limit, offset, err := parsePagination(ctx)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	takenDownOnly := false
+	if ctx.Query("taken_down") != "" {
+		takenDownOnly, err = strconv.ParseBool(ctx.Query("taken_down"))
+		if err != nil {
+			utils.HandleErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	links, err := r.adminService.GetLinks(ctx.Request.Context(), ctx.Query("search"), takenDownOnly, limit, offset)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	utils.RespondOK(ctx, "successfully get links", responses.MapAdminLinkResponses(links))
+}
+
+// GetLink godoc
+// @Summary      Get any link with analytics
+// @Description  Get a link of any user together with its device and country breakdown. Requires the admin role.
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id     path      string  true   "Link ID"
+// @Param        range  query     string  false  "Time range"  Enums(7d, 30d, 90d, all)  default(all)
+// @Success      200  {object}  responses.BaseResponse{data=responses.LinkResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      403  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /admin/links/{id} [get]
+func (r *adminRoutes) GetLink(ctx *gin.Context) {
+	linkId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	link, err := r.adminService.GetLink(ctx.Request.Context(), linkId)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	to := time.Now()
+	from := utils.ParseTimeRange(ctx.DefaultQuery("range", "all")).GetFromDate()
+
+	deviceBreakdown, err := r.clickLogService.GetDeviceBreakdownSingleLink(ctx, link.UserID, link.ID, from, to)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	countryBreakdown, err := r.clickLogService.GetTopCountriesSingleLink(ctx, link.UserID, link.ID, from, to)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	devices := responses.MapDeviceBreakdownSingle(deviceBreakdown)
+	countries := responses.MapTopCountriesSingle(countryBreakdown)
+
+	utils.RespondOK(ctx, "successfully get link", responses.MapLinkResponse(database.GetLinkRow(link), link.Counts, devices, countries, nil))
+}
+
+// TakeDownLink godoc
+// @Summary      Take down a link
+// @Description  Stop a link from redirecting. Visitors get a 410 response with the given reason. Requires the admin role.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                      true  "Link ID"
+// @Param        request  body      requests.TakeDownLinkParam  true  "Takedown reason"
+// @Success      200  {object}  responses.BaseResponse{data=responses.LinkResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      403  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /admin/links/{id}/takedown [post]
+func (r *adminRoutes) TakeDownLink(ctx *gin.Context) {
+	adminId := ctx.MustGet("user_id").(uuid.UUID)
+
+	linkId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	var body requests.TakeDownLinkParam
+
+	err = ctx.ShouldBindJSON(&body)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	link, err := r.adminService.TakeDownLink(ctx.Request.Context(), linkId, adminId, body.Reason)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	invalidateLinkCodes(ctx.Request.Context(), r.cacheService, link.ShortCode, link.CustomShortCode)
+	r.publicStatsService.Invalidate(ctx.Request.Context(), link.StatsShareToken.String)
+
+	recordAudit(ctx, r.auditService, services.AuditEntry{
+		OwnerID:    link.UserID,
+		ActorID:    uuid.NullUUID{UUID: adminId, Valid: true},
+		Action:     utils.AuditActionLinkTakenDown,
+		TargetType: utils.AuditTargetLink,
+		TargetID:   uuid.NullUUID{UUID: link.ID, Valid: true},
+		After:      gin.H{"takedown_reason": link.TakedownReason.String},
+	})
+
+	utils.RespondOK(ctx, "successfully take down link", responses.MapLinkDetailResponse(link))
+}
+
+// RestoreLink godoc
+// @Summary      Restore a taken down link
+// @Description  Lift a takedown so the link redirects again. Requires the admin role.
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Link ID"
+// @Success      200  {object}  responses.BaseResponse{data=responses.LinkResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      403  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /admin/links/{id}/restore [post]
+func (r *adminRoutes) RestoreLink(ctx *gin.Context) {
+	linkId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	link, err := r.adminService.RestoreLink(ctx.Request.Context(), linkId)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	recordAudit(ctx, r.auditService, services.AuditEntry{
+		OwnerID:    link.UserID,
+		ActorID:    uuid.NullUUID{UUID: ctx.MustGet("user_id").(uuid.UUID), Valid: true},
+		Action:     utils.AuditActionLinkRestored,
+		TargetType: utils.AuditTargetLink,
+		TargetID:   uuid.NullUUID{UUID: link.ID, Valid: true},
+	})
+
+	utils.RespondOK(ctx, "successfully restore link", responses.MapLinkDetailResponse(link))
+}
+
+// GetGrowth godoc
+// @Summary      Get platform growth
+// @Description  Get new users, new links and clicks per day across the whole platform. "all" is capped at the last year. Requires the admin role.
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        range  query     string  false  "Time range"  Enums(7d, 30d, 90d, all)  default(30d)
+// @Success      200  {object}  responses.BaseResponse{data=responses.PlatformGrowthResponse}
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      403  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /admin/growth [get]
+func (r *adminRoutes) GetGrowth(ctx *gin.Context) {
+	timeRange := utils.ParseTimeRange(ctx.DefaultQuery("range", "30d"))
+
+	to := time.Now()
+	from := timeRange.GetFromDate()
+
+	rows, err := r.adminService.GetPlatformGrowth(ctx.Request.Context(), from, to)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	if len(rows) > 0 {
+		from = rows[0].Day
+	}
+
+	utils.RespondOK(ctx, "successfully get platform growth", responses.MapPlatformGrowthResponse(string(timeRange), from, to, rows))
+}
+
+func (r *adminRoutes) setUserActive(ctx *gin.Context, active bool) {
+	adminId := ctx.MustGet("user_id").(uuid.UUID)
+
+	userId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	if userId == adminId {
+		utils.RespondBadRequest(ctx, "you cannot change the status of your own account")
+		return
+	}
+
+	user, err := r.adminService.SetUserActive(ctx.Request.Context(), userId, active)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	message, action := "successfully reactivate user", utils.AuditActionUserReactivated
+	if !active {
+		message, action = "successfully suspend user", utils.AuditActionUserSuspended
+	}
+
+	recordAudit(ctx, r.auditService, services.AuditEntry{
+		OwnerID:    user.ID,
+		ActorID:    uuid.NullUUID{UUID: adminId, Valid: true},
+		Action:     action,
+		TargetType: utils.AuditTargetUser,
+		TargetID:   uuid.NullUUID{UUID: user.ID, Valid: true},
+		After:      gin.H{"is_active": user.IsActive},
+	})
+
+	utils.RespondOK(ctx, message, responses.MapAdminUserResponse(user))
+}
+
+// parsePagination reads the page and limit query parameters, defaulting to
+// the first 20 items and allowing at most 100 per page.
+func parsePagination(ctx *gin.Context) (int32, int32, error) {
+	var (
+		page  = 1
+		limit = 20
+		err   error
+	)
+
+	if ctx.Query("page") != "" {
+		page, err = strconv.Atoi(ctx.Query("page"))
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if ctx.Query("limit") != "" {
+		limit, err = strconv.Atoi(ctx.Query("limit"))
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	page = max(page, 1)
+	limit = min(max(limit, 1), 100)
+
+	return int32(limit), int32((page - 1) * limit), nil
+}
@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"io"
+	"net/http"
 	"time"
 
 	"github.com/andriawan24/link-short/internal/models/responses"
@@ -10,15 +12,19 @@ import (
 	"github.com/google/uuid"
 )
 
+const streamHeartbeatInterval = 15 * time.Second
+
 type analyticRoutes struct {
-	linkService     services.LinkService
-	clickLogService services.ClickLogService
+	linkService        services.LinkService
+	clickLogService    services.ClickLogService
+	clickStreamService services.ClickStreamService
 }
 
-func NewAnalyticRoutes(linkService services.LinkService, clickLogService services.ClickLogService) analyticRoutes {
+func NewAnalyticRoutes(linkService services.LinkService, clickLogService services.ClickLogService, clickStreamService services.ClickStreamService) analyticRoutes {
 	return analyticRoutes{
-		linkService:     linkService,
-		clickLogService: clickLogService,
+		linkService:        linkService,
+		clickLogService:    clickLogService,
+		clickStreamService: clickStreamService,
 	}
 }
 
@@ -51,7 +57,7 @@ func (r *analyticRoutes) GetDashboard(ctx *gin.Context) {
 		return
 	}
 
-	topLinks, err := r.linkService.GetLinks(ctx.Request.Context(), userId, 1, 0, utils.OrderByCounts)
+	topLinks, err := r.linkService.GetLinks(ctx.Request.Context(), userId, 1, 0, utils.OrderByCounts, "", "")
 	if err != nil {
 		utils.HandleErrorResponse(ctx, err)
 		return
@@ -63,7 +69,7 @@ func (r *analyticRoutes) GetDashboard(ctx *gin.Context) {
 		return
 	}
 
-	recents, err := r.linkService.GetLinks(ctx.Request.Context(), userId, 5, 0, utils.OrderByCreatedDate)
+	recents, err := r.linkService.GetLinks(ctx.Request.Context(), userId, 5, 0, utils.OrderByCreatedDate, "", "")
 	if err != nil {
 		utils.HandleErrorResponse(ctx, err)
 		return
@@ -127,7 +133,7 @@ func (r *analyticRoutes) GetAnalytics(ctx *gin.Context) {
 		return
 	}
 
-	topLinks, err := r.linkService.GetLinks(ctx.Request.Context(), userId, 1, 0, utils.OrderByCounts)
+	topLinks, err := r.linkService.GetLinks(ctx.Request.Context(), userId, 1, 0, utils.OrderByCounts, "", "")
 	if err != nil {
 		utils.HandleErrorResponse(ctx, err)
 		return
@@ -145,6 +151,18 @@ func (r *analyticRoutes) GetAnalytics(ctx *gin.Context) {
 		return
 	}
 
+	topRegions, err := r.clickLogService.GetTopRegions(ctx, userId, from, to)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	topCities, err := r.clickLogService.GetTopCities(ctx, userId, from, to)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
 	trafficSources, err := r.clickLogService.GetTrafficSources(ctx, userId, from, to)
 	if err != nil {
 		utils.HandleErrorResponse(ctx, err)
@@ -174,6 +192,8 @@ func (r *analyticRoutes) GetAnalytics(ctx *gin.Context) {
 		Overviews:        responses.MapAnalyticsResponse(overviews),
 		DeviceBreakdowns: responses.MapDeviceBreakdown(deviceBreakdown),
 		TopCountries:     responses.MapTopCountries(topCountries),
+		TopRegions:       responses.MapTopRegions(topRegions),
+		TopCities:        responses.MapTopCities(topCities),
 		TrafficSources:   responses.MapTrafficSources(trafficSources),
 		BrowserUsages:    responses.MapBrowserUsage(browserUsage),
 	}
@@ -188,3 +208,79 @@ func (r *analyticRoutes) GetAnalytics(ctx *gin.Context) {
 
 	utils.RespondOK(ctx, "successfully get analytics", response)
 }
+
+// StreamClicks godoc
+// @Summary      Stream click events
+// @Description  Stream click events for the authenticated user's links in real time using Server-Sent Events. A heartbeat event is sent periodically to keep the connection alive.
+// @Tags         Analytics
+// @Produce      text/event-stream
+// @Security     BearerAuth
+// @Param        link_id  query     []string  false  "Only stream clicks for these link IDs"  collectionFormat(multi)
+// @Success      200  {object}  responses.ClickEventResponse
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /analytics/stream [get]
+func (r *analyticRoutes) StreamClicks(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+	reqCtx := ctx.Request.Context()
+
+	linkFilter := make(map[uuid.UUID]bool)
+	for _, rawId := range ctx.QueryArray("link_id") {
+		linkId, err := uuid.Parse(rawId)
+		if err != nil {
+			utils.HandleErrorResponse(ctx, err)
+			return
+		}
+
+		link, err := r.linkService.GetLink(reqCtx, userId, linkId)
+		if err != nil {
+			utils.HandleErrorResponse(ctx, err)
+			return
+		}
+
+		linkFilter[link.ID] = true
+	}
+
+	events, err := r.clickStreamService.Subscribe(reqCtx, userId)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	// The stream is expected to outlive the server's write timeout.
+	_ = http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Time{})
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+
+	// Send the headers right away so clients see the stream open before the
+	// first event or heartbeat.
+	ctx.Status(http.StatusOK)
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-reqCtx.Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+
+			if len(linkFilter) == 0 || linkFilter[event.LinkID] {
+				ctx.SSEvent("click", event)
+			}
+			return true
+		case now := <-heartbeat.C:
+			ctx.SSEvent("heartbeat", gin.H{"time": now})
+			return true
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/andriawan24/link-short/internal/services"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type appLinkRoutes struct {
+	appLinkService services.AppLinkService
+}
+
+func NewAppLinkRoutes(appLinkService services.AppLinkService) appLinkRoutes {
+	return appLinkRoutes{
+		appLinkService: appLinkService,
+	}
+}
+
+// AppleAppSiteAssociation godoc
+// @Summary      Apple app site association
+// @Description  Lists the iOS apps that open short links as universal links. Served as a bare document, not wrapped in the usual response envelope.
+// @Tags         App Links
+// @Produce      json
+// @Success      200  {object}  responses.AppleAppSiteAssociation
+// @Failure      404  {object}  responses.ErrorResponse
+// @Router       /.well-known/apple-app-site-association [get]
+func (r *appLinkRoutes) AppleAppSiteAssociation(ctx *gin.Context) {
+	document, ok := r.appLinkService.AppleAppSiteAssociation()
+	if !ok {
+		utils.RespondNotFound(ctx, "no iOS apps are configured", nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, document)
+}
+
+// AssetLinks godoc
+// @Summary      Android asset links
+// @Description  Lists the Android apps that open short links as verified app links. Served as a bare document, not wrapped in the usual response envelope.
+// @Tags         App Links
+// @Produce      json
+// @Success      200  {array}   responses.AssetLinkStatement
+// @Failure      404  {object}  responses.ErrorResponse
+// @Router       /.well-known/assetlinks.json [get]
+func (r *appLinkRoutes) AssetLinks(ctx *gin.Context) {
+	statements, ok := r.appLinkService.AssetLinks()
+	if !ok {
+		utils.RespondNotFound(ctx, "no Android apps are configured", nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, statements)
+}
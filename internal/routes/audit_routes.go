@@ -0,0 +1,80 @@
+package routes
+
+import (
+	"log/slog"
+
+	"github.com/andriawan24/link-short/internal/models/responses"
+	"github.com/andriawan24/link-short/internal/services"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type auditRoutes struct {
+	auditService services.AuditService
+}
+
+func NewAuditRoutes(auditService services.AuditService) auditRoutes {
+	return auditRoutes{
+		auditService: auditService,
+	}
+}
+
+// GetEvents godoc
+// @Summary      Get audit log
+// @Description  Get changes made to the authenticated user's account and links, newest first
+// @Tags         Audit
+// @Produce      json
+// @Security     BearerAuth
+// @Param        target_type  query     string  false  "Target type"  Enums(link, user)
+// @Param        target_id    query     string  false  "Target ID"
+// @Param        action       query     string  false  "Action, e.g. link.updated"
+// @Param        page         query     int     false  "Page number"     default(1)
+// @Param        limit        query     int     false  "Items per page"  default(20)
+// @Success      200  {object}  responses.BaseResponse{data=[]responses.AuditEventResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /audit [get]
+func (r *auditRoutes) GetEvents(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+
+	limit, offset, err := parsePagination(ctx)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	filter := services.AuditFilter{
+		TargetType: ctx.Query("target_type"),
+		Action:     ctx.Query("action"),
+	}
+
+	if ctx.Query("target_id") != "" {
+		targetId, err := uuid.Parse(ctx.Query("target_id"))
+		if err != nil {
+			utils.HandleErrorResponse(ctx, err)
+			return
+		}
+		filter.TargetID = uuid.NullUUID{UUID: targetId, Valid: true}
+	}
+
+	events, err := r.auditService.GetEvents(ctx.Request.Context(), userId, filter, limit, offset)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	utils.RespondOK(ctx, "successfully get audit events", responses.MapAuditEventResponses(events))
+}
+
+// recordAudit stores entry with the caller's IP address and user agent.
+// Failures are logged rather than failing the request that made the change.
+func recordAudit(ctx *gin.Context, auditService services.AuditService, entry services.AuditEntry) {
+	entry.IPAddress = ctx.ClientIP()
+	entry.UserAgent = ctx.Request.UserAgent()
+
+	if err := auditService.Record(ctx.Request.Context(), entry); err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "failed to record audit event", "action", entry.Action, "error", err)
+	}
+}
@@ -1,12 +1,12 @@
 package routes
 
 import (
+	"context"
 	"database/sql"
 	"errors"
-	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
+	"log/slog"
+	"math"
+	"strconv"
 
 	"github.com/andriawan24/link-short/internal/database"
 	"github.com/andriawan24/link-short/internal/models/requests"
@@ -19,26 +19,40 @@ import (
 )
 
 type authRoutes struct {
-	userService  services.UserService
-	oauthService services.OAuthService
+	userService         services.UserService
+	tokenService        services.TokenService
+	oauthService        services.OAuthService
+	accountService      services.AccountService
+	twoFactorService    services.TwoFactorService
+	loginAttemptService services.LoginAttemptService
+	auditService        services.AuditService
+	profileImageService services.ProfileImageService
 }
 
-func NewAuthRoutes(userService services.UserService, oauthService services.OAuthService) authRoutes {
+func NewAuthRoutes(userService services.UserService, tokenService services.TokenService, oauthService services.OAuthService, accountService services.AccountService, twoFactorService services.TwoFactorService, loginAttemptService services.LoginAttemptService, auditService services.AuditService, profileImageService services.ProfileImageService) authRoutes {
 	return authRoutes{
-		userService:  userService,
-		oauthService: oauthService,
+		userService:         userService,
+		tokenService:        tokenService,
+		oauthService:        oauthService,
+		accountService:      accountService,
+		twoFactorService:    twoFactorService,
+		loginAttemptService: loginAttemptService,
+		auditService:        auditService,
+		profileImageService: profileImageService,
 	}
 }
 
 // Login godoc
 // @Summary      User login
-// @Description  Authenticate user with email and password
+// @Description  Authenticate user with email and password. When two-factor authentication is enabled, a challenge token is returned instead of access tokens and must be exchanged at /auth/2fa/verify.
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
 // @Param        request body requests.LoginParam true "Login credentials"
 // @Success      200  {object}  responses.BaseResponse{data=responses.LoginResponse}
 // @Failure      401  {object}  responses.ErrorResponse
+// @Failure      403  {object}  responses.ErrorResponse
+// @Failure      429  {object}  responses.ErrorResponse
 // @Failure      500  {object}  responses.ErrorResponse
 // @Router       /auth/login [post]
 func (r *authRoutes) Login(ctx *gin.Context) {
@@ -50,9 +64,16 @@ func (r *authRoutes) Login(ctx *gin.Context) {
 		return
 	}
 
+	err = r.loginAttemptService.CheckAllowed(ctx.Request.Context(), param.Email, ctx.ClientIP())
+	if err != nil {
+		handleLoginAttemptError(ctx, err)
+		return
+	}
+
 	user, err := r.userService.FindUserByEmail(ctx.Request.Context(), param.Email)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
+			r.recordLoginAttempt(ctx, uuid.NullUUID{}, param.Email, false, services.LoginReasonUnknownEmail)
 			utils.RespondUnauthorized(ctx, "invalid email or password")
 			return
 		}
@@ -61,19 +82,35 @@ func (r *authRoutes) Login(ctx *gin.Context) {
 		return
 	}
 
+	userId := uuid.NullUUID{UUID: user.ID, Valid: true}
+
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash.String), []byte(param.Password))
 	if err != nil {
+		r.recordLoginAttempt(ctx, userId, param.Email, false, services.LoginReasonInvalidPassword)
 		utils.RespondUnauthorized(ctx, "invalid email or password, mismatch")
 		return
 	}
 
-	accessToken, accessClaim, err := utils.GenerateJwtToken(user)
+	if !user.IsActive {
+		r.recordLoginAttempt(ctx, userId, param.Email, false, services.LoginReasonAccountInactive)
+		utils.RespondForbidden(ctx, "account is suspended")
+		return
+	}
+
+	if user.TotpEnabled {
+		r.respondTwoFactorChallenge(ctx, user)
+		return
+	}
+
+	r.recordLoginAttempt(ctx, userId, param.Email, true, services.LoginReasonPassword)
+
+	accessToken, accessClaim, err := r.tokenService.GenerateAccessToken(user)
 	if err != nil {
 		utils.HandleErrorResponse(ctx, err)
 		return
 	}
 
-	refreshToken, refreshClaim, err := utils.GenerateRefreshToken(user)
+	refreshToken, refreshClaim, err := r.tokenService.GenerateRefreshToken(user)
 	if err != nil {
 		utils.HandleErrorResponse(ctx, err)
 		return
@@ -85,12 +122,14 @@ func (r *authRoutes) Login(ctx *gin.Context) {
 		RefreshToken:          refreshToken,
 		RefreshTokenExpiredAt: refreshClaim.ExpiresAt.Time,
 		User: responses.UserResponse{
-			ID:              user.ID,
-			Name:            user.Name,
-			Email:           user.Email,
-			IsActive:        user.IsActive,
-			IsVerified:      user.IsVerified,
-			ProfileImageUrl: user.ProfileImageUrl.String,
+			ID:               user.ID,
+			Name:             user.Name,
+			Email:            user.Email,
+			IsActive:         user.IsActive,
+			IsVerified:       user.IsVerified,
+			ProfileImageUrl:  user.ProfileImageUrl.String,
+			TwoFactorEnabled: user.TotpEnabled,
+			Role:             user.Role,
 		},
 	}
 
@@ -117,26 +156,31 @@ func (r *authRoutes) Refresh(ctx *gin.Context) {
 		return
 	}
 
-	refreshClaims, err := utils.ParseRefreshToken(param.RefreshToken)
+	refreshClaims, err := r.tokenService.ParseRefreshToken(param.RefreshToken)
 	if err != nil {
 		utils.RespondUnauthorized(ctx, "invalid refresh token")
 		return
 	}
 
 	user, err := r.userService.GetUserByID(ctx.Request.Context(), refreshClaims.UserId)
-	if err != nil {
+	if err != nil || r.tokenService.Revoked(user, refreshClaims) {
 		// Don't leak whether a user exists.
 		utils.RespondUnauthorized(ctx, "invalid refresh token")
 		return
 	}
 
-	accessToken, accessClaim, err := utils.GenerateAccessToken(user)
+	if !user.IsActive {
+		utils.RespondForbidden(ctx, "account is suspended")
+		return
+	}
+
+	accessToken, accessClaim, err := r.tokenService.GenerateAccessToken(user)
 	if err != nil {
 		utils.HandleErrorResponse(ctx, err)
 		return
 	}
 
-	newRefreshToken, newRefreshClaim, err := utils.GenerateRefreshToken(user)
+	newRefreshToken, newRefreshClaim, err := r.tokenService.GenerateRefreshToken(user)
 	if err != nil {
 		utils.HandleErrorResponse(ctx, err)
 		return
@@ -193,13 +237,19 @@ func (r *authRoutes) Register(ctx *gin.Context) {
 		return
 	}
 
-	accessToken, accessClaim, err := utils.GenerateJwtToken(user)
+	r.recordUserAudit(ctx, user.ID, utils.AuditActionUserRegistered, nil, services.NewUserAuditSnapshot(user))
+
+	if err := r.accountService.SendEmailVerification(ctx.Request.Context(), user); err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "failed to send verification email", "user_id", user.ID, "error", err)
+	}
+
+	accessToken, accessClaim, err := r.tokenService.GenerateAccessToken(user)
 	if err != nil {
 		utils.HandleErrorResponse(ctx, err)
 		return
 	}
 
-	refreshToken, refreshClaim, err := utils.GenerateRefreshToken(user)
+	refreshToken, refreshClaim, err := r.tokenService.GenerateRefreshToken(user)
 	if err != nil {
 		utils.HandleErrorResponse(ctx, err)
 		return
@@ -211,12 +261,14 @@ func (r *authRoutes) Register(ctx *gin.Context) {
 		RefreshToken:          refreshToken,
 		RefreshTokenExpiredAt: refreshClaim.ExpiresAt.Time,
 		User: responses.UserResponse{
-			ID:              user.ID,
-			Name:            user.Name,
-			Email:           user.Email,
-			IsActive:        user.IsActive,
-			IsVerified:      user.IsVerified,
-			ProfileImageUrl: user.ProfileImageUrl.String,
+			ID:               user.ID,
+			Name:             user.Name,
+			Email:            user.Email,
+			IsActive:         user.IsActive,
+			IsVerified:       user.IsVerified,
+			ProfileImageUrl:  user.ProfileImageUrl.String,
+			TwoFactorEnabled: user.TotpEnabled,
+			Role:             user.Role,
 		},
 	}
 
@@ -244,12 +296,14 @@ func (r *authRoutes) Profile(ctx *gin.Context) {
 	}
 
 	response := responses.UserResponse{
-		ID:              user.ID,
-		Name:            user.Name,
-		Email:           user.Email,
-		IsActive:        user.IsActive,
-		IsVerified:      user.IsVerified,
-		ProfileImageUrl: user.ProfileImageUrl.String,
+		ID:               user.ID,
+		Name:             user.Name,
+		Email:            user.Email,
+		IsActive:         user.IsActive,
+		IsVerified:       user.IsVerified,
+		ProfileImageUrl:  user.ProfileImageUrl.String,
+		TwoFactorEnabled: user.TotpEnabled,
+		Role:             user.Role,
 	}
 
 	utils.RespondOK(ctx, "successfully get profile", response)
@@ -265,7 +319,7 @@ func (r *authRoutes) Profile(ctx *gin.Context) {
 // @Param        name formData string false "User name"
 // @Param        email formData string false "User email"
 // @Param        password formData string false "User password"
-// @Param        profile_image formData file false "Profile image file (jpg, jpeg, png, gif)"
+// @Param        profile_image formData file false "Profile image (jpeg, png, gif or webp). It is cropped to a square and stored at 64, 128 and 256 pixels; profile_image_url points to the 256 pixel copy and the smaller ones sit next to it named 64 and 128."
 // @Success      200  {object}  responses.BaseResponse{data=responses.UserResponse}
 // @Failure      400  {object}  responses.ErrorResponse
 // @Failure      401  {object}  responses.ErrorResponse
@@ -278,6 +332,7 @@ func (r *authRoutes) UpdateProfile(ctx *gin.Context) {
 		utils.HandleErrorResponse(ctx, err)
 		return
 	}
+	before := services.NewUserAuditSnapshot(user)
 
 	name := ctx.PostForm("name")
 	email := ctx.PostForm("email")
@@ -296,41 +351,35 @@ func (r *authRoutes) UpdateProfile(ctx *gin.Context) {
 		user.Name = name
 	}
 
-	if email != "" && email != user.Email {
+	emailChanged := email != "" && email != user.Email
+	if emailChanged {
 		user.Email = email
 		user.IsVerified = false
 	}
 
+	previousImageUrl := user.ProfileImageUrl.String
+	newImageUrl := ""
+
 	file, err := ctx.FormFile("profile_image")
 	if err == nil && file != nil {
-		ext := strings.ToLower(filepath.Ext(file.Filename))
-		allowedExts := map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".gif": true}
-		if !allowedExts[ext] {
-			utils.RespondBadRequest(ctx, "invalid file type. Allowed: jpg, jpeg, png, gif")
+		upload, err := file.Open()
+		if err != nil {
+			utils.HandleErrorResponse(ctx, err)
 			return
 		}
+		defer upload.Close()
 
-		const maxFileSize = 5 << 20 // 5MB
-		if file.Size > maxFileSize {
-			utils.RespondBadRequest(ctx, "file size exceeds 5MB limit")
+		newImageUrl, err = r.profileImageService.Save(ctx.Request.Context(), userId, upload)
+		if errors.Is(err, services.ErrImageTooLarge) || errors.Is(err, services.ErrUnsupportedImage) || errors.Is(err, services.ErrImageDimensions) {
+			utils.RespondBadRequest(ctx, err.Error())
 			return
 		}
-
-		uploadDir := "uploads/profiles"
-		if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
-			utils.HandleErrorResponse(ctx, err)
-			return
-		}
-
-		filename := fmt.Sprintf("%s%s", uuid.New().String(), ext)
-		filePath := filepath.Join(uploadDir, filename)
-
-		if err := ctx.SaveUploadedFile(file, filePath); err != nil {
+		if err != nil {
 			utils.HandleErrorResponse(ctx, err)
 			return
 		}
 
-		user.ProfileImageUrl.String = "/" + filePath
+		user.ProfileImageUrl.String = newImageUrl
 		user.ProfileImageUrl.Valid = true
 	}
 
@@ -345,22 +394,193 @@ func (r *authRoutes) UpdateProfile(ctx *gin.Context) {
 
 	updatedUser, err := r.userService.UpdateUser(ctx.Request.Context(), updateUserParam)
 	if err != nil {
+		if newImageUrl != "" {
+			r.deleteProfileImage(ctx, newImageUrl)
+		}
 		utils.HandleErrorResponse(ctx, err)
 		return
 	}
 
+	if newImageUrl != "" && previousImageUrl != "" {
+		r.deleteProfileImage(ctx, previousImageUrl)
+	}
+
+	after := services.NewUserAuditSnapshot(updatedUser)
+	after.PasswordChanged = password != ""
+	r.recordUserAudit(ctx, updatedUser.ID, utils.AuditActionProfileUpdated, before, after)
+
+	if emailChanged {
+		if err := r.accountService.SendEmailVerification(ctx.Request.Context(), updatedUser); err != nil {
+			slog.ErrorContext(ctx.Request.Context(), "failed to send verification email", "user_id", updatedUser.ID, "error", err)
+		}
+	}
+
 	response := responses.UserResponse{
-		ID:              updatedUser.ID,
-		Name:            updatedUser.Name,
-		Email:           updatedUser.Email,
-		IsActive:        updatedUser.IsActive,
-		IsVerified:      updatedUser.IsVerified,
-		ProfileImageUrl: updatedUser.ProfileImageUrl.String,
+		ID:               updatedUser.ID,
+		Name:             updatedUser.Name,
+		Email:            updatedUser.Email,
+		IsActive:         updatedUser.IsActive,
+		IsVerified:       updatedUser.IsVerified,
+		ProfileImageUrl:  updatedUser.ProfileImageUrl.String,
+		TwoFactorEnabled: updatedUser.TotpEnabled,
+		Role:             updatedUser.Role,
 	}
 
 	utils.RespondOK(ctx, "successfully update profile", response)
 }
 
+// VerifyEmail godoc
+// @Summary      Verify email address
+// @Description  Confirm the email address of an account using the token sent by email
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body requests.VerifyEmailParam true "Verification token"
+// @Success      200  {object}  responses.BaseResponse{data=responses.UserResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /auth/verify-email [post]
+func (r *authRoutes) VerifyEmail(ctx *gin.Context) {
+	var param requests.VerifyEmailParam
+
+	err := ctx.ShouldBindJSON(&param)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	user, err := r.accountService.VerifyEmail(ctx.Request.Context(), param.Token)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidToken) {
+			utils.RespondBadRequest(ctx, err.Error())
+			return
+		}
+
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	r.recordUserAudit(ctx, user.ID, utils.AuditActionEmailVerified, gin.H{"is_verified": false}, gin.H{"is_verified": true})
+
+	response := responses.UserResponse{
+		ID:               user.ID,
+		Name:             user.Name,
+		Email:            user.Email,
+		IsActive:         user.IsActive,
+		IsVerified:       user.IsVerified,
+		ProfileImageUrl:  user.ProfileImageUrl.String,
+		TwoFactorEnabled: user.TotpEnabled,
+		Role:             user.Role,
+	}
+
+	utils.RespondOK(ctx, "successfully verify email", response)
+}
+
+// ResendVerification godoc
+// @Summary      Resend verification email
+// @Description  Send a new email verification link to the authenticated user
+// @Tags         Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  responses.BaseResponse
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /auth/resend-verification [post]
+func (r *authRoutes) ResendVerification(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+
+	user, err := r.userService.GetUserByID(ctx.Request.Context(), userId)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	if user.IsVerified {
+		utils.RespondBadRequest(ctx, "email address is already verified")
+		return
+	}
+
+	err = r.accountService.SendEmailVerification(ctx.Request.Context(), user)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	utils.RespondOK(ctx, "successfully send verification email", nil)
+}
+
+// ForgotPassword godoc
+// @Summary      Request password reset
+// @Description  Email a password reset link. Always succeeds so registered addresses cannot be discovered.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body requests.ForgotPasswordParam true "Account email"
+// @Success      200  {object}  responses.BaseResponse
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /auth/forgot-password [post]
+func (r *authRoutes) ForgotPassword(ctx *gin.Context) {
+	var param requests.ForgotPasswordParam
+
+	err := ctx.ShouldBindJSON(&param)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	err = r.accountService.SendPasswordReset(ctx.Request.Context(), param.Email)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	utils.RespondOK(ctx, "if the email is registered, a reset link has been sent", nil)
+}
+
+// ResetPassword godoc
+// @Summary      Reset password
+// @Description  Set a new password using the token sent by the forgot password email. Access and refresh tokens issued before the reset stop working, so every session has to sign in again.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body requests.ResetPasswordParam true "Reset token and new password"
+// @Success      200  {object}  responses.BaseResponse
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /auth/reset-password [post]
+func (r *authRoutes) ResetPassword(ctx *gin.Context) {
+	var param requests.ResetPasswordParam
+
+	err := ctx.ShouldBindJSON(&param)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(param.Password), bcrypt.DefaultCost)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	userId, err := r.accountService.ResetPassword(ctx.Request.Context(), param.Token, string(hashedPassword))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidToken) {
+			utils.RespondBadRequest(ctx, err.Error())
+			return
+		}
+
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	r.recordUserAudit(ctx, userId, utils.AuditActionPasswordReset, nil, gin.H{"password_changed": true})
+
+	utils.RespondOK(ctx, "successfully reset password", nil)
+}
+
 // GoogleAuth godoc
 // @Summary      Google OAuth authentication
 // @Description  Authenticate or register user via Google OAuth. Use without code param to get redirect URL, with code param to complete authentication.
@@ -419,13 +639,26 @@ func (r *authRoutes) GoogleAuth(ctx *gin.Context) {
 		}
 	}
 
-	accessToken, accessClaim, err := utils.GenerateJwtToken(user)
+	if !user.IsActive {
+		r.recordLoginAttempt(ctx, uuid.NullUUID{UUID: user.ID, Valid: true}, user.Email, false, services.LoginReasonAccountInactive)
+		utils.RespondForbidden(ctx, "account is suspended")
+		return
+	}
+
+	if user.TotpEnabled {
+		r.respondTwoFactorChallenge(ctx, user)
+		return
+	}
+
+	r.recordLoginAttempt(ctx, uuid.NullUUID{UUID: user.ID, Valid: true}, user.Email, true, services.LoginReasonGoogle)
+
+	accessToken, accessClaim, err := r.tokenService.GenerateAccessToken(user)
 	if err != nil {
 		utils.HandleErrorResponse(ctx, err)
 		return
 	}
 
-	refreshToken, refreshClaim, err := utils.GenerateRefreshToken(user)
+	refreshToken, refreshClaim, err := r.tokenService.GenerateRefreshToken(user)
 	if err != nil {
 		utils.HandleErrorResponse(ctx, err)
 		return
@@ -437,14 +670,367 @@ func (r *authRoutes) GoogleAuth(ctx *gin.Context) {
 		RefreshToken:          refreshToken,
 		RefreshTokenExpiredAt: refreshClaim.ExpiresAt.Time,
 		User: responses.UserResponse{
-			ID:              user.ID,
-			Name:            user.Name,
-			Email:           user.Email,
-			IsActive:        user.IsActive,
-			IsVerified:      user.IsVerified,
-			ProfileImageUrl: user.ProfileImageUrl.String,
+			ID:               user.ID,
+			Name:             user.Name,
+			Email:            user.Email,
+			IsActive:         user.IsActive,
+			IsVerified:       user.IsVerified,
+			ProfileImageUrl:  user.ProfileImageUrl.String,
+			TwoFactorEnabled: user.TotpEnabled,
+			Role:             user.Role,
 		},
 	}
 
 	utils.RespondOK(ctx, "successfully authenticated with Google", response)
 }
+
+// EnrollTwoFactor godoc
+// @Summary      Start two-factor enrollment
+// @Description  Generate a TOTP secret and provisioning URI for an authenticator app. Two-factor authentication is enabled once confirmed.
+// @Tags         Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  responses.BaseResponse{data=responses.TwoFactorEnrollResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /auth/2fa/enroll [post]
+func (r *authRoutes) EnrollTwoFactor(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+
+	user, err := r.userService.GetUserByID(ctx.Request.Context(), userId)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	secret, provisioningURI, err := r.twoFactorService.Enroll(ctx.Request.Context(), user)
+	if err != nil {
+		handleTwoFactorError(ctx, err)
+		return
+	}
+
+	response := responses.TwoFactorEnrollResponse{
+		Secret:          secret,
+		ProvisioningURI: provisioningURI,
+	}
+
+	utils.RespondOK(ctx, "successfully start two-factor enrollment", response)
+}
+
+// ConfirmTwoFactor godoc
+// @Summary      Confirm two-factor enrollment
+// @Description  Enable two-factor authentication with a code from the authenticator app. Recovery codes are only returned once.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body requests.TwoFactorCodeParam true "Authenticator code"
+// @Success      200  {object}  responses.BaseResponse{data=responses.TwoFactorConfirmResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /auth/2fa/confirm [post]
+func (r *authRoutes) ConfirmTwoFactor(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+
+	var param requests.TwoFactorCodeParam
+
+	err := ctx.ShouldBindJSON(&param)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	user, err := r.userService.GetUserByID(ctx.Request.Context(), userId)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	recoveryCodes, err := r.twoFactorService.Confirm(ctx.Request.Context(), user, param.Code)
+	if err != nil {
+		handleTwoFactorError(ctx, err)
+		return
+	}
+
+	r.recordUserAudit(ctx, user.ID, utils.AuditActionTwoFactorEnabled, gin.H{"two_factor_enabled": false}, gin.H{"two_factor_enabled": true})
+
+	response := responses.TwoFactorConfirmResponse{
+		RecoveryCodes: recoveryCodes,
+	}
+
+	utils.RespondOK(ctx, "successfully enable two-factor authentication", response)
+}
+
+// VerifyTwoFactor godoc
+// @Summary      Complete two-factor login
+// @Description  Exchange the login challenge token and an authenticator or recovery code for access and refresh tokens
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body requests.TwoFactorVerifyParam true "Challenge token and code"
+// @Success      200  {object}  responses.BaseResponse{data=responses.LoginResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      429  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /auth/2fa/verify [post]
+func (r *authRoutes) VerifyTwoFactor(ctx *gin.Context) {
+	var param requests.TwoFactorVerifyParam
+
+	err := ctx.ShouldBindJSON(&param)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	challengeClaims, err := r.tokenService.ParseTwoFactorChallengeToken(param.ChallengeToken)
+	if err != nil {
+		utils.RespondUnauthorized(ctx, "invalid challenge token")
+		return
+	}
+
+	user, err := r.userService.GetUserByID(ctx.Request.Context(), challengeClaims.UserId)
+	if err != nil || r.tokenService.Revoked(user, challengeClaims) {
+		utils.RespondUnauthorized(ctx, "invalid challenge token")
+		return
+	}
+
+	err = r.loginAttemptService.CheckAllowed(ctx.Request.Context(), user.Email, ctx.ClientIP())
+	if err != nil {
+		handleLoginAttemptError(ctx, err)
+		return
+	}
+
+	userId := uuid.NullUUID{UUID: user.ID, Valid: true}
+
+	err = r.twoFactorService.Verify(ctx.Request.Context(), user, param.Code)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTwoFactorCode) {
+			r.recordLoginAttempt(ctx, userId, user.Email, false, services.LoginReasonInvalidTwoFactor)
+		}
+
+		handleTwoFactorError(ctx, err)
+		return
+	}
+
+	r.recordLoginAttempt(ctx, userId, user.Email, true, services.LoginReasonTwoFactor)
+
+	accessToken, accessClaim, err := r.tokenService.GenerateAccessToken(user)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	refreshToken, refreshClaim, err := r.tokenService.GenerateRefreshToken(user)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	response := responses.LoginResponse{
+		Token:                 accessToken,
+		TokenExpiredAt:        accessClaim.ExpiresAt.Time,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiredAt: refreshClaim.ExpiresAt.Time,
+		User: responses.UserResponse{
+			ID:               user.ID,
+			Name:             user.Name,
+			Email:            user.Email,
+			IsActive:         user.IsActive,
+			IsVerified:       user.IsVerified,
+			ProfileImageUrl:  user.ProfileImageUrl.String,
+			TwoFactorEnabled: user.TotpEnabled,
+			Role:             user.Role,
+		},
+	}
+
+	utils.RespondOK(ctx, "successfully login", response)
+}
+
+// DisableTwoFactor godoc
+// @Summary      Disable two-factor authentication
+// @Description  Turn off two-factor authentication. Requires the account password (when one is set) and a current authenticator or recovery code. Wrong ones count towards the sign-in throttling.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body requests.TwoFactorDisableParam true "Password and code"
+// @Success      200  {object}  responses.BaseResponse
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      429  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /auth/2fa/disable [post]
+func (r *authRoutes) DisableTwoFactor(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+
+	var param requests.TwoFactorDisableParam
+
+	err := ctx.ShouldBindJSON(&param)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	user, err := r.userService.GetUserByID(ctx.Request.Context(), userId)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	err = r.loginAttemptService.CheckAllowed(ctx.Request.Context(), user.Email, ctx.ClientIP())
+	if err != nil {
+		handleLoginAttemptError(ctx, err)
+		return
+	}
+
+	attemptUserId := uuid.NullUUID{UUID: user.ID, Valid: true}
+
+	// Accounts created through Google have no password to re-enter, so the
+	// second factor alone is required for them.
+	if user.PasswordHash.Valid {
+		err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash.String), []byte(param.Password))
+		if err != nil {
+			r.recordLoginAttempt(ctx, attemptUserId, user.Email, false, services.LoginReasonInvalidPassword)
+			utils.RespondUnauthorized(ctx, "invalid password")
+			return
+		}
+	}
+
+	err = r.twoFactorService.Verify(ctx.Request.Context(), user, param.Code)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTwoFactorCode) {
+			r.recordLoginAttempt(ctx, attemptUserId, user.Email, false, services.LoginReasonInvalidTwoFactor)
+		}
+		handleTwoFactorError(ctx, err)
+		return
+	}
+
+	err = r.twoFactorService.Disable(ctx.Request.Context(), user)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	r.recordUserAudit(ctx, user.ID, utils.AuditActionTwoFactorDisabled, gin.H{"two_factor_enabled": true}, gin.H{"two_factor_enabled": false})
+
+	utils.RespondOK(ctx, "successfully disable two-factor authentication", nil)
+}
+
+// GetActivity godoc
+// @Summary      Get sign-in activity
+// @Description  Get recent successful and failed sign-in attempts on the authenticated user's account
+// @Tags         Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit  query     int  false  "Number of attempts"  default(20)  maximum(100)
+// @Success      200  {object}  responses.BaseResponse{data=[]responses.LoginAttemptResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /auth/activity [get]
+func (r *authRoutes) GetActivity(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+	var (
+		limit = 20
+		err   error
+	)
+
+	if ctx.Query("limit") != "" {
+		limit, err = strconv.Atoi(ctx.Query("limit"))
+		if err != nil {
+			utils.HandleErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	limit = min(max(limit, 1), 100)
+
+	attempts, err := r.loginAttemptService.GetRecentAttempts(ctx.Request.Context(), userId, int32(limit))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	utils.RespondOK(ctx, "successfully get sign-in activity", responses.MapLoginAttemptResponses(attempts))
+}
+
+func (r *authRoutes) recordLoginAttempt(ctx *gin.Context, userId uuid.NullUUID, email string, success bool, reason string) {
+	err := r.loginAttemptService.RecordAttempt(ctx.Request.Context(), services.LoginAttempt{
+		UserID:    userId,
+		Email:     email,
+		IPAddress: ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+		Success:   success,
+		Reason:    reason,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "failed to record login attempt", "email", email, "error", err)
+	}
+
+	if success && userId.Valid {
+		r.recordUserAudit(ctx, userId.UUID, utils.AuditActionLogin, nil, gin.H{"method": reason})
+	}
+}
+
+// recordUserAudit records a change the user made to their own account.
+func (r *authRoutes) recordUserAudit(ctx *gin.Context, userId uuid.UUID, action utils.AuditAction, before any, after any) {
+	recordAudit(ctx, r.auditService, services.AuditEntry{
+		OwnerID:    userId,
+		ActorID:    uuid.NullUUID{UUID: userId, Valid: true},
+		Action:     action,
+		TargetType: utils.AuditTargetUser,
+		TargetID:   uuid.NullUUID{UUID: userId, Valid: true},
+		Before:     before,
+		After:      after,
+	})
+}
+
+// deleteProfileImage removes an image that is no longer referenced. Failures
+// only leave an orphaned object behind, so they are logged and not returned.
+func (r *authRoutes) deleteProfileImage(ctx *gin.Context, imageUrl string) {
+	if err := r.profileImageService.Delete(context.WithoutCancel(ctx.Request.Context()), imageUrl); err != nil {
+		slog.WarnContext(ctx.Request.Context(), "failed to delete profile image", "url", imageUrl, "error", err)
+	}
+}
+
+func handleLoginAttemptError(ctx *gin.Context, err error) {
+	var throttled *services.LoginThrottledError
+	if errors.As(err, &throttled) {
+		ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		utils.RespondTooManyRequests(ctx, throttled.Error())
+		return
+	}
+
+	utils.HandleErrorResponse(ctx, err)
+}
+
+func (r *authRoutes) respondTwoFactorChallenge(ctx *gin.Context, user database.User) {
+	challengeToken, _, err := r.tokenService.GenerateTwoFactorChallengeToken(user)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	response := responses.LoginResponse{
+		TwoFactorRequired: true,
+		ChallengeToken:    challengeToken,
+	}
+
+	utils.RespondOK(ctx, "two-factor authentication required", response)
+}
+
+func handleTwoFactorError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidTwoFactorCode):
+		utils.RespondUnauthorized(ctx, err.Error())
+	case errors.Is(err, services.ErrTwoFactorAlreadyEnabled),
+		errors.Is(err, services.ErrTwoFactorNotEnrolled),
+		errors.Is(err, services.ErrTwoFactorNotEnabled):
+		utils.RespondBadRequest(ctx, err.Error())
+	default:
+		utils.HandleErrorResponse(ctx, err)
+	}
+}
@@ -0,0 +1,520 @@
+package routes
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/models/requests"
+	"github.com/andriawan24/link-short/internal/models/responses"
+	"github.com/andriawan24/link-short/internal/services"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	errInvalidCampaignDates = "starts_at must be before ends_at"
+
+	// campaignTimeRange is reported when the analytics cover the campaign
+	// dates rather than a requested range.
+	campaignTimeRange = "campaign"
+)
+
+type campaignRoutes struct {
+	campaignService services.CampaignService
+	linkService     services.LinkService
+}
+
+func NewCampaignRoutes(campaignService services.CampaignService, linkService services.LinkService) campaignRoutes {
+	return campaignRoutes{
+		campaignService: campaignService,
+		linkService:     linkService,
+	}
+}
+
+// InsertCampaign godoc
+// @Summary      Create a campaign
+// @Description  Create a campaign to group links. Once ends_at has passed, every link of the campaign expires.
+// @Tags         Campaigns
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body requests.InsertCampaignParam true "Campaign details"
+// @Success      201  {object}  responses.BaseResponse{data=responses.CampaignResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /campaigns [post]
+func (r *campaignRoutes) InsertCampaign(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+
+	var body requests.InsertCampaignParam
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	if !validCampaignDates(body.StartsAt, body.EndsAt) {
+		utils.RespondBadRequest(ctx, errInvalidCampaignDates)
+		return
+	}
+
+	campaign, err := r.campaignService.InsertCampaign(ctx.Request.Context(), database.InsertCampaignParams{
+		UserID:      userId,
+		Name:        body.Name,
+		Description: nullString(body.Description),
+		StartsAt:    nullTime(body.StartsAt),
+		EndsAt:      nullTime(body.EndsAt),
+	})
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	response := responses.MapCampaignResponse(database.GetCampaignRow{
+		ID:             campaign.ID,
+		UserID:         campaign.UserID,
+		Name:           campaign.Name,
+		Description:    campaign.Description,
+		StartsAt:       campaign.StartsAt,
+		EndsAt:         campaign.EndsAt,
+		LinksExpiredAt: campaign.LinksExpiredAt,
+		CreatedAt:      campaign.CreatedAt,
+		UpdatedAt:      campaign.UpdatedAt,
+	})
+
+	utils.ResponsdJson(ctx, http.StatusCreated, "successfully insert new campaign", response)
+}
+
+// GetCampaigns godoc
+// @Summary      Get all campaigns
+// @Description  Get the campaigns of the authenticated user, newest first
+// @Tags         Campaigns
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        page   query     int  false  "Page number"     default(1)
+// @Param        limit  query     int  false  "Items per page, at most 100"  default(10)
+// @Success      200  {object}  responses.BaseResponse{data=[]responses.CampaignResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /campaigns [get]
+func (r *campaignRoutes) GetCampaigns(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+	var (
+		page  = 1
+		limit = 10
+		err   error
+	)
+
+	if ctx.Query("page") != "" {
+		page, err = strconv.Atoi(ctx.Query("page"))
+		if err != nil {
+			utils.HandleErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	if ctx.Query("limit") != "" {
+		limit, err = strconv.Atoi(ctx.Query("limit"))
+		if err != nil {
+			utils.HandleErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	if page < 1 {
+		utils.RespondBadRequest(ctx, "page must be at least 1")
+		return
+	}
+	limit = min(max(limit, 1), 100)
+	offset := (page - 1) * limit
+
+	campaigns, err := r.campaignService.GetCampaigns(ctx.Request.Context(), userId, int32(limit), int32(offset))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	utils.RespondOK(ctx, "successfully get campaigns", responses.MapCampaignResponses(campaigns))
+}
+
+// GetCampaign godoc
+// @Summary      Get a campaign
+// @Description  Get a campaign of the authenticated user by its ID
+// @Tags         Campaigns
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Campaign ID (UUID)"
+// @Success      200  {object}  responses.BaseResponse{data=responses.CampaignResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /campaigns/{id} [get]
+func (r *campaignRoutes) GetCampaign(ctx *gin.Context) {
+	campaign, ok := r.campaign(ctx)
+	if !ok {
+		return
+	}
+
+	utils.RespondOK(ctx, "successfully get campaign", responses.MapCampaignResponse(campaign))
+}
+
+// UpdateCampaign godoc
+// @Summary      Update a campaign
+// @Description  Update the name, description and dates of a campaign. Moving ends_at expires the links again at the new date.
+// @Tags         Campaigns
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                        true  "Campaign ID (UUID)"
+// @Param        request  body      requests.UpdateCampaignParam  true  "Campaign details"
+// @Success      200  {object}  responses.BaseResponse{data=responses.CampaignResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /campaigns/{id} [put]
+func (r *campaignRoutes) UpdateCampaign(ctx *gin.Context) {
+	var body requests.UpdateCampaignParam
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	if !validCampaignDates(body.StartsAt, body.EndsAt) {
+		utils.RespondBadRequest(ctx, errInvalidCampaignDates)
+		return
+	}
+
+	existing, ok := r.campaign(ctx)
+	if !ok {
+		return
+	}
+
+	campaign, err := r.campaignService.UpdateCampaign(ctx.Request.Context(), database.UpdateCampaignParams{
+		ID:          existing.ID,
+		UserID:      existing.UserID,
+		Name:        body.Name,
+		Description: nullString(body.Description),
+		StartsAt:    nullTime(body.StartsAt),
+		EndsAt:      nullTime(body.EndsAt),
+	})
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	response := responses.MapCampaignResponse(database.GetCampaignRow{
+		ID:             campaign.ID,
+		UserID:         campaign.UserID,
+		Name:           campaign.Name,
+		Description:    campaign.Description,
+		StartsAt:       campaign.StartsAt,
+		EndsAt:         campaign.EndsAt,
+		LinksExpiredAt: campaign.LinksExpiredAt,
+		CreatedAt:      campaign.CreatedAt,
+		UpdatedAt:      campaign.UpdatedAt,
+		LinkCount:      existing.LinkCount,
+	})
+
+	utils.RespondOK(ctx, "successfully update campaign", response)
+}
+
+// DeleteCampaign godoc
+// @Summary      Delete a campaign
+// @Description  Delete a campaign. Its links are kept and no longer belong to a campaign.
+// @Tags         Campaigns
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Campaign ID (UUID)"
+// @Success      204  {string}  string  "No Content"
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /campaigns/{id} [delete]
+func (r *campaignRoutes) DeleteCampaign(ctx *gin.Context) {
+	campaign, ok := r.campaign(ctx)
+	if !ok {
+		return
+	}
+
+	err := r.campaignService.DeleteCampaign(ctx.Request.Context(), campaign.UserID, campaign.ID)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	utils.ResponsdJson(ctx, http.StatusNoContent, "successfully delete campaign", nil)
+}
+
+// AddLinks godoc
+// @Summary      Add links to a campaign
+// @Description  Move links into a campaign. A link belongs to at most one campaign, so links of another campaign are moved. Links added to a campaign that already ended expire straight away.
+// @Tags         Campaigns
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                       true  "Campaign ID (UUID)"
+// @Param        request  body      requests.CampaignLinksParam  true  "Link IDs"
+// @Success      200  {object}  responses.BaseResponse{data=responses.CampaignResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /campaigns/{id}/links [post]
+func (r *campaignRoutes) AddLinks(ctx *gin.Context) {
+	var body requests.CampaignLinksParam
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	campaign, ok := r.campaign(ctx)
+	if !ok {
+		return
+	}
+
+	for _, linkId := range body.LinkIDs {
+		if _, err := r.linkService.GetLink(ctx.Request.Context(), campaign.UserID, linkId); err != nil {
+			utils.HandleErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	err = r.campaignService.AddLinks(ctx.Request.Context(), campaign, body.LinkIDs)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	campaign, err = r.campaignService.GetCampaign(ctx.Request.Context(), campaign.UserID, campaign.ID)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	utils.RespondOK(ctx, "successfully add links to campaign", responses.MapCampaignResponse(campaign))
+}
+
+// RemoveLink godoc
+// @Summary      Remove a link from a campaign
+// @Description  Take a link out of a campaign. The link itself is kept.
+// @Tags         Campaigns
+// @Security     BearerAuth
+// @Param        id      path      string  true  "Campaign ID (UUID)"
+// @Param        linkId  path      string  true  "Link ID (UUID)"
+// @Success      204  {string}  string  "No Content"
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /campaigns/{id}/links/{linkId} [delete]
+func (r *campaignRoutes) RemoveLink(ctx *gin.Context) {
+	linkId, err := uuid.Parse(ctx.Param("linkId"))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	campaign, ok := r.campaign(ctx)
+	if !ok {
+		return
+	}
+
+	err = r.campaignService.RemoveLink(ctx.Request.Context(), campaign.UserID, campaign.ID, linkId)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	utils.ResponsdJson(ctx, http.StatusNoContent, "successfully remove link from campaign", nil)
+}
+
+// GetAnalytics godoc
+// @Summary      Get campaign analytics
+// @Description  Get the totals, timeseries and breakdowns of all links in a campaign, and a leaderboard of its links by clicks. Without a range the campaign dates are used.
+// @Tags         Campaigns
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id     path      string  true   "Campaign ID (UUID)"
+// @Param        range  query     string  false  "Time range"                 Enums(7d, 30d, 90d, all)
+// @Param        limit  query     int     false  "Links in the leaderboard, at most 100"  default(10)
+// @Success      200  {object}  responses.BaseResponse{data=responses.CampaignAnalyticsResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /campaigns/{id}/analytics [get]
+func (r *campaignRoutes) GetAnalytics(ctx *gin.Context) {
+	var (
+		limit = 10
+		err   error
+	)
+
+	if ctx.Query("limit") != "" {
+		limit, err = strconv.Atoi(ctx.Query("limit"))
+		if err != nil {
+			utils.HandleErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	limit = min(max(limit, 1), 100)
+
+	campaign, ok := r.campaign(ctx)
+	if !ok {
+		return
+	}
+
+	timeRange := campaignTimeRange
+	to := time.Now()
+	from := campaign.StartsAt.Time
+	if campaign.EndsAt.Valid && campaign.EndsAt.Time.Before(to) {
+		to = campaign.EndsAt.Time
+	}
+
+	if ctx.Query("range") != "" {
+		parsed := utils.ParseTimeRange(ctx.Query("range"))
+		timeRange = string(parsed)
+		from = parsed.GetFromDate()
+		to = time.Now()
+	}
+
+	reqCtx := ctx.Request.Context()
+	userId := campaign.UserID
+
+	totalClicks, err := r.campaignService.GetTotalClicks(reqCtx, userId, campaign.ID, from, to)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	overviews, err := r.campaignService.GetByDateRange(reqCtx, userId, campaign.ID, from, to)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	deviceBreakdown, err := r.campaignService.GetDeviceBreakdown(reqCtx, userId, campaign.ID, from, to)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	topCountries, err := r.campaignService.GetTopCountries(reqCtx, userId, campaign.ID, from, to)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	topRegions, err := r.campaignService.GetTopRegions(reqCtx, userId, campaign.ID, from, to)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	topCities, err := r.campaignService.GetTopCities(reqCtx, userId, campaign.ID, from, to)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	trafficSources, err := r.campaignService.GetTrafficSources(reqCtx, userId, campaign.ID, from, to)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	browserUsage, err := r.campaignService.GetBrowserUsage(reqCtx, userId, campaign.ID, from, to)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	leaderboard, err := r.campaignService.GetLeaderboard(reqCtx, userId, campaign.ID, from, to, int32(limit))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	// A campaign without a start date counts from its first click.
+	start := from
+	if start.IsZero() && len(overviews) > 0 {
+		start = overviews[0].Date
+	}
+	daysDiff := to.Sub(start).Hours() / 24
+	if daysDiff < 1 {
+		daysDiff = 1
+	}
+
+	response := responses.CampaignAnalyticsResponse{
+		Campaign:         responses.MapCampaignResponse(campaign),
+		TimeRange:        timeRange,
+		FromDate:         from,
+		ToDate:           to,
+		TotalClicks:      totalClicks,
+		AvgDailyClick:    totalClicks / int64(daysDiff),
+		Overviews:        responses.MapAnalyticsResponse(overviews),
+		DeviceBreakdowns: responses.MapDeviceBreakdown(deviceBreakdown),
+		TopCountries:     responses.MapTopCountries(topCountries),
+		TopRegions:       responses.MapTopRegions(topRegions),
+		TopCities:        responses.MapTopCities(topCities),
+		TrafficSources:   responses.MapTrafficSources(trafficSources),
+		BrowserUsages:    responses.MapBrowserUsage(browserUsage),
+		Leaderboard:      responses.MapCampaignLeaderboard(leaderboard),
+	}
+
+	utils.RespondOK(ctx, "successfully get campaign analytics", response)
+}
+
+// campaign loads the campaign in the id path parameter for the current user
+// and responds with the error when it cannot.
+func (r *campaignRoutes) campaign(ctx *gin.Context) (database.GetCampaignRow, bool) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+
+	campaignId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return database.GetCampaignRow{}, false
+	}
+
+	campaign, err := r.campaignService.GetCampaign(ctx.Request.Context(), userId, campaignId)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return database.GetCampaignRow{}, false
+	}
+
+	return campaign, true
+}
+
+func validCampaignDates(startsAt *time.Time, endsAt *time.Time) bool {
+	return startsAt == nil || endsAt == nil || startsAt.Before(*endsAt)
+}
+
+func nullString(value *string) sql.NullString {
+	return sql.NullString{
+		Valid:  value != nil,
+		String: utils.GetOrElse(value, ""),
+	}
+}
+
+func nullTime(value *time.Time) sql.NullTime {
+	return sql.NullTime{
+		Valid: value != nil,
+		Time:  utils.GetOrElse(value, time.Time{}),
+	}
+}
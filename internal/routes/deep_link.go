@@ -0,0 +1,93 @@
+package routes
+
+import (
+	"database/sql"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+)
+
+type deepLinkPage struct {
+	// AppURL was checked against blocked schemes when the link was saved,
+	// and custom app schemes would otherwise be rewritten by html/template.
+	AppURL template.URL
+	// FallbackURL is empty unless it is an http(s) URL. html/template does
+	// not check URLs inside script, so a destination saved before
+	// original_url had to be http(s) could otherwise run on this origin.
+	FallbackURL         string
+	FallbackDelayMillis int64
+}
+
+func hasDeepLink(link database.GetRedirectLinkRow) bool {
+	return link.IosDeepLink.Valid || link.AndroidDeepLink.Valid
+}
+
+// platformTargets returns the deep link and store URL of the link for the
+// platform of the visitor.
+func platformTargets(link database.GetRedirectLinkRow, platform utils.Platform) (sql.NullString, sql.NullString) {
+	switch platform {
+	case utils.PlatformIOS:
+		return link.IosDeepLink, link.IosStoreUrl
+	case utils.PlatformAndroid:
+		return link.AndroidDeepLink, link.AndroidStoreUrl
+	default:
+		return sql.NullString{}, sql.NullString{}
+	}
+}
+
+// redirectDeepLink sends visitors on a platform the link has an app for to
+// that app. Universal links and app links already open the app before the
+// request gets here, so a regular browser means the app is missing and the
+// visitor goes to the store. In-app browsers of social apps never hand links
+// over to other apps; they get a page that tries the app scheme and falls
+// back after fallbackDelay.
+func redirectDeepLink(ctx *gin.Context, link database.GetRedirectLinkRow, platform utils.Platform, fallbackDelay time.Duration) {
+	// The response depends on the visitor, so it must not be reused.
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Vary", "User-Agent")
+
+	deepLink, storeURL := platformTargets(link, platform)
+	if !deepLink.Valid {
+		ctx.Redirect(http.StatusFound, link.OriginalUrl)
+		return
+	}
+
+	fallbackURL := link.OriginalUrl
+	if storeURL.Valid {
+		fallbackURL = storeURL.String
+	}
+
+	if !utils.IsInAppBrowser(ctx.Request.UserAgent()) {
+		ctx.Redirect(http.StatusFound, fallbackURL)
+		return
+	}
+
+	if !utils.IsWebURL(fallbackURL) {
+		fallbackURL = ""
+	}
+	ctx.Render(http.StatusOK, render.HTML{
+		Template: pageTemplates,
+		Name:     "deep_link.html",
+		Data: deepLinkPage{
+			AppURL:              template.URL(deepLink.String),
+			FallbackURL:         fallbackURL,
+			FallbackDelayMillis: fallbackDelay.Milliseconds(),
+		},
+	})
+}
+
+const errInvalidDeepLink = "ios_deep_link and android_deep_link must be absolute URLs such as myapp://path"
+
+func validDeepLinks(deepLinks ...*string) bool {
+	for _, deepLink := range deepLinks {
+		if deepLink != nil && !utils.ValidDeepLink(*deepLink) {
+			return false
+		}
+	}
+	return true
+}
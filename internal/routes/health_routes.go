@@ -0,0 +1,102 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/models/responses"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+const healthCheckTimeout = time.Second
+
+// Pinger is satisfied by *sql.DB.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+type healthRoutes struct {
+	db           Pinger
+	rdb          *redis.Client
+	dbBreaker    *utils.CircuitBreaker
+	redisBreaker *utils.CircuitBreaker
+}
+
+func NewHealthRoutes(db Pinger, rdb *redis.Client, dbBreaker *utils.CircuitBreaker, redisBreaker *utils.CircuitBreaker) healthRoutes {
+	return healthRoutes{
+		db:           db,
+		rdb:          rdb,
+		dbBreaker:    dbBreaker,
+		redisBreaker: redisBreaker,
+	}
+}
+
+// Liveness godoc
+// @Summary      Liveness probe
+// @Description  Reports that the process is running. Dependencies are not checked.
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object}  responses.BaseResponse{data=responses.HealthResponse}
+// @Router       /healthz [get]
+func (r *healthRoutes) Liveness(ctx *gin.Context) {
+	utils.RespondOK(ctx, "ok", responses.HealthResponse{Status: "ok"})
+}
+
+// Readiness godoc
+// @Summary      Readiness probe
+// @Description  Checks Postgres and Redis. Either one being down still serves traffic in degraded mode; both being down returns 503.
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object}  responses.BaseResponse{data=responses.HealthResponse}
+// @Failure      503  {object}  responses.BaseResponse{data=responses.HealthResponse}
+// @Router       /readyz [get]
+func (r *healthRoutes) Readiness(ctx *gin.Context) {
+	reqCtx := ctx.Request.Context()
+
+	database := checkDependency(reqCtx, r.dbBreaker, r.db.PingContext)
+	cache := checkDependency(reqCtx, r.redisBreaker, func(ctx context.Context) error {
+		return r.rdb.Ping(ctx).Err()
+	})
+
+	health := responses.HealthResponse{
+		Status: "ok",
+		Dependencies: map[string]responses.DependencyHealth{
+			"postgres": database,
+			"redis":    cache,
+		},
+	}
+
+	status := http.StatusOK
+	switch {
+	case database.Status != "up" && cache.Status != "up":
+		health.Status = "unavailable"
+		status = http.StatusServiceUnavailable
+	case database.Status != "up" || cache.Status != "up":
+		health.Status = "degraded"
+	}
+
+	utils.ResponsdJson(ctx, status, health.Status, health)
+}
+
+func checkDependency(ctx context.Context, breaker *utils.CircuitBreaker, ping func(context.Context) error) responses.DependencyHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := ping(ctx)
+
+	health := responses.DependencyHealth{
+		Status:    "up",
+		Circuit:   string(breaker.State()),
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		health.Status = "down"
+		health.Error = err.Error()
+	}
+
+	return health
+}
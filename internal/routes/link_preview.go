@@ -0,0 +1,109 @@
+package routes
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+)
+
+// previewSuffix appended to a short code shows the preview page instead of
+// redirecting, e.g. /abc123+.
+const previewSuffix = "+"
+
+type linkPreviewPage struct {
+	Code         string
+	Destination  string
+	Domain       string
+	Title        string
+	Description  string
+	CreatedAt    time.Time
+	Safety       utils.LinkSafety
+	SafetyText   string
+	Availability string
+	// ContinueURL is empty when the destination must not be followed.
+	ContinueURL string
+	// Forced is set when the owner turned the preview on for every visit.
+	Forced bool
+}
+
+var linkSafetyText = map[utils.LinkSafety]string{
+	utils.LinkSafetyBlocked:     "Blocked: this link was taken down",
+	utils.LinkSafetyUnreachable: "The destination could not be reached recently",
+	utils.LinkSafetyInsecure:    "The destination does not use HTTPS",
+	utils.LinkSafetyUnverified:  "The destination has not been checked yet",
+	utils.LinkSafetyOK:          "The destination was reachable when last checked",
+}
+
+// Preview godoc
+// @Summary      Preview a short link
+// @Description  Shows where a short link goes, with the destination title, creation date and safety status, without redirecting or counting a click. Appending + to the short code, as in /{code}+, shows the same page.
+// @Tags         Redirect
+// @Produce      html
+// @Param        code   path      string  true  "Short code"
+// @Success      200  {string}  string  "Preview page"
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /{code}/preview [get]
+func (r *linkRoutes) Preview(ctx *gin.Context) {
+	r.renderPreview(ctx, ctx.Param("code"), false)
+}
+
+func (r *linkRoutes) renderPreview(ctx *gin.Context, code string, forced bool) {
+	link, err := r.linkService.GetLinkPreview(ctx.Request.Context(), code)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	safety := utils.LinkSafetyOf(link.OriginalUrl, link.TakenDownAt, utils.LinkHealthStatus(link.HealthStatus))
+	page := linkPreviewPage{
+		Code:        code,
+		Destination: link.OriginalUrl,
+		Domain:      destinationDomain(link.OriginalUrl),
+		Title:       link.MetaTitle.String,
+		Description: link.MetaDescription.String,
+		CreatedAt:   link.CreatedAt,
+		Safety:      safety,
+		SafetyText:  linkSafetyText[safety],
+		Forced:      forced,
+	}
+
+	switch utils.LinkStateAt(link.ActiveFrom, link.ExpiredAt, time.Now()) {
+	case utils.LinkStateScheduled:
+		page.Availability = "Not available until " + link.ActiveFrom.Time.UTC().Format("2 January 2006 15:04 MST")
+	case utils.LinkStateExpired:
+		page.Availability = "Expired on " + link.ExpiredAt.Time.UTC().Format("2 January 2006 15:04 MST")
+	default:
+		if safety != utils.LinkSafetyBlocked {
+			page.ContinueURL = link.OriginalUrl
+		}
+	}
+
+	// The page reflects the live state of the link and must not be reused
+	// in place of the redirect.
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Render(http.StatusOK, render.HTML{
+		Template: pageTemplates,
+		Name:     "link_preview.html",
+		Data:     page,
+	})
+}
+
+// previewCode returns the short code of a /{code}+ request.
+func previewCode(code string) (string, bool) {
+	trimmed, ok := strings.CutSuffix(code, previewSuffix)
+	return trimmed, ok && trimmed != ""
+}
+
+func destinationDomain(originalURL string) string {
+	u, err := url.Parse(originalURL)
+	if err != nil || u.Hostname() == "" {
+		return originalURL
+	}
+	return u.Hostname()
+}
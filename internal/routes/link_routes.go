@@ -3,32 +3,56 @@ package routes
 import (
 	"context"
 	"database/sql"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/metrics"
 	"github.com/andriawan24/link-short/internal/models/requests"
 	"github.com/andriawan24/link-short/internal/models/responses"
+	"github.com/andriawan24/link-short/internal/resilience"
 	"github.com/andriawan24/link-short/internal/services"
 	"github.com/andriawan24/link-short/internal/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/medama-io/go-useragent"
+	"github.com/redis/go-redis/v9"
 )
 
+const linkHealthHistoryLimit = 20
+
 type linkRoutes struct {
-	linkService     services.LinkService
-	clickLogService services.ClickLogService
-	cacheService    services.CacheService
+	linkService         services.LinkService
+	clickLogService     services.ClickLogService
+	clickSpool          services.ClickSpool
+	cacheService        services.CacheService
+	clickStreamService  services.ClickStreamService
+	webhookService      services.WebhookService
+	linkMetadataService services.LinkMetadataService
+	shortCodeService    services.ShortCodeService
+	auditService        services.AuditService
+	appLinkService      services.AppLinkService
+	geoLocator          services.GeoLocator
+	publicStatsService  services.PublicStatsService
 }
 
-func NewLinkRoutes(linkService services.LinkService, clickLogService services.ClickLogService, cacheService services.CacheService) linkRoutes {
+func NewLinkRoutes(linkService services.LinkService, clickLogService services.ClickLogService, clickSpool services.ClickSpool, cacheService services.CacheService, clickStreamService services.ClickStreamService, webhookService services.WebhookService, linkMetadataService services.LinkMetadataService, shortCodeService services.ShortCodeService, auditService services.AuditService, appLinkService services.AppLinkService, geoLocator services.GeoLocator, publicStatsService services.PublicStatsService) linkRoutes {
 	return linkRoutes{
-		linkService:     linkService,
-		clickLogService: clickLogService,
-		cacheService:    cacheService,
+		linkService:         linkService,
+		clickLogService:     clickLogService,
+		clickSpool:          clickSpool,
+		cacheService:        cacheService,
+		clickStreamService:  clickStreamService,
+		webhookService:      webhookService,
+		linkMetadataService: linkMetadataService,
+		shortCodeService:    shortCodeService,
+		auditService:        auditService,
+		appLinkService:      appLinkService,
+		geoLocator:          geoLocator,
+		publicStatsService:  publicStatsService,
 	}
 }
 
@@ -64,19 +88,19 @@ func (r *linkRoutes) GetLink(ctx *gin.Context) {
 	to := time.Now()
 	from := time.Time{}
 
-	totalClicks, err := r.linkService.GetTotalCounts(ctx.Request.Context(), userId, from, to)
+	deviceBreakdown, err := r.clickLogService.GetDeviceBreakdownSingleLink(ctx, userId, link.ID, from, to)
 	if err != nil {
 		utils.HandleErrorResponse(ctx, err)
 		return
 	}
 
-	deviceBreakdown, err := r.clickLogService.GetDeviceBreakdownSingleLink(ctx, userId, link.ID, from, to)
+	countryBreakdown, err := r.clickLogService.GetTopCountriesSingleLink(ctx, userId, link.ID, from, to)
 	if err != nil {
 		utils.HandleErrorResponse(ctx, err)
 		return
 	}
 
-	countryBreakdown, err := r.clickLogService.GetTopCountriesSingleLink(ctx, userId, link.ID, from, to)
+	healthChecks, err := r.linkService.GetLinkHealthChecks(ctx.Request.Context(), link.ID, linkHealthHistoryLimit)
 	if err != nil {
 		utils.HandleErrorResponse(ctx, err)
 		return
@@ -85,7 +109,7 @@ func (r *linkRoutes) GetLink(ctx *gin.Context) {
 	devices := responses.MapDeviceBreakdownSingle(deviceBreakdown)
 	countries := responses.MapTopCountriesSingle(countryBreakdown)
 
-	utils.RespondOK(ctx, "successfully get link", responses.MapLinkResponse(link, totalClicks, devices, countries))
+	utils.RespondOK(ctx, "successfully get link", responses.MapLinkResponse(link, link.Counts, devices, countries, healthChecks))
 }
 
 // GetLinks godoc
@@ -98,6 +122,8 @@ func (r *linkRoutes) GetLink(ctx *gin.Context) {
 // @Param        page     query     int     false  "Page number"       default(1)
 // @Param        limit    query     int     false  "Items per page"    default(10)
 // @Param        orderBy  query     string  false  "Order by field"    Enums(created_at, counts)
+// @Param        health   query     string  false  "Health status"     Enums(unknown, healthy, broken)
+// @Param        state    query     string  false  "Activation state"  Enums(scheduled, active, expired)
 // @Success      200  {object}  responses.BaseResponse{data=[]responses.LinkResponse}
 // @Failure      400  {object}  responses.ErrorResponse
 // @Failure      401  {object}  responses.ErrorResponse
@@ -109,6 +135,8 @@ func (r *linkRoutes) GetLinks(ctx *gin.Context) {
 		page    = 1
 		limit   = 10
 		orderBy = utils.OrderByCreatedDate
+		health  utils.LinkHealthStatus
+		state   utils.LinkState
 		err     error
 	)
 
@@ -136,9 +164,25 @@ func (r *linkRoutes) GetLinks(ctx *gin.Context) {
 		}
 	}
 
+	if ctx.Query("health") != "" {
+		health, err = utils.ParseLinkHealthStatus(ctx.Query("health"))
+		if err != nil {
+			utils.HandleErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	if ctx.Query("state") != "" {
+		state, err = utils.ParseLinkState(ctx.Query("state"))
+		if err != nil {
+			utils.HandleErrorResponse(ctx, err)
+			return
+		}
+	}
+
 	offset := (page - 1) * limit
 
-	links, err := r.linkService.GetLinks(ctx.Request.Context(), userId, int32(limit), int32(offset), orderBy)
+	links, err := r.linkService.GetLinks(ctx.Request.Context(), userId, int32(limit), int32(offset), orderBy, health, state)
 	if err != nil {
 		utils.HandleErrorResponse(ctx, err)
 		return
@@ -158,6 +202,7 @@ func (r *linkRoutes) GetLinks(ctx *gin.Context) {
 // @Success      200  {object}  responses.BaseResponse{data=responses.LinkResponse}
 // @Failure      400  {object}  responses.ErrorResponse
 // @Failure      401  {object}  responses.ErrorResponse
+// @Failure      409  {object}  responses.ErrorResponse
 // @Failure      500  {object}  responses.ErrorResponse
 // @Router       /links/create [post]
 func (r *linkRoutes) InsertLink(ctx *gin.Context) {
@@ -171,9 +216,26 @@ func (r *linkRoutes) InsertLink(ctx *gin.Context) {
 		return
 	}
 
+	if !validActivationWindow(body.ActiveFrom, body.ExpiredAt) {
+		utils.RespondBadRequest(ctx, errInvalidActivationWindow)
+		return
+	}
+
+	if !validDeepLinks(body.IOSDeepLink, body.AndroidDeepLink) {
+		utils.RespondBadRequest(ctx, errInvalidDeepLink)
+		return
+	}
+
+	if body.CustomShortCode != nil {
+		err = r.shortCodeService.ValidateCustom(ctx.Request.Context(), *body.CustomShortCode)
+		if err != nil {
+			utils.HandleErrorResponse(ctx, err)
+			return
+		}
+	}
+
 	param := database.InsertLinkParams{
 		OriginalUrl: body.OriginalURL,
-		ShortCode:   utils.GenerateShortCode(),
 		CustomShortCode: sql.NullString{
 			Valid:  body.CustomShortCode != nil,
 			String: utils.GetOrElse(body.CustomShortCode, ""),
@@ -183,6 +245,31 @@ func (r *linkRoutes) InsertLink(ctx *gin.Context) {
 			Valid: body.ExpiredAt != nil,
 			Time:  utils.GetOrElse(body.ExpiredAt, time.Now()),
 		},
+		ActiveFrom: sql.NullTime{
+			Valid: body.ActiveFrom != nil,
+			Time:  utils.GetOrElse(body.ActiveFrom, time.Now()),
+		},
+		PrelaunchUrl: sql.NullString{
+			Valid:  body.PrelaunchURL != nil,
+			String: utils.GetOrElse(body.PrelaunchURL, ""),
+		},
+		IosDeepLink: sql.NullString{
+			Valid:  body.IOSDeepLink != nil,
+			String: utils.GetOrElse(body.IOSDeepLink, ""),
+		},
+		IosStoreUrl: sql.NullString{
+			Valid:  body.IOSStoreURL != nil,
+			String: utils.GetOrElse(body.IOSStoreURL, ""),
+		},
+		AndroidDeepLink: sql.NullString{
+			Valid:  body.AndroidDeepLink != nil,
+			String: utils.GetOrElse(body.AndroidDeepLink, ""),
+		},
+		AndroidStoreUrl: sql.NullString{
+			Valid:  body.AndroidStoreURL != nil,
+			String: utils.GetOrElse(body.AndroidStoreURL, ""),
+		},
+		ForcePreview: utils.GetOrElse(body.ForcePreview, false),
 	}
 
 	link, err := r.linkService.InsertLink(ctx.Request.Context(), param)
@@ -191,7 +278,150 @@ func (r *linkRoutes) InsertLink(ctx *gin.Context) {
 		return
 	}
 
-	utils.ResponsdJson(ctx, http.StatusCreated, "successfully insert new link", responses.MapLinkDetailResponse(link))
+	// The codes may be cached as not found from earlier lookups.
+	r.invalidateCodes(ctx.Request.Context(), link.ShortCode, link.CustomShortCode)
+	r.linkMetadataService.Refresh(link.ID, link.OriginalUrl)
+
+	recordAudit(ctx, r.auditService, services.AuditEntry{
+		OwnerID:    userId,
+		ActorID:    uuid.NullUUID{UUID: userId, Valid: true},
+		Action:     utils.AuditActionLinkCreated,
+		TargetType: utils.AuditTargetLink,
+		TargetID:   uuid.NullUUID{UUID: link.ID, Valid: true},
+		After:      services.NewLinkAuditSnapshot(link.OriginalUrl, link.ShortCode, link.CustomShortCode, link.ExpiredAt),
+	})
+
+	response := responses.MapLinkDetailResponse(link)
+	r.dispatchWebhook(ctx, userId, utils.WebhookEventLinkCreated, response)
+
+	utils.ResponsdJson(ctx, http.StatusCreated, "successfully insert new link", response)
+}
+
+// UpdateLink godoc
+// @Summary      Update an existing link
+// @Description  Update the destination, custom short code, activation window, pre-launch URL, deep links or forced preview of a link
+// @Tags         Links
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                      true  "Link ID (UUID)"
+// @Param        request  body      requests.UpdateLinkParam  true  "Link details"
+// @Success      200  {object}  responses.BaseResponse{data=responses.LinkResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      409  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /links/{id} [put]
+func (r *linkRoutes) UpdateLink(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+
+	linkId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	var body requests.UpdateLinkParam
+
+	err = ctx.ShouldBindJSON(&body)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	if !validActivationWindow(body.ActiveFrom, body.ExpiredAt) {
+		utils.RespondBadRequest(ctx, errInvalidActivationWindow)
+		return
+	}
+
+	if !validDeepLinks(body.IOSDeepLink, body.AndroidDeepLink) {
+		utils.RespondBadRequest(ctx, errInvalidDeepLink)
+		return
+	}
+
+	existing, err := r.linkService.GetLink(ctx.Request.Context(), userId, linkId)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	if body.CustomShortCode != nil && *body.CustomShortCode != existing.CustomShortCode.String {
+		err = r.shortCodeService.ValidateCustom(ctx.Request.Context(), *body.CustomShortCode)
+		if err != nil {
+			utils.HandleErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	param := database.UpdateLinkParams{
+		ID:          existing.ID,
+		UserID:      userId,
+		OriginalUrl: body.OriginalURL,
+		CustomShortCode: sql.NullString{
+			Valid:  body.CustomShortCode != nil,
+			String: utils.GetOrElse(body.CustomShortCode, ""),
+		},
+		ExpiredAt: sql.NullTime{
+			Valid: body.ExpiredAt != nil,
+			Time:  utils.GetOrElse(body.ExpiredAt, time.Now()),
+		},
+		ActiveFrom: sql.NullTime{
+			Valid: body.ActiveFrom != nil,
+			Time:  utils.GetOrElse(body.ActiveFrom, time.Now()),
+		},
+		PrelaunchUrl: sql.NullString{
+			Valid:  body.PrelaunchURL != nil,
+			String: utils.GetOrElse(body.PrelaunchURL, ""),
+		},
+		IosDeepLink: sql.NullString{
+			Valid:  body.IOSDeepLink != nil,
+			String: utils.GetOrElse(body.IOSDeepLink, ""),
+		},
+		IosStoreUrl: sql.NullString{
+			Valid:  body.IOSStoreURL != nil,
+			String: utils.GetOrElse(body.IOSStoreURL, ""),
+		},
+		AndroidDeepLink: sql.NullString{
+			Valid:  body.AndroidDeepLink != nil,
+			String: utils.GetOrElse(body.AndroidDeepLink, ""),
+		},
+		AndroidStoreUrl: sql.NullString{
+			Valid:  body.AndroidStoreURL != nil,
+			String: utils.GetOrElse(body.AndroidStoreURL, ""),
+		},
+		ForcePreview: utils.GetOrElse(body.ForcePreview, false),
+	}
+
+	link, err := r.linkService.UpdateLink(ctx.Request.Context(), param)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	r.invalidateCodes(ctx.Request.Context(), existing.ShortCode, existing.CustomShortCode)
+	if link.CustomShortCode != existing.CustomShortCode {
+		r.invalidateCodes(ctx.Request.Context(), link.ShortCode, link.CustomShortCode)
+	}
+
+	if link.OriginalUrl != existing.OriginalUrl || !link.MetaFetchedAt.Valid {
+		r.linkMetadataService.Refresh(link.ID, link.OriginalUrl)
+	}
+
+	recordAudit(ctx, r.auditService, services.AuditEntry{
+		OwnerID:    userId,
+		ActorID:    uuid.NullUUID{UUID: userId, Valid: true},
+		Action:     utils.AuditActionLinkUpdated,
+		TargetType: utils.AuditTargetLink,
+		TargetID:   uuid.NullUUID{UUID: link.ID, Valid: true},
+		Before:     services.NewLinkAuditSnapshot(existing.OriginalUrl, existing.ShortCode, existing.CustomShortCode, existing.ExpiredAt),
+		After:      services.NewLinkAuditSnapshot(link.OriginalUrl, link.ShortCode, link.CustomShortCode, link.ExpiredAt),
+	})
+
+	response := responses.MapLinkDetailResponse(link)
+	r.dispatchWebhook(ctx, userId, utils.WebhookEventLinkUpdated, response)
+
+	utils.RespondOK(ctx, "successfully update link", response)
 }
 
 // DeleteLink godoc
@@ -232,27 +462,52 @@ func (r *linkRoutes) DeleteLink(ctx *gin.Context) {
 		return
 	}
 
+	r.invalidateCodes(ctx.Request.Context(), link.ShortCode, link.CustomShortCode)
+	r.publicStatsService.Invalidate(ctx.Request.Context(), link.StatsShareToken.String)
+
+	recordAudit(ctx, r.auditService, services.AuditEntry{
+		OwnerID:    userId,
+		ActorID:    uuid.NullUUID{UUID: userId, Valid: true},
+		Action:     utils.AuditActionLinkDeleted,
+		TargetType: utils.AuditTargetLink,
+		TargetID:   uuid.NullUUID{UUID: link.ID, Valid: true},
+		Before:     services.NewLinkAuditSnapshot(link.OriginalUrl, link.ShortCode, link.CustomShortCode, link.ExpiredAt),
+	})
+
+	r.dispatchWebhook(ctx, userId, utils.WebhookEventLinkDeleted, responses.MapLinkResponse(link, link.Counts, nil, nil, nil))
+
 	utils.ResponsdJson(ctx, http.StatusNoContent, "successfully insert new link", nil)
 }
 
 // Redirect godoc
 // @Summary      Redirect to original URL
-// @Description  Redirect to the original URL using the short code
+// @Description  Redirect to the original URL using the short code with 302, which browsers do not cache, so every visit is checked and counted. Before its active_from a scheduled link redirects to its pre-launch URL with 302, or answers 404 with the time it becomes available; after expired_at it answers 410. Links with a deep link send iOS and Android visitors to the app store with 302, or from the in-app browsers of Instagram and Facebook to a page that opens the app. Links with force_preview show the preview page, counting the click. Appending + to the code shows the preview page without counting a click.
 // @Tags         Redirect
+// @Produce      html
 // @Param        code   path      string  true  "Short code"
-// @Success      301  {string}  string  "Redirect to original URL"
+// @Success      200  {string}  string  "Page opening the app, for in-app browsers"
+// @Success      302  {string}  string  "Redirect to the original URL, pre-launch URL or app store"
 // @Failure      404  {object}  responses.ErrorResponse
+// @Failure      410  {object}  responses.ErrorResponse
 // @Failure      500  {object}  responses.ErrorResponse
 // @Router       /{code} [get]
 func (r *linkRoutes) Redirect(ctx *gin.Context) {
 	code := ctx.Param("code")
 	reqCtx := ctx.Request.Context()
 
+	if previewCode, ok := previewCode(code); ok {
+		r.renderPreview(ctx, previewCode, false)
+		return
+	}
+
 	parser := useragent.NewParser()
 	ua := parser.Parse(ctx.Request.UserAgent())
 
 	deviceType := utils.ParseDeviceType(ua)
-	country := utils.ParseCountryFromIp(ctx.ClientIP())
+	location := r.geoLocator.Locate(ctx.ClientIP())
+	if location.Country == "" {
+		location.Country = "unknown"
+	}
 	traffic := utils.ParseTrafficSource(ctx.Request.Referer())
 	browser := utils.ParseBrowser(ua)
 
@@ -275,8 +530,20 @@ func (r *linkRoutes) Redirect(ctx *gin.Context) {
 			String: deviceType,
 		},
 		Country: sql.NullString{
-			Valid:  country != "",
-			String: country,
+			Valid:  true,
+			String: location.Country,
+		},
+		Region: sql.NullString{
+			Valid:  location.Region != "",
+			String: location.Region,
+		},
+		City: sql.NullString{
+			Valid:  location.City != "",
+			String: location.City,
+		},
+		Asn: sql.NullInt64{
+			Valid: location.ASN != 0,
+			Int64: location.ASN,
 		},
 		Traffic: sql.NullString{
 			Valid:  traffic != "",
@@ -288,29 +555,157 @@ func (r *linkRoutes) Redirect(ctx *gin.Context) {
 		},
 	}
 
-	// Try redis
 	originalURL, err := r.cacheService.GetURL(reqCtx, code)
-	if err == nil && originalURL != "" {
-		if _, err := r.clickLogService.InsertClickLog(reqCtx, param); err != nil {
-			log.Printf("failed to insert click log (redis hit) for code %s: %v", code, err)
-		}
-		ctx.Redirect(http.StatusMovedPermanently, originalURL)
+	switch {
+	case err == nil:
+		r.recordClick(reqCtx, param, "cache")
+		ctx.Redirect(http.StatusFound, originalURL)
 		return
+	case errors.Is(err, services.ErrCachedNotFound):
+		utils.HandleErrorResponse(ctx, sql.ErrNoRows)
+		return
+	case !errors.Is(err, redis.Nil) && !errors.Is(err, utils.ErrCircuitOpen):
+		slog.WarnContext(reqCtx, "failed to read cached link", "code", code, "error", err)
 	}
 
-	originalURL, err = r.linkService.GetRedirectedLink(reqCtx, code)
+	link, err := r.linkService.GetRedirectedLink(reqCtx, code)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if err := r.cacheService.SetNotFound(reqCtx, code); err != nil {
+				slog.WarnContext(reqCtx, "failed to cache unknown code", "code", code, "error", err)
+			}
+		}
+
 		utils.HandleErrorResponse(ctx, err)
 		return
 	}
 
+	if link.TakenDownAt.Valid {
+		utils.RespondGone(ctx, "this link has been taken down", gin.H{
+			"reason": link.TakedownReason.String,
+		})
+		return
+	}
+
+	// Links outside their window are never cached, so each request sees
+	// the window open or close on time.
+	switch utils.LinkStateAt(link.ActiveFrom, link.ExpiredAt, time.Now()) {
+	case utils.LinkStateScheduled:
+		if link.PrelaunchUrl.Valid {
+			ctx.Redirect(http.StatusFound, link.PrelaunchUrl.String)
+			return
+		}
+		utils.RespondNotFound(ctx, "this link is not available yet", gin.H{
+			"active_from": link.ActiveFrom.Time,
+		})
+		return
+	case utils.LinkStateExpired:
+		utils.RespondGone(ctx, "this link has expired", nil)
+		return
+	}
+
+	// The owner asked for every visit to go through the preview page. The
+	// visit counts as a click, and the link is not cached so the page is
+	// shown every time.
+	if link.ForcePreview {
+		r.recordClick(reqCtx, param, "db")
+		r.renderPreview(ctx, code, true)
+		return
+	}
+
+	// Links with deep links answer differently per platform, so they are
+	// never cached either.
+	if hasDeepLink(link) {
+		r.recordClick(reqCtx, param, "db")
+		redirectDeepLink(ctx, link, utils.ParsePlatform(ua), r.appLinkService.FallbackDelay())
+		return
+	}
+
+	originalURL = link.OriginalUrl
+
 	go func() {
-		_ = r.cacheService.SetURL(context.Background(), code, originalURL, 24*time.Hour)
+		_ = r.cacheService.SetURL(context.WithoutCancel(reqCtx), code, originalURL, link.ExpiredAt)
 	}()
 
-	if _, err := r.clickLogService.InsertClickLog(reqCtx, param); err != nil {
-		log.Printf("failed to insert click log (db hit) for code %s: %v", code, err)
+	r.recordClick(reqCtx, param, "db")
+
+	// Not 301: browsers would cache it and skip expiry, takedowns and click
+	// counting on later visits.
+	ctx.Redirect(http.StatusFound, originalURL)
+}
+
+// recordClick stores the click and notifies listeners in the background.
+// source tells whether the redirect was served from the cache or the database.
+// While the database is unavailable the click is spooled to disk instead.
+func (r *linkRoutes) recordClick(ctx context.Context, param database.InsertClickLogParams, source string) {
+	clickedAt := time.Now()
+
+	clickLog, err := r.clickLogService.InsertClickLog(ctx, param)
+	if err != nil {
+		if resilience.IsUnavailable(err) {
+			spoolErr := r.clickSpool.Append(param, clickedAt)
+			if spoolErr == nil {
+				return
+			}
+			err = errors.Join(err, spoolErr)
+		}
+
+		metrics.ClickInsertFailures.WithLabelValues(source).Inc()
+		slog.ErrorContext(ctx, "failed to insert click log", "code", param.Code, "source", source, "error", err)
+		return
+	}
+
+	go r.notifyClick(context.WithoutCancel(ctx), clickLog)
+}
+
+// notifyClick fans a recorded click out to live streams and webhooks of the
+// link owner.
+func (r *linkRoutes) notifyClick(ctx context.Context, clickLog database.ClickLog) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	link, err := r.linkService.GetLinkByCode(ctx, clickLog.Code)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to resolve link for click", "code", clickLog.Code, "error", err)
+		return
+	}
+
+	event := responses.MapClickEventResponse(link.ID, clickLog)
+
+	if err := r.clickStreamService.Publish(ctx, link.UserID, event); err != nil {
+		slog.ErrorContext(ctx, "failed to publish click event", "code", clickLog.Code, "error", err)
 	}
 
-	ctx.Redirect(http.StatusMovedPermanently, originalURL)
+	if err := r.webhookService.Dispatch(ctx, link.UserID, utils.WebhookEventClickRecorded, event); err != nil {
+		slog.ErrorContext(ctx, "failed to dispatch webhook event", "event", utils.WebhookEventClickRecorded, "code", clickLog.Code, "error", err)
+	}
+}
+
+func (r *linkRoutes) dispatchWebhook(ctx *gin.Context, userId uuid.UUID, event utils.WebhookEvent, data any) {
+	if err := r.webhookService.Dispatch(ctx.Request.Context(), userId, event, data); err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "failed to dispatch webhook event", "event", event, "user_id", userId, "error", err)
+	}
+}
+
+const errInvalidActivationWindow = "active_from must be before expired_at"
+
+func validActivationWindow(activeFrom *time.Time, expiredAt *time.Time) bool {
+	return activeFrom == nil || expiredAt == nil || activeFrom.Before(*expiredAt)
+}
+
+func (r *linkRoutes) invalidateCodes(ctx context.Context, shortCode string, customShortCode sql.NullString) {
+	invalidateLinkCodes(ctx, r.cacheService, shortCode, customShortCode)
+}
+
+// invalidateLinkCodes drops the cached redirect for both codes of a link.
+func invalidateLinkCodes(ctx context.Context, cacheService services.CacheService, shortCode string, customShortCode sql.NullString) {
+	if err := cacheService.InvalidateURL(ctx, shortCode); err != nil {
+		slog.ErrorContext(ctx, "failed to invalidate cached link", "code", shortCode, "error", err)
+	}
+
+	if customShortCode.Valid {
+		if err := cacheService.InvalidateURL(ctx, customShortCode.String); err != nil {
+			slog.ErrorContext(ctx, "failed to invalidate cached link", "code", customShortCode.String, "error", err)
+		}
+	}
 }
@@ -0,0 +1,115 @@
+package routes
+
+import (
+	"github.com/andriawan24/link-short/internal/models/requests"
+	"github.com/andriawan24/link-short/internal/models/responses"
+	"github.com/andriawan24/link-short/internal/services"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type publicStatsRoutes struct {
+	publicStatsService services.PublicStatsService
+}
+
+func NewPublicStatsRoutes(publicStatsService services.PublicStatsService) publicStatsRoutes {
+	return publicStatsRoutes{
+		publicStatsService: publicStatsService,
+	}
+}
+
+// SetPublicStats godoc
+// @Summary      Turn public statistics of a link on or off
+// @Description  While enabled, anyone with the share token can read the click statistics of the link at /stats/{token}. The token is created the first time and kept when public statistics are turned off and on again.
+// @Tags         Links
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                     true  "Link ID (UUID)"
+// @Param        request  body      requests.PublicStatsParam  true  "Whether the statistics are public"
+// @Success      200  {object}  responses.BaseResponse{data=responses.PublicStatsSettingsResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /links/{id}/public-stats [put]
+func (r *publicStatsRoutes) SetPublicStats(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+
+	linkId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	var body requests.PublicStatsParam
+
+	err = ctx.ShouldBindJSON(&body)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	link, err := r.publicStatsService.SetEnabled(ctx.Request.Context(), userId, linkId, *body.Enabled)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	utils.RespondOK(ctx, "successfully update public stats", responses.MapPublicStatsSettings(link))
+}
+
+// RotatePublicStatsToken godoc
+// @Summary      Replace the share token of a link
+// @Description  Issues a new share token for the public statistics of a link. Statistics shared under the previous token stop being available.
+// @Tags         Links
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Link ID (UUID)"
+// @Success      200  {object}  responses.BaseResponse{data=responses.PublicStatsSettingsResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /links/{id}/public-stats/rotate [post]
+func (r *publicStatsRoutes) RotatePublicStatsToken(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+
+	linkId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	link, err := r.publicStatsService.RotateToken(ctx.Request.Context(), userId, linkId)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	utils.RespondOK(ctx, "successfully rotate share token", responses.MapPublicStatsSettings(link))
+}
+
+// GetPublicStats godoc
+// @Summary      Get public statistics of a link
+// @Description  Read-only click statistics of a link whose owner made them public: total clicks, clicks per day, devices and top countries. No IP addresses, user agents or referrers are included. Responses are cached for a few minutes.
+// @Tags         Public Stats
+// @Produce      json
+// @Param        token  path      string  true   "Share token"
+// @Param        range  query     string  false  "Time range"  Enums(7d, 30d, 90d, all)  default(all)
+// @Success      200  {object}  responses.BaseResponse{data=responses.PublicLinkStatsResponse}
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /stats/{token} [get]
+func (r *publicStatsRoutes) GetPublicStats(ctx *gin.Context) {
+	timeRange := utils.ParseTimeRange(ctx.DefaultQuery("range", string(utils.TimeRangeAll)))
+
+	stats, err := r.publicStatsService.GetStats(ctx.Request.Context(), ctx.Param("token"), timeRange)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	utils.RespondOK(ctx, "successfully get public stats", stats)
+}
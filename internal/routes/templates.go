@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"embed"
+	"html/template"
+)
+
+//go:embed templates/*.html
+var templateFiles embed.FS
+
+// pageTemplates holds the HTML pages served to visitors of short links,
+// looked up by file name.
+var pageTemplates = template.Must(template.ParseFS(templateFiles, "templates/*.html"))
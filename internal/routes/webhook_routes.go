@@ -0,0 +1,271 @@
+package routes
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/andriawan24/link-short/internal/models/requests"
+	"github.com/andriawan24/link-short/internal/models/responses"
+	"github.com/andriawan24/link-short/internal/services"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const errPrivateWebhookURL = "url must point to a public host"
+
+type webhookRoutes struct {
+	webhookService services.WebhookService
+}
+
+func NewWebhookRoutes(webhookService services.WebhookService) webhookRoutes {
+	return webhookRoutes{
+		webhookService: webhookService,
+	}
+}
+
+// InsertWebhook godoc
+// @Summary      Register a webhook
+// @Description  Register an endpoint to receive signed link and click events. The signing secret is only returned once. Localhost, private and link-local addresses are rejected.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body requests.InsertWebhookParam true "Webhook details"
+// @Success      201  {object}  responses.BaseResponse{data=responses.WebhookResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /webhooks [post]
+func (r *webhookRoutes) InsertWebhook(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+
+	var body requests.InsertWebhookParam
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	if target, err := url.Parse(body.URL); err != nil || !utils.IsPublicHost(target.Hostname()) {
+		utils.RespondBadRequest(ctx, errPrivateWebhookURL)
+		return
+	}
+
+	webhook, err := r.webhookService.InsertWebhook(ctx.Request.Context(), userId, body.URL, body.Events)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	response := responses.MapWebhookResponse(webhook)
+	response.Secret = webhook.Secret
+
+	utils.ResponsdJson(ctx, http.StatusCreated, "successfully insert new webhook", response)
+}
+
+// GetWebhooks godoc
+// @Summary      Get all webhooks
+// @Description  Get all webhooks registered by the authenticated user
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  responses.BaseResponse{data=[]responses.WebhookResponse}
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /webhooks [get]
+func (r *webhookRoutes) GetWebhooks(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+
+	webhooks, err := r.webhookService.GetWebhooks(ctx.Request.Context(), userId)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	utils.RespondOK(ctx, "successfully get webhooks", responses.MapWebhookResponses(webhooks))
+}
+
+// DeleteWebhook godoc
+// @Summary      Delete a webhook
+// @Description  Stop delivering events to a webhook
+// @Tags         Webhooks
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Webhook ID (UUID)"
+// @Success      204  {string}  string  "No Content"
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /webhooks/{id} [delete]
+func (r *webhookRoutes) DeleteWebhook(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+
+	webhookId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	webhook, err := r.webhookService.GetWebhook(ctx.Request.Context(), userId, webhookId)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	err = r.webhookService.DeleteWebhook(ctx.Request.Context(), userId, webhook.ID)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	utils.ResponsdJson(ctx, http.StatusNoContent, "successfully delete webhook", nil)
+}
+
+// EnableWebhook godoc
+// @Summary      Enable a webhook
+// @Description  Resume deliveries to a webhook that was disabled after a delivery kept failing. Deliveries queued while it was disabled are sent.
+// @Tags         Webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Webhook ID (UUID)"
+// @Success      200  {object}  responses.BaseResponse{data=responses.WebhookResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /webhooks/{id}/enable [post]
+func (r *webhookRoutes) EnableWebhook(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+
+	webhookId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	webhook, err := r.webhookService.EnableWebhook(ctx.Request.Context(), userId, webhookId)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	utils.RespondOK(ctx, "successfully enable webhook", responses.MapWebhookResponse(webhook))
+}
+
+// GetDeliveries godoc
+// @Summary      Get webhook deliveries
+// @Description  Get the delivery log of a webhook, newest first
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id     path      string  true   "Webhook ID (UUID)"
+// @Param        page   query     int     false  "Page number"     default(1)
+// @Param        limit  query     int     false  "Items per page, at most 100"  default(20)
+// @Success      200  {object}  responses.BaseResponse{data=[]responses.WebhookDeliveryResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /webhooks/{id}/deliveries [get]
+func (r *webhookRoutes) GetDeliveries(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+	var (
+		page  = 1
+		limit = 20
+		err   error
+	)
+
+	webhookId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	if ctx.Query("page") != "" {
+		page, err = strconv.Atoi(ctx.Query("page"))
+		if err != nil {
+			utils.HandleErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	if ctx.Query("limit") != "" {
+		limit, err = strconv.Atoi(ctx.Query("limit"))
+		if err != nil {
+			utils.HandleErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	if page < 1 {
+		utils.RespondBadRequest(ctx, "page must be at least 1")
+		return
+	}
+	limit = min(max(limit, 1), 100)
+
+	webhook, err := r.webhookService.GetWebhook(ctx.Request.Context(), userId, webhookId)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	offset := (page - 1) * limit
+
+	deliveries, err := r.webhookService.GetDeliveries(ctx.Request.Context(), webhook.ID, int32(limit), int32(offset))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	utils.RespondOK(ctx, "successfully get webhook deliveries", responses.MapWebhookDeliveryResponses(deliveries))
+}
+
+// Redeliver godoc
+// @Summary      Redeliver a webhook delivery
+// @Description  Queue a delivery to be sent again, regardless of its previous outcome
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id           path      string  true  "Webhook ID (UUID)"
+// @Param        deliveryId   path      string  true  "Delivery ID (UUID)"
+// @Success      200  {object}  responses.BaseResponse{data=responses.WebhookDeliveryResponse}
+// @Failure      400  {object}  responses.ErrorResponse
+// @Failure      401  {object}  responses.ErrorResponse
+// @Failure      404  {object}  responses.ErrorResponse
+// @Failure      500  {object}  responses.ErrorResponse
+// @Router       /webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (r *webhookRoutes) Redeliver(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(uuid.UUID)
+
+	webhookId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	deliveryId, err := uuid.Parse(ctx.Param("deliveryId"))
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	webhook, err := r.webhookService.GetWebhook(ctx.Request.Context(), userId, webhookId)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	delivery, err := r.webhookService.Redeliver(ctx.Request.Context(), webhook.ID, deliveryId)
+	if err != nil {
+		utils.HandleErrorResponse(ctx, err)
+		return
+	}
+
+	utils.RespondOK(ctx, "successfully queue webhook redelivery", responses.MapWebhookDeliveryResponse(delivery))
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository"
+)
+
+const (
+	accountPurgePollInterval = 10 * time.Minute
+	accountPurgeBatchSize    = 20
+)
+
+type accountPurgeWorker struct {
+	queries             repository.AccountDeletionRepository
+	profileImageService ProfileImageService
+	store               BlobStore
+	cacheService        CacheService
+	gracePeriod         time.Duration
+}
+
+type AccountPurgeWorker interface {
+	Run(ctx context.Context)
+	// Purge permanently deletes a soft deleted account and everything it
+	// owns, except audit events where the user only acted on others.
+	Purge(ctx context.Context, user database.User) error
+}
+
+// NewAccountPurgeWorker erases accounts that were deleted more than
+// gracePeriod ago.
+func NewAccountPurgeWorker(queries repository.AccountDeletionRepository, profileImageService ProfileImageService, store BlobStore, cacheService CacheService, gracePeriod time.Duration) AccountPurgeWorker {
+	return &accountPurgeWorker{
+		queries:             queries,
+		profileImageService: profileImageService,
+		store:               store,
+		cacheService:        cacheService,
+		gracePeriod:         gracePeriod,
+	}
+}
+
+// Run purges due accounts every poll interval until ctx is done. A purge
+// that fails halfway is retried on the next poll, so every step tolerates
+// having already run.
+func (w *accountPurgeWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(accountPurgePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.purgeDue(ctx)
+		}
+	}
+}
+
+func (w *accountPurgeWorker) purgeDue(ctx context.Context) {
+	users, err := w.queries.GetDueAccountDeletions(ctx, database.GetDueAccountDeletionsParams{
+		DeletedBefore: time.Now().Add(-w.gracePeriod),
+		BatchSize:     accountPurgeBatchSize,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load accounts due for deletion", "error", err)
+		return
+	}
+
+	for _, user := range users {
+		if err := w.Purge(ctx, user); err != nil {
+			slog.ErrorContext(ctx, "failed to purge account", "user_id", user.ID, "error", err)
+		}
+	}
+}
+
+func (w *accountPurgeWorker) Purge(ctx context.Context, user database.User) error {
+	codes, err := w.queries.GetUserLinkCodes(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	// Click logs only reference links by code, so they do not cascade.
+	clicks, err := w.queries.DeleteUserClickLogs(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if user.ProfileImageUrl.Valid {
+		if err := w.profileImageService.Delete(ctx, user.ProfileImageUrl.String); err != nil {
+			return err
+		}
+	}
+
+	exportKeys, err := w.queries.GetUserDataExportBlobKeys(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	for _, key := range exportKeys {
+		if err := w.store.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	auditEvents, err := w.queries.PurgeAuditEvents(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	// Links, tokens, webhooks, login attempts and exports cascade.
+	if _, err := w.queries.PurgeUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	for _, code := range codes {
+		w.invalidate(ctx, code.ShortCode)
+		if code.CustomShortCode.Valid {
+			w.invalidate(ctx, code.CustomShortCode.String)
+		}
+	}
+
+	slog.InfoContext(ctx, "purged deleted account", "user_id", user.ID, "links", len(codes), "click_logs", clicks, "audit_events", auditEvents)
+	return nil
+}
+
+func (w *accountPurgeWorker) invalidate(ctx context.Context, code string) {
+	if err := w.cacheService.InvalidateURL(ctx, code); err != nil {
+		slog.WarnContext(ctx, "failed to invalidate cached redirect", "code", code, "error", err)
+	}
+}
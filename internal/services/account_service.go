@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	tokenPurposeEmailVerification = "email_verification"
+	tokenPurposePasswordReset     = "password_reset"
+	tokenPurposeAccountRestore    = "account_restore"
+
+	emailVerificationTTL = 24 * time.Hour
+	passwordResetTTL     = time.Hour
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+type accountService struct {
+	queries             repository.UserRepository
+	mailer              Mailer
+	baseURL             string
+	deletionGracePeriod time.Duration
+}
+
+type AccountService interface {
+	SendEmailVerification(ctx context.Context, user database.User) error
+	VerifyEmail(ctx context.Context, token string) (database.User, error)
+	SendPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token string, passwordHash string) (uuid.UUID, error)
+	// RequestDeletion soft deletes the account and emails a link that
+	// restores it. The account is purged once the grace period, reported as
+	// purgeAfter, is over.
+	RequestDeletion(ctx context.Context, user database.User) (purgeAfter time.Time, err error)
+	RestoreAccount(ctx context.Context, token string) (database.User, error)
+}
+
+// NewAccountService builds the email verification, password reset and
+// account deletion flows. baseURL is the frontend origin the emailed links
+// point to.
+func NewAccountService(queries repository.UserRepository, mailer Mailer, baseURL string, deletionGracePeriod time.Duration) AccountService {
+	return &accountService{
+		queries:             queries,
+		mailer:              mailer,
+		baseURL:             baseURL,
+		deletionGracePeriod: deletionGracePeriod,
+	}
+}
+
+func (s *accountService) SendEmailVerification(ctx context.Context, user database.User) error {
+	token, err := s.issueToken(ctx, user.ID, tokenPurposeEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, MailMessage{
+		To:      user.Email,
+		Subject: "Verify your Pendek.in email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nThe link expires in 24 hours.\n",
+			user.Name, s.link("/verify-email", token),
+		),
+	})
+}
+
+func (s *accountService) VerifyEmail(ctx context.Context, token string) (database.User, error) {
+	userToken, err := s.consumeToken(ctx, token, tokenPurposeEmailVerification)
+	if err != nil {
+		return database.User{}, err
+	}
+
+	user, err := s.queries.MarkUserVerified(ctx, userToken.UserID)
+	if err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+// SendPasswordReset emails a reset link if the address belongs to an account.
+// Unknown addresses are ignored so the endpoint cannot be used to probe for
+// registered emails.
+func (s *accountService) SendPasswordReset(ctx context.Context, email string) error {
+	user, err := s.queries.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := s.issueToken(ctx, user.ID, tokenPurposePasswordReset, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, MailMessage{
+		To:      user.Email,
+		Subject: "Reset your Pendek.in password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nSomeone asked to reset the password of your account. If it was you, open the link below:\n\n%s\n\nThe link expires in 1 hour. If you did not ask for this, you can ignore this email.\n",
+			user.Name, s.link("/reset-password", token),
+		),
+	})
+}
+
+func (s *accountService) ResetPassword(ctx context.Context, token string, passwordHash string) (uuid.UUID, error) {
+	userToken, err := s.consumeToken(ctx, token, tokenPurposePasswordReset)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	err = s.queries.UpdateUserPassword(ctx, database.UpdateUserPasswordParams{
+		ID:           userToken.UserID,
+		PasswordHash: sql.NullString{String: passwordHash, Valid: true},
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	err = s.queries.InvalidateUserTokens(ctx, database.InvalidateUserTokensParams{
+		UserID:  userToken.UserID,
+		Purpose: tokenPurposePasswordReset,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return userToken.UserID, nil
+}
+
+func (s *accountService) RequestDeletion(ctx context.Context, user database.User) (time.Time, error) {
+	// The token has to exist before the user row is hidden by deleted_at.
+	token, err := s.issueToken(ctx, user.ID, tokenPurposeAccountRestore, s.deletionGracePeriod)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	deleted, err := s.queries.SoftDeleteUser(ctx, user.ID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	purgeAfter := deleted.DeletedAt.Time.Add(s.deletionGracePeriod)
+
+	err = s.mailer.Send(ctx, MailMessage{
+		To:      user.Email,
+		Subject: "Your Pendek.in account will be deleted",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nYour account has been scheduled for deletion. On %s your links, click history, uploads and account details will be erased for good.\n\nIf you change your mind before then, open the link below to keep your account:\n\n%s\n",
+			user.Name, purgeAfter.UTC().Format("2 January 2006 15:04 MST"), s.link("/restore-account", token),
+		),
+	})
+	if err != nil {
+		// The deletion stands; the user just has no emailed way back.
+		slog.ErrorContext(ctx, "failed to send account deletion email", "user_id", user.ID, "error", err)
+	}
+
+	return purgeAfter, nil
+}
+
+func (s *accountService) RestoreAccount(ctx context.Context, token string) (database.User, error) {
+	userToken, err := s.consumeToken(ctx, token, tokenPurposeAccountRestore)
+	if err != nil {
+		return database.User{}, err
+	}
+
+	user, err := s.queries.RestoreUser(ctx, userToken.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return user, ErrInvalidToken
+	}
+	return user, err
+}
+
+// issueToken replaces any outstanding token of the same purpose so only the
+// most recently emailed link works.
+func (s *accountService) issueToken(ctx context.Context, userId uuid.UUID, purpose string, ttl time.Duration) (string, error) {
+	err := s.queries.InvalidateUserTokens(ctx, database.InvalidateUserTokensParams{
+		UserID:  userId,
+		Purpose: purpose,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	token, tokenHash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = s.queries.InsertUserToken(ctx, database.InsertUserTokenParams{
+		UserID:    userId,
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (s *accountService) consumeToken(ctx context.Context, token string, purpose string) (database.UserToken, error) {
+	userToken, err := s.queries.ConsumeUserToken(ctx, database.ConsumeUserTokenParams{
+		TokenHash: utils.HashOpaqueToken(token),
+		Purpose:   purpose,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return userToken, ErrInvalidToken
+		}
+		return userToken, err
+	}
+
+	return userToken, nil
+}
+
+func (s *accountService) link(path string, token string) string {
+	return s.baseURL + path + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository"
+	"github.com/google/uuid"
+)
+
+const maxPlatformGrowthRange = 365 * 24 * time.Hour
+
+type adminService struct {
+	queries repository.AdminRepository
+}
+
+type AdminService interface {
+	GetUsers(ctx context.Context, search string, isActive sql.NullBool, limit int32, offset int32) ([]database.AdminGetUsersRow, error)
+	SetUserActive(ctx context.Context, userId uuid.UUID, active bool) (database.User, error)
+	SetUserRole(ctx context.Context, userId uuid.UUID, role string) (database.User, error)
+	PromoteAdmins(ctx context.Context, emails []string) error
+	GetLinks(ctx context.Context, search string, takenDownOnly bool, limit int32, offset int32) ([]database.AdminGetLinksRow, error)
+	GetLink(ctx context.Context, linkId uuid.UUID) (database.AdminGetLinkRow, error)
+	TakeDownLink(ctx context.Context, linkId uuid.UUID, adminId uuid.UUID, reason string) (database.Link, error)
+	RestoreLink(ctx context.Context, linkId uuid.UUID) (database.Link, error)
+	GetPlatformGrowth(ctx context.Context, from time.Time, to time.Time) ([]database.GetPlatformGrowthRow, error)
+}
+
+func NewAdminService(queries repository.AdminRepository) AdminService {
+	return &adminService{
+		queries: queries,
+	}
+}
+
+func (s *adminService) GetUsers(ctx context.Context, search string, isActive sql.NullBool, limit int32, offset int32) ([]database.AdminGetUsersRow, error) {
+	users, err := s.queries.AdminGetUsers(ctx, database.AdminGetUsersParams{
+		Search:   strings.TrimSpace(search),
+		IsActive: isActive,
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return users, err
+	}
+
+	return users, nil
+}
+
+func (s *adminService) SetUserActive(ctx context.Context, userId uuid.UUID, active bool) (database.User, error) {
+	user, err := s.queries.SetUserActive(ctx, database.SetUserActiveParams{
+		ID:       userId,
+		IsActive: active,
+	})
+	if err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+func (s *adminService) SetUserRole(ctx context.Context, userId uuid.UUID, role string) (database.User, error) {
+	user, err := s.queries.SetUserRole(ctx, database.SetUserRoleParams{
+		ID:   userId,
+		Role: role,
+	})
+	if err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+// PromoteAdmins grants the admin role to existing accounts with one of the
+// given emails. It is used to bootstrap the first administrators.
+func (s *adminService) PromoteAdmins(ctx context.Context, emails []string) error {
+	if len(emails) == 0 {
+		return nil
+	}
+
+	normalized := make([]string, len(emails))
+	for i, email := range emails {
+		normalized[i] = strings.ToLower(strings.TrimSpace(email))
+	}
+
+	return s.queries.PromoteUsersToAdmin(ctx, normalized)
+}
+
+func (s *adminService) GetLinks(ctx context.Context, search string, takenDownOnly bool, limit int32, offset int32) ([]database.AdminGetLinksRow, error) {
+	links, err := s.queries.AdminGetLinks(ctx, database.AdminGetLinksParams{
+		Search:        strings.TrimSpace(search),
+		TakenDownOnly: takenDownOnly,
+		Limit:         limit,
+		Offset:        offset,
+	})
+	if err != nil {
+		return links, err
+	}
+
+	return links, nil
+}
+
+func (s *adminService) GetLink(ctx context.Context, linkId uuid.UUID) (database.AdminGetLinkRow, error) {
+	link, err := s.queries.AdminGetLink(ctx, linkId)
+	if err != nil {
+		return link, err
+	}
+
+	return link, nil
+}
+
+func (s *adminService) TakeDownLink(ctx context.Context, linkId uuid.UUID, adminId uuid.UUID, reason string) (database.Link, error) {
+	link, err := s.queries.TakeDownLink(ctx, database.TakeDownLinkParams{
+		ID:             linkId,
+		TakedownReason: sql.NullString{String: reason, Valid: true},
+		TakenDownBy:    uuid.NullUUID{UUID: adminId, Valid: true},
+	})
+	if err != nil {
+		return link, err
+	}
+
+	return link, nil
+}
+
+func (s *adminService) RestoreLink(ctx context.Context, linkId uuid.UUID) (database.Link, error) {
+	link, err := s.queries.RestoreLink(ctx, linkId)
+	if err != nil {
+		return link, err
+	}
+
+	return link, nil
+}
+
+// GetPlatformGrowth returns one row per day. The range is capped at a year
+// so an "all time" request does not generate a series from year one.
+func (s *adminService) GetPlatformGrowth(ctx context.Context, from time.Time, to time.Time) ([]database.GetPlatformGrowthRow, error) {
+	if to.Sub(from) > maxPlatformGrowthRange {
+		from = to.Add(-maxPlatformGrowthRange)
+	}
+
+	rows, err := s.queries.GetPlatformGrowth(ctx, database.GetPlatformGrowthParams{
+		FromDate: from,
+		ToDate:   to,
+	})
+	if err != nil {
+		return rows, err
+	}
+
+	return rows, nil
+}
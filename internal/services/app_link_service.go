@@ -0,0 +1,86 @@
+package services
+
+import (
+	"time"
+
+	"github.com/andriawan24/link-short/internal/models/responses"
+)
+
+const assetLinkRelation = "delegate_permission/common.handle_all_urls"
+
+type AppLinkOptions struct {
+	// IOSAppIDs are "<team ID>.<bundle ID>" pairs, opened for IOSPaths.
+	IOSAppIDs []string
+	IOSPaths  []string
+	// AndroidPackages are all signed with AndroidCertFingerprints.
+	AndroidPackages         []string
+	AndroidCertFingerprints []string
+	// FallbackDelay is how long the in-app browser page waits for the app
+	// before it leaves for the fallback URL.
+	FallbackDelay time.Duration
+}
+
+type appLinkService struct {
+	options AppLinkOptions
+}
+
+type AppLinkService interface {
+	// AppleAppSiteAssociation returns false when no iOS app is configured.
+	AppleAppSiteAssociation() (responses.AppleAppSiteAssociation, bool)
+	// AssetLinks returns false when no Android app is configured.
+	AssetLinks() ([]responses.AssetLinkStatement, bool)
+	FallbackDelay() time.Duration
+}
+
+// NewAppLinkService publishes the apps that may open short links directly,
+// so links tapped in Safari or Chrome skip the browser altogether.
+func NewAppLinkService(options AppLinkOptions) AppLinkService {
+	return &appLinkService{
+		options: options,
+	}
+}
+
+func (s *appLinkService) AppleAppSiteAssociation() (responses.AppleAppSiteAssociation, bool) {
+	if len(s.options.IOSAppIDs) == 0 {
+		return responses.AppleAppSiteAssociation{}, false
+	}
+
+	components := make([]map[string]string, 0, len(s.options.IOSPaths))
+	for _, path := range s.options.IOSPaths {
+		components = append(components, map[string]string{"/": path})
+	}
+
+	return responses.AppleAppSiteAssociation{
+		AppLinks: responses.AppleAppLinks{
+			Apps: []string{},
+			Details: []responses.AppleAppLinkDetail{{
+				AppIDs:     s.options.IOSAppIDs,
+				Components: components,
+			}},
+		},
+	}, true
+}
+
+func (s *appLinkService) AssetLinks() ([]responses.AssetLinkStatement, bool) {
+	if len(s.options.AndroidPackages) == 0 {
+		return nil, false
+	}
+
+	statements := make([]responses.AssetLinkStatement, 0, len(s.options.AndroidPackages))
+	for _, pkg := range s.options.AndroidPackages {
+		statements = append(statements, responses.AssetLinkStatement{
+			Relation: []string{assetLinkRelation},
+			Target: responses.AssetLinkTarget{
+				Namespace:              "android_app",
+				PackageName:            pkg,
+				SHA256CertFingerprints: s.options.AndroidCertFingerprints,
+			},
+		})
+	}
+
+	return statements, true
+}
+
+func (s *appLinkService) FallbackDelay() time.Duration {
+	return s.options.FallbackDelay
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/google/uuid"
+)
+
+// AuditEntry describes a single change. OwnerID is the account whose audit
+// log shows the event, ActorID whoever made the change, which differs from
+// the owner for admin actions. Before and After are marshalled to JSON and
+// only the fields that differ end up in the stored diff.
+type AuditEntry struct {
+	OwnerID    uuid.UUID
+	ActorID    uuid.NullUUID
+	Action     utils.AuditAction
+	TargetType string
+	TargetID   uuid.NullUUID
+	Before     any
+	After      any
+	IPAddress  string
+	UserAgent  string
+}
+
+type AuditFilter struct {
+	TargetType string
+	TargetID   uuid.NullUUID
+	Action     string
+}
+
+type LinkAuditSnapshot struct {
+	OriginalURL     string     `json:"original_url"`
+	ShortCode       string     `json:"short_code"`
+	CustomShortCode *string    `json:"custom_short_code"`
+	ExpiredAt       *time.Time `json:"expired_at"`
+}
+
+func NewLinkAuditSnapshot(originalURL string, shortCode string, customShortCode sql.NullString, expiredAt sql.NullTime) LinkAuditSnapshot {
+	snapshot := LinkAuditSnapshot{
+		OriginalURL: originalURL,
+		ShortCode:   shortCode,
+	}
+
+	if customShortCode.Valid {
+		snapshot.CustomShortCode = &customShortCode.String
+	}
+
+	if expiredAt.Valid {
+		snapshot.ExpiredAt = &expiredAt.Time
+	}
+
+	return snapshot
+}
+
+// UserAuditSnapshot never carries the password hash, only whether the
+// password was changed.
+type UserAuditSnapshot struct {
+	Name            string `json:"name"`
+	Email           string `json:"email"`
+	IsVerified      bool   `json:"is_verified"`
+	ProfileImageURL string `json:"profile_image_url"`
+	PasswordChanged bool   `json:"password_changed,omitempty"`
+}
+
+func NewUserAuditSnapshot(user database.User) UserAuditSnapshot {
+	return UserAuditSnapshot{
+		Name:            user.Name,
+		Email:           user.Email,
+		IsVerified:      user.IsVerified,
+		ProfileImageURL: user.ProfileImageUrl.String,
+	}
+}
+
+type auditService struct {
+	queries repository.AuditRepository
+}
+
+type AuditService interface {
+	Record(ctx context.Context, entry AuditEntry) error
+	GetEvents(ctx context.Context, ownerId uuid.UUID, filter AuditFilter, limit int32, offset int32) ([]database.AuditEvent, error)
+}
+
+func NewAuditService(queries repository.AuditRepository) AuditService {
+	return &auditService{
+		queries: queries,
+	}
+}
+
+func (s *auditService) Record(ctx context.Context, entry AuditEntry) error {
+	changes, err := diffAuditValues(entry.Before, entry.After)
+	if err != nil {
+		return err
+	}
+
+	return s.queries.InsertAuditEvent(ctx, database.InsertAuditEventParams{
+		OwnerID:    entry.OwnerID,
+		ActorID:    entry.ActorID,
+		Action:     string(entry.Action),
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Changes:    changes,
+		IpAddress:  sql.NullString{String: entry.IPAddress, Valid: entry.IPAddress != ""},
+		UserAgent:  sql.NullString{String: truncateAuditValue(entry.UserAgent, 512), Valid: entry.UserAgent != ""},
+	})
+}
+
+func (s *auditService) GetEvents(ctx context.Context, ownerId uuid.UUID, filter AuditFilter, limit int32, offset int32) ([]database.AuditEvent, error) {
+	events, err := s.queries.GetAuditEvents(ctx, database.GetAuditEventsParams{
+		OwnerID:    ownerId,
+		TargetType: sql.NullString{String: filter.TargetType, Valid: filter.TargetType != ""},
+		TargetID:   filter.TargetID,
+		Action:     sql.NullString{String: filter.Action, Valid: filter.Action != ""},
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		return events, err
+	}
+
+	return events, nil
+}
+
+type auditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// diffAuditValues returns {"field": {"before": x, "after": y}} for every
+// top-level field whose value differs between before and after.
+func diffAuditValues(before any, after any) (json.RawMessage, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]auditChange)
+	for key, value := range beforeFields {
+		if !reflect.DeepEqual(value, afterFields[key]) {
+			changes[key] = auditChange{Before: value, After: afterFields[key]}
+		}
+	}
+	for key, value := range afterFields {
+		if _, ok := beforeFields[key]; !ok && value != nil {
+			changes[key] = auditChange{After: value}
+		}
+	}
+
+	return json.Marshal(changes)
+}
+
+func auditFields(value any) (map[string]any, error) {
+	fields := make(map[string]any)
+	if value == nil {
+		return fields, nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+func truncateAuditValue(value string, maxLength int) string {
+	if len(value) > maxLength {
+		return value[:maxLength]
+	}
+	return value
+}
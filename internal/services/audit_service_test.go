@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/repository/memory"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/google/uuid"
+)
+
+func TestDiffAuditValues(t *testing.T) {
+	expiredAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	link := NewLinkAuditSnapshot("https://example.test/a", "abc123", sql.NullString{}, sql.NullTime{})
+
+	for _, tc := range []struct {
+		name   string
+		before any
+		after  any
+		want   string
+	}{
+		{"nothing", nil, nil, `{}`},
+		{"unchanged", link, link, `{}`},
+		{
+			name:   "changed field",
+			before: link,
+			after:  NewLinkAuditSnapshot("https://example.test/b", "abc123", sql.NullString{}, sql.NullTime{}),
+			want:   `{"original_url":{"before":"https://example.test/a","after":"https://example.test/b"}}`,
+		},
+		{
+			name:   "field set",
+			before: link,
+			after:  NewLinkAuditSnapshot("https://example.test/a", "abc123", sql.NullString{String: "promo", Valid: true}, sql.NullTime{Time: expiredAt, Valid: true}),
+			want:   `{"custom_short_code":{"before":null,"after":"promo"},"expired_at":{"before":null,"after":"2026-01-02T03:04:05Z"}}`,
+		},
+		{
+			name:   "created",
+			before: nil,
+			after:  map[string]any{"name": "Ada", "empty": nil},
+			want:   `{"name":{"before":null,"after":"Ada"}}`,
+		},
+		{
+			name:   "deleted",
+			before: map[string]any{"name": "Ada"},
+			after:  nil,
+			want:   `{"name":{"before":"Ada","after":null}}`,
+		},
+		{
+			name:   "nested value",
+			before: map[string]any{"events": []string{"link.created"}, "active": true},
+			after:  map[string]any{"events": []string{"link.created", "link.deleted"}, "active": true},
+			want:   `{"events":{"before":["link.created"],"after":["link.created","link.deleted"]}}`,
+		},
+		{
+			name:   "password change without the hash",
+			before: UserAuditSnapshot{Name: "Ada", Email: "ada@example.com"},
+			after:  UserAuditSnapshot{Name: "Ada", Email: "ada@example.com", PasswordChanged: true},
+			want:   `{"password_changed":{"before":null,"after":true}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := diffAuditValues(tc.before, tc.after)
+			if err != nil {
+				t.Fatalf("diffAuditValues: %v", err)
+			}
+			if !jsonEqual(t, got, tc.want) {
+				t.Fatalf("diffAuditValues = %s, want %s", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := diffAuditValues(nil, "not an object"); err == nil {
+		t.Fatal("diffAuditValues of a string succeeded, want an error")
+	}
+}
+
+func TestAuditRecord(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	service := NewAuditService(store)
+
+	ownerId := uuid.New()
+	linkId := uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	err := service.Record(ctx, AuditEntry{
+		OwnerID:    ownerId,
+		ActorID:    uuid.NullUUID{UUID: ownerId, Valid: true},
+		Action:     utils.AuditActionLinkUpdated,
+		TargetType: utils.AuditTargetLink,
+		TargetID:   linkId,
+		Before:     NewLinkAuditSnapshot("https://example.test/a", "abc123", sql.NullString{}, sql.NullTime{}),
+		After:      NewLinkAuditSnapshot("https://example.test/b", "abc123", sql.NullString{}, sql.NullTime{}),
+		IPAddress:  "192.0.2.1",
+		UserAgent:  strings.Repeat("x", 600),
+	})
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if err := service.Record(ctx, AuditEntry{OwnerID: ownerId, Action: utils.AuditActionLogin, TargetType: utils.AuditTargetUser}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	events, err := service.GetEvents(ctx, ownerId, AuditFilter{TargetType: utils.AuditTargetLink, TargetID: linkId}, 10, 0)
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("GetEvents returned %d events, want the link update only", len(events))
+	}
+
+	event := events[0]
+	if event.Action != string(utils.AuditActionLinkUpdated) || event.IpAddress.String != "192.0.2.1" || len(event.UserAgent.String) != 512 {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+	if want := `{"original_url":{"before":"https://example.test/a","after":"https://example.test/b"}}`; !jsonEqual(t, event.Changes, want) {
+		t.Fatalf("changes = %s, want %s", event.Changes, want)
+	}
+
+	if events, _ := service.GetEvents(ctx, uuid.New(), AuditFilter{}, 10, 0); len(events) != 0 {
+		t.Fatalf("another owner sees %d events", len(events))
+	}
+}
+
+func jsonEqual(t *testing.T, got []byte, want string) bool {
+	t.Helper()
+
+	var gotValue, wantValue any
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("invalid JSON %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("invalid JSON %s: %v", want, err)
+	}
+
+	gotJSON, _ := json.Marshal(gotValue)
+	wantJSON, _ := json.Marshal(wantValue)
+	return string(gotJSON) == string(wantJSON)
+}
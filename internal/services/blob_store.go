@@ -0,0 +1,349 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore keeps uploaded objects such as profile images and data exports.
+// Keys are slash-separated paths like "profiles/<user>/<id>/256.jpg".
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get returns ErrBlobNotFound for a missing key.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL returns the public URL the object is served from.
+	URL(key string) string
+	// KeyFromURL reverses URL. It reports false for URLs this store did not
+	// produce, such as avatars hosted by an OAuth provider.
+	KeyFromURL(rawURL string) (string, bool)
+}
+
+type localBlobStore struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalBlobStore writes objects below dir. They are expected to be served
+// under baseURL, e.g. with gin's Static handler.
+func NewLocalBlobStore(dir, baseURL string) BlobStore {
+	return &localBlobStore{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (s *localBlobStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filePath)
+}
+
+func (s *localBlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	filePath, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+	return data, err
+}
+
+func (s *localBlobStore) Delete(ctx context.Context, key string) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *localBlobStore) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+func (s *localBlobStore) KeyFromURL(rawURL string) (string, bool) {
+	return trimBlobURL(rawURL, s.baseURL)
+}
+
+// path maps key into dir, refusing keys that would escape it.
+func (s *localBlobStore) path(key string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(key)) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+type S3Options struct {
+	// Endpoint is the scheme and host of the S3 API, e.g.
+	// https://s3.eu-west-1.amazonaws.com or http://localhost:9000 for MinIO.
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle addresses the bucket as endpoint/bucket/key instead of
+	// bucket.endpoint/key. Most self-hosted S3-compatible servers need it.
+	PathStyle bool
+	// PublicURL is where objects are read from, typically a CDN in front of
+	// the bucket. Defaults to the bucket URL.
+	PublicURL string
+}
+
+type s3BlobStore struct {
+	options   S3Options
+	bucketURL *url.URL
+	publicURL string
+	client    *http.Client
+	now       func() time.Time
+}
+
+// NewS3BlobStore talks to an S3-compatible API with requests signed using
+// AWS Signature Version 4. options.Endpoint must be an absolute URL. Objects
+// must be publicly readable through PublicURL, usually via a bucket policy.
+func NewS3BlobStore(options S3Options, client *http.Client) BlobStore {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	bucketURL := url.URL{}
+	if endpoint, err := url.Parse(strings.TrimSuffix(options.Endpoint, "/")); err == nil {
+		bucketURL = *endpoint
+	}
+	if options.PathStyle {
+		bucketURL.Path += "/" + options.Bucket
+	} else {
+		bucketURL.Host = options.Bucket + "." + bucketURL.Host
+	}
+
+	publicURL := strings.TrimSuffix(options.PublicURL, "/")
+	if publicURL == "" {
+		publicURL = bucketURL.String()
+	}
+
+	return &s3BlobStore{
+		options:   options,
+		bucketURL: &bucketURL,
+		publicURL: publicURL,
+		client:    client,
+		now:       time.Now,
+	}
+}
+
+func (s *s3BlobStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	_, err := s.do(ctx, http.MethodPut, key, header, data)
+	return err
+}
+
+func (s *s3BlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return s.do(ctx, http.MethodGet, key, http.Header{}, nil)
+}
+
+func (s *s3BlobStore) Delete(ctx context.Context, key string) error {
+	_, err := s.do(ctx, http.MethodDelete, key, http.Header{}, nil)
+	if errors.Is(err, ErrBlobNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (s *s3BlobStore) URL(key string) string {
+	return s.publicURL + "/" + key
+}
+
+func (s *s3BlobStore) KeyFromURL(rawURL string) (string, bool) {
+	return trimBlobURL(rawURL, s.publicURL)
+}
+
+// do sends a signed request for key and returns the response body.
+func (s *s3BlobStore) do(ctx context.Context, method, key string, header http.Header, body []byte) ([]byte, error) {
+	objectURL := *s.bucketURL
+	objectURL.Path = s.bucketURL.Path + "/" + key
+
+	req, err := http.NewRequestWithContext(ctx, method, objectURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+	req.ContentLength = int64(len(body))
+
+	signS3Request(req, body, s.options.Region, s.options.AccessKeyID, s.options.SecretAccessKey, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrBlobNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("s3 %s %s: status %d: %s", method, key, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// signS3Request adds the x-amz-date, x-amz-content-sha256 and Authorization
+// headers described by AWS Signature Version 4. Every header already set on
+// req is signed along with Host.
+func signS3Request(req *http.Request, body []byte, region, accessKeyID, secretAccessKey string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	payloadHash := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3EscapePath(req.URL.Path),
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3EscapePath URI-encodes every path segment as SigV4 expects, leaving the
+// separating slashes alone.
+func s3EscapePath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = s3Escape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, s3Escape(key)+"="+s3Escape(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3Escape percent-encodes everything except the RFC 3986 unreserved
+// characters.
+func s3Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func trimBlobURL(rawURL, baseURL string) (string, bool) {
+	key, ok := strings.CutPrefix(rawURL, baseURL+"/")
+	if !ok || key == "" || path.Clean(key) != key || strings.HasPrefix(key, "../") {
+		return "", false
+	}
+	return key, true
+}
@@ -2,37 +2,179 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
 	"time"
 
+	"github.com/andriawan24/link-short/internal/metrics"
+	"github.com/andriawan24/link-short/internal/utils"
 	"github.com/redis/go-redis/v9"
 )
 
+// ErrCachedNotFound is returned by GetURL for codes recently looked up and
+// found not to exist.
+var ErrCachedNotFound = errors.New("short code is cached as not found")
+
+const (
+	urlCachePrefix         = "url:"
+	urlInvalidationChannel = "url:invalidate"
+
+	// notFoundMarker is stored in place of a URL for unknown codes. It can
+	// never be a valid destination.
+	notFoundMarker = "!"
+)
+
+type CacheOptions struct {
+	// TTL applies to resolved redirects in Redis.
+	TTL time.Duration
+	// NegativeTTL applies to codes that do not exist.
+	NegativeTTL time.Duration
+	// LocalSize and LocalTTL bound the in-process cache. Entries are also
+	// dropped on every instance when a code is invalidated, so LocalTTL only
+	// limits staleness if an invalidation message is lost.
+	LocalSize int
+	LocalTTL  time.Duration
+}
+
 type cacheService struct {
-	rdb    *redis.Client
-	prefix string
+	rdb     *redis.Client
+	options CacheOptions
+	local   *utils.LRU[string, string]
 }
 
 type CacheService interface {
 	GetURL(ctx context.Context, code string) (string, error)
-	SetURL(ctx context.Context, code, originalURL string, ttl time.Duration) error
+	// SetURL caches the redirect target of code. The entry never outlives
+	// expiresAt, when it is set.
+	SetURL(ctx context.Context, code, originalURL string, expiresAt sql.NullTime) error
+	SetNotFound(ctx context.Context, code string) error
 	InvalidateURL(ctx context.Context, code string) error
+	Run(ctx context.Context)
 }
 
-func NewCacheService(rdb *redis.Client) CacheService {
+// NewCacheService caches redirect targets in process, backed by Redis.
+func NewCacheService(rdb *redis.Client, options CacheOptions) CacheService {
 	return &cacheService{
-		rdb:    rdb,
-		prefix: "url:",
+		rdb:     rdb,
+		options: options,
+		local:   utils.NewLRU[string, string](options.LocalSize),
 	}
 }
 
+// GetURL looks the code up in process first and then in Redis. A miss in
+// both returns redis.Nil.
 func (c *cacheService) GetURL(ctx context.Context, code string) (string, error) {
-	return c.rdb.Get(ctx, c.prefix+code).Result()
+	if value, ok := c.local.Get(code); ok {
+		if value == notFoundMarker {
+			metrics.RedirectCacheResults.WithLabelValues("negative_hit").Inc()
+			return "", ErrCachedNotFound
+		}
+
+		metrics.RedirectCacheResults.WithLabelValues("local_hit").Inc()
+		return value, nil
+	}
+
+	value, err := c.rdb.Get(ctx, urlCachePrefix+code).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			metrics.RedirectCacheResults.WithLabelValues("miss").Inc()
+		} else {
+			metrics.RedirectCacheResults.WithLabelValues("error").Inc()
+		}
+		return "", err
+	}
+
+	if value == notFoundMarker {
+		c.local.Set(code, notFoundMarker, min(c.options.LocalTTL, c.options.NegativeTTL))
+		metrics.RedirectCacheResults.WithLabelValues("negative_hit").Inc()
+		return "", ErrCachedNotFound
+	}
+
+	c.local.Set(code, value, c.options.LocalTTL)
+	metrics.RedirectCacheResults.WithLabelValues("hit").Inc()
+	return value, nil
+}
+
+func (c *cacheService) SetURL(ctx context.Context, code string, originalURL string, expiresAt sql.NullTime) error {
+	ttl := c.options.TTL
+	localTTL := c.options.LocalTTL
+	if expiresAt.Valid {
+		// Redis rejects expirations below a millisecond, and an entry that
+		// short is not worth keeping anyway.
+		remaining := time.Until(expiresAt.Time)
+		if remaining < time.Second {
+			return nil
+		}
+		ttl = min(ttl, remaining)
+		localTTL = min(localTTL, remaining)
+	}
+
+	c.local.Set(code, originalURL, localTTL)
+	return c.rdb.Set(ctx, urlCachePrefix+code, originalURL, ttl).Err()
+}
+
+func (c *cacheService) SetNotFound(ctx context.Context, code string) error {
+	c.local.Set(code, notFoundMarker, min(c.options.LocalTTL, c.options.NegativeTTL))
+	return c.rdb.Set(ctx, urlCachePrefix+code, notFoundMarker, c.options.NegativeTTL).Err()
 }
 
+// InvalidateURL removes the code from Redis and tells every instance,
+// including this one, to drop it from its in-process cache.
 func (c *cacheService) InvalidateURL(ctx context.Context, code string) error {
-	return c.rdb.Del(ctx, c.prefix+code).Err()
+	c.local.Delete(code)
+
+	if err := c.rdb.Del(ctx, urlCachePrefix+code).Err(); err != nil {
+		return err
+	}
+
+	return c.rdb.Publish(ctx, urlInvalidationChannel, code).Err()
 }
 
-func (c *cacheService) SetURL(ctx context.Context, code string, originalURL string, ttl time.Duration) error {
-	return c.rdb.Set(ctx, c.prefix+code, originalURL, ttl).Err()
+// Run applies invalidations published by other instances until ctx is
+// done. Whatever was cached while the subscription was down is dropped once
+// it is back.
+func (c *cacheService) Run(ctx context.Context) {
+	pubsub := c.rdb.Subscribe(ctx)
+
+	// Receive blocks on the connection without watching ctx, closing the
+	// subscription is what unblocks it on shutdown.
+	go func() {
+		<-ctx.Done()
+		_ = pubsub.Close()
+	}()
+
+	if err := pubsub.Subscribe(ctx, urlInvalidationChannel); err != nil {
+		slog.ErrorContext(ctx, "failed to subscribe to cache invalidations", "error", err)
+	}
+
+	for {
+		message, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			// go-redis reconnects on the next Receive. Invalidations sent in
+			// the meantime are lost, so start from an empty local cache.
+			c.local.Purge()
+			slog.WarnContext(ctx, "cache invalidation subscription interrupted", "error", err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		switch message := message.(type) {
+		case *redis.Message:
+			c.local.Delete(message.Payload)
+		case *redis.Subscription:
+			if message.Kind == "subscribe" {
+				c.local.Purge()
+			}
+		}
+	}
 }
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestCacheService(t *testing.T, mr *miniredis.Miniredis) *cacheService {
+	t.Helper()
+
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	return NewCacheService(rdb, CacheOptions{
+		TTL:         time.Hour,
+		NegativeTTL: time.Minute,
+		LocalSize:   100,
+		LocalTTL:    10 * time.Minute,
+	}).(*cacheService)
+}
+
+func TestCacheServiceTiers(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	cache := newTestCacheService(t, mr)
+
+	if _, err := cache.GetURL(ctx, "abc"); !errors.Is(err, redis.Nil) {
+		t.Fatalf("GetURL of an unknown code: error = %v, want redis.Nil", err)
+	}
+
+	if err := cache.SetURL(ctx, "abc", "https://example.test", sql.NullTime{}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := mr.Get(urlCachePrefix + "abc"); got != "https://example.test" {
+		t.Fatalf("Redis holds %q, want the URL", got)
+	}
+	if ttl := mr.TTL(urlCachePrefix + "abc"); ttl != time.Hour {
+		t.Fatalf("Redis TTL = %s, want 1h", ttl)
+	}
+
+	// The in-process copy answers without Redis.
+	mr.FlushAll()
+	if got, err := cache.GetURL(ctx, "abc"); err != nil || got != "https://example.test" {
+		t.Fatalf("GetURL from the local cache = %q, %v", got, err)
+	}
+
+	// A Redis hit fills the local cache.
+	mr.Set(urlCachePrefix+"xyz", "https://example.test/xyz")
+	if got, err := cache.GetURL(ctx, "xyz"); err != nil || got != "https://example.test/xyz" {
+		t.Fatalf("GetURL from Redis = %q, %v", got, err)
+	}
+	mr.Del(urlCachePrefix + "xyz")
+	if _, ok := cache.local.Get("xyz"); !ok {
+		t.Fatal("Redis hit was not copied to the local cache")
+	}
+
+	// Links that expire soon are cached no longer than they are valid.
+	if err := cache.SetURL(ctx, "soon", "https://example.test/soon", sql.NullTime{Time: time.Now().Add(30 * time.Second), Valid: true}); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := mr.TTL(urlCachePrefix + "soon"); ttl <= 0 || ttl > 30*time.Second {
+		t.Fatalf("Redis TTL of an expiring link = %s, want at most 30s", ttl)
+	}
+	if err := cache.SetURL(ctx, "gone", "https://example.test/gone", sql.NullTime{Time: time.Now().Add(-time.Second), Valid: true}); err != nil {
+		t.Fatal(err)
+	}
+	if mr.Exists(urlCachePrefix+"gone") || cache.local.Len() != 3 {
+		t.Fatal("an expired link was cached")
+	}
+
+	if err := cache.InvalidateURL(ctx, "abc"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.GetURL(ctx, "abc"); !errors.Is(err, redis.Nil) {
+		t.Fatalf("GetURL after InvalidateURL: error = %v, want redis.Nil", err)
+	}
+}
+
+func TestCacheServiceNegativeCache(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	cache := newTestCacheService(t, mr)
+
+	if err := cache.SetNotFound(ctx, "missing"); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := mr.TTL(urlCachePrefix + "missing"); ttl != time.Minute {
+		t.Fatalf("Redis TTL of a negative entry = %s, want the negative TTL", ttl)
+	}
+	if _, err := cache.GetURL(ctx, "missing"); !errors.Is(err, ErrCachedNotFound) {
+		t.Fatalf("GetURL of a cached miss: error = %v, want %v", err, ErrCachedNotFound)
+	}
+
+	// A negative entry written by another instance is honoured too.
+	other := newTestCacheService(t, mr)
+	if _, err := other.GetURL(ctx, "missing"); !errors.Is(err, ErrCachedNotFound) {
+		t.Fatalf("GetURL of a miss cached elsewhere: error = %v, want %v", err, ErrCachedNotFound)
+	}
+
+	// Creating the link replaces the negative entry.
+	if err := cache.InvalidateURL(ctx, "missing"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.SetURL(ctx, "missing", "https://example.test/new", sql.NullTime{}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := cache.GetURL(ctx, "missing"); err != nil || got != "https://example.test/new" {
+		t.Fatalf("GetURL after the link was created = %q, %v", got, err)
+	}
+}
+
+func TestCacheServiceInvalidationAcrossInstances(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mr := miniredis.RunT(t)
+	first := newTestCacheService(t, mr)
+	second := newTestCacheService(t, mr)
+
+	done := make(chan struct{})
+	go func() {
+		second.Run(ctx)
+		close(done)
+	}()
+	waitFor(t, func() bool { return mr.PubSubNumSub(urlInvalidationChannel)[urlInvalidationChannel] == 1 })
+
+	if err := second.SetURL(ctx, "abc", "https://example.test/old", sql.NullTime{}); err != nil {
+		t.Fatal(err)
+	}
+	second.local.Set("other", "https://example.test/other", time.Minute)
+
+	if err := first.InvalidateURL(ctx, "abc"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool {
+		_, ok := second.local.Get("abc")
+		return !ok
+	})
+	if _, ok := second.local.Get("other"); !ok {
+		t.Fatal("an unrelated code was dropped")
+	}
+	if _, err := second.GetURL(ctx, "abc"); !errors.Is(err, redis.Nil) {
+		t.Fatalf("GetURL on the other instance: error = %v, want redis.Nil", err)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after the context was cancelled")
+	}
+}
+
+// waitFor polls condition until it holds or a second has passed.
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	campaignPollInterval = time.Minute
+	campaignBatchSize    = 20
+)
+
+type campaignService struct {
+	queries      repository.CampaignRepository
+	clickLogs    repository.ClickLogRepository
+	cacheService CacheService
+}
+
+type CampaignService interface {
+	InsertCampaign(ctx context.Context, param database.InsertCampaignParams) (database.Campaign, error)
+	GetCampaigns(ctx context.Context, userId uuid.UUID, limit int32, offset int32) ([]database.GetCampaignsRow, error)
+	GetCampaign(ctx context.Context, userId uuid.UUID, id uuid.UUID) (database.GetCampaignRow, error)
+	UpdateCampaign(ctx context.Context, param database.UpdateCampaignParams) (database.Campaign, error)
+	DeleteCampaign(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
+	// AddLinks moves links of the campaign owner into the campaign. Links
+	// added after the campaign ended are expired straight away.
+	AddLinks(ctx context.Context, campaign database.GetCampaignRow, linkIds []uuid.UUID) error
+	RemoveLink(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, linkId uuid.UUID) error
+
+	GetTotalClicks(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, from time.Time, to time.Time) (int64, error)
+	GetByDateRange(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, from time.Time, to time.Time) ([]database.GetByDateRangeRow, error)
+	GetDeviceBreakdown(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, from time.Time, to time.Time) ([]database.GetDeviceBreakdownRow, error)
+	GetTopCountries(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, from time.Time, to time.Time) ([]database.GetTopCountriesRow, error)
+	GetTopRegions(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, from time.Time, to time.Time) ([]database.GetTopRegionsRow, error)
+	GetTopCities(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, from time.Time, to time.Time) ([]database.GetTopCitiesRow, error)
+	GetTrafficSources(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, from time.Time, to time.Time) ([]database.GetTrafficSourcesRow, error)
+	GetBrowserUsage(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, from time.Time, to time.Time) ([]database.GetBrowserUsageRow, error)
+	GetLeaderboard(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, from time.Time, to time.Time, limit int32) ([]database.GetCampaignLeaderboardRow, error)
+
+	// ExpireEnded expires the links of every campaign that has reached its
+	// end date and not been handled yet.
+	ExpireEnded(ctx context.Context)
+	Run(ctx context.Context)
+}
+
+// NewCampaignService groups links into campaigns. Campaign analytics run the
+// same queries as the account wide analytics, narrowed to the campaign.
+func NewCampaignService(queries repository.CampaignRepository, clickLogs repository.ClickLogRepository, cacheService CacheService) CampaignService {
+	return &campaignService{
+		queries:      queries,
+		clickLogs:    clickLogs,
+		cacheService: cacheService,
+	}
+}
+
+func (s *campaignService) InsertCampaign(ctx context.Context, param database.InsertCampaignParams) (database.Campaign, error) {
+	campaign, err := s.queries.InsertCampaign(ctx, param)
+	if err != nil {
+		return campaign, err
+	}
+
+	return campaign, nil
+}
+
+func (s *campaignService) GetCampaigns(ctx context.Context, userId uuid.UUID, limit int32, offset int32) ([]database.GetCampaignsRow, error) {
+	campaigns, err := s.queries.GetCampaigns(ctx, database.GetCampaignsParams{
+		UserID: userId,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return campaigns, nil
+}
+
+func (s *campaignService) GetCampaign(ctx context.Context, userId uuid.UUID, id uuid.UUID) (database.GetCampaignRow, error) {
+	campaign, err := s.queries.GetCampaign(ctx, database.GetCampaignParams{
+		ID:     id,
+		UserID: userId,
+	})
+	if err != nil {
+		return campaign, err
+	}
+
+	return campaign, nil
+}
+
+func (s *campaignService) UpdateCampaign(ctx context.Context, param database.UpdateCampaignParams) (database.Campaign, error) {
+	campaign, err := s.queries.UpdateCampaign(ctx, param)
+	if err != nil {
+		return campaign, err
+	}
+
+	return campaign, nil
+}
+
+func (s *campaignService) DeleteCampaign(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	return s.queries.DeleteCampaign(ctx, database.DeleteCampaignParams{
+		ID:     id,
+		UserID: userId,
+	})
+}
+
+func (s *campaignService) AddLinks(ctx context.Context, campaign database.GetCampaignRow, linkIds []uuid.UUID) error {
+	_, err := s.queries.AddLinksToCampaign(ctx, database.AddLinksToCampaignParams{
+		CampaignID: campaign.ID,
+		UserID:     campaign.UserID,
+		LinkIds:    linkIds,
+	})
+	if err != nil {
+		return err
+	}
+
+	if campaign.LinksExpiredAt.Valid && campaign.EndsAt.Valid {
+		return s.expireLinks(ctx, campaign.ID, campaign.EndsAt.Time)
+	}
+
+	return nil
+}
+
+func (s *campaignService) RemoveLink(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, linkId uuid.UUID) error {
+	removed, err := s.queries.RemoveLinkFromCampaign(ctx, database.RemoveLinkFromCampaignParams{
+		ID:         linkId,
+		UserID:     userId,
+		CampaignID: campaignId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if removed == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func campaignFilter(campaignId uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: campaignId, Valid: true}
+}
+
+func (s *campaignService) GetTotalClicks(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, from time.Time, to time.Time) (int64, error) {
+	return s.clickLogs.GetTotalClicks(ctx, database.GetTotalClicksParams{
+		FromDate:   from,
+		ToDate:     to,
+		UserID:     userId,
+		CampaignID: campaignFilter(campaignId),
+	})
+}
+
+func (s *campaignService) GetByDateRange(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, from time.Time, to time.Time) ([]database.GetByDateRangeRow, error) {
+	return s.clickLogs.GetByDateRange(ctx, database.GetByDateRangeParams{
+		FromDate:   from,
+		ToDate:     to,
+		UserID:     userId,
+		CampaignID: campaignFilter(campaignId),
+	})
+}
+
+func (s *campaignService) GetDeviceBreakdown(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, from time.Time, to time.Time) ([]database.GetDeviceBreakdownRow, error) {
+	return s.clickLogs.GetDeviceBreakdown(ctx, database.GetDeviceBreakdownParams{
+		FromDate:   from,
+		ToDate:     to,
+		UserID:     userId,
+		CampaignID: campaignFilter(campaignId),
+	})
+}
+
+func (s *campaignService) GetTopCountries(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, from time.Time, to time.Time) ([]database.GetTopCountriesRow, error) {
+	return s.clickLogs.GetTopCountries(ctx, database.GetTopCountriesParams{
+		FromDate:   from,
+		ToDate:     to,
+		UserID:     userId,
+		CampaignID: campaignFilter(campaignId),
+	})
+}
+
+func (s *campaignService) GetTopRegions(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, from time.Time, to time.Time) ([]database.GetTopRegionsRow, error) {
+	return s.clickLogs.GetTopRegions(ctx, database.GetTopRegionsParams{
+		FromDate:   from,
+		ToDate:     to,
+		UserID:     userId,
+		CampaignID: campaignFilter(campaignId),
+	})
+}
+
+func (s *campaignService) GetTopCities(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, from time.Time, to time.Time) ([]database.GetTopCitiesRow, error) {
+	return s.clickLogs.GetTopCities(ctx, database.GetTopCitiesParams{
+		FromDate:   from,
+		ToDate:     to,
+		UserID:     userId,
+		CampaignID: campaignFilter(campaignId),
+	})
+}
+
+func (s *campaignService) GetTrafficSources(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, from time.Time, to time.Time) ([]database.GetTrafficSourcesRow, error) {
+	return s.clickLogs.GetTrafficSources(ctx, database.GetTrafficSourcesParams{
+		FromDate:   from,
+		ToDate:     to,
+		UserID:     userId,
+		CampaignID: campaignFilter(campaignId),
+	})
+}
+
+func (s *campaignService) GetBrowserUsage(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, from time.Time, to time.Time) ([]database.GetBrowserUsageRow, error) {
+	return s.clickLogs.GetBrowserUsage(ctx, database.GetBrowserUsageParams{
+		FromDate:   from,
+		ToDate:     to,
+		UserID:     userId,
+		CampaignID: campaignFilter(campaignId),
+	})
+}
+
+func (s *campaignService) GetLeaderboard(ctx context.Context, userId uuid.UUID, campaignId uuid.UUID, from time.Time, to time.Time, limit int32) ([]database.GetCampaignLeaderboardRow, error) {
+	return s.queries.GetCampaignLeaderboard(ctx, database.GetCampaignLeaderboardParams{
+		FromDate:   from,
+		ToDate:     to,
+		CampaignID: campaignId,
+		UserID:     userId,
+		Limit:      limit,
+	})
+}
+
+// Run expires the links of ended campaigns every poll interval until ctx is
+// done.
+func (s *campaignService) Run(ctx context.Context) {
+	ticker := time.NewTicker(campaignPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.ExpireEnded(ctx)
+		}
+	}
+}
+
+func (s *campaignService) ExpireEnded(ctx context.Context) {
+	campaigns, err := s.queries.GetEndedCampaigns(ctx, campaignBatchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load ended campaigns", "error", err)
+		return
+	}
+
+	for _, campaign := range campaigns {
+		if err := s.expireLinks(ctx, campaign.ID, campaign.EndsAt.Time); err != nil {
+			slog.ErrorContext(ctx, "failed to expire campaign links", "campaign_id", campaign.ID, "error", err)
+			continue
+		}
+
+		err := s.queries.MarkCampaignLinksExpired(ctx, database.MarkCampaignLinksExpiredParams{
+			ID:     campaign.ID,
+			EndsAt: campaign.EndsAt.Time,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to mark campaign links as expired", "campaign_id", campaign.ID, "error", err)
+		}
+	}
+}
+
+// expireLinks sets the expiry of every link in the campaign to endsAt,
+// unless it already expires earlier. Cached redirects of those links were
+// stored without that expiry, so they are dropped.
+func (s *campaignService) expireLinks(ctx context.Context, campaignId uuid.UUID, endsAt time.Time) error {
+	codes, err := s.queries.ExpireCampaignLinks(ctx, database.ExpireCampaignLinksParams{
+		EndsAt:     endsAt,
+		CampaignID: campaignId,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, code := range codes {
+		s.invalidate(ctx, code.ShortCode)
+		if code.CustomShortCode.Valid {
+			s.invalidate(ctx, code.CustomShortCode.String)
+		}
+	}
+
+	if len(codes) > 0 {
+		slog.InfoContext(ctx, "expired campaign links", "campaign_id", campaignId, "links", len(codes))
+	}
+	return nil
+}
+
+func (s *campaignService) invalidate(ctx context.Context, code string) {
+	if err := s.cacheService.InvalidateURL(ctx, code); err != nil {
+		slog.WarnContext(ctx, "failed to invalidate cached redirect", "code", code, "error", err)
+	}
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"errors"
+	"log/slog"
+	"path"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository"
+)
+
+const (
+	clickLogRetentionInterval  = time.Hour
+	clickLogPartitionsAhead    = 2
+	clickLogArchivePageSize    = 5000
+	clickLogAnonymizeBatchSize = 5000
+)
+
+type clickLogRetentionWorker struct {
+	queries        repository.ClickLogRetentionRepository
+	store          BlobStore
+	months         int
+	archive        bool
+	anonymizeAfter time.Duration
+	now            func() time.Time
+}
+
+type ClickLogRetentionWorker interface {
+	Run(ctx context.Context)
+	// Maintain creates the partitions of the coming months and applies the
+	// retention policy once.
+	Maintain(ctx context.Context) error
+}
+
+// NewClickLogRetentionWorker keeps monthly click_logs partitions ahead of
+// time, removes months older than the last months calendar months (0 keeps
+// everything), archiving them to store first when archive is set, and
+// truncates IP addresses of clicks older than anonymizeAfter (0 never does).
+func NewClickLogRetentionWorker(queries repository.ClickLogRetentionRepository, store BlobStore, months int, archive bool, anonymizeAfter time.Duration) ClickLogRetentionWorker {
+	return &clickLogRetentionWorker{
+		queries:        queries,
+		store:          store,
+		months:         months,
+		archive:        archive,
+		anonymizeAfter: anonymizeAfter,
+		now:            time.Now,
+	}
+}
+
+// Run maintains the partitions right away, so clicks of a new month never
+// land in the default partition, and then every interval until ctx is done.
+func (w *clickLogRetentionWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(clickLogRetentionInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Maintain(ctx); err != nil {
+			slog.ErrorContext(ctx, "click log maintenance failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *clickLogRetentionWorker) Maintain(ctx context.Context) error {
+	now := w.now().UTC()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var errs []error
+	for i := range clickLogPartitionsAhead + 1 {
+		month := thisMonth.AddDate(0, i, 0)
+		created, err := w.queries.EnsureClickLogPartition(ctx, month)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if created {
+			slog.InfoContext(ctx, "created click log partition", "month", month.Format("2006-01"))
+		}
+	}
+
+	if w.months > 0 {
+		errs = append(errs, w.expire(ctx, thisMonth.AddDate(0, 1-w.months, 0)))
+	}
+
+	if w.anonymizeAfter > 0 {
+		errs = append(errs, w.anonymize(ctx, now.Add(-w.anonymizeAfter)))
+	}
+
+	return errors.Join(errs...)
+}
+
+// expire removes every month before cutoff. Partitions are dropped whole;
+// stray rows of older months in the default partition are deleted after.
+func (w *clickLogRetentionWorker) expire(ctx context.Context, cutoff time.Time) error {
+	months, err := w.queries.GetClickLogPartitionsBefore(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+
+	for _, month := range months {
+		if w.archive {
+			if err := w.archiveMonth(ctx, month); err != nil {
+				return err
+			}
+		}
+		if err := w.queries.DropClickLogPartition(ctx, month); err != nil {
+			return err
+		}
+		slog.InfoContext(ctx, "dropped click log partition", "month", month.Format("2006-01"), "archived", w.archive)
+	}
+
+	deleted, err := w.queries.DeleteClickLogsBefore(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		slog.InfoContext(ctx, "deleted expired click logs outside partitions", "count", deleted)
+	}
+	return nil
+}
+
+// archiveMonth writes every click of month to
+// archives/click_logs/<yyyy-mm>.csv.gz. Rerunning it replaces the archive,
+// so a drop that failed after archiving is simply retried.
+func (w *clickLogRetentionWorker) archiveMonth(ctx context.Context, month time.Time) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	cw := csv.NewWriter(gz)
+	cw.Write(clickLogCSVHeader)
+
+	params := database.GetClickLogsForArchiveParams{
+		FromTime:  month,
+		ToTime:    month.AddDate(0, 1, 0),
+		BatchSize: clickLogArchivePageSize,
+	}
+	for {
+		clicks, err := w.queries.GetClickLogsForArchive(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		for _, click := range clicks {
+			cw.Write(clickLogCSVRecord(click))
+		}
+
+		if len(clicks) < clickLogArchivePageSize {
+			break
+		}
+		last := clicks[len(clicks)-1]
+		params.AfterClickedAt = last.ClickedAt
+		params.AfterID = last.ID
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	return w.store.Put(ctx, clickLogArchiveKey(month), buf.Bytes(), "application/gzip")
+}
+
+func (w *clickLogRetentionWorker) anonymize(ctx context.Context, before time.Time) error {
+	var total int64
+	for {
+		updated, err := w.queries.AnonymizeClickLogIPs(ctx, database.AnonymizeClickLogIPsParams{
+			Before:    before,
+			BatchSize: clickLogAnonymizeBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		total += updated
+		if updated < clickLogAnonymizeBatchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if total > 0 {
+		slog.InfoContext(ctx, "anonymized click log IP addresses", "count", total)
+	}
+	return nil
+}
+
+// clickLogArchiveKey is the blob key a month of click logs is archived to.
+func clickLogArchiveKey(month time.Time) string {
+	return path.Join("archives", "click_logs", month.UTC().Format("2006-01")+".csv.gz")
+}
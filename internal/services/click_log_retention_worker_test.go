@@ -0,0 +1,91 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository/memory"
+)
+
+func TestClickLogRetentionWorker(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	blobs := NewLocalBlobStore(t.TempDir(), "/uploads")
+
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	click := func(at time.Time, ip string) {
+		err := store.InsertSpooledClickLog(ctx, database.InsertSpooledClickLogParams{
+			Code:      "abc",
+			IpAddress: sql.NullString{String: ip, Valid: true},
+			ClickedAt: at,
+		})
+		if err != nil {
+			t.Fatalf("insert click: %v", err)
+		}
+	}
+	click(time.Date(2026, 1, 31, 23, 0, 0, 0, time.UTC), "203.0.113.7")
+	click(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), "2001:db8:1234:5678::1")
+	click(now.Add(-time.Hour), "198.51.100.23")
+
+	// Partitions for January and February already exist.
+	for _, month := range []time.Time{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)} {
+		store.EnsureClickLogPartition(ctx, month)
+	}
+
+	worker := NewClickLogRetentionWorker(store, blobs, 4, true, 30*24*time.Hour).(*clickLogRetentionWorker)
+	worker.now = func() time.Time { return now }
+
+	if err := worker.Maintain(ctx); err != nil {
+		t.Fatalf("Maintain: %v", err)
+	}
+
+	// Keeping 4 months in May means February to May.
+	partitions, _ := store.GetClickLogPartitionsBefore(ctx, time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC))
+	var got []string
+	for _, month := range partitions {
+		got = append(got, month.Format("2006-01"))
+	}
+	if want := []string{"2026-02", "2026-05", "2026-06", "2026-07"}; !slices.Equal(got, want) {
+		t.Fatalf("partitions = %v, want %v", got, want)
+	}
+
+	archived, err := blobs.Get(ctx, clickLogArchiveKey(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatalf("January archive: %v", err)
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(archived))
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	records, err := csv.NewReader(gz).ReadAll()
+	if err != nil || len(records) != 2 || records[1][2] != "203.0.113.7" {
+		t.Fatalf("archive records = %v, %v; want the header and the January click", records, err)
+	}
+
+	clicks, _ := store.GetClickLogsForArchive(ctx, database.GetClickLogsForArchiveParams{
+		FromTime:  time.Time{},
+		ToTime:    now,
+		BatchSize: 10,
+	})
+	if len(clicks) != 2 {
+		t.Fatalf("clicks left = %d, want 2", len(clicks))
+	}
+	if ip := clicks[0].IpAddress; !clicks[0].IpAnonymized || ip.String != "2001:db8:1234::" {
+		t.Fatalf("old click ip = %+v, anonymized %v; want 2001:db8:1234::", ip, clicks[0].IpAnonymized)
+	}
+	if ip := clicks[1].IpAddress; clicks[1].IpAnonymized || ip.String != "198.51.100.23" {
+		t.Fatalf("recent click ip = %+v, want it untouched", ip)
+	}
+
+	// A second run has nothing left to do.
+	if err := worker.Maintain(ctx); err != nil {
+		t.Fatalf("second Maintain: %v", err)
+	}
+}
@@ -5,11 +5,12 @@ import (
 	"time"
 
 	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository"
 	"github.com/google/uuid"
 )
 
 type clickLogService struct {
-	queries *database.Queries
+	queries repository.ClickLogRepository
 }
 
 type ClickLogService interface {
@@ -18,12 +19,14 @@ type ClickLogService interface {
 	GetDeviceBreakdown(ctx context.Context, userId uuid.UUID, from time.Time, to time.Time) ([]database.GetDeviceBreakdownRow, error)
 	GetDeviceBreakdownSingleLink(ctx context.Context, userId uuid.UUID, linkId uuid.UUID, from time.Time, to time.Time) ([]database.GetDeviceBreakdownSingleRow, error)
 	GetTopCountries(ctx context.Context, userId uuid.UUID, from time.Time, to time.Time) ([]database.GetTopCountriesRow, error)
+	GetTopRegions(ctx context.Context, userId uuid.UUID, from time.Time, to time.Time) ([]database.GetTopRegionsRow, error)
+	GetTopCities(ctx context.Context, userId uuid.UUID, from time.Time, to time.Time) ([]database.GetTopCitiesRow, error)
 	GetTopCountriesSingleLink(ctx context.Context, userId uuid.UUID, linkId uuid.UUID, from time.Time, to time.Time) ([]database.GetTopCountriesSingleRow, error)
 	GetTrafficSources(ctx context.Context, userId uuid.UUID, from time.Time, to time.Time) ([]database.GetTrafficSourcesRow, error)
 	GetBrowserUsage(ctx context.Context, userId uuid.UUID, from time.Time, to time.Time) ([]database.GetBrowserUsageRow, error)
 }
 
-func NewClickLogService(queries *database.Queries) ClickLogService {
+func NewClickLogService(queries repository.ClickLogRepository) ClickLogService {
 	return &clickLogService{
 		queries: queries,
 	}
@@ -77,6 +80,32 @@ func (c *clickLogService) GetTopCountries(ctx context.Context, userId uuid.UUID,
 	return countries, nil
 }
 
+func (c *clickLogService) GetTopRegions(ctx context.Context, userId uuid.UUID, from time.Time, to time.Time) ([]database.GetTopRegionsRow, error) {
+	regions, err := c.queries.GetTopRegions(ctx, database.GetTopRegionsParams{
+		FromDate: from,
+		ToDate:   to,
+		UserID:   userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return regions, nil
+}
+
+func (c *clickLogService) GetTopCities(ctx context.Context, userId uuid.UUID, from time.Time, to time.Time) ([]database.GetTopCitiesRow, error) {
+	cities, err := c.queries.GetTopCities(ctx, database.GetTopCitiesParams{
+		FromDate: from,
+		ToDate:   to,
+		UserID:   userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cities, nil
+}
+
 func (c *clickLogService) GetTrafficSources(ctx context.Context, userId uuid.UUID, from time.Time, to time.Time) ([]database.GetTrafficSourcesRow, error) {
 	sources, err := c.queries.GetTrafficSources(ctx, database.GetTrafficSourcesParams{
 		FromDate: from,
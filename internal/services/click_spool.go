@@ -0,0 +1,302 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/metrics"
+	"github.com/andriawan24/link-short/internal/repository"
+	"github.com/andriawan24/link-short/internal/resilience"
+)
+
+const (
+	clickSpoolFile       = "clicks.jsonl"
+	clickSpoolReplayFile = "clicks.replay.jsonl"
+)
+
+// ErrClickSpoolFull is returned by Append once the spool has reached its
+// size limit. The click is lost.
+var ErrClickSpoolFull = errors.New("click spool is full")
+
+// spooledClick is one line of the spool file.
+type spooledClick struct {
+	Code       string    `json:"code"`
+	IpAddress  *string   `json:"ip_address,omitempty"`
+	UserAgent  *string   `json:"user_agent,omitempty"`
+	Referrer   *string   `json:"referrer,omitempty"`
+	Country    *string   `json:"country,omitempty"`
+	Traffic    *string   `json:"traffic,omitempty"`
+	DeviceType *string   `json:"device_type,omitempty"`
+	Browser    *string   `json:"browser,omitempty"`
+	Region     *string   `json:"region,omitempty"`
+	City       *string   `json:"city,omitempty"`
+	Asn        *int64    `json:"asn,omitempty"`
+	ClickedAt  time.Time `json:"clicked_at"`
+}
+
+type clickSpool struct {
+	queries        repository.ClickLogRepository
+	dir            string
+	maxBytes       int64
+	replayInterval time.Duration
+
+	// mu serialises writes to the spool file and its rotation.
+	mu sync.Mutex
+}
+
+type ClickSpool interface {
+	Append(param database.InsertClickLogParams, clickedAt time.Time) error
+	Run(ctx context.Context)
+}
+
+// NewClickSpool buffers clicks on disk under dir while the database is
+// unavailable and replays them every replayInterval.
+func NewClickSpool(queries repository.ClickLogRepository, dir string, maxBytes int64, replayInterval time.Duration) ClickSpool {
+	return &clickSpool{
+		queries:        queries,
+		dir:            dir,
+		maxBytes:       maxBytes,
+		replayInterval: replayInterval,
+	}
+}
+
+func (s *clickSpool) Append(param database.InsertClickLogParams, clickedAt time.Time) error {
+	line, err := json.Marshal(spooledClick{
+		Code:       param.Code,
+		IpAddress:  fromNullString(param.IpAddress),
+		UserAgent:  fromNullString(param.UserAgent),
+		Referrer:   fromNullString(param.Referrer),
+		Country:    fromNullString(param.Country),
+		Traffic:    fromNullString(param.Traffic),
+		DeviceType: fromNullString(param.DeviceType),
+		Browser:    fromNullString(param.Browser),
+		Region:     fromNullString(param.Region),
+		City:       fromNullString(param.City),
+		Asn:        fromNullInt64(param.Asn),
+		ClickedAt:  clickedAt.UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(filepath.Join(s.dir, clickSpoolFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if info.Size()+int64(len(line)) > s.maxBytes {
+		metrics.SpooledClicks.WithLabelValues("dropped").Inc()
+		return ErrClickSpoolFull
+	}
+
+	if _, err := file.Write(line); err != nil {
+		return err
+	}
+
+	metrics.SpooledClicks.WithLabelValues("spooled").Inc()
+	return nil
+}
+
+// Run replays spooled clicks into the database until ctx is done.
+func (s *clickSpool) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.replayInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.replay(ctx); err != nil {
+				slog.Error("failed to replay spooled clicks", "error", err)
+			}
+		}
+	}
+}
+
+// replay moves the spool file aside so new clicks can keep being appended,
+// then inserts its records in order. When the database becomes unavailable
+// again the records not yet inserted are kept for the next run.
+func (s *clickSpool) replay(ctx context.Context) error {
+	replayPath := filepath.Join(s.dir, clickSpoolReplayFile)
+
+	if err := s.rotate(replayPath); err != nil {
+		return err
+	}
+
+	file, err := os.Open(replayPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var (
+		remaining [][]byte
+		replayed  int
+	)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		if remaining != nil {
+			remaining = append(remaining, append([]byte(nil), line...))
+			continue
+		}
+
+		var click spooledClick
+		if err := json.Unmarshal(line, &click); err != nil {
+			metrics.SpooledClicks.WithLabelValues("dropped").Inc()
+			slog.Error("dropping malformed spooled click", "record", string(line), "error", err)
+			continue
+		}
+
+		if err := s.insert(ctx, click); err != nil {
+			if ctx.Err() != nil || resilience.IsUnavailable(err) {
+				remaining = [][]byte{append([]byte(nil), line...)}
+				continue
+			}
+
+			metrics.SpooledClicks.WithLabelValues("dropped").Inc()
+			slog.Error("dropping spooled click", "record", string(line), "error", err)
+			continue
+		}
+
+		replayed++
+		metrics.SpooledClicks.WithLabelValues("replayed").Inc()
+	}
+	scanErr := scanner.Err()
+	file.Close()
+
+	if scanErr != nil {
+		return scanErr
+	}
+
+	if replayed > 0 {
+		slog.Info("replayed spooled clicks", "count", replayed, "remaining", len(remaining))
+	}
+
+	if remaining == nil {
+		return os.Remove(replayPath)
+	}
+
+	return writeLines(replayPath, remaining)
+}
+
+// rotate renames the spool file to replayPath unless an earlier replay is
+// still unfinished.
+func (s *clickSpool) rotate(replayPath string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := os.Stat(replayPath); err == nil {
+		return nil
+	}
+
+	err := os.Rename(filepath.Join(s.dir, clickSpoolFile), replayPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *clickSpool) insert(ctx context.Context, click spooledClick) error {
+	return s.queries.InsertSpooledClickLog(ctx, database.InsertSpooledClickLogParams{
+		Code:       click.Code,
+		IpAddress:  toNullString(click.IpAddress),
+		UserAgent:  toNullString(click.UserAgent),
+		Referrer:   toNullString(click.Referrer),
+		Country:    toNullString(click.Country),
+		Traffic:    toNullString(click.Traffic),
+		DeviceType: toNullString(click.DeviceType),
+		Browser:    toNullString(click.Browser),
+		Region:     toNullString(click.Region),
+		City:       toNullString(click.City),
+		Asn:        toNullInt64(click.Asn),
+		ClickedAt:  click.ClickedAt,
+	})
+}
+
+// writeLines atomically replaces path with lines.
+func writeLines(path string, lines [][]byte) error {
+	tmp := path + ".tmp"
+
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	for _, line := range lines {
+		writer.Write(line)
+		writer.WriteByte('\n')
+	}
+
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+func fromNullString(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+	return &value.String
+}
+
+func toNullString(value *string) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *value, Valid: true}
+}
+
+func fromNullInt64(value sql.NullInt64) *int64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Int64
+}
+
+func toNullInt64(value *int64) sql.NullInt64 {
+	if value == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *value, Valid: true}
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository"
+	"github.com/andriawan24/link-short/internal/utils"
+)
+
+// flakyClickLogs stores spooled clicks and fails like an unreachable database
+// while down is set or once failAt clicks are stored.
+type flakyClickLogs struct {
+	repository.ClickLogRepository
+
+	mu     sync.Mutex
+	down   bool
+	failAt int
+	stored []database.InsertSpooledClickLogParams
+}
+
+func (f *flakyClickLogs) InsertSpooledClickLog(ctx context.Context, arg database.InsertSpooledClickLogParams) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.down || (f.failAt > 0 && len(f.stored) == f.failAt) {
+		return utils.ErrCircuitOpen
+	}
+	f.stored = append(f.stored, arg)
+	return nil
+}
+
+func (f *flakyClickLogs) setDown(down bool, failAt int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = down
+	f.failAt = failAt
+}
+
+func (f *flakyClickLogs) codes() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	codes := make([]string, len(f.stored))
+	for i, click := range f.stored {
+		codes[i] = click.Code
+	}
+	return codes
+}
+
+func TestClickSpoolReplay(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	clickLogs := &flakyClickLogs{down: true}
+	spool := NewClickSpool(clickLogs, dir, 1<<20, time.Hour).(*clickSpool)
+
+	clickedAt := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	appendClick := func(code string) {
+		t.Helper()
+		err := spool.Append(database.InsertClickLogParams{
+			Code:      code,
+			IpAddress: sql.NullString{String: "192.0.2.1", Valid: true},
+			Country:   sql.NullString{String: "ID", Valid: true},
+			Asn:       sql.NullInt64{Int64: 7713, Valid: true},
+		}, clickedAt)
+		if err != nil {
+			t.Fatalf("Append(%s): %v", code, err)
+		}
+	}
+
+	for _, code := range []string{"a", "b", "c"} {
+		appendClick(code)
+	}
+
+	// While the database is down nothing is lost.
+	if err := spool.replay(ctx); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if got := clickLogs.codes(); len(got) != 0 {
+		t.Fatalf("stored %v while the database was down", got)
+	}
+
+	// The database goes away again after one click. The rest is kept in
+	// order, and clicks spooled in the meantime come after it.
+	clickLogs.setDown(false, 1)
+	if err := spool.replay(ctx); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	appendClick("d")
+	if err := os.WriteFile(filepath.Join(dir, clickSpoolFile), append(readFile(t, filepath.Join(dir, clickSpoolFile)), "not json\n"...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	appendClick("e")
+
+	clickLogs.setDown(false, 0)
+	if err := spool.replay(ctx); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if err := spool.replay(ctx); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if got, want := clickLogs.codes(), []string{"a", "b", "c", "d", "e"}; !slices.Equal(got, want) {
+		t.Fatalf("stored codes = %v, want %v", got, want)
+	}
+
+	first := clickLogs.stored[0]
+	if first.IpAddress.String != "192.0.2.1" || first.Country.String != "ID" || first.Asn.Int64 != 7713 || first.UserAgent.Valid || !first.ClickedAt.Equal(clickedAt) {
+		t.Fatalf("replayed click = %+v", first)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("files left after a full replay: %v", entries)
+	}
+}
+
+func TestClickSpoolFull(t *testing.T) {
+	dir := t.TempDir()
+	spool := NewClickSpool(&flakyClickLogs{}, dir, 200, time.Hour)
+
+	var err error
+	for range 10 {
+		if err = spool.Append(database.InsertClickLogParams{Code: "abc"}, time.Now()); err != nil {
+			break
+		}
+	}
+	if !errors.Is(err, ErrClickSpoolFull) {
+		t.Fatalf("Append to a full spool: %v, want %v", err, ErrClickSpoolFull)
+	}
+
+	content := readFile(t, filepath.Join(dir, clickSpoolFile))
+	if len(content) > 200 || !strings.HasSuffix(string(content), "\n") {
+		t.Fatalf("spool file has %d bytes, want whole lines within the limit", len(content))
+	}
+}
+
+func readFile(t *testing.T, path string) []byte {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
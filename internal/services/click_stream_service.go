@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/andriawan24/link-short/internal/metrics"
+	"github.com/andriawan24/link-short/internal/models/responses"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// clickStreamBuffer is how many events a stream may fall behind before new
+// ones are dropped for it.
+const clickStreamBuffer = 64
+
+type clickStreamService struct {
+	rdb    *redis.Client
+	prefix string
+}
+
+type ClickStreamService interface {
+	Publish(ctx context.Context, userId uuid.UUID, event responses.ClickEventResponse) error
+	Subscribe(ctx context.Context, userId uuid.UUID) (<-chan responses.ClickEventResponse, error)
+}
+
+func NewClickStreamService(rdb *redis.Client) ClickStreamService {
+	return &clickStreamService{
+		rdb:    rdb,
+		prefix: "clicks:",
+	}
+}
+
+// Publish broadcasts the click on the link owner's channel, so every API
+// instance holding a stream for that user receives it.
+func (s *clickStreamService) Publish(ctx context.Context, userId uuid.UUID, event responses.ClickEventResponse) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return s.rdb.Publish(ctx, s.channel(userId), payload).Err()
+}
+
+// Subscribe returns the click events published for the user's links. The
+// channel is closed once ctx is done. Events are dropped while the channel
+// is full, so a slow client never holds up the subscription.
+func (s *clickStreamService) Subscribe(ctx context.Context, userId uuid.UUID) (<-chan responses.ClickEventResponse, error) {
+	pubsub := s.rdb.Subscribe(ctx, s.channel(userId))
+
+	// Wait for the subscription to be confirmed so connection errors surface
+	// to the caller instead of producing a silent, empty stream.
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, err
+	}
+
+	events := make(chan responses.ClickEventResponse, clickStreamBuffer)
+
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var event responses.ClickEventResponse
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					slog.ErrorContext(ctx, "failed to decode click event", "channel", msg.Channel, "error", err)
+					continue
+				}
+
+				select {
+				case events <- event:
+				default:
+					metrics.ClickStreamDropped.Inc()
+					slog.WarnContext(ctx, "dropping click event for a slow stream", "channel", msg.Channel)
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func (s *clickStreamService) channel(userId uuid.UUID) string {
+	return s.prefix + userId.String()
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/andriawan24/link-short/internal/metrics"
+	"github.com/andriawan24/link-short/internal/models/responses"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestClickStreamService(t *testing.T, mr *miniredis.Miniredis) ClickStreamService {
+	t.Helper()
+
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	return NewClickStreamService(rdb)
+}
+
+// receiveClick waits for the next event on events.
+func receiveClick(t *testing.T, events <-chan responses.ClickEventResponse) responses.ClickEventResponse {
+	t.Helper()
+
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatal("stream closed")
+		}
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+	}
+	return responses.ClickEventResponse{}
+}
+
+func TestClickStreamPerUser(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mr := miniredis.RunT(t)
+	publisher := newTestClickStreamService(t, mr)
+	subscriber := newTestClickStreamService(t, mr)
+
+	alice, bob := uuid.New(), uuid.New()
+	aliceEvents, err := subscriber.Subscribe(ctx, alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobEvents, err := subscriber.Subscribe(ctx, bob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := publisher.Publish(ctx, alice, responses.ClickEventResponse{Code: "alice-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := publisher.Publish(ctx, bob, responses.ClickEventResponse{Code: "bob-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Bob's first event is his own, so Alice's click never reached him.
+	if event := receiveClick(t, bobEvents); event.Code != "bob-1" {
+		t.Fatalf("bob received %q, want bob-1", event.Code)
+	}
+	if event := receiveClick(t, aliceEvents); event.Code != "alice-1" {
+		t.Fatalf("alice received %q, want alice-1", event.Code)
+	}
+}
+
+func TestClickStreamUnsubscribe(t *testing.T) {
+	mr := miniredis.RunT(t)
+	streams := newTestClickStreamService(t, mr)
+	userId := uuid.New()
+	channel := "clicks:" + userId.String()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := streams.Subscribe(ctx, userId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subscribers := mr.PubSubNumSub(channel)[channel]; subscribers != 1 {
+		t.Fatalf("subscribers = %d, want 1", subscribers)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Fatal("event received after the stream was cancelled")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("stream not closed after the context was cancelled")
+	}
+	waitFor(t, func() bool { return mr.PubSubNumSub(channel)[channel] == 0 })
+}
+
+func TestClickStreamSubscribeRedisDown(t *testing.T) {
+	mr := miniredis.RunT(t)
+	streams := newTestClickStreamService(t, mr)
+	mr.Close()
+
+	if _, err := streams.Subscribe(context.Background(), uuid.New()); err == nil {
+		t.Fatal("Subscribe succeeded without Redis")
+	}
+}
+
+func TestClickStreamDropsForSlowSubscriber(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mr := miniredis.RunT(t)
+	streams := newTestClickStreamService(t, mr)
+	userId := uuid.New()
+
+	events, err := streams.Subscribe(ctx, userId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const extra = 5
+	dropped := testutil.ToFloat64(metrics.ClickStreamDropped)
+	for i := range clickStreamBuffer + extra {
+		if err := streams.Publish(ctx, userId, responses.ClickEventResponse{Code: strconv.Itoa(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor(t, func() bool { return testutil.ToFloat64(metrics.ClickStreamDropped)-dropped == extra })
+
+	// The buffered events are the oldest ones, and the stream keeps working
+	// once the client catches up.
+	for i := range clickStreamBuffer {
+		if event := receiveClick(t, events); event.Code != strconv.Itoa(i) {
+			t.Fatalf("event %d has code %q", i, event.Code)
+		}
+	}
+	if err := streams.Publish(ctx, userId, responses.ClickEventResponse{Code: "after"}); err != nil {
+		t.Fatal(err)
+	}
+	if event := receiveClick(t, events); event.Code != "after" {
+		t.Fatalf("event after catching up = %q, want after", event.Code)
+	}
+}
@@ -3,19 +3,19 @@ package services
 import (
 	"context"
 
-	"github.com/andriawan24/link-short/internal/database"
 	"github.com/andriawan24/link-short/internal/models/responses"
+	"github.com/andriawan24/link-short/internal/repository"
 )
 
 type dashboardService struct {
-	queries *database.Queries
+	queries repository.DashboardRepository
 }
 
 type DashboardService interface {
 	GetLandingStats(ctx context.Context) (responses.LandingStatsResponse, error)
 }
 
-func NewDashboardService(queries *database.Queries) DashboardService {
+func NewDashboardService(queries repository.DashboardRepository) DashboardService {
 	return &dashboardService{
 		queries: queries,
 	}
@@ -0,0 +1,441 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	dataExportPollInterval  = 30 * time.Second
+	dataExportBatchSize     = 5
+	dataExportLease         = 10 * time.Minute
+	dataExportMaxAttempts   = 3
+	dataExportClickPageSize = 5000
+	dataExportPruneBatch    = 100
+
+	DataExportStatusPending    = "pending"
+	DataExportStatusProcessing = "processing"
+	DataExportStatusReady      = "ready"
+	DataExportStatusFailed     = "failed"
+)
+
+var ErrDataExportNotReady = errors.New("data export is not ready")
+
+type dataExportService struct {
+	queries repository.DataExportRepository
+	users   repository.UserRepository
+	store   BlobStore
+	ttl     time.Duration
+	wake    chan struct{}
+}
+
+type DataExportService interface {
+	// Request returns the latest export of the user. A new one is queued,
+	// and created is true, unless an export is still being built or can
+	// still be downloaded.
+	Request(ctx context.Context, userID uuid.UUID) (export database.DataExport, created bool, err error)
+	Download(ctx context.Context, userID uuid.UUID, exportID uuid.UUID) ([]byte, error)
+	Run(ctx context.Context)
+}
+
+// NewDataExportService builds personal data exports in the background and
+// keeps the resulting ZIP files in store for ttl.
+func NewDataExportService(queries repository.DataExportRepository, users repository.UserRepository, store BlobStore, ttl time.Duration) DataExportService {
+	return &dataExportService{
+		queries: queries,
+		users:   users,
+		store:   store,
+		ttl:     ttl,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+func (s *dataExportService) Request(ctx context.Context, userID uuid.UUID) (database.DataExport, bool, error) {
+	latest, err := s.queries.GetLatestDataExport(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return latest, false, err
+	}
+	if err == nil {
+		switch latest.Status {
+		case DataExportStatusPending, DataExportStatusProcessing:
+			return latest, false, nil
+		case DataExportStatusReady:
+			if latest.ExpiresAt.Valid && latest.ExpiresAt.Time.After(time.Now()) {
+				return latest, false, nil
+			}
+		}
+	}
+
+	export, err := s.queries.InsertDataExport(ctx, userID)
+	if err != nil {
+		return export, false, err
+	}
+
+	// Start on it right away instead of waiting for the next poll.
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	return export, true, nil
+}
+
+func (s *dataExportService) Download(ctx context.Context, userID uuid.UUID, exportID uuid.UUID) ([]byte, error) {
+	export, err := s.queries.GetDataExport(ctx, database.GetDataExportParams{
+		ID:     exportID,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if export.Status != DataExportStatusReady || !export.BlobKey.Valid {
+		return nil, ErrDataExportNotReady
+	}
+
+	data, err := s.store.Get(ctx, export.BlobKey.String)
+	if errors.Is(err, ErrBlobNotFound) {
+		return nil, sql.ErrNoRows
+	}
+	return data, err
+}
+
+// Run builds queued exports and deletes expired ones until ctx is done.
+func (s *dataExportService) Run(ctx context.Context) {
+	ticker := time.NewTicker(dataExportPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.pruneExpired(ctx)
+		case <-s.wake:
+		}
+		s.processPending(ctx)
+	}
+}
+
+func (s *dataExportService) processPending(ctx context.Context) {
+	exports, err := s.queries.ClaimPendingDataExports(ctx, database.ClaimPendingDataExportsParams{
+		LeaseUntil: time.Now().Add(dataExportLease),
+		BatchSize:  dataExportBatchSize,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to claim data exports", "error", err)
+		return
+	}
+
+	for _, export := range exports {
+		s.process(ctx, export)
+	}
+}
+
+func (s *dataExportService) process(ctx context.Context, export database.DataExport) {
+	archive, err := s.build(ctx, export.UserID)
+	if err == nil {
+		key := "exports/" + export.UserID.String() + "/" + export.ID.String() + ".zip"
+		if err = s.store.Put(ctx, key, archive, "application/zip"); err == nil {
+			err = s.queries.CompleteDataExport(ctx, database.CompleteDataExportParams{
+				BlobKey:   sql.NullString{String: key, Valid: true},
+				SizeBytes: sql.NullInt64{Int64: int64(len(archive)), Valid: true},
+				ExpiresAt: sql.NullTime{Time: time.Now().Add(s.ttl), Valid: true},
+				ID:        export.ID,
+			})
+			if err == nil {
+				return
+			}
+		}
+	}
+
+	slog.ErrorContext(ctx, "failed to build data export", "export_id", export.ID, "user_id", export.UserID, "attempt", export.Attempts, "error", err)
+
+	status := DataExportStatusPending
+	expiresAt := sql.NullTime{}
+	if export.Attempts >= dataExportMaxAttempts || errors.Is(err, sql.ErrNoRows) {
+		// Failed exports are kept as long as ready ones so the user sees
+		// what happened, then pruned.
+		status = DataExportStatusFailed
+		expiresAt = sql.NullTime{Time: time.Now().Add(s.ttl), Valid: true}
+	}
+
+	err = s.queries.FailDataExport(context.WithoutCancel(ctx), database.FailDataExportParams{
+		Status:    status,
+		Error:     sql.NullString{String: err.Error(), Valid: true},
+		ExpiresAt: expiresAt,
+		ID:        export.ID,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record data export failure", "export_id", export.ID, "error", err)
+	}
+}
+
+func (s *dataExportService) pruneExpired(ctx context.Context) {
+	exports, err := s.queries.GetExpiredDataExports(ctx, dataExportPruneBatch)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load expired data exports", "error", err)
+		return
+	}
+
+	for _, export := range exports {
+		if export.BlobKey.Valid {
+			if err := s.store.Delete(ctx, export.BlobKey.String); err != nil {
+				slog.ErrorContext(ctx, "failed to delete data export file", "export_id", export.ID, "error", err)
+				continue
+			}
+		}
+		if err := s.queries.DeleteDataExport(ctx, export.ID); err != nil {
+			slog.ErrorContext(ctx, "failed to delete data export", "export_id", export.ID, "error", err)
+		}
+	}
+}
+
+// build writes profile.json, links.json, links.csv and click_logs.csv into
+// a ZIP archive.
+func (s *dataExportService) build(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+	user, err := s.users.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := s.queries.GetUserLinksForExport(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	if err := writeZipJSON(archive, "profile.json", newExportProfile(user)); err != nil {
+		return nil, err
+	}
+
+	exportLinks := make([]exportLink, 0, len(links))
+	for _, link := range links {
+		exportLinks = append(exportLinks, newExportLink(link))
+	}
+	if err := writeZipJSON(archive, "links.json", exportLinks); err != nil {
+		return nil, err
+	}
+
+	linksCSV, err := archive.Create("links.csv")
+	if err != nil {
+		return nil, err
+	}
+	w := csv.NewWriter(linksCSV)
+	w.Write([]string{"id", "original_url", "short_code", "custom_short_code", "active_from", "expired_at", "prelaunch_url", "ios_deep_link", "ios_store_url", "android_deep_link", "android_store_url", "force_preview", "public_stats", "created_at", "updated_at", "deleted_at", "taken_down_at", "takedown_reason"})
+	for _, link := range links {
+		w.Write([]string{
+			link.ID.String(),
+			link.OriginalUrl,
+			link.ShortCode,
+			link.CustomShortCode.String,
+			csvTime(link.ActiveFrom),
+			csvTime(link.ExpiredAt),
+			link.PrelaunchUrl.String,
+			link.IosDeepLink.String,
+			link.IosStoreUrl.String,
+			link.AndroidDeepLink.String,
+			link.AndroidStoreUrl.String,
+			strconv.FormatBool(link.ForcePreview),
+			strconv.FormatBool(link.PublicStats),
+			link.CreatedAt.UTC().Format(time.RFC3339),
+			link.UpdatedAt.UTC().Format(time.RFC3339),
+			csvTime(link.DeletedAt),
+			csvTime(link.TakenDownAt),
+			link.TakedownReason.String,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	if err := s.writeClickLogs(ctx, archive, userID); err != nil {
+		return nil, err
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeClickLogs pages through the clicks of every link the user ever had
+// so large histories are not loaded at once.
+func (s *dataExportService) writeClickLogs(ctx context.Context, archive *zip.Writer, userID uuid.UUID) error {
+	file, err := archive.Create("click_logs.csv")
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(file)
+	w.Write(clickLogCSVHeader)
+
+	params := database.GetUserClickLogsForExportParams{
+		UserID:    userID,
+		BatchSize: dataExportClickPageSize,
+	}
+	for {
+		clicks, err := s.queries.GetUserClickLogsForExport(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		for _, click := range clicks {
+			w.Write(clickLogCSVRecord(click))
+		}
+
+		if len(clicks) < dataExportClickPageSize {
+			break
+		}
+		last := clicks[len(clicks)-1]
+		params.AfterClickedAt = last.ClickedAt
+		params.AfterID = last.ID
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+type exportProfile struct {
+	ID               uuid.UUID `json:"id"`
+	Name             string    `json:"name"`
+	Email            string    `json:"email"`
+	IsVerified       bool      `json:"is_verified"`
+	Role             string    `json:"role"`
+	ProfileImageUrl  string    `json:"profile_image_url,omitempty"`
+	GoogleLinked     bool      `json:"google_linked"`
+	TwoFactorEnabled bool      `json:"two_factor_enabled"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// newExportProfile leaves out credentials such as the password hash and the
+// TOTP secret.
+func newExportProfile(user database.User) exportProfile {
+	return exportProfile{
+		ID:               user.ID,
+		Name:             user.Name,
+		Email:            user.Email,
+		IsVerified:       user.IsVerified,
+		Role:             user.Role,
+		ProfileImageUrl:  user.ProfileImageUrl.String,
+		GoogleLinked:     user.GoogleID.Valid,
+		TwoFactorEnabled: user.TotpEnabled,
+		CreatedAt:        user.CreatedAt,
+		UpdatedAt:        user.UpdatedAt,
+	}
+}
+
+type exportLink struct {
+	ID              uuid.UUID  `json:"id"`
+	OriginalUrl     string     `json:"original_url"`
+	ShortCode       string     `json:"short_code"`
+	CustomShortCode string     `json:"custom_short_code,omitempty"`
+	ActiveFrom      *time.Time `json:"active_from,omitempty"`
+	ExpiredAt       *time.Time `json:"expired_at,omitempty"`
+	PrelaunchURL    string     `json:"prelaunch_url,omitempty"`
+	IOSDeepLink     string     `json:"ios_deep_link,omitempty"`
+	IOSStoreURL     string     `json:"ios_store_url,omitempty"`
+	AndroidDeepLink string     `json:"android_deep_link,omitempty"`
+	AndroidStoreURL string     `json:"android_store_url,omitempty"`
+	ForcePreview    bool       `json:"force_preview"`
+	PublicStats     bool       `json:"public_stats"`
+	MetaTitle       string     `json:"meta_title,omitempty"`
+	MetaDescription string     `json:"meta_description,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+	TakenDownAt     *time.Time `json:"taken_down_at,omitempty"`
+	TakedownReason  string     `json:"takedown_reason,omitempty"`
+}
+
+func newExportLink(link database.Link) exportLink {
+	return exportLink{
+		ID:              link.ID,
+		OriginalUrl:     link.OriginalUrl,
+		ShortCode:       link.ShortCode,
+		CustomShortCode: link.CustomShortCode.String,
+		ActiveFrom:      nullTimePtr(link.ActiveFrom),
+		ExpiredAt:       nullTimePtr(link.ExpiredAt),
+		PrelaunchURL:    link.PrelaunchUrl.String,
+		IOSDeepLink:     link.IosDeepLink.String,
+		IOSStoreURL:     link.IosStoreUrl.String,
+		AndroidDeepLink: link.AndroidDeepLink.String,
+		AndroidStoreURL: link.AndroidStoreUrl.String,
+		ForcePreview:    link.ForcePreview,
+		PublicStats:     link.PublicStats,
+		MetaTitle:       link.MetaTitle.String,
+		MetaDescription: link.MetaDescription.String,
+		CreatedAt:       link.CreatedAt,
+		UpdatedAt:       link.UpdatedAt,
+		DeletedAt:       nullTimePtr(link.DeletedAt),
+		TakenDownAt:     nullTimePtr(link.TakenDownAt),
+		TakedownReason:  link.TakedownReason.String,
+	}
+}
+
+func writeZipJSON(archive *zip.Writer, name string, value any) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+var clickLogCSVHeader = []string{"clicked_at", "code", "ip_address", "user_agent", "referrer", "country", "device_type", "traffic", "browser", "region", "city", "asn"}
+
+// clickLogCSVRecord is the row of click in data exports and click log
+// archives, in the order of clickLogCSVHeader.
+func clickLogCSVRecord(click database.ClickLog) []string {
+	return []string{
+		click.ClickedAt.UTC().Format(time.RFC3339Nano),
+		click.Code,
+		click.IpAddress.String,
+		click.UserAgent.String,
+		click.Referrer.String,
+		click.Country.String,
+		click.DeviceType.String,
+		click.Traffic.String,
+		click.Browser.String,
+		click.Region.String,
+		click.City.String,
+		csvInt(click.Asn),
+	}
+}
+
+func csvInt(n sql.NullInt64) string {
+	if !n.Valid {
+		return ""
+	}
+	return strconv.FormatInt(n.Int64, 10)
+}
+
+func csvTime(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.UTC().Format(time.RFC3339)
+}
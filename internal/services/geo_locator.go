@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/mostafa-asg/ip2country"
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// GeoLocation is where a click came from. Fields the database does not know
+// are left empty.
+type GeoLocation struct {
+	Country string
+	Region  string
+	City    string
+	ASN     int64
+}
+
+type GeoLocator interface {
+	Locate(ipAddress string) GeoLocation
+	// Run keeps the database up to date with the file on disk until ctx is
+	// done.
+	Run(ctx context.Context)
+}
+
+type csvCountryLocator struct{}
+
+// NewCSVCountryLocator resolves countries only, from a DB-IP country CSV
+// read once at startup.
+func NewCSVCountryLocator(path string) (GeoLocator, error) {
+	if err := ip2country.Load(path); err != nil {
+		return nil, err
+	}
+
+	return csvCountryLocator{}, nil
+}
+
+func (csvCountryLocator) Locate(ipAddress string) GeoLocation {
+	return GeoLocation{Country: ip2country.GetCountry(ipAddress)}
+}
+
+// Run returns straight away, the CSV is not reloaded.
+func (csvCountryLocator) Run(ctx context.Context) {}
+
+type MMDBOptions struct {
+	// Path is a MaxMind DB with country, region and city, such as
+	// GeoLite2-City. It may carry the autonomous system as well.
+	Path string
+	// ASNPath optionally points to a separate ASN database, such as
+	// GeoLite2-ASN.
+	ASNPath string
+	// ReloadInterval is how often the files are checked for changes.
+	ReloadInterval time.Duration
+}
+
+// mmdbRecord holds the fields read from both the City and ASN layouts.
+type mmdbRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	Subdivisions []struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"subdivisions"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	AutonomousSystemNumber uint `maxminddb:"autonomous_system_number"`
+}
+
+type mmdbLocator struct {
+	options MMDBOptions
+	city    *mmdbFile
+	asn     *mmdbFile
+}
+
+// NewMMDBLocator resolves country, region, city and ASN from MaxMind DB
+// files. Run reloads a file once it changes on disk; lookups keep using the
+// previous version until the new one has been read and verified.
+func NewMMDBLocator(options MMDBOptions) (GeoLocator, error) {
+	city, err := openMMDBFile(options.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	locator := &mmdbLocator{options: options, city: city}
+	if options.ASNPath != "" {
+		locator.asn, err = openMMDBFile(options.ASNPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return locator, nil
+}
+
+func (l *mmdbLocator) Locate(ipAddress string) GeoLocation {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return GeoLocation{}
+	}
+
+	var record mmdbRecord
+	if err := l.city.lookup(ip, &record); err != nil {
+		slog.Warn("failed to look up IP location", "path", l.city.path, "error", err)
+	}
+	if l.asn != nil {
+		if err := l.asn.lookup(ip, &record); err != nil {
+			slog.Warn("failed to look up IP autonomous system", "path", l.asn.path, "error", err)
+		}
+	}
+
+	location := GeoLocation{
+		Country: record.Country.ISOCode,
+		City:    record.City.Names["en"],
+		ASN:     int64(record.AutonomousSystemNumber),
+	}
+	if len(record.Subdivisions) > 0 {
+		location.Region = record.Subdivisions[0].Names["en"]
+		if location.Region == "" {
+			location.Region = record.Subdivisions[0].ISOCode
+		}
+	}
+	return location
+}
+
+func (l *mmdbLocator) Run(ctx context.Context) {
+	ticker := time.NewTicker(l.options.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			l.city.reloadIfChanged()
+			if l.asn != nil {
+				l.asn.reloadIfChanged()
+			}
+		}
+	}
+}
+
+// mmdbFile is a MaxMind DB read fully into memory, so a reader can be
+// swapped out while lookups on the old one are still running.
+type mmdbFile struct {
+	path    string
+	reader  atomic.Pointer[maxminddb.Reader]
+	modTime time.Time
+	size    int64
+}
+
+func openMMDBFile(path string) (*mmdbFile, error) {
+	file := &mmdbFile{path: path}
+	if err := file.load(); err != nil {
+		return nil, fmt.Errorf("load %s: %w", path, err)
+	}
+	return file, nil
+}
+
+func (f *mmdbFile) load() error {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+
+	reader, err := maxminddb.FromBytes(data)
+	if err != nil {
+		return err
+	}
+	// A file caught halfway through being copied fails verification.
+	if err := reader.Verify(); err != nil {
+		return err
+	}
+
+	f.reader.Store(reader)
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	return nil
+}
+
+// reloadIfChanged is only called from Run, so modTime and size are not
+// shared with lookups.
+func (f *mmdbFile) reloadIfChanged() {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		slog.Warn("failed to check geolocation database", "path", f.path, "error", err)
+		return
+	}
+	if info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return
+	}
+
+	if err := f.load(); err != nil {
+		// The broken version is not retried, only the next change is.
+		f.modTime = info.ModTime()
+		f.size = info.Size()
+		slog.Error("failed to reload geolocation database, keeping the previous version", "path", f.path, "error", err)
+		return
+	}
+	slog.Info("reloaded geolocation database", "path", f.path, "build_epoch", f.reader.Load().Metadata.BuildEpoch)
+}
+
+func (f *mmdbFile) lookup(ip net.IP, record *mmdbRecord) error {
+	reader := f.reader.Load()
+	// An IPv6 address is simply not found in an IPv4 only database.
+	if ip.To4() == nil && reader.Metadata.IPVersion == 4 {
+		return nil
+	}
+	return reader.Lookup(ip, record)
+}
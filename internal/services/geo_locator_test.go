@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMMDBLocator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "city.mmdb")
+	if err := os.WriteFile(path, testMMDB("Bandung"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	locator, err := NewMMDBLocator(MMDBOptions{Path: path, ReloadInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewMMDBLocator: %v", err)
+	}
+
+	want := GeoLocation{Country: "ID", Region: "West Java", City: "Bandung", ASN: 7713}
+	if got := locator.Locate("1.2.3.4"); got != want {
+		t.Fatalf("Locate(1.2.3.4) = %+v, want %+v", got, want)
+	}
+	for _, ip := range []string{"200.1.1.1", "2001:db8::1", "not an ip"} {
+		if got := locator.Locate(ip); got != (GeoLocation{}) {
+			t.Fatalf("Locate(%s) = %+v, want nothing", ip, got)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go locator.Run(ctx)
+
+	// A broken file is ignored and the previous database stays in use.
+	if err := os.WriteFile(path, []byte("not a database"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := locator.Locate("1.2.3.4"); got != want {
+		t.Fatalf("after a broken update Locate(1.2.3.4) = %+v, want %+v", got, want)
+	}
+
+	if err := os.WriteFile(path, testMMDB("Bogor"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for locator.Locate("1.2.3.4").City != "Bogor" {
+		if time.Now().After(deadline) {
+			t.Fatalf("database was not reloaded, city = %q", locator.Locate("1.2.3.4").City)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// testMMDB builds an IPv4 MaxMind DB with a single node, placing 0.0.0.0/1
+// in city and leaving 128.0.0.0/1 unknown.
+func testMMDB(city string) []byte {
+	const nodeCount = 1
+
+	var db []byte
+	// Both records are 24 bits. The left one points at offset 0 of the data
+	// section, the right one equals the node count, which means not found.
+	db = append(db, 0, 0, nodeCount+16, 0, 0, nodeCount)
+	db = append(db, make([]byte, 16)...)
+	db = appendMMDBValue(db, map[string]any{
+		"autonomous_system_number": uint32(7713),
+		"city":                     map[string]any{"names": map[string]any{"en": city}},
+		"country":                  map[string]any{"iso_code": "ID"},
+		"subdivisions": []any{
+			map[string]any{"iso_code": "JB", "names": map[string]any{"en": "West Java"}},
+		},
+	})
+	db = append(db, "\xAB\xCD\xEFMaxMind.com"...)
+	return appendMMDBValue(db, map[string]any{
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"build_epoch":                 uint64(time.Now().Unix()),
+		"database_type":               "Test-City",
+		"description":                 map[string]any{"en": "Test database"},
+		"ip_version":                  uint16(4),
+		"languages":                   []any{"en"},
+		"node_count":                  uint32(nodeCount),
+		"record_size":                 uint16(24),
+	})
+}
+
+// appendMMDBValue encodes the few MaxMind DB types the test databases use.
+// Sizes are assumed to fit in the control byte.
+func appendMMDBValue(b []byte, value any) []byte {
+	control := func(typ byte, size int) []byte {
+		if typ > 7 {
+			return []byte{byte(size), typ - 7}
+		}
+		return []byte{typ<<5 | byte(size)}
+	}
+	unsigned := func(typ byte, n uint64) []byte {
+		raw := binary.BigEndian.AppendUint64(nil, n)
+		for len(raw) > 0 && raw[0] == 0 {
+			raw = raw[1:]
+		}
+		return append(control(typ, len(raw)), raw...)
+	}
+
+	switch v := value.(type) {
+	case string:
+		return append(append(b, control(2, len(v))...), v...)
+	case uint16:
+		return append(b, unsigned(5, uint64(v))...)
+	case uint32:
+		return append(b, unsigned(6, uint64(v))...)
+	case uint64:
+		return append(b, unsigned(9, v)...)
+	case map[string]any:
+		b = append(b, control(7, len(v))...)
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			b = appendMMDBValue(b, key)
+			b = appendMMDBValue(b, v[key])
+		}
+		return b
+	case []any:
+		b = append(b, control(11, len(v))...)
+		for _, item := range v {
+			b = appendMMDBValue(b, item)
+		}
+		return b
+	}
+	panic("unsupported MaxMind DB value")
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/models/responses"
+	"github.com/andriawan24/link-short/internal/repository"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	linkHealthPollInterval     = time.Minute
+	linkHealthRecheckInterval  = 6 * time.Hour
+	linkHealthBatchSize        = 50
+	linkHealthConcurrency      = 8
+	linkHealthHostGap          = 2 * time.Second
+	linkHealthRequestTimeout   = 10 * time.Second
+	linkHealthMaxRedirects     = 10
+	linkHealthFailureThreshold = 3
+	linkHealthRetention        = 30 * 24 * time.Hour
+	linkHealthPruneInterval    = 24 * time.Hour
+	linkHealthUserAgent        = "Pendek.in-HealthCheck/1.0"
+)
+
+var errTooManyRedirects = errors.New("too many redirects")
+
+type LinkHealthResult struct {
+	Success       bool
+	StatusCode    int
+	Latency       time.Duration
+	RedirectChain []string
+	Err           error
+}
+
+type linkHealthWorker struct {
+	queries        repository.LinkRepository
+	webhookService WebhookService
+	client         *http.Client
+}
+
+type LinkHealthWorker interface {
+	Run(ctx context.Context)
+	Check(ctx context.Context, rawURL string) LinkHealthResult
+}
+
+// NewLinkHealthWorker periodically checks link destinations and dispatches a
+// link.broken webhook event when a link is marked as broken. When client is
+// nil a client that refuses to reach private networks is used.
+func NewLinkHealthWorker(queries repository.LinkRepository, webhookService WebhookService, client *http.Client) LinkHealthWorker {
+	if client == nil {
+		client = utils.NewSafeHTTPClient(linkHealthRequestTimeout)
+	}
+
+	// Redirects are followed by hand so every hop can be recorded.
+	noRedirect := *client
+	noRedirect.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return &linkHealthWorker{
+		queries:        queries,
+		webhookService: webhookService,
+		client:         &noRedirect,
+	}
+}
+
+// Run checks links that are due every poll interval until ctx is done. Old
+// check history is pruned once a day.
+func (w *linkHealthWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(linkHealthPollInterval)
+	defer ticker.Stop()
+
+	var lastPrunedAt time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.checkDue(ctx)
+
+			if time.Since(lastPrunedAt) >= linkHealthPruneInterval {
+				w.prune(ctx)
+				lastPrunedAt = time.Now()
+			}
+		}
+	}
+}
+
+// Check requests rawURL with HEAD, falling back to GET for servers that
+// reject HEAD, and follows up to linkHealthMaxRedirects redirects.
+func (w *linkHealthWorker) Check(ctx context.Context, rawURL string) (result LinkHealthResult) {
+	result.RedirectChain = []string{}
+	start := time.Now()
+	defer func() { result.Latency = time.Since(start) }()
+
+	current, err := url.Parse(rawURL)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	method := http.MethodHead
+
+	for hops := 0; ; {
+		statusCode, location, err := w.request(ctx, method, current)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+
+		if method == http.MethodHead && statusCode >= 400 {
+			method = http.MethodGet
+			continue
+		}
+
+		if statusCode >= 300 && statusCode < 400 && location != nil {
+			hops++
+			if hops > linkHealthMaxRedirects {
+				result.StatusCode = statusCode
+				result.Err = errTooManyRedirects
+				return result
+			}
+
+			result.RedirectChain = append(result.RedirectChain, location.String())
+			current = location
+			method = http.MethodHead
+			continue
+		}
+
+		result.StatusCode = statusCode
+		result.Success = isHealthyStatus(statusCode)
+		if !result.Success {
+			result.Err = fmt.Errorf("unexpected status code %d", statusCode)
+		}
+
+		return result
+	}
+}
+
+func (w *linkHealthWorker) request(ctx context.Context, method string, target *url.URL) (int, *url.URL, error) {
+	ctx, cancel := context.WithTimeout(ctx, linkHealthRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("User-Agent", linkHealthUserAgent)
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+
+	// A missing or malformed Location header leaves location nil, which
+	// makes the response final.
+	location, _ := resp.Location()
+
+	return resp.StatusCode, location, nil
+}
+
+// checkDue claims a batch of links and checks them. Links on the same host
+// are checked one after another with a pause in between, while different
+// hosts are checked concurrently.
+func (w *linkHealthWorker) checkDue(ctx context.Context) {
+	links, err := w.queries.ClaimLinksForHealthCheck(ctx, database.ClaimLinksForHealthCheckParams{
+		CheckedBefore: time.Now().Add(-linkHealthRecheckInterval),
+		BatchSize:     linkHealthBatchSize,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to claim links for health check", "error", err)
+		return
+	}
+
+	byHost := make(map[string][]database.ClaimLinksForHealthCheckRow)
+	for _, link := range links {
+		host := link.OriginalUrl
+		if parsed, err := url.Parse(link.OriginalUrl); err == nil {
+			host = strings.ToLower(parsed.Hostname())
+		}
+		byHost[host] = append(byHost[host], link)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		semaphore = make(chan struct{}, linkHealthConcurrency)
+	)
+
+	for _, hostLinks := range byHost {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			for i, link := range hostLinks {
+				if i > 0 {
+					select {
+					case <-ctx.Done():
+						return
+					case <-time.After(linkHealthHostGap):
+					}
+				}
+
+				w.record(ctx, link.ID, w.Check(ctx, link.OriginalUrl))
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (w *linkHealthWorker) record(ctx context.Context, linkId uuid.UUID, result LinkHealthResult) {
+	var errMessage sql.NullString
+	if result.Err != nil {
+		message := result.Err.Error()
+		if len(message) > 500 {
+			message = message[:500]
+		}
+		errMessage = sql.NullString{String: message, Valid: true}
+	}
+
+	err := w.queries.InsertLinkHealthCheck(ctx, database.InsertLinkHealthCheckParams{
+		LinkID:        linkId,
+		Success:       result.Success,
+		StatusCode:    sql.NullInt32{Int32: int32(result.StatusCode), Valid: result.StatusCode != 0},
+		LatencyMs:     int32(result.Latency.Milliseconds()),
+		RedirectChain: result.RedirectChain,
+		Error:         errMessage,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record health check", "link_id", linkId, "error", err)
+		return
+	}
+
+	if result.Success {
+		if err := w.queries.MarkLinkHealthy(ctx, linkId); err != nil {
+			slog.ErrorContext(ctx, "failed to update health status", "link_id", linkId, "error", err)
+		}
+		return
+	}
+
+	link, err := w.queries.MarkLinkHealthFailure(ctx, database.MarkLinkHealthFailureParams{
+		FailureThreshold: linkHealthFailureThreshold,
+		ID:               linkId,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update health status", "link_id", linkId, "error", err)
+		return
+	}
+
+	// A healthy check resets the count, so the threshold is only reached
+	// once for every time the link breaks.
+	if link.ConsecutiveFailures != linkHealthFailureThreshold {
+		return
+	}
+
+	err = w.webhookService.Dispatch(ctx, link.UserID, utils.WebhookEventLinkBroken, responses.MapLinkDetailResponse(link))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to dispatch webhook event", "event", utils.WebhookEventLinkBroken, "link_id", link.ID, "error", err)
+	}
+}
+
+func (w *linkHealthWorker) prune(ctx context.Context) {
+	err := w.queries.DeleteLinkHealthChecksBefore(ctx, time.Now().Add(-linkHealthRetention))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to prune link health checks", "error", err)
+	}
+}
+
+// isHealthyStatus treats auth and rate limit responses as healthy since they
+// prove the destination still exists.
+func isHealthyStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return true
+	}
+
+	return statusCode < 400
+}
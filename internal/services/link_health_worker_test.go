@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/models/responses"
+	"github.com/andriawan24/link-short/internal/repository/memory"
+	"github.com/andriawan24/link-short/internal/utils"
+)
+
+func TestLinkHealthCheck(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/get-only", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/moved-again", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/moved-again", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusFound)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	worker := NewLinkHealthWorker(nil, nil, server.Client())
+
+	for _, tc := range []struct {
+		path    string
+		success bool
+		status  int
+		chain   []string
+		err     error
+	}{
+		{path: "/ok", success: true, status: http.StatusOK},
+		{path: "/get-only", success: true, status: http.StatusOK},
+		{path: "/moved", success: true, status: http.StatusOK, chain: []string{server.URL + "/moved-again", server.URL + "/ok"}},
+		{path: "/login", success: true, status: http.StatusUnauthorized},
+		{path: "/gone", success: false, status: http.StatusNotFound},
+		{path: "/loop", success: false, status: http.StatusFound, err: errTooManyRedirects},
+	} {
+		result := worker.Check(context.Background(), server.URL+tc.path)
+		if result.Success != tc.success || result.StatusCode != tc.status {
+			t.Errorf("Check(%s) = success %v, status %d, error %v; want %v, %d", tc.path, result.Success, result.StatusCode, result.Err, tc.success, tc.status)
+		}
+		if tc.chain != nil && !slices.Equal(result.RedirectChain, tc.chain) {
+			t.Errorf("Check(%s) redirect chain = %v, want %v", tc.path, result.RedirectChain, tc.chain)
+		}
+		if tc.err != nil && !errors.Is(result.Err, tc.err) {
+			t.Errorf("Check(%s) error = %v, want %v", tc.path, result.Err, tc.err)
+		}
+		if tc.success && result.Err != nil {
+			t.Errorf("Check(%s) error = %v on success", tc.path, result.Err)
+		}
+	}
+}
+
+func TestLinkHealthTransitions(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	user, err := store.InsertUser(ctx, database.InsertUserParams{Name: "Ada", Email: "ada@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	link, err := store.InsertLink(ctx, database.InsertLinkParams{OriginalUrl: server.URL, ShortCode: "abc123", UserID: user.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	webhookService := NewWebhookService(store)
+	webhook, err := webhookService.InsertWebhook(ctx, user.ID, server.URL, []string{string(utils.WebhookEventLinkBroken)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	worker := NewLinkHealthWorker(store, webhookService, server.Client()).(*linkHealthWorker)
+	check := func() {
+		t.Helper()
+		worker.record(ctx, link.ID, worker.Check(ctx, link.OriginalUrl))
+	}
+	expectState := func(status utils.LinkHealthStatus, failures int32, notifications int) {
+		t.Helper()
+		got, err := store.GetLink(ctx, database.GetLinkParams{UserID: user.ID, ID: link.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		deliveries, _ := webhookService.GetDeliveries(ctx, webhook.ID, 100, 0)
+		if got.HealthStatus != string(status) || got.ConsecutiveFailures != failures || len(deliveries) != notifications {
+			t.Fatalf("status = %s, failures = %d, notifications = %d; want %s, %d, %d", got.HealthStatus, got.ConsecutiveFailures, len(deliveries), status, failures, notifications)
+		}
+	}
+
+	// The first check goes through the claim, and is recorded in the history.
+	worker.checkDue(ctx)
+	checks, _ := store.GetLinkHealthChecks(ctx, database.GetLinkHealthChecksParams{LinkID: link.ID, Limit: 10})
+	if len(checks) != 1 || checks[0].Success || checks[0].StatusCode.Int32 != http.StatusServiceUnavailable || !checks[0].Error.Valid {
+		t.Fatalf("health checks = %+v, want one failed check", checks)
+	}
+	expectState(utils.LinkHealthUnknown, 1, 0)
+
+	check()
+	expectState(utils.LinkHealthUnknown, 2, 0)
+	check()
+	expectState(utils.LinkHealthBroken, linkHealthFailureThreshold, 1)
+
+	// Staying broken does not notify again.
+	check()
+	check()
+	expectState(utils.LinkHealthBroken, linkHealthFailureThreshold+2, 1)
+
+	healthy.Store(true)
+	check()
+	expectState(utils.LinkHealthHealthy, 0, 1)
+
+	// A single failure is not enough to break a healthy link, but breaking
+	// again after recovering is a new outage.
+	healthy.Store(false)
+	check()
+	expectState(utils.LinkHealthHealthy, 1, 1)
+	check()
+	check()
+	expectState(utils.LinkHealthBroken, linkHealthFailureThreshold, 2)
+
+	deliveries, _ := webhookService.GetDeliveries(ctx, webhook.ID, 1, 0)
+	var payload responses.LinkResponse
+	if err := json.Unmarshal(deliveries[0].Payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if deliveries[0].Event != string(utils.WebhookEventLinkBroken) || payload.ID != link.ID {
+		t.Fatalf("delivery = %s %s, want link.broken for %s", deliveries[0].Event, deliveries[0].Payload, link.ID)
+	}
+}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/google/uuid"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	metadataFetchTimeout   = 10 * time.Second
+	metadataMaxBodySize    = 512 << 10
+	metadataMaxConcurrency = 4
+	metadataUserAgent      = "Pendek.in-LinkPreview/1.0"
+
+	metadataMaxTitleLength       = 300
+	metadataMaxDescriptionLength = 1000
+	metadataMaxURLLength         = 2048
+)
+
+var ErrUnsupportedURL = errors.New("only http and https urls can be fetched")
+
+type LinkMetadata struct {
+	Title       string
+	Description string
+	ImageURL    string
+	FaviconURL  string
+}
+
+type linkMetadataService struct {
+	queries   repository.LinkRepository
+	client    *http.Client
+	semaphore chan struct{}
+}
+
+type LinkMetadataService interface {
+	Fetch(ctx context.Context, rawURL string) (LinkMetadata, error)
+	Refresh(linkId uuid.UUID, originalUrl string)
+}
+
+// NewLinkMetadataService fetches destination previews. When client is nil a
+// client that refuses to reach private networks is used; pass a plain client
+// to fetch from a local test server.
+func NewLinkMetadataService(queries repository.LinkRepository, client *http.Client) LinkMetadataService {
+	if client == nil {
+		client = utils.NewSafeHTTPClient(metadataFetchTimeout)
+	}
+
+	return &linkMetadataService{
+		queries:   queries,
+		client:    client,
+		semaphore: make(chan struct{}, metadataMaxConcurrency),
+	}
+}
+
+// Fetch downloads at most metadataMaxBodySize bytes of the page and extracts
+// its title, description, OpenGraph image and favicon. Relative URLs are
+// resolved against the final URL after redirects.
+func (s *linkMetadataService) Fetch(ctx context.Context, rawURL string) (LinkMetadata, error) {
+	var metadata LinkMetadata
+
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return metadata, err
+	}
+
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return metadata, ErrUnsupportedURL
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, metadataFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return metadata, err
+	}
+	req.Header.Set("User-Agent", metadataUserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.1")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return metadata, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return metadata, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	base := resp.Request.URL
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	if mediaType == "text/html" || mediaType == "application/xhtml+xml" {
+		body, err := charset.NewReader(io.LimitReader(resp.Body, metadataMaxBodySize), contentType)
+		if err != nil {
+			return metadata, err
+		}
+
+		metadata = parseHTMLMetadata(body, base)
+	}
+
+	if metadata.FaviconURL == "" {
+		metadata.FaviconURL = resolveMetadataURL(base, "/favicon.ico")
+	}
+
+	return metadata, nil
+}
+
+// Refresh fetches the metadata in the background and stores it on the link.
+// The result is dropped if the link's destination changed in the meantime.
+func (s *linkMetadataService) Refresh(linkId uuid.UUID, originalUrl string) {
+	go func() {
+		s.semaphore <- struct{}{}
+		defer func() { <-s.semaphore }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*metadataFetchTimeout)
+		defer cancel()
+
+		metadata, err := s.Fetch(ctx, originalUrl)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to fetch link metadata", "link_id", linkId, "error", err)
+			return
+		}
+
+		err = s.queries.UpdateLinkMetadata(ctx, database.UpdateLinkMetadataParams{
+			MetaTitle:       nullMetadataString(metadata.Title, metadataMaxTitleLength),
+			MetaDescription: nullMetadataString(metadata.Description, metadataMaxDescriptionLength),
+			MetaImageUrl:    nullMetadataString(metadata.ImageURL, metadataMaxURLLength),
+			MetaFaviconUrl:  nullMetadataString(metadata.FaviconURL, metadataMaxURLLength),
+			ID:              linkId,
+			OriginalUrl:     originalUrl,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to store link metadata", "link_id", linkId, "error", err)
+		}
+	}()
+}
+
+// parseHTMLMetadata walks the document head. OpenGraph values win over the
+// plain <title> and description tags.
+func parseHTMLMetadata(body io.Reader, base *url.URL) LinkMetadata {
+	var (
+		metadata           LinkMetadata
+		title, description string
+		ogTitle, ogDesc    string
+		inTitle            bool
+	)
+
+	tokenizer := html.NewTokenizer(body)
+
+loop:
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			break loop
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "body":
+				break loop
+			case "title":
+				inTitle = tokenType == html.StartTagToken
+			case "meta":
+				key := strings.ToLower(htmlAttr(token, "property"))
+				if key == "" {
+					key = strings.ToLower(htmlAttr(token, "name"))
+				}
+				content := strings.TrimSpace(htmlAttr(token, "content"))
+
+				switch key {
+				case "og:title":
+					ogTitle = content
+				case "og:description":
+					ogDesc = content
+				case "description":
+					description = content
+				case "og:image", "og:image:url", "og:image:secure_url", "twitter:image":
+					if metadata.ImageURL == "" {
+						metadata.ImageURL = resolveMetadataURL(base, content)
+					}
+				}
+			case "link":
+				rel := strings.Fields(strings.ToLower(htmlAttr(token, "rel")))
+				for _, value := range rel {
+					if value == "icon" || value == "apple-touch-icon" {
+						if metadata.FaviconURL == "" {
+							metadata.FaviconURL = resolveMetadataURL(base, htmlAttr(token, "href"))
+						}
+						break
+					}
+				}
+			}
+		case html.EndTagToken:
+			switch tokenizer.Token().Data {
+			case "title":
+				inTitle = false
+			case "head":
+				break loop
+			}
+		case html.TextToken:
+			if inTitle && title == "" {
+				title = strings.Join(strings.Fields(string(tokenizer.Text())), " ")
+			}
+		}
+	}
+
+	metadata.Title = firstNonEmpty(ogTitle, title)
+	metadata.Description = firstNonEmpty(ogDesc, description)
+
+	return metadata
+}
+
+func htmlAttr(token html.Token, key string) string {
+	for _, attr := range token.Attr {
+		if strings.EqualFold(attr.Key, key) {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// resolveMetadataURL makes ref absolute and drops anything that is not an
+// http or https URL, such as data: or javascript: values.
+func resolveMetadataURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+
+	parsed, err := base.Parse(ref)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return ""
+	}
+
+	return parsed.String()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func nullMetadataString(value string, maxLength int) sql.NullString {
+	value = strings.TrimSpace(value)
+	if len(value) > maxLength {
+		value = value[:maxLength]
+		for !utf8.ValidString(value) {
+			value = value[:len(value)-1]
+		}
+	}
+
+	return sql.NullString{String: value, Valid: value != ""}
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/andriawan24/link-short/internal/utils"
+)
+
+func TestLinkMetadataFetch(t *testing.T) {
+	pages := map[string]string{
+		"/article": `<!doctype html><html><head>
+			<title>
+				Plain   title
+			</title>
+			<meta name="description" content="Plain description">
+			<meta property="og:title" content=" OpenGraph title ">
+			<meta property="og:description" content="OpenGraph description">
+			<meta property="og:image" content="javascript:alert(1)">
+			<meta name="twitter:image" content="/images/card.png">
+			<link rel="shortcut icon" href="../favicon.png">
+			</head><body><meta property="og:image" content="/late.png"></body></html>`,
+		"/plain":      `<html><head><title>Only a title</title><meta name="description" content="Only a description"></head></html>`,
+		"/body-first": `<html><body><title>Not in the head</title></body></html>`,
+		"/huge":       "<html><head><!--" + strings.Repeat("x", metadataMaxBodySize) + "--><title>Past the limit</title></head></html>",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/moved":
+			http.Redirect(w, r, "/blog/posts/article", http.StatusMovedPermanently)
+		case "/latin1":
+			w.Header().Set("Content-Type", "text/html; charset=iso-8859-1")
+			w.Write([]byte("<title>Caf\xe9</title>"))
+		case "/image.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("<title>Not HTML</title>"))
+		case "/missing":
+			http.NotFound(w, r)
+		default:
+			page, ok := pages[strings.TrimPrefix(r.URL.Path, "/blog/posts")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(page))
+		}
+	}))
+	defer server.Close()
+
+	service := NewLinkMetadataService(nil, server.Client())
+
+	for _, tc := range []struct {
+		path string
+		want LinkMetadata
+	}{
+		{
+			// Relative URLs resolve against the page after the redirect.
+			path: "/moved",
+			want: LinkMetadata{
+				Title:       "OpenGraph title",
+				Description: "OpenGraph description",
+				ImageURL:    server.URL + "/images/card.png",
+				FaviconURL:  server.URL + "/blog/favicon.png",
+			},
+		},
+		{
+			path: "/plain",
+			want: LinkMetadata{Title: "Only a title", Description: "Only a description", FaviconURL: server.URL + "/favicon.ico"},
+		},
+		{path: "/body-first", want: LinkMetadata{FaviconURL: server.URL + "/favicon.ico"}},
+		{path: "/huge", want: LinkMetadata{FaviconURL: server.URL + "/favicon.ico"}},
+		{path: "/latin1", want: LinkMetadata{Title: "Café", FaviconURL: server.URL + "/favicon.ico"}},
+		{path: "/image.png", want: LinkMetadata{FaviconURL: server.URL + "/favicon.ico"}},
+	} {
+		got, err := service.Fetch(context.Background(), server.URL+tc.path)
+		if err != nil {
+			t.Fatalf("Fetch(%s): %v", tc.path, err)
+		}
+		if got != tc.want {
+			t.Errorf("Fetch(%s) = %+v, want %+v", tc.path, got, tc.want)
+		}
+	}
+
+	if _, err := service.Fetch(context.Background(), server.URL+"/missing"); err == nil {
+		t.Error("Fetch of a missing page succeeded, want an error")
+	}
+	if _, err := service.Fetch(context.Background(), "ftp://example.com/file"); !errors.Is(err, ErrUnsupportedURL) {
+		t.Errorf("Fetch(ftp) error = %v, want %v", err, ErrUnsupportedURL)
+	}
+
+	// Without a client of its own the service refuses the loopback server.
+	if _, err := NewLinkMetadataService(nil, nil).Fetch(context.Background(), server.URL+"/plain"); !errors.Is(err, utils.ErrBlockedAddress) {
+		t.Errorf("Fetch with the default client: error = %v, want %v", err, utils.ErrBlockedAddress)
+	}
+}
+
+func TestNullMetadataString(t *testing.T) {
+	for _, tc := range []struct {
+		value     string
+		maxLength int
+		want      string
+	}{
+		{"  title  ", 10, "title"},
+		{"   ", 10, ""},
+		{"abcdef", 4, "abcd"},
+		// A multi-byte character cut in half is dropped entirely.
+		{"ab日本", 4, "ab"},
+		{"日本語", 6, "日本"},
+	} {
+		got := nullMetadataString(tc.value, tc.maxLength)
+		if got.String != tc.want || got.Valid != (tc.want != "") || !utf8.ValidString(got.String) {
+			t.Errorf("nullMetadataString(%q, %d) = %+v, want %q", tc.value, tc.maxLength, got, tc.want)
+		}
+	}
+}
@@ -2,30 +2,48 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository"
 	"github.com/andriawan24/link-short/internal/utils"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"golang.org/x/sync/singleflight"
 )
 
+// redirectLookupTimeout bounds the shared redirect query, which no longer
+// stops when the request that started it goes away.
+const redirectLookupTimeout = 5 * time.Second
+
 type linkService struct {
-	queries *database.Queries
+	queries          repository.LinkRepository
+	clickLogs        repository.ClickLogRepository
+	shortCodeService ShortCodeService
+	redirects        singleflight.Group
 }
 
 type LinkService interface {
 	GetTotalCounts(ctx context.Context, userId uuid.UUID, from time.Time, to time.Time) (int64, error)
 	GetTotalActiveLinks(ctx context.Context, userId uuid.UUID) (int64, error)
-	GetLinks(ctx context.Context, userId uuid.UUID, limit int32, offset int32, orderBy utils.LinkOrderBy) ([]database.GetLinksRow, error)
+	GetLinks(ctx context.Context, userId uuid.UUID, limit int32, offset int32, orderBy utils.LinkOrderBy, health utils.LinkHealthStatus, state utils.LinkState) ([]database.GetLinksRow, error)
 	GetLink(ctx context.Context, userId uuid.UUID, id uuid.UUID) (database.GetLinkRow, error)
-	GetRedirectedLink(ctx context.Context, shortCode string) (string, error)
+	GetLinkHealthChecks(ctx context.Context, linkId uuid.UUID, limit int32) ([]database.LinkHealthCheck, error)
+	GetLinkByCode(ctx context.Context, code string) (database.GetLinkByCodeRow, error)
+	GetRedirectedLink(ctx context.Context, shortCode string) (database.GetRedirectLinkRow, error)
+	GetLinkPreview(ctx context.Context, shortCode string) (database.GetLinkPreviewRow, error)
 	InsertLink(ctx context.Context, param database.InsertLinkParams) (database.Link, error)
+	UpdateLink(ctx context.Context, param database.UpdateLinkParams) (database.Link, error)
 	DeleteLink(ctx context.Context, param database.DeleteLinkParams) error
 }
 
-func NewLinkService(queries *database.Queries) LinkService {
+func NewLinkService(queries repository.LinkRepository, clickLogs repository.ClickLogRepository, shortCodeService ShortCodeService) LinkService {
 	return &linkService{
-		queries: queries,
+		queries:          queries,
+		clickLogs:        clickLogs,
+		shortCodeService: shortCodeService,
 	}
 }
 
@@ -43,12 +61,16 @@ func (l *linkService) GetLink(ctx context.Context, userId uuid.UUID, id uuid.UUI
 	return link, nil
 }
 
-func (l *linkService) GetLinks(ctx context.Context, userId uuid.UUID, limit int32, offset int32, orderBy utils.LinkOrderBy) ([]database.GetLinksRow, error) {
+// GetLinks returns every link of the user, or only those with the given
+// health status and activation state when health or state are not empty.
+func (l *linkService) GetLinks(ctx context.Context, userId uuid.UUID, limit int32, offset int32, orderBy utils.LinkOrderBy, health utils.LinkHealthStatus, state utils.LinkState) ([]database.GetLinksRow, error) {
 	param := database.GetLinksParams{
-		UserID:  userId,
-		Limit:   limit,
-		Offset:  offset,
-		OrderBy: orderBy.GetString(),
+		UserID:       userId,
+		Limit:        limit,
+		Offset:       offset,
+		OrderBy:      orderBy.GetString(),
+		HealthStatus: sql.NullString{String: string(health), Valid: health != ""},
+		State:        sql.NullString{String: string(state), Valid: state != ""},
 	}
 
 	links, err := l.queries.GetLinks(ctx, param)
@@ -59,8 +81,24 @@ func (l *linkService) GetLinks(ctx context.Context, userId uuid.UUID, limit int3
 	return links, nil
 }
 
-func (l *linkService) GetRedirectedLink(ctx context.Context, shortCode string) (string, error) {
-	link, err := l.queries.GetRedirectLink(ctx, shortCode)
+func (l *linkService) GetLinkHealthChecks(ctx context.Context, linkId uuid.UUID, limit int32) ([]database.LinkHealthCheck, error) {
+	checks, err := l.queries.GetLinkHealthChecks(ctx, database.GetLinkHealthChecksParams{
+		LinkID: linkId,
+		Limit:  limit,
+	})
+	if err != nil {
+		return checks, err
+	}
+
+	if checks == nil {
+		checks = []database.LinkHealthCheck{}
+	}
+
+	return checks, nil
+}
+
+func (l *linkService) GetLinkByCode(ctx context.Context, code string) (database.GetLinkByCodeRow, error) {
+	link, err := l.queries.GetLinkByCode(ctx, code)
 	if err != nil {
 		return link, err
 	}
@@ -68,8 +106,62 @@ func (l *linkService) GetRedirectedLink(ctx context.Context, shortCode string) (
 	return link, nil
 }
 
+func (l *linkService) GetLinkPreview(ctx context.Context, shortCode string) (database.GetLinkPreviewRow, error) {
+	preview, err := l.queries.GetLinkPreview(ctx, shortCode)
+	if err != nil {
+		return preview, err
+	}
+
+	return preview, nil
+}
+
+// GetRedirectedLink shares a single query between concurrent lookups of the
+// same code, so a popular code missing from the cache does not flood the
+// database. The query is detached from the caller's cancellation since other
+// requests may be waiting on it, and bounded by redirectLookupTimeout instead.
+func (l *linkService) GetRedirectedLink(ctx context.Context, shortCode string) (database.GetRedirectLinkRow, error) {
+	result, err, _ := l.redirects.Do(shortCode, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), redirectLookupTimeout)
+		defer cancel()
+
+		return l.queries.GetRedirectLink(ctx, shortCode)
+	})
+	if err != nil {
+		return database.GetRedirectLinkRow{}, err
+	}
+
+	return result.(database.GetRedirectLinkRow), nil
+}
+
+// InsertLink generates the short code when param.ShortCode is empty and
+// retries with a fresh one if it loses a race for the same code.
 func (l *linkService) InsertLink(ctx context.Context, param database.InsertLinkParams) (database.Link, error) {
-	link, err := l.queries.InsertLink(ctx, param)
+	if param.ShortCode != "" {
+		return l.queries.InsertLink(ctx, param)
+	}
+
+	var (
+		link database.Link
+		err  error
+	)
+
+	for range l.shortCodeService.MaxAttempts() {
+		param.ShortCode, err = l.shortCodeService.Generate(ctx)
+		if err != nil {
+			return link, err
+		}
+
+		link, err = l.queries.InsertLink(ctx, param)
+		if !isUniqueViolation(err, "links_short_code_key") {
+			return link, err
+		}
+	}
+
+	return link, ErrShortCodeExhausted
+}
+
+func (l *linkService) UpdateLink(ctx context.Context, param database.UpdateLinkParams) (database.Link, error) {
+	link, err := l.queries.UpdateLink(ctx, param)
 	if err != nil {
 		return link, err
 	}
@@ -84,7 +176,7 @@ func (l *linkService) GetTotalCounts(ctx context.Context, userId uuid.UUID, from
 		ToDate:   to,
 	}
 
-	count, err := l.queries.GetTotalClicks(ctx, param)
+	count, err := l.clickLogs.GetTotalClicks(ctx, param)
 	if err != nil {
 		return 0, err
 	}
@@ -105,3 +197,8 @@ func (l *linkService) DeleteLink(ctx context.Context, param database.DeleteLinkP
 	err := l.queries.DeleteLink(ctx, param)
 	return err
 }
+
+func isUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository"
+)
+
+// redirectLinks records how the context of a redirect lookup looked while
+// the query ran.
+type redirectLinks struct {
+	repository.LinkRepository
+	deadline    time.Time
+	hasDeadline bool
+	err         error
+}
+
+func (r *redirectLinks) GetRedirectLink(ctx context.Context, shortCode string) (database.GetRedirectLinkRow, error) {
+	r.deadline, r.hasDeadline = ctx.Deadline()
+	r.err = ctx.Err()
+	return database.GetRedirectLinkRow{OriginalUrl: "https://example.com"}, nil
+}
+
+func TestGetRedirectedLinkContext(t *testing.T) {
+	links := &redirectLinks{}
+	service := NewLinkService(links, nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := service.GetRedirectedLink(ctx, "abc123"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The lookup outlives a cancelled caller, but not by more than the
+	// lookup timeout.
+	if links.err != nil {
+		t.Fatalf("lookup ran with a done context: %v", links.err)
+	}
+	if !links.hasDeadline || time.Until(links.deadline) > redirectLookupTimeout {
+		t.Fatalf("lookup deadline = %v (set %v), want within %v", links.deadline, links.hasDeadline, redirectLookupTimeout)
+	}
+}
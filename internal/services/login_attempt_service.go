@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	loginAttemptWindow = 15 * time.Minute
+
+	accountDelayAfterFailures   = 3
+	accountLockoutAfterFailures = 10
+	ipDelayAfterFailures        = 20
+	ipLockoutAfterFailures      = 50
+
+	loginBaseDelay = time.Second
+	loginMaxDelay  = 30 * time.Second
+	loginLockout   = 15 * time.Minute
+
+	LoginReasonInvalidPassword  = "invalid_password"
+	LoginReasonUnknownEmail     = "unknown_email"
+	LoginReasonAccountInactive  = "account_inactive"
+	LoginReasonInvalidTwoFactor = "invalid_2fa_code"
+	LoginReasonPassword         = "password"
+	LoginReasonTwoFactor        = "2fa"
+	LoginReasonGoogle           = "google"
+)
+
+// LoginThrottledError is returned while an account or IP address has to wait
+// before trying to sign in again.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("too many failed sign-in attempts, try again in %d minutes", int(e.RetryAfter.Minutes())+1)
+	}
+	return fmt.Sprintf("too many failed sign-in attempts, try again in %d seconds", int(e.RetryAfter.Seconds())+1)
+}
+
+type LoginAttempt struct {
+	UserID    uuid.NullUUID
+	Email     string
+	IPAddress string
+	UserAgent string
+	Success   bool
+	Reason    string
+}
+
+type loginAttemptService struct {
+	queries repository.LoginAttemptRepository
+}
+
+type LoginAttemptService interface {
+	CheckAllowed(ctx context.Context, email string, ipAddress string) error
+	RecordAttempt(ctx context.Context, attempt LoginAttempt) error
+	GetRecentAttempts(ctx context.Context, userId uuid.UUID, limit int32) ([]database.LoginAttempt, error)
+}
+
+func NewLoginAttemptService(queries repository.LoginAttemptRepository) LoginAttemptService {
+	return &loginAttemptService{
+		queries: queries,
+	}
+}
+
+// CheckAllowed returns a *LoginThrottledError when either the account or the
+// IP address has failed too often within the attempt window. Failures are
+// counted per account since its last successful sign-in.
+func (s *loginAttemptService) CheckAllowed(ctx context.Context, email string, ipAddress string) error {
+	since := time.Now().Add(-loginAttemptWindow)
+
+	account, err := s.queries.GetEmailLoginFailures(ctx, database.GetEmailLoginFailuresParams{
+		Email: normalizeLoginEmail(email),
+		Since: since,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := throttle(account.Failures, account.LastFailureAt, accountDelayAfterFailures, accountLockoutAfterFailures); err != nil {
+		return err
+	}
+
+	ip, err := s.queries.GetIPLoginFailures(ctx, database.GetIPLoginFailuresParams{
+		IpAddress: ipAddress,
+		Since:     since,
+	})
+	if err != nil {
+		return err
+	}
+
+	return throttle(ip.Failures, ip.LastFailureAt, ipDelayAfterFailures, ipLockoutAfterFailures)
+}
+
+func (s *loginAttemptService) RecordAttempt(ctx context.Context, attempt LoginAttempt) error {
+	return s.queries.InsertLoginAttempt(ctx, database.InsertLoginAttemptParams{
+		UserID:    attempt.UserID,
+		Email:     normalizeLoginEmail(attempt.Email),
+		IpAddress: attempt.IPAddress,
+		UserAgent: sql.NullString{String: attempt.UserAgent, Valid: attempt.UserAgent != ""},
+		Success:   attempt.Success,
+		Reason:    sql.NullString{String: attempt.Reason, Valid: attempt.Reason != ""},
+	})
+}
+
+func (s *loginAttemptService) GetRecentAttempts(ctx context.Context, userId uuid.UUID, limit int32) ([]database.LoginAttempt, error) {
+	attempts, err := s.queries.GetUserLoginAttempts(ctx, database.GetUserLoginAttemptsParams{
+		UserID: uuid.NullUUID{UUID: userId, Valid: true},
+		Limit:  limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+// throttle doubles the required wait with every failure past delayAfter and
+// locks out completely once lockoutAfter failures are reached.
+func throttle(failures int64, lastFailureAt time.Time, delayAfter int64, lockoutAfter int64) error {
+	if failures < delayAfter {
+		return nil
+	}
+
+	if failures >= lockoutAfter {
+		if retryAfter := time.Until(lastFailureAt.Add(loginLockout)); retryAfter > 0 {
+			return &LoginThrottledError{RetryAfter: retryAfter, Locked: true}
+		}
+		return nil
+	}
+
+	delay := loginBaseDelay << (failures - delayAfter)
+	if delay > loginMaxDelay {
+		delay = loginMaxDelay
+	}
+
+	if retryAfter := time.Until(lastFailureAt.Add(delay)); retryAfter > 0 {
+		return &LoginThrottledError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+func normalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+var (
+	ErrImageTooLarge    = errors.New("image exceeds the upload size limit")
+	ErrUnsupportedImage = errors.New("unsupported image type. Allowed: jpeg, png, gif, webp")
+	ErrImageDimensions  = errors.New("image dimensions are too large")
+)
+
+// ProfileImageSizes are the square avatar sizes, in pixels, generated for
+// every upload.
+var ProfileImageSizes = []int{64, 128, 256}
+
+const (
+	// maxProfileImagePixels rejects decompression bombs before decoding.
+	maxProfileImagePixels   = 40_000_000
+	profileImageJPEGQuality = 85
+)
+
+type ProfileImageService interface {
+	// Save validates and resizes an uploaded image and stores one copy per
+	// entry in ProfileImageSizes. Every copy shares a directory and is named
+	// after its size, e.g. ".../64.jpg"; the URL of the largest is returned.
+	Save(ctx context.Context, userID uuid.UUID, upload io.Reader) (string, error)
+	// Delete removes every size of an image previously returned by Save.
+	// URLs this service did not create are ignored.
+	Delete(ctx context.Context, imageURL string) error
+}
+
+type profileImageService struct {
+	store    BlobStore
+	maxBytes int64
+}
+
+func NewProfileImageService(store BlobStore, maxBytes int64) ProfileImageService {
+	return &profileImageService{
+		store:    store,
+		maxBytes: maxBytes,
+	}
+}
+
+func (s *profileImageService) Save(ctx context.Context, userID uuid.UUID, upload io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(upload, s.maxBytes+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) > s.maxBytes {
+		return "", ErrImageTooLarge
+	}
+
+	// Trust the content, not the file name or the client's Content-Type.
+	decode, decodeConfig := imageDecoder(http.DetectContentType(data))
+	if decode == nil {
+		return "", ErrUnsupportedImage
+	}
+
+	config, err := decodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrUnsupportedImage
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxProfileImagePixels {
+		return "", ErrImageDimensions
+	}
+
+	src, err := decode(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrUnsupportedImage
+	}
+
+	// Re-encoding drops EXIF and any other metadata, so the orientation it
+	// carries has to be applied to the pixels first.
+	src = applyOrientation(src, jpegOrientation(data))
+	square := centerSquare(src)
+
+	prefix := path.Join("profiles", userID.String(), uuid.NewString())
+	var stored []string
+	var imageURL string
+
+	for _, size := range ProfileImageSizes {
+		dst := image.NewRGBA(image.Rect(0, 0, size, size))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, square, draw.Src, nil)
+
+		encoded, ext, contentType, err := encodeProfileImage(dst)
+		if err != nil {
+			return "", err
+		}
+
+		key := path.Join(prefix, strconv.Itoa(size)+ext)
+		if err := s.store.Put(ctx, key, encoded, contentType); err != nil {
+			for _, key := range stored {
+				s.store.Delete(context.WithoutCancel(ctx), key)
+			}
+			return "", err
+		}
+
+		stored = append(stored, key)
+		imageURL = s.store.URL(key)
+	}
+
+	return imageURL, nil
+}
+
+func (s *profileImageService) Delete(ctx context.Context, imageURL string) error {
+	key, ok := s.store.KeyFromURL(imageURL)
+	if !ok {
+		return nil
+	}
+
+	largest := ProfileImageSizes[len(ProfileImageSizes)-1]
+	dir, file := path.Split(key)
+	ext := path.Ext(file)
+	if strings.TrimSuffix(file, ext) != strconv.Itoa(largest) {
+		// Uploaded before images were resized: a single object.
+		return s.store.Delete(ctx, key)
+	}
+
+	var errs []error
+	for _, size := range ProfileImageSizes {
+		if err := s.store.Delete(ctx, dir+strconv.Itoa(size)+ext); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func imageDecoder(contentType string) (func(io.Reader) (image.Image, error), func(io.Reader) (image.Config, error)) {
+	switch contentType {
+	case "image/jpeg":
+		return jpeg.Decode, jpeg.DecodeConfig
+	case "image/png":
+		return png.Decode, png.DecodeConfig
+	case "image/gif":
+		return gif.Decode, gif.DecodeConfig
+	case "image/webp":
+		return webp.Decode, webp.DecodeConfig
+	default:
+		return nil, nil
+	}
+}
+
+// encodeProfileImage writes opaque images as JPEG and keeps PNG for images
+// with transparency.
+func encodeProfileImage(img *image.RGBA) ([]byte, string, string, error) {
+	var buf bytes.Buffer
+	if img.Opaque() {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: profileImageJPEGQuality}); err != nil {
+			return nil, "", "", err
+		}
+		return buf.Bytes(), ".jpg", "image/jpeg", nil
+	}
+
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", "", err
+	}
+	return buf.Bytes(), ".png", "image/png", nil
+}
+
+func centerSquare(img image.Image) image.Rectangle {
+	b := img.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x := b.Min.X + (b.Dx()-side)/2
+	y := b.Min.Y + (b.Dy()-side)/2
+	return image.Rect(x, y, x+side, y+side)
+}
+
+// jpegOrientation returns the EXIF orientation tag (1-8) of a JPEG, or 1 when
+// there is none.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 {
+			// Start of scan: metadata segments come before it.
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return 1
+		}
+		if marker == 0xE1 {
+			if orientation, ok := exifOrientation(data[i+4 : end]); ok {
+				return orientation
+			}
+		}
+		i = end
+	}
+	return 1
+}
+
+func exifOrientation(segment []byte) (int, bool) {
+	tiff, ok := bytes.CutPrefix(segment, []byte("Exif\x00\x00"))
+	if !ok || len(tiff) < 8 {
+		return 0, false
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0, false
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0, false
+	}
+
+	entries := int(order.Uint16(tiff[ifd:]))
+	for n := 0; n < entries; n++ {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 0, false
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 0, false
+			}
+			return orientation, true
+		}
+	}
+	return 0, false
+}
+
+// applyOrientation transforms img so it displays upright once the EXIF
+// orientation tag is gone.
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if orientation >= 5 {
+		w, h = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored horizontally
+				dx, dy = b.Dx()-1-x, y
+			case 3: // rotated 180°
+				dx, dy = b.Dx()-1-x, b.Dy()-1-y
+			case 4: // mirrored vertically
+				dx, dy = x, b.Dy()-1-y
+			case 5: // transposed
+				dx, dy = y, x
+			case 6: // rotated 90° clockwise
+				dx, dy = b.Dy()-1-y, x
+			case 7: // transversed
+				dx, dy = b.Dy()-1-y, b.Dx()-1-x
+			case 8: // rotated 90° counter-clockwise
+				dx, dy = y, b.Dx()-1-x
+			}
+			dst.Set(dx, dy, color.RGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)))
+		}
+	}
+	return dst
+}
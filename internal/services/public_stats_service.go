@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/models/responses"
+	"github.com/andriawan24/link-short/internal/repository"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const publicStatsKeyPrefix = "public_stats:"
+
+var publicStatsRanges = []utils.TimeRange{utils.TimeRange7Days, utils.TimeRange30Days, utils.TimeRange90Days, utils.TimeRangeAll}
+
+type publicStatsService struct {
+	links     repository.LinkRepository
+	clickLogs repository.ClickLogRepository
+	rdb       *redis.Client
+	ttl       time.Duration
+}
+
+type PublicStatsService interface {
+	// SetEnabled turns public stats of a link on or off. The share token is
+	// created the first time and kept afterwards.
+	SetEnabled(ctx context.Context, userId uuid.UUID, linkId uuid.UUID, enabled bool) (database.Link, error)
+	// RotateToken replaces the share token, so links shared before stop
+	// working.
+	RotateToken(ctx context.Context, userId uuid.UUID, linkId uuid.UUID) (database.Link, error)
+	GetStats(ctx context.Context, token string, timeRange utils.TimeRange) (responses.PublicLinkStatsResponse, error)
+	// Invalidate drops the cached statistics shared under token.
+	Invalidate(ctx context.Context, token string)
+}
+
+// NewPublicStatsService serves link statistics to people without an
+// account. Responses are cached in Redis for ttl, so a widely shared link
+// does not run the analytics queries on every view.
+func NewPublicStatsService(links repository.LinkRepository, clickLogs repository.ClickLogRepository, rdb *redis.Client, ttl time.Duration) PublicStatsService {
+	return &publicStatsService{
+		links:     links,
+		clickLogs: clickLogs,
+		rdb:       rdb,
+		ttl:       ttl,
+	}
+}
+
+func (s *publicStatsService) SetEnabled(ctx context.Context, userId uuid.UUID, linkId uuid.UUID, enabled bool) (database.Link, error) {
+	token, err := generateShareToken()
+	if err != nil {
+		return database.Link{}, err
+	}
+
+	link, err := s.links.SetLinkPublicStats(ctx, database.SetLinkPublicStatsParams{
+		PublicStats:     enabled,
+		StatsShareToken: token,
+		ID:              linkId,
+		UserID:          userId,
+	})
+	if err != nil {
+		return link, err
+	}
+
+	if !enabled {
+		s.Invalidate(ctx, link.StatsShareToken.String)
+	}
+
+	return link, nil
+}
+
+func (s *publicStatsService) RotateToken(ctx context.Context, userId uuid.UUID, linkId uuid.UUID) (database.Link, error) {
+	token, err := generateShareToken()
+	if err != nil {
+		return database.Link{}, err
+	}
+
+	previous, err := s.links.GetLink(ctx, database.GetLinkParams{UserID: userId, ID: linkId})
+	if err != nil {
+		return database.Link{}, err
+	}
+
+	link, err := s.links.RotateLinkStatsToken(ctx, database.RotateLinkStatsTokenParams{
+		StatsShareToken: token,
+		ID:              linkId,
+		UserID:          userId,
+	})
+	if err != nil {
+		return link, err
+	}
+
+	if previous.StatsShareToken.Valid {
+		s.Invalidate(ctx, previous.StatsShareToken.String)
+	}
+
+	return link, nil
+}
+
+func (s *publicStatsService) GetStats(ctx context.Context, token string, timeRange utils.TimeRange) (responses.PublicLinkStatsResponse, error) {
+	var stats responses.PublicLinkStatsResponse
+
+	key := publicStatsKey(token, timeRange)
+	cached, err := s.rdb.Get(ctx, key).Bytes()
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(cached, &stats); err == nil {
+			return stats, nil
+		}
+	case !errors.Is(err, redis.Nil) && !errors.Is(err, utils.ErrCircuitOpen):
+		slog.WarnContext(ctx, "failed to read cached public stats", "error", err)
+	}
+
+	stats, err = s.computeStats(ctx, token, timeRange)
+	if err != nil {
+		return stats, err
+	}
+
+	if encoded, err := json.Marshal(stats); err == nil {
+		if err := s.rdb.Set(ctx, key, encoded, s.ttl).Err(); err != nil && !errors.Is(err, utils.ErrCircuitOpen) {
+			slog.WarnContext(ctx, "failed to cache public stats", "error", err)
+		}
+	}
+
+	return stats, nil
+}
+
+func (s *publicStatsService) computeStats(ctx context.Context, token string, timeRange utils.TimeRange) (responses.PublicLinkStatsResponse, error) {
+	link, err := s.links.GetLinkByStatsToken(ctx, sql.NullString{String: token, Valid: true})
+	if err != nil {
+		return responses.PublicLinkStatsResponse{}, err
+	}
+
+	to := time.Now()
+	from := timeRange.GetFromDate()
+	linkFilter := uuid.NullUUID{UUID: link.ID, Valid: true}
+
+	totalClicks, err := s.clickLogs.GetTotalClicks(ctx, database.GetTotalClicksParams{
+		UserID:   link.UserID,
+		FromDate: from,
+		ToDate:   to,
+		LinkID:   linkFilter,
+	})
+	if err != nil {
+		return responses.PublicLinkStatsResponse{}, err
+	}
+
+	overviews, err := s.clickLogs.GetByDateRange(ctx, database.GetByDateRangeParams{
+		UserID:   link.UserID,
+		FromDate: from,
+		ToDate:   to,
+		LinkID:   linkFilter,
+	})
+	if err != nil {
+		return responses.PublicLinkStatsResponse{}, err
+	}
+
+	devices, err := s.clickLogs.GetDeviceBreakdownSingle(ctx, database.GetDeviceBreakdownSingleParams{
+		UserID:   link.UserID,
+		ID:       link.ID,
+		FromDate: from,
+		ToDate:   to,
+	})
+	if err != nil {
+		return responses.PublicLinkStatsResponse{}, err
+	}
+
+	countries, err := s.clickLogs.GetTopCountriesSingle(ctx, database.GetTopCountriesSingleParams{
+		UserID:   link.UserID,
+		ID:       link.ID,
+		FromDate: from,
+		ToDate:   to,
+	})
+	if err != nil {
+		return responses.PublicLinkStatsResponse{}, err
+	}
+
+	return responses.MapPublicLinkStats(link, string(timeRange), from, to, totalClicks, overviews, devices, countries), nil
+}
+
+func (s *publicStatsService) Invalidate(ctx context.Context, token string) {
+	if token == "" {
+		return
+	}
+
+	keys := make([]string, 0, len(publicStatsRanges))
+	for _, timeRange := range publicStatsRanges {
+		keys = append(keys, publicStatsKey(token, timeRange))
+	}
+
+	if err := s.rdb.Del(ctx, keys...).Err(); err != nil {
+		slog.WarnContext(ctx, "failed to invalidate cached public stats", "error", err)
+	}
+}
+
+func publicStatsKey(token string, timeRange utils.TimeRange) string {
+	return publicStatsKeyPrefix + token + ":" + string(timeRange)
+}
+
+// generateShareToken returns 128 random bits, enough that share tokens
+// cannot be guessed.
+func generateShareToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/andriawan24/link-short/internal/repository"
+	"github.com/andriawan24/link-short/internal/utils"
+)
+
+var (
+	ErrShortCodeExhausted = errors.New("could not generate an unused short code")
+
+	customShortCodePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+)
+
+type ShortCodeOptions struct {
+	Length          int
+	Alphabet        string
+	MaxAttempts     int
+	MinCustomLength int
+	MaxCustomLength int
+	ReservedWords   []string
+	BlockedWords    []string
+}
+
+func DefaultShortCodeOptions() ShortCodeOptions {
+	return ShortCodeOptions{
+		Length:          utils.DefaultShortCodeLength,
+		Alphabet:        utils.DefaultShortCodeAlphabet,
+		MaxAttempts:     5,
+		MinCustomLength: 3,
+		MaxCustomLength: 32,
+		ReservedWords:   utils.ReservedShortCodes,
+		BlockedWords:    utils.BlockedShortCodeWords,
+	}
+}
+
+type shortCodeService struct {
+	queries  repository.LinkRepository
+	options  ShortCodeOptions
+	reserved map[string]struct{}
+}
+
+type ShortCodeService interface {
+	Generate(ctx context.Context) (string, error)
+	ValidateCustom(ctx context.Context, code string) error
+	MaxAttempts() int
+}
+
+func NewShortCodeService(queries repository.LinkRepository, options ShortCodeOptions) ShortCodeService {
+	reserved := make(map[string]struct{}, len(options.ReservedWords))
+	for _, word := range options.ReservedWords {
+		reserved[strings.ToLower(word)] = struct{}{}
+	}
+
+	return &shortCodeService{
+		queries:  queries,
+		options:  options,
+		reserved: reserved,
+	}
+}
+
+// Generate returns a random code that is not reserved, contains no blocked
+// word and is not used by any link yet. Inserting it can still race with
+// another request, so callers retry on a unique violation.
+func (s *shortCodeService) Generate(ctx context.Context) (string, error) {
+	for range s.options.MaxAttempts {
+		code := utils.GenerateShortCode(s.options.Length, s.options.Alphabet)
+		if s.isReserved(code) || s.containsBlockedWord(code) {
+			continue
+		}
+
+		exists, err := s.queries.ShortCodeExists(ctx, code)
+		if err != nil {
+			return "", err
+		}
+
+		if !exists {
+			return code, nil
+		}
+	}
+
+	return "", ErrShortCodeExhausted
+}
+
+// ValidateCustom checks a user chosen code against the charset, length,
+// reserved path and blocked word rules, and makes sure no other link (even a
+// deleted one) uses it.
+func (s *shortCodeService) ValidateCustom(ctx context.Context, code string) error {
+	if len(code) < s.options.MinCustomLength || len(code) > s.options.MaxCustomLength {
+		return &utils.InvalidShortCodeError{
+			Value:  code,
+			Reason: fmt.Sprintf("must be between %d and %d characters", s.options.MinCustomLength, s.options.MaxCustomLength),
+		}
+	}
+
+	if !customShortCodePattern.MatchString(code) {
+		return &utils.InvalidShortCodeError{
+			Value:  code,
+			Reason: "may only contain letters, digits, '-' and '_', and must start with a letter or digit",
+		}
+	}
+
+	if s.isReserved(code) {
+		return &utils.InvalidShortCodeError{Value: code, Reason: "is reserved"}
+	}
+
+	if s.containsBlockedWord(code) {
+		return &utils.InvalidShortCodeError{Value: code, Reason: "contains a blocked word"}
+	}
+
+	exists, err := s.queries.ShortCodeExists(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return utils.ErrShortCodeTaken
+	}
+
+	return nil
+}
+
+func (s *shortCodeService) MaxAttempts() int {
+	return s.options.MaxAttempts
+}
+
+func (s *shortCodeService) isReserved(code string) bool {
+	_, ok := s.reserved[strings.ToLower(code)]
+	return ok
+}
+
+func (s *shortCodeService) containsBlockedWord(code string) bool {
+	code = strings.ToLower(code)
+	for _, word := range s.options.BlockedWords {
+		if strings.Contains(code, strings.ToLower(word)) {
+			return true
+		}
+	}
+	return false
+}
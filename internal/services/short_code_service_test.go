@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andriawan24/link-short/internal/repository/memory"
+	"github.com/andriawan24/link-short/internal/utils"
+)
+
+func TestShortCodeValidateCustom(t *testing.T) {
+	service := NewShortCodeService(memory.New(), DefaultShortCodeOptions())
+
+	for _, code := range []string{"analytics-2024", "canal", "banal", "Analysis", "grand-canal-tour", "my_link"} {
+		if err := service.ValidateCustom(context.Background(), code); err != nil {
+			t.Errorf("ValidateCustom(%q) = %v, want it accepted", code, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		code   string
+		reason string
+	}{
+		{"ab", "must be between 3 and 32 characters"},
+		{"-dash", "may only contain letters, digits, '-' and '_', and must start with a letter or digit"},
+		{"Analytics", "is reserved"},
+		{"swagger", "is reserved"},
+		{"myShitLink", "contains a blocked word"},
+		{"PORN", "contains a blocked word"},
+	} {
+		err := service.ValidateCustom(context.Background(), tc.code)
+
+		var invalid *utils.InvalidShortCodeError
+		if !errors.As(err, &invalid) || invalid.Reason != tc.reason {
+			t.Errorf("ValidateCustom(%q) = %v, want %q", tc.code, err, tc.reason)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/utils"
+)
+
+type TokenOptions struct {
+	Secret                string
+	RefreshSecret         string
+	AccessTokenTTL        time.Duration
+	RefreshTokenTTL       time.Duration
+	TwoFactorChallengeTTL time.Duration
+}
+
+type tokenService struct {
+	secret        []byte
+	refreshSecret []byte
+	options       TokenOptions
+}
+
+type TokenService interface {
+	GenerateAccessToken(user database.User) (string, utils.JwtClaims, error)
+	GenerateRefreshToken(user database.User) (string, utils.JwtClaims, error)
+	GenerateTwoFactorChallengeToken(user database.User) (string, utils.JwtClaims, error)
+	ParseAccessToken(token string) (*utils.JwtClaims, error)
+	ParseRefreshToken(token string) (*utils.JwtClaims, error)
+	ParseTwoFactorChallengeToken(token string) (*utils.JwtClaims, error)
+	// Revoked reports whether a token of the user was issued before their
+	// password was last changed, which signs out every existing session.
+	Revoked(user database.User, claims *utils.JwtClaims) bool
+}
+
+func NewTokenService(options TokenOptions) TokenService {
+	return &tokenService{
+		secret:        []byte(options.Secret),
+		refreshSecret: []byte(options.RefreshSecret),
+		options:       options,
+	}
+}
+
+func (s *tokenService) GenerateAccessToken(user database.User) (string, utils.JwtClaims, error) {
+	return utils.SignToken(user, utils.AccessTokenType, s.options.AccessTokenTTL, s.secret)
+}
+
+func (s *tokenService) GenerateRefreshToken(user database.User) (string, utils.JwtClaims, error) {
+	return utils.SignToken(user, utils.RefreshTokenType, s.options.RefreshTokenTTL, s.refreshSecret)
+}
+
+// GenerateTwoFactorChallengeToken issues the short-lived token returned by a
+// password login when the account has two-factor authentication enabled.
+func (s *tokenService) GenerateTwoFactorChallengeToken(user database.User) (string, utils.JwtClaims, error) {
+	return utils.SignToken(user, utils.TwoFactorChallengeTokenType, s.options.TwoFactorChallengeTTL, s.secret)
+}
+
+// ParseAccessToken also accepts tokens without a type, which were issued
+// before token types existed.
+func (s *tokenService) ParseAccessToken(token string) (*utils.JwtClaims, error) {
+	return utils.ParseSignedToken(token, s.secret, utils.AccessTokenType, "")
+}
+
+func (s *tokenService) ParseRefreshToken(token string) (*utils.JwtClaims, error) {
+	return utils.ParseSignedToken(token, s.refreshSecret, utils.RefreshTokenType)
+}
+
+func (s *tokenService) ParseTwoFactorChallengeToken(token string) (*utils.JwtClaims, error) {
+	return utils.ParseSignedToken(token, s.secret, utils.TwoFactorChallengeTokenType)
+}
+
+func (s *tokenService) Revoked(user database.User, claims *utils.JwtClaims) bool {
+	return user.PasswordChangedAt.Valid && claims.IssuedBefore(user.PasswordChangedAt.Time)
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/google/uuid"
+	"github.com/pquerna/otp/hotp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	totpIssuer           = "Pendek.in"
+	recoveryCodeCount    = 10
+	recoveryCodeLength   = 10
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+	// totpPeriod and totpSkew match the defaults of totp.Validate: 30 second
+	// steps, accepting one step either side of the current one.
+	totpPeriod = 30
+	totpSkew   = 1
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("two-factor authentication has not been enrolled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+)
+
+type twoFactorService struct {
+	queries repository.UserRepository
+}
+
+type TwoFactorService interface {
+	Enroll(ctx context.Context, user database.User) (secret string, provisioningURI string, err error)
+	Confirm(ctx context.Context, user database.User, code string) ([]string, error)
+	Verify(ctx context.Context, user database.User, code string) error
+	Disable(ctx context.Context, user database.User) error
+}
+
+func NewTwoFactorService(queries repository.UserRepository) TwoFactorService {
+	return &twoFactorService{
+		queries: queries,
+	}
+}
+
+// Enroll stores a fresh TOTP secret for the user. It only takes effect once
+// Confirm has been called with a code generated from it.
+func (s *twoFactorService) Enroll(ctx context.Context, user database.User) (string, string, error) {
+	if user.TotpEnabled {
+		return "", "", ErrTwoFactorAlreadyEnabled
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      totpIssuer,
+		AccountName: user.Email,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	err = s.queries.SetUserTotpSecret(ctx, database.SetUserTotpSecretParams{
+		ID:         user.ID,
+		TotpSecret: sql.NullString{String: key.Secret(), Valid: true},
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return key.Secret(), key.URL(), nil
+}
+
+// Confirm enables two-factor authentication and returns the plain recovery
+// codes. Only their hashes are stored, so they cannot be shown again.
+func (s *twoFactorService) Confirm(ctx context.Context, user database.User, code string) ([]string, error) {
+	if user.TotpEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	if !user.TotpSecret.Valid {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+
+	accepted, err := s.acceptTotp(ctx, user, normalizeTwoFactorCode(code))
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, err := s.replaceRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.queries.EnableUserTotp(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Verify accepts either a current TOTP code or an unused recovery code. Each
+// TOTP code is accepted only once.
+func (s *twoFactorService) Verify(ctx context.Context, user database.User, code string) error {
+	if !user.TotpEnabled || !user.TotpSecret.Valid {
+		return ErrTwoFactorNotEnabled
+	}
+
+	code = normalizeTwoFactorCode(code)
+	accepted, err := s.acceptTotp(ctx, user, code)
+	if err != nil {
+		return err
+	}
+	if accepted {
+		return nil
+	}
+
+	_, err = s.queries.ConsumeRecoveryCode(ctx, database.ConsumeRecoveryCodeParams{
+		UserID:   user.ID,
+		CodeHash: utils.HashOpaqueToken(code),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidTwoFactorCode
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *twoFactorService) Disable(ctx context.Context, user database.User) error {
+	if err := s.queries.DeleteRecoveryCodes(ctx, user.ID); err != nil {
+		return err
+	}
+
+	return s.queries.DisableUserTotp(ctx, user.ID)
+}
+
+// acceptTotp checks code against the user's TOTP secret and records its time
+// step. Codes of a step at or before the last accepted one are rejected, so
+// a code seen by someone else cannot be replayed while it is still valid.
+func (s *twoFactorService) acceptTotp(ctx context.Context, user database.User, code string) (bool, error) {
+	step, ok := totpStep(code, user.TotpSecret.String, time.Now())
+	if !ok {
+		return false, nil
+	}
+
+	claimed, err := s.queries.ClaimUserTotpStep(ctx, database.ClaimUserTotpStepParams{
+		Step: step,
+		ID:   user.ID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return claimed > 0, nil
+}
+
+// totpStep returns the time step code was generated for.
+func totpStep(code string, secret string, now time.Time) (int64, bool) {
+	current := now.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if hotp.Validate(code, uint64(step), secret) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func (s *twoFactorService) replaceRecoveryCodes(ctx context.Context, userId uuid.UUID) ([]string, error) {
+	if err := s.queries.DeleteRecoveryCodes(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.queries.InsertRecoveryCode(ctx, database.InsertRecoveryCodeParams{
+			UserID:   userId,
+			CodeHash: utils.HashOpaqueToken(normalizeTwoFactorCode(code)),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		codes[i] = code
+	}
+
+	return codes, nil
+}
+
+// generateRecoveryCode returns a code formatted as "xxxxx-xxxxx".
+func generateRecoveryCode() (string, error) {
+	max := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	out := make([]byte, 0, recoveryCodeLength+1)
+
+	for i := range recoveryCodeLength {
+		if i == recoveryCodeLength/2 {
+			out = append(out, '-')
+		}
+
+		v, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		out = append(out, recoveryCodeAlphabet[v.Int64()])
+	}
+
+	return string(out), nil
+}
+
+func normalizeTwoFactorCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(strings.ReplaceAll(code, "-", ""), " ", "")
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository/memory"
+	"github.com/pquerna/otp/totp"
+)
+
+func TestTwoFactorCodesAreSingleUse(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	twoFactor := NewTwoFactorService(store)
+
+	user, err := store.InsertUser(ctx, database.InsertUserParams{Name: "Ada", Email: "ada@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reload := func() database.User {
+		t.Helper()
+		user, err := store.GetUser(ctx, user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return user
+	}
+	codeAt := func(secret string, offset time.Duration) string {
+		t.Helper()
+		code, err := totp.GenerateCode(secret, time.Now().Add(offset))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	secret, _, err := twoFactor.Enroll(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	confirmCode := codeAt(secret, 0)
+	recoveryCodes, err := twoFactor.Confirm(ctx, reload(), confirmCode)
+	if err != nil {
+		t.Fatalf("Confirm: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		code string
+		want error
+	}{
+		{"code used to confirm", confirmCode, ErrInvalidTwoFactorCode},
+		{"earlier code", codeAt(secret, -totpPeriod*time.Second), ErrInvalidTwoFactorCode},
+		{"next code", codeAt(secret, totpPeriod*time.Second), nil},
+		{"next code again", codeAt(secret, totpPeriod*time.Second), ErrInvalidTwoFactorCode},
+		{"current code after the next one", codeAt(secret, 0), ErrInvalidTwoFactorCode},
+		{"code outside the skew", codeAt(secret, 3*totpPeriod*time.Second), ErrInvalidTwoFactorCode},
+		{"recovery code", recoveryCodes[0], nil},
+		{"recovery code again", recoveryCodes[0], ErrInvalidTwoFactorCode},
+	} {
+		if err := twoFactor.Verify(ctx, reload(), tc.code); !errors.Is(err, tc.want) {
+			t.Fatalf("Verify(%s) = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+
+	// A new secret starts over.
+	if err := twoFactor.Disable(ctx, reload()); err != nil {
+		t.Fatal(err)
+	}
+	secret, _, err = twoFactor.Enroll(ctx, reload())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := twoFactor.Confirm(ctx, reload(), codeAt(secret, 0)); err != nil {
+		t.Fatalf("Confirm after re-enrolling: %v", err)
+	}
+}
@@ -5,11 +5,12 @@ import (
 	"database/sql"
 
 	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository"
 	"github.com/google/uuid"
 )
 
 type userService struct {
-	queries *database.Queries
+	queries repository.UserRepository
 }
 
 type UserService interface {
@@ -21,7 +22,7 @@ type UserService interface {
 	UpdateUser(ctx context.Context, param database.UpdateUserParams) (database.User, error)
 }
 
-func NewUserService(queries *database.Queries) UserService {
+func NewUserService(queries repository.UserRepository) UserService {
 	return &userService{
 		queries: queries,
 	}
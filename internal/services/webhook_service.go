@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/google/uuid"
+)
+
+type webhookService struct {
+	queries repository.WebhookRepository
+}
+
+type WebhookService interface {
+	InsertWebhook(ctx context.Context, userId uuid.UUID, url string, events []string) (database.Webhook, error)
+	GetWebhooks(ctx context.Context, userId uuid.UUID) ([]database.Webhook, error)
+	GetWebhook(ctx context.Context, userId uuid.UUID, id uuid.UUID) (database.Webhook, error)
+	DeleteWebhook(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
+	// EnableWebhook resumes deliveries to a webhook that was disabled after
+	// a delivery failed for good. Deliveries queued in the meantime are sent.
+	EnableWebhook(ctx context.Context, userId uuid.UUID, id uuid.UUID) (database.Webhook, error)
+	GetDeliveries(ctx context.Context, webhookId uuid.UUID, limit int32, offset int32) ([]database.WebhookDelivery, error)
+	Redeliver(ctx context.Context, webhookId uuid.UUID, id uuid.UUID) (database.WebhookDelivery, error)
+	Dispatch(ctx context.Context, userId uuid.UUID, event utils.WebhookEvent, data any) error
+}
+
+func NewWebhookService(queries repository.WebhookRepository) WebhookService {
+	return &webhookService{
+		queries: queries,
+	}
+}
+
+func (s *webhookService) InsertWebhook(ctx context.Context, userId uuid.UUID, url string, events []string) (database.Webhook, error) {
+	secret, err := utils.GenerateWebhookSecret()
+	if err != nil {
+		return database.Webhook{}, err
+	}
+
+	webhook, err := s.queries.InsertWebhook(ctx, database.InsertWebhookParams{
+		UserID: userId,
+		Url:    url,
+		Secret: secret,
+		Events: events,
+	})
+	if err != nil {
+		return webhook, err
+	}
+
+	return webhook, nil
+}
+
+func (s *webhookService) GetWebhooks(ctx context.Context, userId uuid.UUID) ([]database.Webhook, error) {
+	webhooks, err := s.queries.GetWebhooks(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (s *webhookService) GetWebhook(ctx context.Context, userId uuid.UUID, id uuid.UUID) (database.Webhook, error) {
+	webhook, err := s.queries.GetWebhook(ctx, database.GetWebhookParams{
+		ID:     id,
+		UserID: userId,
+	})
+	if err != nil {
+		return webhook, err
+	}
+
+	return webhook, nil
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	return s.queries.DeleteWebhook(ctx, database.DeleteWebhookParams{
+		ID:     id,
+		UserID: userId,
+	})
+}
+
+func (s *webhookService) EnableWebhook(ctx context.Context, userId uuid.UUID, id uuid.UUID) (database.Webhook, error) {
+	webhook, err := s.queries.EnableWebhook(ctx, database.EnableWebhookParams{
+		ID:     id,
+		UserID: userId,
+	})
+	if err != nil {
+		return webhook, err
+	}
+
+	return webhook, nil
+}
+
+func (s *webhookService) GetDeliveries(ctx context.Context, webhookId uuid.UUID, limit int32, offset int32) ([]database.WebhookDelivery, error) {
+	deliveries, err := s.queries.GetWebhookDeliveries(ctx, database.GetWebhookDeliveriesParams{
+		WebhookID: webhookId,
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (s *webhookService) Redeliver(ctx context.Context, webhookId uuid.UUID, id uuid.UUID) (database.WebhookDelivery, error) {
+	delivery, err := s.queries.RedeliverWebhookDelivery(ctx, database.RedeliverWebhookDeliveryParams{
+		ID:        id,
+		WebhookID: webhookId,
+	})
+	if err != nil {
+		return delivery, err
+	}
+
+	return delivery, nil
+}
+
+// Dispatch queues a delivery of event to every active webhook the user has
+// subscribed to it. Delivery itself happens in the WebhookWorker.
+func (s *webhookService) Dispatch(ctx context.Context, userId uuid.UUID, event utils.WebhookEvent, data any) error {
+	webhooks, err := s.queries.GetSubscribedWebhooks(ctx, database.GetSubscribedWebhooksParams{
+		UserID: userId,
+		Event:  string(event),
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		_, err := s.queries.InsertWebhookDelivery(ctx, database.InsertWebhookDeliveryParams{
+			WebhookID: webhook.ID,
+			Event:     string(event),
+			Payload:   payload,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
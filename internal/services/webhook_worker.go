@@ -0,0 +1,204 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/models/responses"
+	"github.com/andriawan24/link-short/internal/repository"
+	"github.com/andriawan24/link-short/internal/utils"
+)
+
+const (
+	webhookPollInterval   = 5 * time.Second
+	webhookBatchSize      = 20
+	webhookLease          = time.Minute
+	webhookRequestTimeout = 10 * time.Second
+	webhookMaxAttempts    = 8
+	webhookBaseBackoff    = 30 * time.Second
+	webhookMaxBackoff     = 6 * time.Hour
+
+	webhookStatusPending   = "pending"
+	webhookStatusSucceeded = "succeeded"
+	webhookStatusFailed    = "failed"
+)
+
+type webhookWorker struct {
+	queries        repository.WebhookRepository
+	links          repository.LinkRepository
+	webhookService WebhookService
+	client         *http.Client
+}
+
+type WebhookWorker interface {
+	Run(ctx context.Context)
+	Deliver(ctx context.Context, delivery database.ClaimDueWebhookDeliveriesRow) (int, error)
+}
+
+// NewWebhookWorker delivers events to webhook endpoints. Without a client it
+// uses one that refuses to connect to private and loopback addresses, since
+// the endpoints are chosen by users.
+func NewWebhookWorker(queries repository.WebhookRepository, links repository.LinkRepository, webhookService WebhookService, client *http.Client) WebhookWorker {
+	if client == nil {
+		client = utils.NewSafeHTTPClient(webhookRequestTimeout)
+	}
+
+	return &webhookWorker{
+		queries:        queries,
+		links:          links,
+		webhookService: webhookService,
+		client:         client,
+	}
+}
+
+// Run polls for newly expired links and due deliveries until ctx is done.
+func (w *webhookWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.dispatchExpiredLinks(ctx)
+			w.deliverDue(ctx)
+		}
+	}
+}
+
+// Deliver POSTs a single delivery to its webhook and returns the response
+// status code. Any non-2xx status is reported as an error.
+func (w *webhookWorker) Deliver(ctx context.Context, delivery database.ClaimDueWebhookDeliveriesRow) (int, error) {
+	body, err := json.Marshal(responses.WebhookPayload{
+		ID:        delivery.ID,
+		Event:     delivery.Event,
+		CreatedAt: delivery.CreatedAt,
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Pendek.in-Webhooks/1.0")
+	req.Header.Set("X-Pendekin-Event", delivery.Event)
+	req.Header.Set("X-Pendekin-Delivery", delivery.ID.String())
+	req.Header.Set("X-Pendekin-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Pendekin-Signature", utils.SignWebhookPayload(delivery.Secret, timestamp, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook endpoint responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+func (w *webhookWorker) dispatchExpiredLinks(ctx context.Context) {
+	links, err := w.links.ClaimExpiredLinks(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to claim expired links", "error", err)
+		return
+	}
+
+	for _, link := range links {
+		err := w.webhookService.Dispatch(ctx, link.UserID, utils.WebhookEventLinkExpired, responses.MapLinkDetailResponse(link))
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to dispatch webhook event", "event", utils.WebhookEventLinkExpired, "link_id", link.ID, "error", err)
+		}
+	}
+}
+
+func (w *webhookWorker) deliverDue(ctx context.Context) {
+	deliveries, err := w.queries.ClaimDueWebhookDeliveries(ctx, database.ClaimDueWebhookDeliveriesParams{
+		LeaseUntil: time.Now().Add(webhookLease),
+		BatchSize:  webhookBatchSize,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to claim webhook deliveries", "error", err)
+		return
+	}
+
+	for _, delivery := range deliveries {
+		reqCtx, cancel := context.WithTimeout(ctx, webhookRequestTimeout)
+		statusCode, err := w.Deliver(reqCtx, delivery)
+		cancel()
+
+		if err := w.recordAttempt(ctx, delivery, statusCode, err); err != nil {
+			slog.ErrorContext(ctx, "failed to record webhook delivery", "delivery_id", delivery.ID, "error", err)
+		}
+	}
+}
+
+func (w *webhookWorker) recordAttempt(ctx context.Context, delivery database.ClaimDueWebhookDeliveriesRow, statusCode int, deliveryErr error) error {
+	attempts := delivery.Attempts + 1
+	now := time.Now()
+
+	param := database.UpdateWebhookDeliveryAttemptParams{
+		ID:             delivery.ID,
+		Status:         webhookStatusSucceeded,
+		NextAttemptAt:  now,
+		LastStatusCode: sql.NullInt32{Int32: int32(statusCode), Valid: statusCode != 0},
+		DeliveredAt:    sql.NullTime{Time: now, Valid: true},
+	}
+
+	if deliveryErr != nil {
+		param.LastError = sql.NullString{String: deliveryErr.Error(), Valid: true}
+		param.DeliveredAt = sql.NullTime{}
+
+		if attempts >= webhookMaxAttempts {
+			param.Status = webhookStatusFailed
+			w.disableWebhook(ctx, delivery)
+		} else {
+			param.Status = webhookStatusPending
+			param.NextAttemptAt = now.Add(webhookBackoff(attempts))
+		}
+	}
+
+	return w.queries.UpdateWebhookDeliveryAttempt(ctx, param)
+}
+
+// disableWebhook stops deliveries to an endpoint that kept failing until the
+// owner enables it again.
+func (w *webhookWorker) disableWebhook(ctx context.Context, delivery database.ClaimDueWebhookDeliveriesRow) {
+	if err := w.queries.DisableWebhook(ctx, delivery.WebhookID); err != nil {
+		slog.ErrorContext(ctx, "failed to disable webhook", "webhook_id", delivery.WebhookID, "error", err)
+		return
+	}
+	slog.WarnContext(ctx, "disabled webhook after repeated delivery failures", "webhook_id", delivery.WebhookID, "delivery_id", delivery.ID)
+}
+
+// webhookBackoff doubles the wait after every failed attempt, starting at
+// webhookBaseBackoff and capped at webhookMaxBackoff.
+func webhookBackoff(attempts int32) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := int32(1); i < attempts; i++ {
+		backoff *= 2
+		if backoff >= webhookMaxBackoff {
+			return webhookMaxBackoff
+		}
+	}
+	return backoff
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/repository/memory"
+	"github.com/andriawan24/link-short/internal/utils"
+	"github.com/google/uuid"
+)
+
+// webhookReceiver is an httptest endpoint that answers with status and
+// checks the signature of every request against secret.
+type webhookReceiver struct {
+	mu       sync.Mutex
+	secret   string
+	status   int
+	requests int
+	invalid  []string
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests++
+	timestamp, err := strconv.ParseInt(req.Header.Get("X-Pendekin-Timestamp"), 10, 64)
+	if err != nil || !utils.VerifyWebhookSignature(r.secret, timestamp, body, req.Header.Get("X-Pendekin-Signature")) {
+		r.invalid = append(r.invalid, req.Header.Get("X-Pendekin-Delivery"))
+	}
+	if req.Header.Get("X-Pendekin-Event") != string(utils.WebhookEventLinkCreated) {
+		r.invalid = append(r.invalid, "event "+req.Header.Get("X-Pendekin-Event"))
+	}
+	w.WriteHeader(r.status)
+}
+
+func (r *webhookReceiver) respond(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *webhookReceiver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.requests
+}
+
+func TestWebhookWorker(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	user, err := store.InsertUser(ctx, database.InsertUserParams{Name: "Ada", Email: "ada@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receiver := &webhookReceiver{status: http.StatusInternalServerError}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	webhookService := NewWebhookService(store)
+	webhook, err := webhookService.InsertWebhook(ctx, user.ID, server.URL, []string{string(utils.WebhookEventLinkCreated)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiver.secret = webhook.Secret
+
+	worker := NewWebhookWorker(store, store, webhookService, server.Client()).(*webhookWorker)
+	dispatch := func() database.WebhookDelivery {
+		t.Helper()
+		if err := webhookService.Dispatch(ctx, user.ID, utils.WebhookEventLinkCreated, map[string]string{"short_code": "abc"}); err != nil {
+			t.Fatalf("Dispatch: %v", err)
+		}
+		deliveries, _ := webhookService.GetDeliveries(ctx, webhook.ID, 1, 0)
+		return deliveries[0]
+	}
+	delivery := func(id uuid.UUID) database.WebhookDelivery {
+		t.Helper()
+		deliveries, _ := webhookService.GetDeliveries(ctx, webhook.ID, 100, 0)
+		for _, d := range deliveries {
+			if d.ID == id {
+				return d
+			}
+		}
+		t.Fatalf("delivery %s not found", id)
+		return database.WebhookDelivery{}
+	}
+
+	// A failed attempt is retried after the first backoff, not before.
+	first := dispatch()
+	before := time.Now()
+	worker.deliverDue(ctx)
+	got := delivery(first.ID)
+	if receiver.count() != 1 || got.Status != webhookStatusPending || got.Attempts != 1 || got.LastStatusCode.Int32 != http.StatusInternalServerError {
+		t.Fatalf("after a failed attempt: %d requests, delivery %+v", receiver.count(), got)
+	}
+	if wait := got.NextAttemptAt.Sub(before); wait < webhookBaseBackoff || wait > webhookBaseBackoff+time.Second {
+		t.Fatalf("next attempt in %s, want %s", wait, webhookBaseBackoff)
+	}
+	worker.deliverDue(ctx)
+	if receiver.count() != 1 {
+		t.Fatalf("delivery was retried before its backoff passed")
+	}
+
+	// The last allowed attempt failing marks the delivery failed and turns
+	// the webhook off.
+	for range webhookMaxAttempts - 2 {
+		err := store.UpdateWebhookDeliveryAttempt(ctx, database.UpdateWebhookDeliveryAttemptParams{
+			ID:            first.ID,
+			Status:        webhookStatusPending,
+			NextAttemptAt: time.Now().Add(-time.Second),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	worker.deliverDue(ctx)
+	if got := delivery(first.ID); got.Status != webhookStatusFailed || got.Attempts != webhookMaxAttempts {
+		t.Fatalf("after the last attempt: delivery %+v", got)
+	}
+	if got, _ := webhookService.GetWebhook(ctx, user.ID, webhook.ID); got.IsActive {
+		t.Fatal("webhook is still active after a delivery failed for good")
+	}
+
+	// Events are not queued for a disabled webhook, and nothing goes out.
+	if err := webhookService.Dispatch(ctx, user.ID, utils.WebhookEventLinkCreated, nil); err != nil {
+		t.Fatal(err)
+	}
+	if deliveries, _ := webhookService.GetDeliveries(ctx, webhook.ID, 100, 0); len(deliveries) != 1 {
+		t.Fatalf("deliveries of a disabled webhook = %d, want 1", len(deliveries))
+	}
+
+	if _, err := webhookService.EnableWebhook(ctx, user.ID, webhook.ID); err != nil {
+		t.Fatalf("EnableWebhook: %v", err)
+	}
+	receiver.respond(http.StatusNoContent)
+	second := dispatch()
+	worker.deliverDue(ctx)
+	if got := delivery(second.ID); got.Status != webhookStatusSucceeded || !got.DeliveredAt.Valid {
+		t.Fatalf("after enabling: delivery %+v", got)
+	}
+
+	if len(receiver.invalid) > 0 {
+		t.Fatalf("requests with a bad signature or event: %v", receiver.invalid)
+	}
+}
+
+func TestWebhookWorkerRefusesPrivateAddresses(t *testing.T) {
+	receiver := &webhookReceiver{status: http.StatusOK}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	worker := NewWebhookWorker(nil, nil, nil, nil)
+	_, err := worker.Deliver(context.Background(), database.ClaimDueWebhookDeliveriesRow{
+		ID:      uuid.New(),
+		Event:   string(utils.WebhookEventLinkCreated),
+		Payload: []byte("{}"),
+		Url:     server.URL,
+		Secret:  "whsec_test",
+	})
+	if !errors.Is(err, utils.ErrBlockedAddress) {
+		t.Fatalf("Deliver to %s: error = %v, want %v", server.URL, err, utils.ErrBlockedAddress)
+	}
+	if receiver.count() != 0 {
+		t.Fatal("request reached the loopback receiver")
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	for _, tc := range []struct {
+		attempts int32
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{10, 4*time.Hour + 16*time.Minute},
+		{11, webhookMaxBackoff},
+		{40, webhookMaxBackoff},
+	} {
+		if got := webhookBackoff(tc.attempts); got != tc.want {
+			t.Errorf("webhookBackoff(%d) = %s, want %s", tc.attempts, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/andriawan24/link-short/internal/database"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedDB struct {
+	db database.DBTX
+}
+
+// WrapDBTX starts a span for every query run through db. sqlc prefixes each
+// query with a "-- name: GetLink :one" comment, which becomes the span name.
+func WrapDBTX(db database.DBTX) database.DBTX {
+	return &tracedDB{db: db}
+}
+
+func (t *tracedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	result, err := t.db.ExecContext(ctx, query, args...)
+	recordQueryError(span, err)
+	return result, err
+}
+
+func (t *tracedDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	stmt, err := t.db.PrepareContext(ctx, query)
+	recordQueryError(span, err)
+	return stmt, err
+}
+
+// QueryContext only covers running the query, not iterating the rows.
+func (t *tracedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	rows, err := t.db.QueryContext(ctx, query, args...)
+	recordQueryError(span, err)
+	return rows, err
+}
+
+func (t *tracedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	row := t.db.QueryRowContext(ctx, query, args...)
+	recordQueryError(span, row.Err())
+	return row
+}
+
+func startQuerySpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	name := queryName(query)
+
+	return Tracer().Start(ctx, "db "+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBOperationName(name),
+			semconv.DBQueryText(query),
+		),
+	)
+}
+
+// recordQueryError marks the span as failed. sql.ErrNoRows is an expected
+// outcome of lookups and is not treated as an error.
+func recordQueryError(span trace.Span, err error) {
+	if err == nil || err == sql.ErrNoRows {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+func queryName(query string) string {
+	query = strings.TrimSpace(query)
+
+	if rest, ok := strings.CutPrefix(query, "-- name: "); ok {
+		if name, _, ok := strings.Cut(rest, " "); ok {
+			return name
+		}
+	}
+
+	if operation, _, ok := strings.Cut(query, " "); ok {
+		return strings.ToUpper(operation)
+	}
+
+	return "query"
+}
@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type redisHook struct{}
+
+// InstrumentRedis adds a span for every command and pipeline sent through
+// rdb. Only command names are recorded since arguments may hold user data.
+func InstrumentRedis(rdb *redis.Client) {
+	rdb.AddHook(redisHook{})
+}
+
+func (redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network string, addr string) (net.Conn, error) {
+		// The pool also dials in the background, which would otherwise show
+		// up as a stream of root spans.
+		if !trace.SpanContextFromContext(ctx).IsValid() {
+			return next(ctx, network, addr)
+		}
+
+		ctx, span := startRedisSpan(ctx, "redis dial")
+		defer span.End()
+
+		conn, err := next(ctx, network, addr)
+		recordRedisError(span, err)
+		return conn, err
+	}
+}
+
+func (redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := startRedisSpan(ctx, "redis "+cmd.Name(),
+			semconv.DBOperationName(cmd.Name()),
+		)
+		defer span.End()
+
+		err := next(ctx, cmd)
+		recordRedisError(span, err)
+		return err
+	}
+}
+
+func (redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		names := make([]string, 0, len(cmds))
+		for _, cmd := range cmds {
+			names = append(names, cmd.Name())
+		}
+
+		ctx, span := startRedisSpan(ctx, "redis pipeline",
+			semconv.DBOperationName("pipeline"),
+			attribute.String("db.redis.commands", strings.Join(names, " ")),
+		)
+		defer span.End()
+
+		err := next(ctx, cmds)
+		recordRedisError(span, err)
+		return err
+	}
+}
+
+func startRedisSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemNameRedis),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// recordRedisError ignores redis.Nil, which only means a key was missing.
+func recordRedisError(span trace.Span, err error) {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,175 @@
+package tracing_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/middlewares"
+	"github.com/andriawan24/link-short/internal/tracing"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// fakeDB answers every statement with err, or one affected row.
+type fakeDB struct {
+	database.DBTX
+	err error
+}
+
+func (f fakeDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+// setupTracing installs a tracer provider that keeps finished spans in
+// memory and restores the previous one when the test ends.
+func setupTracing(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+
+	return exporter
+}
+
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name
+	}
+	t.Fatalf("no %q span in %v", name, names)
+	return tracetest.SpanStub{}
+}
+
+func TestRequestSpans(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	exporter := setupTracing(t)
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	tracing.InstrumentRedis(rdb)
+
+	// Open the connection up front so its handshake is not part of the
+	// request.
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		t.Fatal(err)
+	}
+	exporter.Reset()
+
+	store := database.New(tracing.WrapDBTX(fakeDB{}))
+	failing := database.New(tracing.WrapDBTX(fakeDB{err: errors.New("connection refused")}))
+
+	r := gin.New()
+	r.Use(middlewares.Tracing())
+	r.DELETE("/campaigns/:id", func(ctx *gin.Context) {
+		reqCtx := ctx.Request.Context()
+		params := database.DeleteCampaignParams{ID: uuid.New(), UserID: uuid.New()}
+
+		if err := store.DeleteCampaign(reqCtx, params); err != nil {
+			t.Error(err)
+		}
+		if err := failing.DeleteCampaign(reqCtx, params); err == nil {
+			t.Error("DeleteCampaign succeeded on a failing database")
+		}
+		if err := rdb.Get(reqCtx, "missing").Err(); !errors.Is(err, redis.Nil) {
+			t.Errorf("GET missing key: %v", err)
+		}
+		if _, err := rdb.Pipelined(reqCtx, func(pipe redis.Pipeliner) error {
+			pipe.Incr(reqCtx, "clicks")
+			pipe.Expire(reqCtx, "clicks", 0)
+			return nil
+		}); err != nil {
+			t.Error(err)
+		}
+		ctx.Status(http.StatusNoContent)
+	})
+
+	const (
+		traceId      = "4bf92f3577b34da6a3ce929d0e0e4736"
+		callerSpanId = "00f067aa0ba902b7"
+	)
+	req := httptest.NewRequest(http.MethodDelete, "/campaigns/"+uuid.NewString(), nil)
+	req.Header.Set("traceparent", "00-"+traceId+"-"+callerSpanId+"-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	server := findSpan(t, spans, "DELETE /campaigns/:id")
+	if server.SpanKind != trace.SpanKindServer || server.Parent.SpanID().String() != callerSpanId || !server.Parent.IsRemote() {
+		t.Fatalf("server span kind %v, parent %v; want a server span continuing the caller", server.SpanKind, server.Parent.SpanID())
+	}
+	if server.SpanContext.TraceID().String() != traceId {
+		t.Fatalf("server span trace = %s, want %s", server.SpanContext.TraceID(), traceId)
+	}
+
+	// The database and Redis calls are children of the request, and only
+	// the failed query is marked as an error.
+	var queries int
+	for _, span := range spans {
+		if span.Name == server.Name {
+			continue
+		}
+		if span.Parent.SpanID() != server.SpanContext.SpanID() || span.SpanKind != trace.SpanKindClient {
+			t.Errorf("%s: parent %v, kind %v; want a client span under the request", span.Name, span.Parent.SpanID(), span.SpanKind)
+		}
+		if span.Name == "db DeleteCampaign" {
+			queries++
+		}
+	}
+	if queries != 2 {
+		t.Fatalf("got %d db DeleteCampaign spans, want 2", queries)
+	}
+
+	var failed int
+	for _, span := range spans {
+		if span.Status.Code == codes.Error {
+			failed++
+			if span.Name != "db DeleteCampaign" || span.Status.Description != "connection refused" {
+				t.Errorf("%s failed with %q", span.Name, span.Status.Description)
+			}
+		}
+	}
+	if failed != 1 {
+		t.Fatalf("%d spans failed, want 1", failed)
+	}
+
+	if get := findSpan(t, spans, "redis get"); get.Status.Code != codes.Unset {
+		t.Fatalf("GET of a missing key has status %v", get.Status)
+	}
+	pipeline := findSpan(t, spans, "redis pipeline")
+	for _, attr := range pipeline.Attributes {
+		if attr.Key == "db.redis.commands" && attr.Value.AsString() != "incr expire" {
+			t.Fatalf("pipeline commands = %q", attr.Value.AsString())
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package utils
+
+type AuditAction string
+
+const (
+	AuditActionLinkCreated       AuditAction = "link.created"
+	AuditActionLinkUpdated       AuditAction = "link.updated"
+	AuditActionLinkDeleted       AuditAction = "link.deleted"
+	AuditActionLinkTakenDown     AuditAction = "link.taken_down"
+	AuditActionLinkRestored      AuditAction = "link.restored"
+	AuditActionUserRegistered    AuditAction = "user.registered"
+	AuditActionProfileUpdated    AuditAction = "user.profile_updated"
+	AuditActionUserSuspended     AuditAction = "user.suspended"
+	AuditActionUserReactivated   AuditAction = "user.reactivated"
+	AuditActionUserRoleChanged   AuditAction = "user.role_changed"
+	AuditActionDataExported      AuditAction = "user.data_exported"
+	AuditActionDeletionRequested AuditAction = "user.deletion_requested"
+	AuditActionAccountRestored   AuditAction = "user.restored"
+	AuditActionLogin             AuditAction = "auth.login"
+	AuditActionEmailVerified     AuditAction = "auth.email_verified"
+	AuditActionPasswordReset     AuditAction = "auth.password_reset"
+	AuditActionTwoFactorEnabled  AuditAction = "auth.2fa_enabled"
+	AuditActionTwoFactorDisabled AuditAction = "auth.2fa_disabled"
+)
+
+const (
+	AuditTargetLink = "link"
+	AuditTargetUser = "user"
+)
@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned instead of calling a dependency that has been
+// failing, so requests fail fast rather than piling up on timeouts.
+var ErrCircuitOpen = errors.New("dependency unavailable: circuit breaker is open")
+
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// CircuitBreaker opens after threshold consecutive failures. Once
+// openTimeout has passed a single trial call is let through: success closes
+// the circuit again, failure reopens it.
+type CircuitBreaker struct {
+	name        string
+	threshold   int
+	openTimeout time.Duration
+
+	// OnStateChange, when set, is called after every transition.
+	OnStateChange func(name string, from CircuitState, to CircuitState)
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewCircuitBreaker(name string, threshold int, openTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		name:        name,
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		state:       CircuitClosed,
+	}
+}
+
+func (b *CircuitBreaker) Name() string {
+	return b.name
+}
+
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// Allow reports whether a call may proceed. Every allowed call must be
+// followed by Record with its outcome.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.openTimeout {
+			return ErrCircuitOpen
+		}
+		b.transition(CircuitHalfOpen)
+		b.probing = true
+		return nil
+	case CircuitHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (b *CircuitBreaker) Record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitHalfOpen {
+		b.probing = false
+		if failed {
+			b.open()
+		} else {
+			b.failures = 0
+			b.transition(CircuitClosed)
+		}
+		return
+	}
+
+	if !failed {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitClosed && b.failures >= b.threshold {
+		b.open()
+	}
+}
+
+func (b *CircuitBreaker) open() {
+	b.openedAt = time.Now()
+	b.transition(CircuitOpen)
+}
+
+func (b *CircuitBreaker) transition(to CircuitState) {
+	from := b.state
+	if from == to {
+		return
+	}
+
+	b.state = to
+	if b.OnStateChange != nil {
+		b.OnStateChange(b.name, from, to)
+	}
+}
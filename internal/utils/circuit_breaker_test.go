@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	const openTimeout = 20 * time.Millisecond
+
+	breaker := NewCircuitBreaker("postgres", 3, openTimeout)
+
+	var transitions []string
+	breaker.OnStateChange = func(name string, from CircuitState, to CircuitState) {
+		if name != "postgres" {
+			t.Errorf("OnStateChange name = %q, want postgres", name)
+		}
+		transitions = append(transitions, string(from)+">"+string(to))
+	}
+
+	call := func(failed bool) error {
+		t.Helper()
+		if err := breaker.Allow(); err != nil {
+			return err
+		}
+		breaker.Record(failed)
+		return nil
+	}
+
+	// A success in between resets the count of consecutive failures.
+	for _, failed := range []bool{true, true, false, true, true} {
+		if err := call(failed); err != nil {
+			t.Fatalf("call rejected while closed: %v", err)
+		}
+	}
+	if breaker.State() != CircuitClosed {
+		t.Fatalf("state = %s after non-consecutive failures, want closed", breaker.State())
+	}
+
+	if err := call(true); err != nil {
+		t.Fatal(err)
+	}
+	if breaker.State() != CircuitOpen {
+		t.Fatalf("state = %s after 3 consecutive failures, want open", breaker.State())
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Allow while open = %v, want %v", err, ErrCircuitOpen)
+	}
+
+	// After the timeout a single trial call goes through. Its failure opens
+	// the circuit for another full timeout.
+	time.Sleep(openTimeout)
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("trial call rejected: %v", err)
+	}
+	if breaker.State() != CircuitHalfOpen {
+		t.Fatalf("state = %s during the trial call, want half_open", breaker.State())
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second call during the trial = %v, want %v", err, ErrCircuitOpen)
+	}
+	breaker.Record(true)
+	if breaker.State() != CircuitOpen {
+		t.Fatalf("state = %s after a failed trial, want open", breaker.State())
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Allow right after a failed trial = %v, want %v", err, ErrCircuitOpen)
+	}
+
+	// A successful trial closes the circuit with a clean failure count.
+	time.Sleep(openTimeout)
+	if err := call(false); err != nil {
+		t.Fatalf("trial call rejected: %v", err)
+	}
+	if breaker.State() != CircuitClosed {
+		t.Fatalf("state = %s after a successful trial, want closed", breaker.State())
+	}
+	for range 2 {
+		if err := call(true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if breaker.State() != CircuitClosed {
+		t.Fatalf("state = %s after 2 failures, want closed", breaker.State())
+	}
+
+	want := []string{
+		"closed>open",
+		"open>half_open",
+		"half_open>open",
+		"open>half_open",
+		"half_open>closed",
+	}
+	if !slices.Equal(transitions, want) {
+		t.Fatalf("transitions = %v, want %v", transitions, want)
+	}
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/medama-io/go-useragent"
+)
+
+// Platform is the mobile platform a visitor is on, as far as deep links are
+// concerned.
+type Platform string
+
+const (
+	PlatformIOS     Platform = "ios"
+	PlatformAndroid Platform = "android"
+	PlatformOther   Platform = "other"
+)
+
+func ParsePlatform(ua useragent.UserAgent) Platform {
+	switch {
+	case ua.IsIOS():
+		return PlatformIOS
+	case ua.IsAndroidOS():
+		return PlatformAndroid
+	default:
+		return PlatformOther
+	}
+}
+
+// inAppBrowserTokens mark the embedded browsers of social apps, which do not
+// hand universal links and app links over to the installed app.
+var inAppBrowserTokens = []string{"Instagram", "FBAN/", "FBAV/", "FB_IAB/"}
+
+// IsInAppBrowser reports whether the user agent belongs to the in-app
+// browser of Instagram or Facebook. The parser has no notion of these, so
+// the raw string is checked.
+func IsInAppBrowser(userAgent string) bool {
+	return slices.ContainsFunc(inAppBrowserTokens, func(token string) bool {
+		return strings.Contains(userAgent, token)
+	})
+}
+
+var blockedDeepLinkSchemes = []string{"javascript", "data", "vbscript", "file"}
+
+// ValidDeepLink reports whether s is an absolute URL that may be handed to
+// the browser to open an app, e.g. myapp://product/42 or an https link.
+func ValidDeepLink(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "" && u.Path == "") {
+		return false
+	}
+
+	return !slices.Contains(blockedDeepLinkSchemes, strings.ToLower(u.Scheme))
+}
+
+// IsWebURL reports whether s is an absolute http or https URL, the only kind
+// a page may navigate to on its own.
+func IsWebURL(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	return scheme == "http" || scheme == "https"
+}
@@ -3,7 +3,7 @@ package utils
 import (
 	"errors"
 	"fmt"
-	"os"
+	"slices"
 	"time"
 
 	"github.com/andriawan24/link-short/internal/database"
@@ -18,24 +18,14 @@ type JwtClaims struct {
 }
 
 const (
-	accessTokenType  = "access"
-	refreshTokenType = "refresh"
-
-	defaultAccessTokenTTL  = 24 * time.Hour
-	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+	AccessTokenType             = "access"
+	RefreshTokenType            = "refresh"
+	TwoFactorChallengeTokenType = "2fa_challenge"
 
 	defaultIssuer = "Link Short"
 )
 
-func tokenSecretFromEnv(key string) ([]byte, error) {
-	secret := os.Getenv(key)
-	if secret == "" {
-		return nil, errors.New(key + " is not set")
-	}
-	return []byte(secret), nil
-}
-
-func generateToken(user database.User, tokenType string, ttl time.Duration, secretKey []byte) (string, JwtClaims, error) {
+func SignToken(user database.User, tokenType string, ttl time.Duration, secretKey []byte) (string, JwtClaims, error) {
 	claims := JwtClaims{
 		UserId:    user.ID,
 		TokenType: tokenType,
@@ -56,67 +46,21 @@ func generateToken(user database.User, tokenType string, ttl time.Duration, secr
 	return signedToken, claims, err
 }
 
-func GenerateJwtToken(user database.User) (string, JwtClaims, error) {
-	return GenerateAccessToken(user)
-}
-
-func GenerateAccessToken(user database.User) (string, JwtClaims, error) {
-	secret, err := tokenSecretFromEnv("TOKEN_SECRET")
-	if err != nil {
-		return "", JwtClaims{}, err
-	}
-	return generateToken(user, accessTokenType, defaultAccessTokenTTL, secret)
-}
-
-func GenerateRefreshToken(user database.User) (string, JwtClaims, error) {
-	secret, err := tokenSecretFromEnv("REFRESH_TOKEN_SECRET")
-	if err != nil {
-		return "", JwtClaims{}, err
-	}
-	return generateToken(user, refreshTokenType, defaultRefreshTokenTTL, secret)
+// IssuedBefore reports whether the token was issued before t. The iat claim
+// only has second precision, so tokens issued within the same second as t
+// are not counted.
+func (c *JwtClaims) IssuedBefore(t time.Time) bool {
+	return c.IssuedAt == nil || c.IssuedAt.Time.Before(t.Truncate(time.Second))
 }
 
-func ParseToken(tokenStr string) (*JwtClaims, error) {
-	secret, err := tokenSecretFromEnv("TOKEN_SECRET")
-	if err != nil {
-		return nil, err
-	}
-
-	token, err := jwt.ParseWithClaims(tokenStr, &JwtClaims{}, func(t *jwt.Token) (any, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
-		}
-
-		return secret, nil
-	})
-
-	if err != nil || token == nil {
-		return nil, err
-	}
-
-	claims, ok := token.Claims.(*JwtClaims)
-	if !ok || !token.Valid {
-		return nil, errors.New("Invalid token claims")
-	}
-
-	if claims.TokenType != "" && claims.TokenType != accessTokenType {
-		return nil, errors.New("invalid token type")
-	}
-
-	return claims, err
-}
-
-func ParseRefreshToken(tokenStr string) (*JwtClaims, error) {
-	secret, err := tokenSecretFromEnv("REFRESH_TOKEN_SECRET")
-	if err != nil {
-		return nil, err
-	}
-
+// ParseSignedToken verifies tokenStr against secretKey and accepts it only
+// when its type is one of tokenTypes.
+func ParseSignedToken(tokenStr string, secretKey []byte, tokenTypes ...string) (*JwtClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenStr, &JwtClaims{}, func(t *jwt.Token) (any, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
 		}
-		return secret, nil
+		return secretKey, nil
 	})
 
 	if err != nil || token == nil {
@@ -128,7 +72,7 @@ func ParseRefreshToken(tokenStr string) (*JwtClaims, error) {
 		return nil, errors.New("Invalid token claims")
 	}
 
-	if claims.TokenType != refreshTokenType {
+	if !slices.Contains(tokenTypes, claims.TokenType) {
 		return nil, errors.New("invalid token type")
 	}
 
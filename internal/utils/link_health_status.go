@@ -0,0 +1,26 @@
+package utils
+
+type LinkHealthStatus string
+
+const (
+	LinkHealthUnknown LinkHealthStatus = "unknown"
+	LinkHealthHealthy LinkHealthStatus = "healthy"
+	LinkHealthBroken  LinkHealthStatus = "broken"
+)
+
+func ParseLinkHealthStatus(s string) (LinkHealthStatus, error) {
+	switch LinkHealthStatus(s) {
+	case LinkHealthUnknown, LinkHealthHealthy, LinkHealthBroken:
+		return LinkHealthStatus(s), nil
+	default:
+		return "", &InvalidLinkHealthStatusError{Value: s}
+	}
+}
+
+type InvalidLinkHealthStatusError struct {
+	Value string
+}
+
+func (e *InvalidLinkHealthStatusError) Error() string {
+	return "invalid health value: " + e.Value + ". Valid values are: unknown, healthy, broken"
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"database/sql"
+	"net/url"
+)
+
+// LinkSafety is what the preview page tells visitors about a destination
+// before they follow it.
+type LinkSafety string
+
+const (
+	// LinkSafetyBlocked links were taken down by an admin.
+	LinkSafetyBlocked LinkSafety = "blocked"
+	// LinkSafetyUnreachable destinations failed their last health checks.
+	LinkSafetyUnreachable LinkSafety = "unreachable"
+	// LinkSafetyInsecure destinations are reachable over plain HTTP only.
+	LinkSafetyInsecure LinkSafety = "insecure"
+	// LinkSafetyUnverified destinations have not been checked yet.
+	LinkSafetyUnverified LinkSafety = "unverified"
+	LinkSafetyOK         LinkSafety = "ok"
+)
+
+// LinkSafetyOf rates a destination from its takedown and health state. A
+// takedown outranks everything else.
+func LinkSafetyOf(originalURL string, takenDownAt sql.NullTime, health LinkHealthStatus) LinkSafety {
+	switch {
+	case takenDownAt.Valid:
+		return LinkSafetyBlocked
+	case health == LinkHealthBroken:
+		return LinkSafetyUnreachable
+	}
+
+	if u, err := url.Parse(originalURL); err != nil || u.Scheme != "https" {
+		return LinkSafetyInsecure
+	}
+
+	if health == LinkHealthHealthy {
+		return LinkSafetyOK
+	}
+	return LinkSafetyUnverified
+}
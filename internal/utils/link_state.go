@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"database/sql"
+	"time"
+)
+
+// LinkState is where a link stands in its activation window.
+type LinkState string
+
+const (
+	LinkStateScheduled LinkState = "scheduled"
+	LinkStateActive    LinkState = "active"
+	LinkStateExpired   LinkState = "expired"
+)
+
+func ParseLinkState(s string) (LinkState, error) {
+	switch LinkState(s) {
+	case LinkStateScheduled, LinkStateActive, LinkStateExpired:
+		return LinkState(s), nil
+	default:
+		return "", &InvalidLinkStateError{Value: s}
+	}
+}
+
+// LinkStateAt returns the state of a link with the given window at now. A
+// link is active from activeFrom, inclusive, until expiredAt, exclusive;
+// either bound may be missing.
+func LinkStateAt(activeFrom sql.NullTime, expiredAt sql.NullTime, now time.Time) LinkState {
+	switch {
+	case activeFrom.Valid && activeFrom.Time.After(now):
+		return LinkStateScheduled
+	case expiredAt.Valid && !expiredAt.Time.After(now):
+		return LinkStateExpired
+	default:
+		return LinkStateActive
+	}
+}
+
+type InvalidLinkStateError struct {
+	Value string
+}
+
+func (e *InvalidLinkStateError) Error() string {
+	return "invalid state value: " + e.Value + ". Valid values are: scheduled, active, expired"
+}
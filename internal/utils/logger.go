@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type requestIDKey struct{}
+
+// ContextWithRequestID attaches a request ID that loggers created by
+// NewLogger add to every record logged with the context.
+func ContextWithRequestID(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestId)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIDKey{}).(string)
+	return requestId
+}
+
+// NewLogger returns a JSON logger that adds the request ID and trace ID of
+// the context to each record. level is one of debug, info, warn or
+// error and defaults to info.
+func NewLogger(w io.Writer, level string) *slog.Logger {
+	var logLevel slog.Level
+	switch strings.ToLower(level) {
+	case "debug":
+		logLevel = slog.LevelDebug
+	case "warn":
+		logLevel = slog.LevelWarn
+	case "error":
+		logLevel = slog.LevelError
+	default:
+		logLevel = slog.LevelInfo
+	}
+
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{Level: logLevel})
+	return slog.New(requestIDHandler{Handler: handler})
+}
+
+type requestIDHandler struct {
+	slog.Handler
+}
+
+func (h requestIDHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestId := RequestIDFromContext(ctx); requestId != "" {
+		record.AddAttrs(slog.String("request_id", requestId))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", spanContext.TraceID().String()),
+			slog.String("span_id", spanContext.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h requestIDHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return requestIDHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h requestIDHandler) WithGroup(name string) slog.Handler {
+	return requestIDHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUEviction(t *testing.T) {
+	cache := NewLRU[string, int](3)
+	cache.Set("a", 1, time.Minute)
+	cache.Set("b", 2, time.Minute)
+	cache.Set("c", 3, time.Minute)
+
+	// Reading a and rewriting b leave c as the least recently used entry.
+	if value, ok := cache.Get("a"); !ok || value != 1 {
+		t.Fatalf("Get(a) = %d, %v, want 1, true", value, ok)
+	}
+	cache.Set("b", 20, time.Minute)
+	cache.Set("d", 4, time.Minute)
+
+	if _, ok := cache.Get("c"); ok {
+		t.Fatal("c was not evicted")
+	}
+	for _, tc := range []struct {
+		key  string
+		want int
+	}{{"a", 1}, {"b", 20}, {"d", 4}} {
+		if value, ok := cache.Get(tc.key); !ok || value != tc.want {
+			t.Fatalf("Get(%s) = %d, %v, want %d, true", tc.key, value, ok, tc.want)
+		}
+	}
+	if cache.Len() != 3 {
+		t.Fatalf("Len = %d, want 3", cache.Len())
+	}
+
+	cache.Set("e", 5, time.Minute)
+	if _, ok := cache.Get("a"); ok {
+		t.Fatal("a was not evicted after b and d were read more recently")
+	}
+}
+
+func TestLRUExpiry(t *testing.T) {
+	cache := NewLRU[string, string](10)
+	cache.Set("short", "x", 10*time.Millisecond)
+	cache.Set("long", "y", time.Minute)
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := cache.Get("short"); ok {
+		t.Fatal("expired entry was returned")
+	}
+	if cache.Len() != 1 {
+		t.Fatalf("Len = %d, want the expired entry removed", cache.Len())
+	}
+
+	// Setting a key again renews its expiry.
+	cache.Set("short", "z", time.Minute)
+	if value, ok := cache.Get("short"); !ok || value != "z" {
+		t.Fatalf("Get(short) = %q, %v, want z, true", value, ok)
+	}
+}
+
+func TestLRUDeleteAndPurge(t *testing.T) {
+	cache := NewLRU[string, string](10)
+	cache.Set("a", "1", time.Minute)
+	cache.Set("b", "2", time.Minute)
+
+	cache.Delete("a")
+	cache.Delete("missing")
+	if _, ok := cache.Get("a"); ok || cache.Len() != 1 {
+		t.Fatalf("after Delete(a): Len = %d, a present = %v", cache.Len(), ok)
+	}
+
+	cache.Purge()
+	if _, ok := cache.Get("b"); ok || cache.Len() != 0 {
+		t.Fatalf("after Purge: Len = %d, b present = %v", cache.Len(), ok)
+	}
+
+	cache.Set("c", "3", time.Minute)
+	if value, ok := cache.Get("c"); !ok || value != "3" {
+		t.Fatalf("Get(c) after Purge = %q, %v, want 3, true", value, ok)
+	}
+}
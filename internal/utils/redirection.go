@@ -5,7 +5,6 @@ import (
 	"strings"
 
 	"github.com/medama-io/go-useragent"
-	"github.com/mostafa-asg/ip2country"
 )
 
 func ParseDeviceType(ua useragent.UserAgent) string {
@@ -57,11 +56,3 @@ func ParseTrafficSource(referrer string) string {
 
 	return host
 }
-
-func ParseCountryFromIp(ipAddress string) string {
-	country := ip2country.GetCountry(ipAddress)
-	if country == "" {
-		return "unknown"
-	}
-	return country
-}
@@ -0,0 +1,52 @@
+package utils
+
+// ReservedShortCodes are top level paths served by the router itself, plus a
+// few we expect to need later. A short code with one of these values would be
+// shadowed by the real route, so they can never be used.
+var ReservedShortCodes = []string{
+	"admin",
+	"analytics",
+	"api",
+	"audit",
+	"auth",
+	"campaigns",
+	"dashboard",
+	"docs",
+	"favicon.ico",
+	"health",
+	"healthz",
+	"links",
+	"login",
+	"logout",
+	"metrics",
+	"readyz",
+	"register",
+	"reset-password",
+	"robots.txt",
+	"static",
+	"stats",
+	"swagger",
+	"uploads",
+	"verify-email",
+	"webhooks",
+}
+
+// BlockedShortCodeWords are never generated and rejected as custom codes when
+// they appear anywhere inside the code. Words that are also part of common
+// ones, like "anal" in "analytics" or "canal", are left out.
+var BlockedShortCodeWords = []string{
+	"bitch",
+	"cock",
+	"cunt",
+	"dick",
+	"fag",
+	"fuck",
+	"nazi",
+	"nigg",
+	"porn",
+	"pussy",
+	"rape",
+	"shit",
+	"slut",
+	"whore",
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/andriawan24/link-short/internal/models/responses"
 	"github.com/gin-gonic/gin"
@@ -36,6 +37,26 @@ func RespondBadRequest(ctx *gin.Context, message string) {
 	respondError(ctx, http.StatusBadRequest, message, nil)
 }
 
+func RespondForbidden(ctx *gin.Context, message string) {
+	respondError(ctx, http.StatusForbidden, message, nil)
+}
+
+func RespondConflict(ctx *gin.Context, message string) {
+	respondError(ctx, http.StatusConflict, message, nil)
+}
+
+func RespondNotFound(ctx *gin.Context, message string, data any) {
+	respondError(ctx, http.StatusNotFound, message, data)
+}
+
+func RespondGone(ctx *gin.Context, message string, data any) {
+	respondError(ctx, http.StatusGone, message, data)
+}
+
+func RespondTooManyRequests(ctx *gin.Context, message string) {
+	respondError(ctx, http.StatusTooManyRequests, message, nil)
+}
+
 func respondError(ctx *gin.Context, status int, message string, err any) {
 	ctx.JSON(status, responses.ErrorResponse{
 		Message: message,
@@ -55,6 +76,8 @@ func HandleErrorResponse(ctx *gin.Context, err error) {
 		respondError(ctx, http.StatusBadRequest, "invalid JSON", nil)
 	case errors.As(err, new(*json.UnmarshalTypeError)):
 		respondError(ctx, http.StatusBadRequest, "invalid JSON field type", nil)
+	case errors.As(err, new(*strconv.NumError)):
+		respondError(ctx, http.StatusBadRequest, "invalid number in request parameter", nil)
 	case errors.As(err, new(validator.ValidationErrors)):
 		ve := err.(validator.ValidationErrors)
 		fieldErrors := make(map[string]string, len(ve))
@@ -69,6 +92,10 @@ func HandleErrorResponse(ctx *gin.Context, err error) {
 				fieldErrors[field] = "must be at least " + fe.Param()
 			case "max":
 				fieldErrors[field] = "must be at most " + fe.Param()
+			case "url":
+				fieldErrors[field] = "must be a valid URL"
+			case "oneof":
+				fieldErrors[field] = "must be one of: " + fe.Param()
 			default:
 				fieldErrors[field] = fe.Tag()
 			}
@@ -106,6 +133,12 @@ func HandleErrorResponse(ctx *gin.Context, err error) {
 		}
 	case errors.Is(err, sql.ErrNoRows):
 		respondError(ctx, http.StatusNotFound, "resource not found", nil)
+	case errors.As(err, new(*InvalidOrderByError)), errors.As(err, new(*InvalidLinkHealthStatusError)), errors.As(err, new(*InvalidLinkStateError)), errors.As(err, new(*InvalidShortCodeError)):
+		respondError(ctx, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, ErrShortCodeTaken):
+		respondError(ctx, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, ErrCircuitOpen):
+		respondError(ctx, http.StatusServiceUnavailable, "service temporarily unavailable", nil)
 	default:
 		internal := any(nil)
 		if gin.IsDebugging() {
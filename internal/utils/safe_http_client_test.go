@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestIsPublicAddr(t *testing.T) {
+	for _, tc := range []struct {
+		addr string
+		want bool
+	}{
+		{"8.8.8.8", true},
+		{"1.1.1.1", true},
+		{"2606:4700:4700::1111", true},
+		{"::ffff:8.8.8.8", true},
+
+		{"127.0.0.1", false},
+		{"127.10.0.1", false},
+		{"::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"10.0.0.1", false},
+		{"172.16.0.1", false},
+		{"172.31.255.255", false},
+		{"192.168.1.1", false},
+		{"fc00::1", false},
+		{"fd12:3456::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+		{"::ffff:169.254.169.254", false},
+		{"64:ff9b::7f00:1", false},
+		{"100.64.0.1", false},
+		{"192.0.2.1", false},
+		{"198.18.0.1", false},
+		{"2001:db8::1", false},
+		{"224.0.0.1", false},
+		{"ff02::1", false},
+		{"255.255.255.255", false},
+	} {
+		if got := IsPublicAddr(netip.MustParseAddr(tc.addr)); got != tc.want {
+			t.Errorf("IsPublicAddr(%s) = %v, want %v", tc.addr, got, tc.want)
+		}
+	}
+
+	if IsPublicAddr(netip.Addr{}) {
+		t.Error("IsPublicAddr(zero value) = true, want false")
+	}
+}
+
+func TestIsPublicHost(t *testing.T) {
+	for _, tc := range []struct {
+		host string
+		want bool
+	}{
+		{"example.com", true},
+		{"8.8.8.8", true},
+		{"localhost.example.com", true},
+
+		{"", false},
+		{"localhost", false},
+		{"LOCALHOST.", false},
+		{"api.localhost", false},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"::ffff:192.168.0.1", false},
+		{"169.254.169.254", false},
+	} {
+		if got := IsPublicHost(tc.host); got != tc.want {
+			t.Errorf("IsPublicHost(%q) = %v, want %v", tc.host, got, tc.want)
+		}
+	}
+}
+
+func TestSafeHTTPClient(t *testing.T) {
+	private := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request reached the private server: %s", r.URL)
+	}))
+	defer private.Close()
+
+	// The public server stands in for an internet host that redirects to an
+	// internal address. It is the only address the client may reach.
+	public := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusNoContent)
+		case "/private":
+			http.Redirect(w, r, private.URL, http.StatusFound)
+		case "/file":
+			http.Redirect(w, r, "file:///etc/passwd", http.StatusFound)
+		default:
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		}
+	}))
+	defer public.Close()
+
+	publicAddr := netip.MustParseAddrPort(public.Listener.Addr().String())
+	client := newSafeHTTPClient(time.Second, func(addr netip.AddrPort) bool {
+		return addr == publicAddr
+	})
+
+	resp, err := client.Get(public.URL + "/ok")
+	if err != nil {
+		t.Fatalf("GET /ok: %v", err)
+	}
+	resp.Body.Close()
+
+	for _, path := range []string{"/private", "/file", "/loop"} {
+		resp, err := client.Get(public.URL + path)
+		if err == nil {
+			resp.Body.Close()
+			t.Fatalf("GET %s succeeded with status %d, want an error", path, resp.StatusCode)
+		}
+		if path == "/private" && !errors.Is(err, ErrBlockedAddress) {
+			t.Fatalf("GET %s: error = %v, want %v", path, err, ErrBlockedAddress)
+		}
+	}
+
+	// The real client refuses the loopback servers outright.
+	for _, target := range []string{public.URL, private.URL} {
+		_, err := NewSafeHTTPClient(time.Second).Get(target)
+		if !errors.Is(err, ErrBlockedAddress) {
+			t.Fatalf("GET %s: error = %v, want %v", target, err, ErrBlockedAddress)
+		}
+	}
+
+	ipv6 := "http://[::ffff:127.0.0.1]:" + portOf(t, private.URL)
+	if _, err := NewSafeHTTPClient(time.Second).Get(ipv6); !errors.Is(err, ErrBlockedAddress) {
+		t.Fatalf("GET %s: error = %v, want %v", ipv6, err, ErrBlockedAddress)
+	}
+}
+
+func portOf(t *testing.T, rawURL string) string {
+	t.Helper()
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.Port()
+}
@@ -2,34 +2,46 @@ package utils
 
 import (
 	"crypto/rand"
+	"errors"
 	"math/big"
 	"time"
 )
 
 const (
-	shortCodeAlphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
-	defaultShortCodeN = 8
+	// DefaultShortCodeAlphabet leaves out characters that are easy to mix up
+	// when a code is read aloud or retyped: 0/O, 1/l/I.
+	DefaultShortCodeAlphabet = "23456789abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+	DefaultShortCodeLength   = 8
 )
 
-func GenerateShortCode() string {
-	const n = defaultShortCodeN
+var ErrShortCodeTaken = errors.New("short code is already taken")
 
-	out := make([]byte, n)
-	max := big.NewInt(int64(len(shortCodeAlphabet)))
+type InvalidShortCodeError struct {
+	Value  string
+	Reason string
+}
+
+func (e *InvalidShortCodeError) Error() string {
+	return "invalid short code " + e.Value + ": " + e.Reason
+}
+
+func GenerateShortCode(length int, alphabet string) string {
+	out := make([]byte, length)
+	max := big.NewInt(int64(len(alphabet)))
 
-	for i := range n {
+	for i := range length {
 		v, err := rand.Int(rand.Reader, max)
 		if err != nil {
-			return fallbackShortCode(n)
+			return fallbackShortCode(length, alphabet)
 		}
 
-		out[i] = shortCodeAlphabet[v.Int64()]
+		out[i] = alphabet[v.Int64()]
 	}
 
 	return string(out)
 }
 
-func fallbackShortCode(n int) string {
+func fallbackShortCode(n int, alphabet string) string {
 	if n <= 0 {
 		return ""
 	}
@@ -37,8 +49,8 @@ func fallbackShortCode(n int) string {
 	x := uint64(time.Now().UnixNano())
 	out := make([]byte, 0, n)
 	for len(out) < n {
-		out = append(out, shortCodeAlphabet[x%uint64(len(shortCodeAlphabet))])
-		x = x/uint64(len(shortCodeAlphabet)) + uint64(time.Now().UnixNano())
+		out = append(out, alphabet[x%uint64(len(alphabet))])
+		x = x/uint64(len(alphabet)) + uint64(time.Now().UnixNano())
 	}
 	return string(out[:n])
 }
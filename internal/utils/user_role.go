@@ -0,0 +1,6 @@
+package utils
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
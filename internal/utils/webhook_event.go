@@ -0,0 +1,20 @@
+package utils
+
+type WebhookEvent string
+
+const (
+	WebhookEventLinkCreated   WebhookEvent = "link.created"
+	WebhookEventLinkUpdated   WebhookEvent = "link.updated"
+	WebhookEventLinkDeleted   WebhookEvent = "link.deleted"
+	WebhookEventLinkExpired   WebhookEvent = "link.expired"
+	WebhookEventLinkBroken    WebhookEvent = "link.broken"
+	WebhookEventClickRecorded WebhookEvent = "click.recorded"
+)
+
+func (e WebhookEvent) IsValid() bool {
+	switch e {
+	case WebhookEventLinkCreated, WebhookEventLinkUpdated, WebhookEventLinkDeleted, WebhookEventLinkExpired, WebhookEventLinkBroken, WebhookEventClickRecorded:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+const webhookSecretPrefix = "whsec_"
+
+func GenerateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + hex.EncodeToString(b), nil
+}
+
+// SignWebhookPayload signs "<timestamp>.<body>" with HMAC-SHA256 so receivers
+// can reject both tampered and replayed deliveries.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func VerifyWebhookSignature(secret string, timestamp int64, body []byte, signature string) bool {
+	expected := SignWebhookPayload(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
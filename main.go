@@ -5,22 +5,29 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
+	"path/filepath"
+	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/andriawan24/link-short/internal/config"
 	"github.com/andriawan24/link-short/internal/database"
+	"github.com/andriawan24/link-short/internal/metrics"
 	"github.com/andriawan24/link-short/internal/middlewares"
+	"github.com/andriawan24/link-short/internal/repository"
+	"github.com/andriawan24/link-short/internal/resilience"
 	"github.com/andriawan24/link-short/internal/routes"
 	"github.com/andriawan24/link-short/internal/services"
+	"github.com/andriawan24/link-short/internal/tracing"
+	"github.com/andriawan24/link-short/internal/utils"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
-	"github.com/mostafa-asg/ip2country"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -52,208 +59,431 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	loadEnv()
-	loadIPDatabase()
+	envErr := loadEnv()
 
-	rdb := newRedisClient()
-	db := newPostgresDB(ctx)
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	setupLogger(cfg.Log)
+	if envErr != nil {
+		slog.Warn("failed to read .env file", "error", envErr)
+	}
+
+	geoLocator := newGeoLocator(cfg.GeoIP)
+
+	shutdownTracing := setupTracing(ctx, cfg.Tracing)
+	defer flushTraces(shutdownTracing)
+
+	rdb := newRedisClient(cfg.Redis)
+	db := newPostgresDB(ctx, cfg.Database)
 	defer db.Close()
 
-	queries := database.New(db)
-	router := setupRouter(ctx, db, queries, rdb)
-	server := newHTTPServer(router)
+	metrics.RegisterPools(db, rdb)
+	tracing.InstrumentRedis(rdb)
+
+	dbBreaker := resilience.NewBreaker("postgres", cfg.Breaker.FailureThreshold, cfg.Breaker.OpenTimeout)
+	redisBreaker := resilience.NewBreaker("redis", cfg.Breaker.FailureThreshold, cfg.Breaker.OpenTimeout)
+	resilience.ProtectRedis(rdb, redisBreaker)
 
-	gracefulShutdown(ctx, server)
+	store := database.New(tracing.WrapDBTX(resilience.WrapDBTX(db, dbBreaker)))
+	router := setupRouter(ctx, cfg, db, store, rdb, geoLocator, dbBreaker, redisBreaker)
+	server := newHTTPServer(cfg.HTTP, router)
+
+	gracefulShutdown(ctx, cfg.HTTP, server)
 	startServer(server)
 }
 
-func loadEnv() {
-	if err := godotenv.Load(); err != nil {
-		fmt.Println("Failed to read .env file: %v", err)
+func loadEnv() error {
+	return godotenv.Load()
+}
+
+// setupLogger routes both slog and the standard log package through a JSON
+// logger that adds the request ID of the context to each line.
+func setupLogger(cfg config.LogConfig) {
+	slog.SetDefault(utils.NewLogger(os.Stdout, cfg.Level))
+}
+
+// fatal logs msg and exits, the slog counterpart of log.Fatalf.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+func setupTracing(ctx context.Context, cfg config.TracingConfig) func(context.Context) error {
+	shutdown, err := tracing.Setup(ctx, tracing.Config{
+		Exporter:    cfg.Exporter,
+		ServiceName: cfg.ServiceName,
+		SampleRatio: cfg.SampleRatio,
+	})
+	if err != nil {
+		fatal("failed to set up tracing", "error", err)
 	}
+
+	return shutdown
 }
 
-func loadIPDatabase() {
-	if err := ip2country.Load("internal/sources/dbip-country.csv"); err != nil {
-		log.Fatalf("Failed to load IP country database file: %v", err)
+func flushTraces(shutdown func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := shutdown(ctx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
 	}
 }
 
-func newRedisClient() *redis.Client {
+// newGeoLocator prefers the MaxMind DB, which also resolves regions and
+// cities, over the country CSV.
+func newGeoLocator(cfg config.GeoIPConfig) services.GeoLocator {
+	if cfg.MMDBPath != "" {
+		locator, err := services.NewMMDBLocator(services.MMDBOptions{
+			Path:           cfg.MMDBPath,
+			ASNPath:        cfg.ASNMMDBPath,
+			ReloadInterval: cfg.ReloadInterval,
+		})
+		if err != nil {
+			fatal("failed to load IP geolocation database", "path", cfg.MMDBPath, "error", err)
+		}
+		return locator
+	}
+
+	locator, err := services.NewCSVCountryLocator(cfg.CountryDatabasePath)
+	if err != nil {
+		fatal("failed to load IP country database file", "path", cfg.CountryDatabasePath, "error", err)
+	}
+	return locator
+}
+
+func newRedisClient(cfg config.RedisConfig) *redis.Client {
 	return redis.NewClient(&redis.Options{
-		Addr:     getenv("REDIS_ADDR", "localhost:6379"),
-		Password: getenv("REDIS_PASSWORD", ""),
-		DB:       0,
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+		PoolSize: cfg.PoolSize,
 		Protocol: 2,
 	})
 }
 
-func newPostgresDB(ctx context.Context) *sql.DB {
-	db, err := sql.Open("postgres", buildConnectionString())
+func newMailer(cfg config.MailConfig) services.Mailer {
+	switch cfg.Driver {
+	case "smtp":
+		return services.NewSMTPMailer(
+			cfg.SMTPHost,
+			strconv.Itoa(cfg.SMTPPort),
+			cfg.SMTPUsername,
+			cfg.SMTPPassword,
+			cfg.From,
+		)
+	case "memory":
+		return services.NewMemoryMailer()
+	default:
+		return services.NewFileMailer(cfg.FileDir, cfg.From)
+	}
+}
+
+func newBlobStore(cfg config.StorageConfig) services.BlobStore {
+	if cfg.Driver == "s3" {
+		return services.NewS3BlobStore(services.S3Options{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			PathStyle:       cfg.S3PathStyle,
+			PublicURL:       cfg.S3PublicURL,
+		}, nil)
+	}
+
+	return services.NewLocalBlobStore(cfg.LocalDir, "/uploads")
+}
+
+func newShortCodeOptions(cfg config.ShortCodeConfig) services.ShortCodeOptions {
+	options := services.DefaultShortCodeOptions()
+
+	options.Length = cfg.Length
+	options.Alphabet = cfg.Alphabet
+	options.ReservedWords = append(options.ReservedWords, cfg.ReservedWords...)
+	options.BlockedWords = append(options.BlockedWords, cfg.BlockedWords...)
+
+	return options
+}
+
+func newTokenService(cfg config.AuthConfig) services.TokenService {
+	return services.NewTokenService(services.TokenOptions{
+		Secret:                cfg.TokenSecret,
+		RefreshSecret:         cfg.RefreshTokenSecret,
+		AccessTokenTTL:        cfg.AccessTokenTTL,
+		RefreshTokenTTL:       cfg.RefreshTokenTTL,
+		TwoFactorChallengeTTL: cfg.TwoFactorChallengeTTL,
+	})
+}
+
+func newPostgresDB(ctx context.Context, cfg config.DatabaseConfig) *sql.DB {
+	db, err := sql.Open("postgres", buildConnectionString(cfg))
 	if err != nil {
-		log.Fatalf("Failed to open database connection: %v", err)
+		fatal("failed to open database connection", "error", err)
 	}
 
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(25)
-	db.SetConnMaxLifetime(30 * time.Minute)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
-	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	pingCtx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
 	defer cancel()
 
 	if err := db.PingContext(pingCtx); err != nil {
-		log.Fatalf("Failed to ping database: %v", err)
+		fatal("failed to ping database", "error", err)
 	}
 
 	return db
 }
 
-func buildConnectionString() string {
+func buildConnectionString(cfg config.DatabaseConfig) string {
 	return fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		getenv("DB_HOST", "localhost"),
-		getenv("DB_PORT", "5432"),
-		getenv("DB_USER", "postgres"),
-		getenv("DB_PASSWORD", "postgres"),
-		getenv("DB_NAME", "link-short"),
-		getenv("DB_SSLMODE", "disable"),
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host,
+		cfg.Port,
+		cfg.User,
+		cfg.Password,
+		cfg.Name,
+		cfg.SSLMode,
 	)
 }
 
-func setupRouter(ctx context.Context, db *sql.DB, queries *database.Queries, rdb *redis.Client) *gin.Engine {
+func setupRouter(ctx context.Context, cfg config.Config, db routes.Pinger, store repository.Store, rdb *redis.Client, geoLocator services.GeoLocator, dbBreaker, redisBreaker *utils.CircuitBreaker) *gin.Engine {
 	r := gin.New()
-	r.Use(gin.Logger(), gin.Recovery())
+	r.Use(middlewares.RequestID(), middlewares.Tracing(), middlewares.RequestLogger(), gin.Recovery())
 	_ = r.SetTrustedProxies(nil)
 
-	r.Use(cors.New(buildCORSConfig()))
+	r.Use(cors.New(buildCORSConfig(cfg.CORS)))
 
-	registerRoutes(r, ctx, db, queries, rdb)
+	registerRoutes(r, ctx, cfg, db, store, rdb, geoLocator, dbBreaker, redisBreaker)
 
 	return r
 }
 
-func buildCORSConfig() cors.Config {
+func buildCORSConfig(cfg config.CORSConfig) cors.Config {
 	return cors.Config{
-		AllowOrigins:     parseAllowedOrigins(),
+		AllowOrigins:     cfg.AllowOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: getenv("CORS_ALLOW_CREDENTIALS", "true") == "true",
-		MaxAge:           12 * time.Hour,
+		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", middlewares.RequestIDHeader, "traceparent", "tracestate"},
+		ExposeHeaders:    []string{"Content-Length", middlewares.RequestIDHeader},
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
 	}
 }
 
-func parseAllowedOrigins() []string {
-	corsOrigins := getenv("CORS_ALLOW_ORIGINS", "*")
-	if corsOrigins == "*" {
-		return []string{"*"}
-	}
-
-	var origins []string
-	for origin := range strings.SplitSeq(corsOrigins, ",") {
-		if trimmed := strings.TrimSpace(origin); trimmed != "" {
-			origins = append(origins, trimmed)
-		}
+func registerRoutes(r *gin.Engine, ctx context.Context, cfg config.Config, db routes.Pinger, store repository.Store, rdb *redis.Client, geoLocator services.GeoLocator, dbBreaker, redisBreaker *utils.CircuitBreaker) {
+	userService := services.NewUserService(store)
+	tokenService := newTokenService(cfg.Auth)
+	shortCodeService := services.NewShortCodeService(store, newShortCodeOptions(cfg.ShortCode))
+	linkService := services.NewLinkService(store, store, shortCodeService)
+	cacheService := services.NewCacheService(rdb, services.CacheOptions{
+		TTL:         cfg.Cache.RedirectTTL,
+		NegativeTTL: cfg.Cache.NegativeTTL,
+		LocalSize:   cfg.Cache.LocalSize,
+		LocalTTL:    cfg.Cache.LocalTTL,
+	})
+	clickLogService := services.NewClickLogService(store)
+	campaignService := services.NewCampaignService(store, store, cacheService)
+	publicStatsService := services.NewPublicStatsService(store, store, rdb, cfg.Cache.PublicStatsTTL)
+	appLinkService := services.NewAppLinkService(services.AppLinkOptions{
+		IOSAppIDs:               cfg.AppLinks.IOSAppIDs,
+		IOSPaths:                cfg.AppLinks.IOSPaths,
+		AndroidPackages:         cfg.AppLinks.AndroidPackages,
+		AndroidCertFingerprints: cfg.AppLinks.AndroidCertFingerprints,
+		FallbackDelay:           cfg.AppLinks.FallbackDelay,
+	})
+	clickSpool := services.NewClickSpool(store, cfg.Spool.Dir, int64(cfg.Spool.MaxBytes), cfg.Spool.ReplayInterval)
+	oauthService := services.NewOAuthService(cfg.Google.ClientID, cfg.Google.ClientSecret, cfg.Google.CallbackURL)
+	accountService := services.NewAccountService(store, newMailer(cfg.Mail), cfg.App.BaseURL, cfg.Privacy.DeletionGracePeriod)
+	dashboardService := services.NewDashboardService(store)
+	twoFactorService := services.NewTwoFactorService(store)
+	loginAttemptService := services.NewLoginAttemptService(store)
+	clickStreamService := services.NewClickStreamService(rdb)
+	webhookService := services.NewWebhookService(store)
+	webhookWorker := services.NewWebhookWorker(store, store, webhookService, nil)
+	linkMetadataService := services.NewLinkMetadataService(store, nil)
+	linkHealthWorker := services.NewLinkHealthWorker(store, webhookService, nil)
+	adminService := services.NewAdminService(store)
+	auditService := services.NewAuditService(store)
+	blobStore := newBlobStore(cfg.Storage)
+	profileImageService := services.NewProfileImageService(blobStore, int64(cfg.Storage.MaxUploadBytes))
+	dataExportService := services.NewDataExportService(store, store, blobStore, cfg.Privacy.ExportTTL)
+	accountPurgeWorker := services.NewAccountPurgeWorker(store, profileImageService, blobStore, cacheService, cfg.Privacy.DeletionGracePeriod)
+	clickLogRetentionWorker := services.NewClickLogRetentionWorker(store, blobStore, cfg.Retention.ClickLogMonths, cfg.Retention.ClickLogMode == "archive", cfg.Retention.IPAnonymizeAfter)
+
+	if err := adminService.PromoteAdmins(ctx, cfg.App.AdminEmails); err != nil {
+		slog.Error("failed to promote admin accounts", "error", err)
 	}
-	return origins
-}
 
-func registerRoutes(r *gin.Engine, ctx context.Context, db *sql.DB, queries *database.Queries, rdb *redis.Client) {
-	userService := services.NewUserService(queries)
-	linkService := services.NewLinkService(queries)
-	cacheService := services.NewCacheService(rdb)
-	clickLogService := services.NewClickLogService(queries)
-	oauthService := services.NewOAuthService()
-	dashboardService := services.NewDashboardService(queries)
-
-	linkRoutes := routes.NewLinkRoutes(linkService, clickLogService, cacheService)
-	authRoutes := routes.NewAuthRoutes(userService, oauthService)
-	analyticRoutes := routes.NewAnalyticRoutes(linkService, clickLogService)
+	go cacheService.Run(ctx)
+	go clickSpool.Run(ctx)
+	go webhookWorker.Run(ctx)
+	go linkHealthWorker.Run(ctx)
+	go dataExportService.Run(ctx)
+	go accountPurgeWorker.Run(ctx)
+	go clickLogRetentionWorker.Run(ctx)
+	go campaignService.Run(ctx)
+	go geoLocator.Run(ctx)
+
+	linkRoutes := routes.NewLinkRoutes(linkService, clickLogService, clickSpool, cacheService, clickStreamService, webhookService, linkMetadataService, shortCodeService, auditService, appLinkService, geoLocator, publicStatsService)
+	authRoutes := routes.NewAuthRoutes(userService, tokenService, oauthService, accountService, twoFactorService, loginAttemptService, auditService, profileImageService)
+	analyticRoutes := routes.NewAnalyticRoutes(linkService, clickLogService, clickStreamService)
+	campaignRoutes := routes.NewCampaignRoutes(campaignService, linkService)
 	dashboardRoutes := routes.NewDashboardRoutes(dashboardService)
+	webhookRoutes := routes.NewWebhookRoutes(webhookService)
+	adminRoutes := routes.NewAdminRoutes(adminService, clickLogService, cacheService, publicStatsService, auditService)
+	auditRoutes := routes.NewAuditRoutes(auditService)
+	accountRoutes := routes.NewAccountRoutes(userService, accountService, twoFactorService, dataExportService, loginAttemptService, auditService)
+	healthRoutes := routes.NewHealthRoutes(db, rdb, dbBreaker, redisBreaker)
+	appLinkRoutes := routes.NewAppLinkRoutes(appLinkService)
+	publicStatsRoutes := routes.NewPublicStatsRoutes(publicStatsService)
+
+	requiredAuth := middlewares.RequiredAuth(tokenService, userService)
 
 	authGroup := r.Group("/auth")
 	{
-		authGroup.GET("/me", middlewares.RequiredAuth(), authRoutes.Profile)
+		authGroup.GET("/me", requiredAuth, authRoutes.Profile)
+		authGroup.GET("/activity", requiredAuth, authRoutes.GetActivity)
 		authGroup.POST("/login", authRoutes.Login)
 		authGroup.POST("/refresh", authRoutes.Refresh)
 		authGroup.POST("/register", authRoutes.Register)
-		authGroup.PUT("/update-profile", middlewares.RequiredAuth(), authRoutes.UpdateProfile)
+		authGroup.PUT("/update-profile", requiredAuth, authRoutes.UpdateProfile)
 		authGroup.GET("/google", authRoutes.GoogleAuth)
+		authGroup.POST("/verify-email", authRoutes.VerifyEmail)
+		authGroup.POST("/resend-verification", requiredAuth, authRoutes.ResendVerification)
+		authGroup.POST("/forgot-password", authRoutes.ForgotPassword)
+		authGroup.POST("/reset-password", authRoutes.ResetPassword)
+		authGroup.POST("/2fa/enroll", requiredAuth, authRoutes.EnrollTwoFactor)
+		authGroup.POST("/2fa/confirm", requiredAuth, authRoutes.ConfirmTwoFactor)
+		authGroup.POST("/2fa/verify", authRoutes.VerifyTwoFactor)
+		authGroup.POST("/2fa/disable", requiredAuth, authRoutes.DisableTwoFactor)
 	}
 
-	linkGroup := r.Group("/links", middlewares.RequiredAuth())
+	createLinkHandlers := []gin.HandlerFunc{linkRoutes.InsertLink}
+	if cfg.App.RequireEmailVerification {
+		createLinkHandlers = append([]gin.HandlerFunc{middlewares.RequiredVerifiedEmail(userService)}, createLinkHandlers...)
+	}
+
+	accountGroup := r.Group("/account")
+	{
+		accountGroup.GET("/export", requiredAuth, accountRoutes.ExportData)
+		accountGroup.GET("/export/:id/download", requiredAuth, accountRoutes.DownloadDataExport)
+		accountGroup.DELETE("", requiredAuth, accountRoutes.DeleteAccount)
+		accountGroup.POST("/restore", accountRoutes.RestoreAccount)
+	}
+
+	linkGroup := r.Group("/links", requiredAuth)
 	{
 		linkGroup.GET("/all", linkRoutes.GetLinks)
 		linkGroup.GET("/:id", linkRoutes.GetLink)
-		linkGroup.POST("/create", linkRoutes.InsertLink)
+		linkGroup.POST("/create", createLinkHandlers...)
+		linkGroup.PUT("/:id", linkRoutes.UpdateLink)
 		linkGroup.DELETE("/:id", linkRoutes.DeleteLink)
+		linkGroup.PUT("/:id/public-stats", publicStatsRoutes.SetPublicStats)
+		linkGroup.POST("/:id/public-stats/rotate", publicStatsRoutes.RotatePublicStatsToken)
 	}
 
-	analyticGroup := r.Group("/analytics", middlewares.RequiredAuth())
+	analyticGroup := r.Group("/analytics", requiredAuth)
 	{
 		analyticGroup.GET("/dashboard", analyticRoutes.GetDashboard)
+		analyticGroup.GET("/stream", analyticRoutes.StreamClicks)
 		analyticGroup.GET("/", analyticRoutes.GetAnalytics)
 	}
 
+	campaignGroup := r.Group("/campaigns", requiredAuth)
+	{
+		campaignGroup.GET("", campaignRoutes.GetCampaigns)
+		campaignGroup.POST("", campaignRoutes.InsertCampaign)
+		campaignGroup.GET("/:id", campaignRoutes.GetCampaign)
+		campaignGroup.PUT("/:id", campaignRoutes.UpdateCampaign)
+		campaignGroup.DELETE("/:id", campaignRoutes.DeleteCampaign)
+		campaignGroup.GET("/:id/analytics", campaignRoutes.GetAnalytics)
+		campaignGroup.POST("/:id/links", campaignRoutes.AddLinks)
+		campaignGroup.DELETE("/:id/links/:linkId", campaignRoutes.RemoveLink)
+	}
+
+	webhookGroup := r.Group("/webhooks", requiredAuth)
+	{
+		webhookGroup.GET("", webhookRoutes.GetWebhooks)
+		webhookGroup.POST("", webhookRoutes.InsertWebhook)
+		webhookGroup.DELETE("/:id", webhookRoutes.DeleteWebhook)
+		webhookGroup.POST("/:id/enable", webhookRoutes.EnableWebhook)
+		webhookGroup.GET("/:id/deliveries", webhookRoutes.GetDeliveries)
+		webhookGroup.POST("/:id/deliveries/:deliveryId/redeliver", webhookRoutes.Redeliver)
+	}
+
+	auditGroup := r.Group("/audit", requiredAuth)
+	{
+		auditGroup.GET("", auditRoutes.GetEvents)
+	}
+
+	adminGroup := r.Group("/admin", requiredAuth, middlewares.RequiredAdmin())
+	{
+		adminGroup.GET("/users", adminRoutes.GetUsers)
+		adminGroup.POST("/users/:id/suspend", adminRoutes.SuspendUser)
+		adminGroup.POST("/users/:id/reactivate", adminRoutes.ReactivateUser)
+		adminGroup.PUT("/users/:id/role", adminRoutes.UpdateUserRole)
+		adminGroup.GET("/links", adminRoutes.GetLinks)
+		adminGroup.GET("/links/:id", adminRoutes.GetLink)
+		adminGroup.POST("/links/:id/takedown", adminRoutes.TakeDownLink)
+		adminGroup.POST("/links/:id/restore", adminRoutes.RestoreLink)
+		adminGroup.GET("/growth", adminRoutes.GetGrowth)
+	}
+
 	dashboardGroup := r.Group("/dashboard")
 	{
 		dashboardGroup.GET("/stats", dashboardRoutes.GetLandingStats)
 	}
 
-	r.Static("/uploads", "./uploads")
+	r.GET("/stats/:token", publicStatsRoutes.GetPublicStats)
 
+	if cfg.Storage.Driver == "local" {
+		// Only profile images are public; data exports are downloaded
+		// through the authenticated /account routes.
+		r.Static("/uploads/profiles", filepath.Join(cfg.Storage.LocalDir, "profiles"))
+	}
+
+	r.GET("/.well-known/apple-app-site-association", appLinkRoutes.AppleAppSiteAssociation)
+	r.GET("/.well-known/assetlinks.json", appLinkRoutes.AssetLinks)
 	r.GET("/:code", linkRoutes.Redirect)
-	r.GET("/health", healthCheckHandler(db))
+	r.GET("/:code/preview", linkRoutes.Preview)
+	r.GET("/healthz", healthRoutes.Liveness)
+	r.GET("/readyz", healthRoutes.Readiness)
+	r.GET("/health", healthRoutes.Readiness)
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	r.NoRoute()
 }
 
-func healthCheckHandler(db *sql.DB) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		healthCtx, cancel := context.WithTimeout(c.Request.Context(), 1*time.Second)
-		defer cancel()
-
-		if err := db.PingContext(healthCtx); err != nil {
-			c.JSON(http.StatusServiceUnavailable, gin.H{"message": "db unavailable"})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{"message": "ok"})
-	}
-}
-
-func newHTTPServer(handler http.Handler) *http.Server {
+func newHTTPServer(cfg config.HTTPConfig, handler http.Handler) *http.Server {
 	return &http.Server{
-		Addr:              ":" + getenv("HTTP_PORT", "8080"),
+		Addr:              ":" + strconv.Itoa(cfg.Port),
 		Handler:           handler,
-		ReadHeaderTimeout: 5 * time.Second,
-		ReadTimeout:       10 * time.Second,
-		WriteTimeout:      15 * time.Second,
-		IdleTimeout:       60 * time.Second,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
 	}
 }
 
-func gracefulShutdown(ctx context.Context, srv *http.Server) {
+func gracefulShutdown(ctx context.Context, cfg config.HTTPConfig, srv *http.Server) {
 	go func() {
 		<-ctx.Done()
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 		defer cancel()
 		if err := srv.Shutdown(shutdownCtx); err != nil {
-			log.Printf("HTTP server shutdown error: %v", err)
+			slog.Error("HTTP server shutdown error", "error", err)
 		}
 	}()
 }
 
 func startServer(srv *http.Server) {
-	log.Printf("HTTP server listening on %s", srv.Addr)
+	slog.Info("HTTP server listening", "addr", srv.Addr)
 	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-		log.Fatalf("Failed to run server: %v", err)
-	}
-}
-
-func getenv(key, fallback string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
+		fatal("failed to run server", "error", err)
 	}
-	return fallback
 }